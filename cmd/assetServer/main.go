@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"github.com/ZilDuck/zilliqa-chain-indexer/generated/dic"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/asset"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/config"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/shutdown"
 	"go.uber.org/zap"
 	"net/http"
 )
@@ -18,9 +20,27 @@ func main() {
 		container.GetMetadataService(),
 	).Router()
 
-	zap.L().Info("Serving assets on :" + config.Get().AssetPort)
+	ctx, stop := shutdown.Notify()
+	defer stop()
 
-	if err := http.ListenAndServe(":"+config.Get().AssetPort, router); err != nil {
-		zap.L().With(zap.Error(err)).Error("Failed to start asset server")
+	server := &http.Server{Addr: ":" + config.Get().AssetPort, Handler: router}
+
+	go func() {
+		zap.L().Info("Serving assets on :" + config.Get().AssetPort)
+
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			zap.L().With(zap.Error(err)).Error("Failed to start asset server")
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+
+	// Requests in flight are given the shutdown timeout to complete
+	timeout, cancel := context.WithTimeout(context.Background(), shutdown.Timeout())
+	defer cancel()
+
+	if err := server.Shutdown(timeout); err != nil {
+		zap.L().With(zap.Error(err)).Error("Failed to shut down asset server")
 	}
 }
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/ZilDuck/zilliqa-chain-indexer/generated/dic"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/config"
@@ -12,6 +15,8 @@ import (
 	"github.com/urfave/cli/v2"
 	"go.uber.org/zap"
 	"os"
+	"strings"
+	"time"
 )
 
 var (
@@ -20,6 +25,7 @@ var (
 	contractRepo       repository.ContractRepository
 	txRepo             repository.TransactionRepository
 	nftRepo            repository.NftRepository
+	deadLetterRepo     repository.DeadLetterRepository
 	contractIndexer    indexer.ContractIndexer
 	marketplaceIndexer indexer.MarketplaceIndexer
 	metadataIndexer    indexer.MetadataIndexer
@@ -36,6 +42,7 @@ func main() {
 	contractRepo = container.GetContractRepo()
 	txRepo = container.GetTxRepo()
 	nftRepo = container.GetNftRepo()
+	deadLetterRepo = container.GetDeadLetterRepo()
 	contractIndexer = container.GetContractIndexer()
 	marketplaceIndexer = container.GetMarketplaceIndexer()
 	metadataIndexer = container.GetMetadataIndexer()
@@ -71,11 +78,74 @@ func main() {
 					&cli.Int64Flag{Name: "from", Value: 0, Usage: "Import contracts from blockNum"},
 				},
 			},
+			{
+				Name:   "contract:shapes",
+				Usage:  "Report the shapes a contract matches, and the rules failed for those it does not",
+				Action: matchContractShapes,
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "contract", Value: "", Usage: "The contract to check", Required: true},
+				},
+			},
+			{
+				Name:   "nft:reconcile",
+				Usage:  "Compare nft owners with the token_owners of their contracts, recording the discrepancies",
+				Action: reconcileNfts,
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "contract", Value: "", Usage: "Reconcile a single contract"},
+					&cli.BoolFlag{Name: "repair", Value: false, Usage: "Correct owners which also differed on the previous reconcile"},
+				},
+			},
+			{
+				Name:   "governance:import",
+				Usage:  "Import the governance history of ZRC6 contracts from their txs",
+				Action: importGovernance,
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "contract", Value: "", Usage: "Import for a single contract"},
+				},
+			},
 			{
 				Name:   "marketplace",
 				Usage:  "Reindex all marketplace actions",
 				Action: processMarketplaceActions,
 			},
+			{
+				Name:   "migrate:status",
+				Usage:  "List mapping migrations and whether they have been applied",
+				Action: migrationStatus,
+			},
+			{
+				Name:   "migrate:up",
+				Usage:  "Apply pending mapping migrations",
+				Action: migrateUp,
+			},
+			{
+				Name:   "deadletter:list",
+				Usage:  "List requests that failed to persist",
+				Action: listDeadLetters,
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "id", Value: "", Usage: "Show the document of a single dead letter"},
+					&cli.IntFlag{Name: "size", Value: 100, Usage: "Number of dead letters to list"},
+					&cli.IntFlag{Name: "page", Value: 1, Usage: "Page of dead letters to list"},
+				},
+			},
+			{
+				Name:   "deadletter:fix",
+				Usage:  "Edit the document of a dead letter before replaying it",
+				Action: fixDeadLetter,
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "id", Value: "", Usage: "The dead letter to fix", Required: true},
+					&cli.StringSliceFlag{Name: "remove", Usage: "Remove a field from the document, e.g. metadata.properties"},
+					&cli.StringFlag{Name: "document", Value: "", Usage: "Replace the document with this json"},
+				},
+			},
+			{
+				Name:   "deadletter:replay",
+				Usage:  "Replay dead letters to their original index",
+				Action: replayDeadLetters,
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "id", Value: "", Usage: "Replay a single dead letter"},
+				},
+			},
 		},
 	}
 
@@ -87,6 +157,8 @@ func main() {
 
 // METADATA
 func processMetadata(c *cli.Context) error {
+	ctx := c.Context
+
 	size, err := messengerService.GetQueueSize(messenger.MetadataRefresh)
 	if err != nil {
 		zap.L().With(zap.Error(err)).Error("Could not get the queue size")
@@ -97,12 +169,12 @@ func processMetadata(c *cli.Context) error {
 		return nil
 	}
 
-	if err := metadataIndexer.RefreshByStatus(entity.MetadataPending); err != nil {
+	if err := metadataIndexer.RefreshByStatus(ctx, entity.MetadataPending); err != nil {
 		zap.L().With(zap.Error(err)).Fatal("Failed to process pending metadata")
 		return err
 	}
 
-	if err := metadataIndexer.RefreshByStatus(entity.MetadataFailure); err != nil {
+	if err := metadataIndexer.RefreshByStatus(ctx, entity.MetadataFailure); err != nil {
 		zap.L().With(zap.Error(err)).Fatal("Failed to process failure metadata")
 		return err
 	}
@@ -112,27 +184,29 @@ func processMetadata(c *cli.Context) error {
 
 // NFTS
 func importNfts(c *cli.Context) error {
+	ctx := c.Context
+
 	contractAddr := c.String("contract")
 	purge := c.Bool("purge")
 
 	if contractAddr != "" {
-		contract, err := contractRepo.GetContractByAddress(contractAddr)
+		contract, err := contractRepo.GetContractByAddress(ctx, contractAddr)
 		if err != nil {
 			zap.S().Errorf("Failed to find contract: %s", contractAddr)
 			return err
 		}
 
 		if purge {
-			if err := nftRepo.PurgeContract(contract.Address); err != nil {
+			if err := nftRepo.PurgeContract(ctx, contract.Address); err != nil {
 				return err
 			}
 		}
 
-		importNftsForContract(*contract)
-		importMarketplaceSalesForContract(*contract)
+		importNftsForContract(ctx, *contract)
+		importMarketplaceSalesForContract(ctx, *contract)
 	} else {
-		importAllNfts()
-		importMarketplaceSales()
+		importAllNfts(ctx)
+		importMarketplaceSales(ctx)
 	}
 
 	zap.L().Info("Ready for exit")
@@ -141,12 +215,12 @@ func importNfts(c *cli.Context) error {
 	return nil
 }
 
-func importAllNfts() {
+func importAllNfts(ctx context.Context) {
 	size := 100
 	page := 1
 
 	for {
-		contracts, total, err := contractRepo.GetAllNftContracts(size, page)
+		contracts, total, err := contractRepo.GetAllNftContracts(ctx, size, page)
 		if err != nil {
 			zap.L().With(zap.Error(err)).Error("Failed to get contracts")
 			break
@@ -158,7 +232,7 @@ func importAllNfts() {
 			break
 		}
 		for _, c := range contracts {
-			importNftsForContract(c)
+			importNftsForContract(ctx, c)
 		}
 		elastic.BatchPersist()
 		page++
@@ -166,18 +240,18 @@ func importAllNfts() {
 	elastic.Persist()
 }
 
-func importNftsForContract(contract entity.Contract) {
+func importNftsForContract(ctx context.Context, contract entity.Contract) {
 	zap.L().Info("*** Import Nfts For Contract: " + contract.Address)
-	_ = nftRepo.PurgeActions(contract.Address)
+	_ = nftRepo.PurgeActions(ctx, contract.Address)
 
 	if contract.MatchesStandard(entity.ZRC6) {
 		zap.L().With(zap.String("contractAddr", contract.Address), zap.String("shape", "ZRC6")).Info("Import nfts for contract")
-		if err := zrc6Indexer.IndexContract(contract); err != nil {
+		if err := zrc6Indexer.IndexContract(ctx, contract); err != nil {
 			zap.S().Fatalf("Failed to index ZRC6 NFTs for contract %s", contract.Address)
 		}
 	} else if contract.MatchesStandard(entity.ZRC1) {
 		zap.L().With(zap.String("contractAddr", contract.Address), zap.String("shape", "ZRC1")).Info("Import nfts for contract")
-		if err := zrc1Indexer.IndexContract(contract); err != nil {
+		if err := zrc1Indexer.IndexContract(ctx, contract); err != nil {
 			zap.S().Fatalf("Failed to index ZRC1 NFTs for contract %s", contract.Address)
 		}
 	}
@@ -185,6 +259,8 @@ func importNftsForContract(contract entity.Contract) {
 }
 
 func importContracts(c *cli.Context) error {
+	ctx := c.Context
+
 	contractAddr := c.String("contract")
 	from := c.Uint64("from")
 	if from == 0 {
@@ -192,18 +268,18 @@ func importContracts(c *cli.Context) error {
 	}
 
 	if contractAddr != "" {
-		tx, err := txRepo.GetContractCreationForContract(contractAddr)
+		tx, err := txRepo.GetContractCreationForContract(ctx, contractAddr)
 		if err != nil {
 			zap.L().With(zap.String("contract", contractAddr)).Error("Failed to find contract creation tx")
 			return err
 		}
 
-		contractIndexer.Index([]entity.Transaction{*tx})
+		contractIndexer.Index(ctx, []entity.Transaction{*tx})
 		elastic.Persist()
 		return nil
 	}
 
-	if err := contractIndexer.BulkIndex(from); err != nil {
+	if err := contractIndexer.BulkIndex(ctx, from); err != nil {
 		zap.L().Error("Failed to bulk index contracts")
 		return err
 	}
@@ -211,11 +287,116 @@ func importContracts(c *cli.Context) error {
 	return nil
 }
 
-func importMarketplaceSales() {
+func matchContractShapes(c *cli.Context) error {
+	contract, err := contractRepo.GetContractByAddress(c.Context, c.String("contract"))
+	if err != nil {
+		zap.S().Errorf("Failed to find contract: %s", c.String("contract"))
+		return err
+	}
+
+	for _, match := range container.GetContractShapes().Match(*contract) {
+		zap.L().With(
+			zap.String("shape", match.Shape),
+			zap.String("standard", string(match.Standard)),
+			zap.Bool("matched", match.Matched),
+			zap.Bool("byAddress", match.ByAddress),
+			zap.Strings("failures", match.Failures),
+		).Info("Contract shape")
+	}
+
+	return nil
+}
+
+func reconcileNfts(c *cli.Context) error {
+	ctx := c.Context
+	reconciler := container.GetNftReconciler()
+
+	reconcile := func(contract entity.Contract) error {
+		_, err := reconciler.Reconcile(ctx, contract, c.Bool("repair"))
+		if err == indexer.ErrNotNftContract {
+			return nil
+		}
+		elastic.Persist()
+
+		return err
+	}
+
+	if contractAddr := c.String("contract"); contractAddr != "" {
+		contract, err := contractRepo.GetContractByAddress(ctx, contractAddr)
+		if err != nil {
+			zap.S().Errorf("Failed to find contract: %s", contractAddr)
+			return err
+		}
+
+		return reconcile(*contract)
+	}
+
+	size := 100
+	page := 1
+	for {
+		contracts, _, err := contractRepo.GetAllNftContracts(ctx, size, page)
+		if err != nil {
+			zap.L().With(zap.Error(err)).Error("Failed to get contracts")
+			return err
+		}
+		if len(contracts) == 0 {
+			break
+		}
+		for _, contract := range contracts {
+			if err := reconcile(contract); err != nil {
+				if err == indexer.ErrIndexBehindChain {
+					return nil
+				}
+				zap.L().With(zap.Error(err), zap.String("contract", contract.Address)).Error("Failed to reconcile contract")
+			}
+		}
+		page++
+	}
+
+	return nil
+}
+
+func importGovernance(c *cli.Context) error {
+	ctx := c.Context
+
+	if contractAddr := c.String("contract"); contractAddr != "" {
+		contract, err := contractRepo.GetContractByAddress(ctx, contractAddr)
+		if err != nil {
+			zap.S().Errorf("Failed to find contract: %s", contractAddr)
+			return err
+		}
+
+		return zrc6Indexer.IndexGovernance(ctx, *contract)
+	}
+
+	size := 100
+	page := 1
+	for {
+		contracts, _, err := contractRepo.GetAllNftContracts(ctx, size, page)
+		if err != nil {
+			zap.L().With(zap.Error(err)).Error("Failed to get contracts")
+			return err
+		}
+		if len(contracts) == 0 {
+			break
+		}
+		for _, contract := range contracts {
+			if err := zrc6Indexer.IndexGovernance(ctx, contract); err != nil {
+				zap.L().With(zap.Error(err), zap.String("contract", contract.Address)).Error("Failed to import governance history")
+				return err
+			}
+		}
+		page++
+	}
+
+	return nil
+}
+
+func importMarketplaceSales(ctx context.Context) {
 	page := 1
 	size := 100
 	for {
-		txs, _, err := txRepo.GetNftMarketplaceExecutionTxs(0, size, page)
+		txs, _, err := txRepo.GetNftMarketplaceExecutionTxs(ctx, 0, size, page)
 		if err != nil {
 			break
 		}
@@ -223,18 +404,18 @@ func importMarketplaceSales() {
 		if len(txs) == 0 {
 			break
 		}
-		_ = marketplaceIndexer.IndexTxs(txs)
+		_ = marketplaceIndexer.IndexTxs(ctx, txs)
 		elastic.BatchPersist()
 		page++
 	}
 	elastic.Persist()
 }
 
-func importMarketplaceSalesForContract(c entity.Contract) {
+func importMarketplaceSalesForContract(ctx context.Context, c entity.Contract) {
 	page := 1
 	size := 100
 	for {
-		txs, _, err := txRepo.GetContractExecutionsByContract(c, size, page)
+		txs, _, err := txRepo.GetContractExecutionsByContract(ctx, c, size, page)
 		if err != nil {
 			break
 		}
@@ -242,7 +423,7 @@ func importMarketplaceSalesForContract(c entity.Contract) {
 		if len(txs) == 0 {
 			break
 		}
-		_ = marketplaceIndexer.IndexTxs(txs)
+		_ = marketplaceIndexer.IndexTxs(ctx, txs)
 		elastic.BatchPersist()
 		page++
 	}
@@ -251,10 +432,12 @@ func importMarketplaceSalesForContract(c entity.Contract) {
 
 // MARKETPLACE
 func processMarketplaceActions(c *cli.Context) error {
+	ctx := c.Context
+
 	page := 1
 	size := 100
 	for {
-		txs, _, err := container.GetTxRepo().GetNftMarketplaceExecutionTxs(0, size, page)
+		txs, _, err := container.GetTxRepo().GetNftMarketplaceExecutionTxs(ctx, 0, size, page)
 		if err != nil {
 			return err
 		}
@@ -262,7 +445,7 @@ func processMarketplaceActions(c *cli.Context) error {
 		if len(txs) == 0 {
 			break
 		}
-		marketplaceIndexer.IndexTxs(txs)
+		marketplaceIndexer.IndexTxs(ctx, txs)
 		elastic.BatchPersist()
 		page++
 	}
@@ -270,3 +453,160 @@ func processMarketplaceActions(c *cli.Context) error {
 
 	return nil
 }
+
+// MIGRATIONS
+func migrationStatus(c *cli.Context) error {
+	migrations, err := elastic.GetMigrations()
+	if err != nil {
+		zap.L().With(zap.Error(err)).Error("Failed to get migrations")
+		return err
+	}
+
+	for _, migration := range migrations {
+		status := "pending"
+		if migration.AppliedAt != nil {
+			status = "applied " + migration.AppliedAt.Format(time.RFC3339)
+		}
+		zap.S().Infof("%04d %-40s %-16s %-16s %s", migration.Version, migration.Name, migration.Index, migration.Type, status)
+	}
+
+	return nil
+}
+
+func migrateUp(c *cli.Context) error {
+	if err := elastic.Migrate(); err != nil {
+		zap.L().With(zap.Error(err)).Error("Failed to apply migrations")
+		return err
+	}
+	zap.L().Info("Migrations applied")
+
+	return nil
+}
+
+// DEAD LETTERS
+func listDeadLetters(c *cli.Context) error {
+	ctx := c.Context
+
+	if id := c.String("id"); id != "" {
+		deadLetter, err := deadLetterRepo.GetDeadLetter(ctx, id)
+		if err != nil {
+			zap.L().With(zap.Error(err), zap.String("id", id)).Error("Failed to get dead letter")
+			return err
+		}
+
+		document, err := json.MarshalIndent(deadLetter, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(document))
+
+		return nil
+	}
+
+	deadLetters, total, err := deadLetterRepo.GetDeadLetters(ctx, c.Int("size"), c.Int("page"))
+	if err != nil {
+		zap.L().With(zap.Error(err)).Error("Failed to get dead letters")
+		return err
+	}
+
+	zap.S().Infof("Found %d dead letters", total)
+	for _, deadLetter := range deadLetters {
+		zap.L().With(
+			zap.String("id", deadLetter.Slug()),
+			zap.String("index", deadLetter.Index),
+			zap.String("docId", deadLetter.DocId),
+			zap.Uint64("blockNum", deadLetter.BlockNum),
+			zap.String("errorType", deadLetter.ErrorType),
+			zap.String("reason", deadLetter.Reason),
+		).Info("Dead letter")
+	}
+
+	return nil
+}
+
+func fixDeadLetter(c *cli.Context) error {
+	ctx := c.Context
+
+	deadLetter, err := deadLetterRepo.GetDeadLetter(ctx, c.String("id"))
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.String("id", c.String("id"))).Error("Failed to get dead letter")
+		return err
+	}
+
+	if document := c.String("document"); document != "" {
+		if !json.Valid([]byte(document)) {
+			return errors.New("document is not valid json")
+		}
+		deadLetter.Document = json.RawMessage(document)
+	}
+
+	if len(c.StringSlice("remove")) != 0 {
+		var document map[string]interface{}
+		if err := json.Unmarshal(deadLetter.Document, &document); err != nil {
+			return err
+		}
+
+		for _, path := range c.StringSlice("remove") {
+			removeField(document, strings.Split(path, "."))
+		}
+
+		if deadLetter.Document, err = json.Marshal(document); err != nil {
+			return err
+		}
+	}
+
+	elastic.Save(elastic_search.DeadLetterIndex.Get(), *deadLetter)
+	zap.L().With(zap.String("id", deadLetter.Slug())).Info("Dead letter fixed")
+
+	return nil
+}
+
+func removeField(document map[string]interface{}, path []string) {
+	if len(path) == 1 {
+		delete(document, path[0])
+		return
+	}
+
+	if child, ok := document[path[0]].(map[string]interface{}); ok {
+		removeField(child, path[1:])
+	}
+}
+
+func replayDeadLetters(c *cli.Context) error {
+	ctx := c.Context
+
+	deadLetters := make([]entity.DeadLetter, 0)
+
+	if id := c.String("id"); id != "" {
+		deadLetter, err := deadLetterRepo.GetDeadLetter(ctx, id)
+		if err != nil {
+			zap.L().With(zap.Error(err), zap.String("id", id)).Error("Failed to get dead letter")
+			return err
+		}
+		deadLetters = append(deadLetters, *deadLetter)
+	} else {
+		size := 100
+		page := 1
+		for {
+			results, _, err := deadLetterRepo.GetDeadLetters(ctx, size, page)
+			if err != nil {
+				return err
+			}
+			if len(results) == 0 {
+				break
+			}
+			deadLetters = append(deadLetters, results...)
+			page++
+		}
+	}
+
+	for _, deadLetter := range deadLetters {
+		if err := deadLetterRepo.Replay(ctx, deadLetter); err != nil {
+			zap.L().With(zap.Error(err), zap.String("id", deadLetter.Slug())).Error("Failed to replay dead letter")
+			continue
+		}
+		zap.L().With(zap.String("id", deadLetter.Slug()), zap.String("index", deadLetter.Index)).Info("Dead letter replayed")
+	}
+
+	return nil
+}
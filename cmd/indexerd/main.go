@@ -1,9 +1,12 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
 	"github.com/ZilDuck/zilliqa-chain-indexer/generated/dic"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/config"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/shutdown"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 	"net/http"
@@ -12,14 +15,26 @@ import (
 var container *dic.Container
 
 func main() {
+	dryRun := flag.Bool("dry-run", false, "Index into memory without writing to storage")
+	flag.Parse()
+
 	config.Init("indexer")
+	if *dryRun {
+		config.EnableDryRun()
+		zap.L().Info("Dry run, nothing will be persisted")
+	}
 	container, _ = dic.NewContainer()
 
+	ctx, stop := shutdown.Notify()
+	defer stop()
+
 	go health()
 
 	zap.L().With(zap.String("port", config.Get().HealthPort)).Info("Indexer Started")
 
-	container.GetDaemon().Execute()
+	shutdown.Run(ctx, func() {
+		container.GetDaemon().Execute(ctx)
+	})
 }
 
 func health() {
@@ -35,5 +50,10 @@ func router() *mux.Router {
 		_, _ = fmt.Fprintf(w, "OK")
 	}).Methods("GET")
 
+	r.HandleFunc("/health/rpc", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(container.GetZilliqa().GetEndpointStats())
+	}).Methods("GET")
+
 	return r
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"github.com/ZilDuck/zilliqa-chain-indexer/generated/dic"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/config"
 	"go.uber.org/zap"
@@ -12,6 +13,7 @@ var container *dic.Container
 
 func main() {
 	initialize()
+	ctx := context.Background()
 
 	args := os.Args[1:]
 	blockNum, err := strconv.ParseInt(args[1], 10, 64)
@@ -19,7 +21,7 @@ func main() {
 		zap.L().With(zap.Error(err)).Fatal("Failed to get block num arg")
 	}
 
-	txs, err := container.GetTxIndexer().Index(uint64(blockNum), 1)
+	txs, err := container.GetTxIndexer().Index(ctx, uint64(blockNum), 1)
 	if err != nil {
 		zap.L().With(zap.Error(err)).Fatal("Failed to index")
 	}
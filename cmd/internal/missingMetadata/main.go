@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"github.com/ZilDuck/zilliqa-chain-indexer/generated/dic"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/config"
 	"go.uber.org/zap"
@@ -8,6 +9,7 @@ import (
 
 func main() {
 	config.Init("missingMetadata")
+	ctx := context.Background()
 
 	container, _ := dic.NewContainer()
 	metadataIndexer := container.GetMetadataIndexer()
@@ -16,7 +18,7 @@ func main() {
 	size := 100
 
 	for {
-		nfts, _, err := container.GetNftRepo().GetAllNfts(size, page)
+		nfts, _, err := container.GetNftRepo().GetAllNfts(ctx, size, page)
 		if err != nil {
 			zap.L().Fatal(err.Error())
 		}
@@ -31,7 +33,7 @@ func main() {
 			if nft.Metadata != nil {
 				if nft.Metadata.Status == "failure" {
 					zap.S().Infof("Refresh metadata for token %d", nft.TokenId)
-					metadataIndexer.RefreshMetadata(nft.Contract, nft.TokenId)
+					metadataIndexer.RefreshMetadata(ctx, nft.Contract, nft.TokenId)
 				}
 			}
 
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"github.com/ZilDuck/zilliqa-chain-indexer/generated/dic"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/config"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
@@ -11,13 +12,14 @@ import (
 
 func main() {
 	config.Init("missingTx")
+	ctx := context.Background()
 
 	container, _ := dic.NewContainer()
 
 	txRepo := container.GetTxRepo()
 	elastic := container.GetElastic()
 
-	bestBlock, _ := container.GetTxRepo().GetBestBlockNum()
+	bestBlock, _ := container.GetTxRepo().GetBestBlockNum(ctx)
 	zap.S().Infof("Transaction index best block: %d", bestBlock)
 
 
@@ -31,14 +33,14 @@ func main() {
 	var size uint64 = 100
 
 	for {
-		txs, _ := container.GetTxIndexer().CreateTransactions(from, size)
+		txs, _ := container.GetTxIndexer().CreateTransactions(ctx, from, size)
 		if len(txs) > 0 {
 			txIds := make([]string, len(txs))
 			for idx, tx := range txs {
 				txIds[idx] = tx.ID
 			}
 
-			missingTxs, err := txRepo.GetMissingTxs(txIds)
+			missingTxs, err := txRepo.GetMissingTxs(ctx, txIds)
 			if err != nil {
 				panic(err)
 			}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/ZilDuck/zilliqa-chain-indexer/generated/dic"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/config"
@@ -8,6 +9,7 @@ import (
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/indexer"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/messenger"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/shutdown"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"go.uber.org/zap"
 )
@@ -30,20 +32,32 @@ func main() {
 	contractRepo = container.GetContractRepo()
 	elastic = container.GetElastic()
 
+	ctx, stop := shutdown.Notify()
+	defer stop()
+
 	messages := make(chan *sqs.Message, 10)
 
 	go func() {
-		for {
-			msg := <-messages
-			refreshMetadata(msg)
-		}
+		defer close(messages)
+		zap.L().Info("Subscribing to metadata refresh")
+		messageService.PollMessages(ctx, messenger.MetadataRefresh, messages)
 	}()
 
-	zap.L().Info("Subscribing to metadata refresh")
-	messageService.PollMessages(messenger.MetadataRefresh, messages)
+	shutdown.Run(ctx, func() {
+		for msg := range messages {
+			// Messages received but not yet handled are left on the queue for the next consumer
+			if ctx.Err() != nil {
+				return
+			}
+
+			work, cancel := shutdown.Grace(ctx)
+			refreshMetadata(work, msg)
+			cancel()
+		}
+	})
 }
 
-func refreshMetadata(msg *sqs.Message) {
+func refreshMetadata(ctx context.Context, msg *sqs.Message) {
 	defer messageService.DeleteMessage(messenger.MetadataRefresh, msg)
 
 	var data messenger.Nft
@@ -53,7 +67,7 @@ func refreshMetadata(msg *sqs.Message) {
 	}
 
 	if data.TokenId == 0 {
-		contract, err := contractRepo.GetContractByAddress(data.Contract)
+		contract, err := contractRepo.GetContractByAddress(ctx, data.Contract)
 		if err != nil {
 			zap.L().With(zap.String("contract", data.Contract), zap.Uint64("tokenId", data.TokenId), zap.Error(err)).Error("Contract Metadata refresh failed")
 		} else {
@@ -64,7 +78,7 @@ func refreshMetadata(msg *sqs.Message) {
 		return
 	}
 
-	_, err := metadataIndexer.RefreshMetadata(data.Contract, data.TokenId)
+	_, err := metadataIndexer.RefreshMetadata(ctx, data.Contract, data.TokenId)
 	if err != nil {
 		zap.L().With(zap.String("contract", data.Contract), zap.Uint64("tokenId", data.TokenId), zap.Error(err)).Error("Metadata refresh failed")
 	} else {
@@ -0,0 +1,83 @@
+-- Every entity is stored as its json document, with the fields that are queried on
+-- generated from it so they can be indexed and constrained.
+
+CREATE TABLE transactions (
+    id                 text PRIMARY KEY,
+    doc                jsonb NOT NULL,
+    tx_id              text GENERATED ALWAYS AS (doc->>'ID') STORED,
+    block_num          bigint GENERATED ALWAYS AS ((doc->>'BlockNum')::bigint) STORED,
+    contract_address   text GENERATED ALWAYS AS (doc->>'ContractAddress') STORED,
+    contract_creation  boolean GENERATED ALWAYS AS ((doc->>'ContractCreation')::boolean) STORED,
+    contract_execution boolean GENERATED ALWAYS AS ((doc->>'ContractExecution')::boolean) STORED
+);
+CREATE INDEX transactions_tx_id_idx ON transactions (tx_id);
+CREATE INDEX transactions_block_num_idx ON transactions (block_num);
+CREATE INDEX transactions_contract_address_idx ON transactions (contract_address, block_num);
+CREATE INDEX transactions_event_logs_idx ON transactions USING gin ((doc->'Receipt'->'event_logs') jsonb_path_ops);
+
+CREATE TABLE contracts (
+    id        text PRIMARY KEY,
+    doc       jsonb NOT NULL,
+    address   text GENERATED ALWAYS AS (doc->>'address') STORED,
+    block_num bigint GENERATED ALWAYS AS ((doc->>'blockNum')::bigint) STORED
+);
+CREATE INDEX contracts_address_idx ON contracts (address);
+CREATE INDEX contracts_block_num_idx ON contracts (block_num);
+
+CREATE TABLE contract_states (
+    id      text PRIMARY KEY,
+    doc     jsonb NOT NULL,
+    address text GENERATED ALWAYS AS (doc->>'address') STORED
+);
+CREATE INDEX contract_states_address_idx ON contract_states (address);
+
+CREATE TABLE contract_metadata (
+    id       text PRIMARY KEY,
+    doc      jsonb NOT NULL,
+    contract text GENERATED ALWAYS AS (doc->>'contract') STORED
+);
+CREATE INDEX contract_metadata_contract_idx ON contract_metadata (lower(contract));
+
+CREATE TABLE nfts (
+    id        text PRIMARY KEY,
+    doc       jsonb NOT NULL,
+    contract  text GENERATED ALWAYS AS (doc->>'contract') STORED,
+    token_id  numeric(20) GENERATED ALWAYS AS ((doc->>'tokenId')::numeric) STORED,
+    block_num bigint GENERATED ALWAYS AS ((doc->>'blockNum')::bigint) STORED,
+    owner     text GENERATED ALWAYS AS (doc->>'owner') STORED,
+    UNIQUE (contract, token_id)
+);
+CREATE INDEX nfts_contract_lower_idx ON nfts (lower(contract), token_id);
+CREATE INDEX nfts_block_num_idx ON nfts (block_num);
+CREATE INDEX nfts_owner_idx ON nfts (owner);
+CREATE INDEX nfts_metadata_status_idx ON nfts ((doc->'metadata'->>'status'));
+
+-- Actions are removed with their nft. The check is deferred so an nft and its mint action can be
+-- written in either order within a transaction.
+CREATE TABLE nft_actions (
+    id        text PRIMARY KEY,
+    doc       jsonb NOT NULL,
+    contract  text GENERATED ALWAYS AS (doc->>'contract') STORED,
+    token_id  numeric(20) GENERATED ALWAYS AS ((doc->>'tokenId')::numeric) STORED,
+    block_num bigint GENERATED ALWAYS AS ((doc->>'blockNum')::bigint) STORED,
+    action    text GENERATED ALWAYS AS (doc->>'action') STORED,
+    tx_id     text GENERATED ALWAYS AS (doc->>'txId') STORED,
+    FOREIGN KEY (contract, token_id) REFERENCES nfts (contract, token_id) ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED
+);
+CREATE INDEX nft_actions_nft_idx ON nft_actions (contract, token_id, block_num);
+CREATE INDEX nft_actions_block_num_idx ON nft_actions (block_num);
+CREATE INDEX nft_actions_tx_id_idx ON nft_actions (tx_id);
+
+CREATE TABLE checkpoints (
+    id    text PRIMARY KEY,
+    doc   jsonb NOT NULL,
+    stage text GENERATED ALWAYS AS (doc->>'stage') STORED
+);
+CREATE INDEX checkpoints_stage_idx ON checkpoints (stage);
+
+CREATE TABLE dead_letters (
+    id         text PRIMARY KEY,
+    doc        jsonb NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX dead_letters_created_at_idx ON dead_letters (created_at);
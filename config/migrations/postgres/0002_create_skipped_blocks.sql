@@ -0,0 +1,6 @@
+CREATE TABLE skipped_blocks (
+    id        text PRIMARY KEY,
+    doc       jsonb NOT NULL,
+    block_num bigint GENERATED ALWAYS AS ((doc->>'blockNum')::bigint) STORED
+);
+CREATE INDEX skipped_blocks_block_num_idx ON skipped_blocks (block_num);
@@ -0,0 +1,15 @@
+CREATE TABLE blocks (
+    id           text PRIMARY KEY,
+    doc          jsonb NOT NULL,
+    block_num    bigint GENERATED ALWAYS AS ((doc->>'blockNum')::bigint) STORED,
+    ds_block_num bigint GENERATED ALWAYS AS ((doc->>'dsBlockNum')::bigint) STORED
+);
+CREATE INDEX blocks_block_num_idx ON blocks (block_num);
+CREATE INDEX blocks_ds_block_num_idx ON blocks (ds_block_num);
+
+CREATE TABLE ds_blocks (
+    id        text PRIMARY KEY,
+    doc       jsonb NOT NULL,
+    block_num bigint GENERATED ALWAYS AS ((doc->>'blockNum')::bigint) STORED
+);
+CREATE INDEX ds_blocks_block_num_idx ON ds_blocks (block_num);
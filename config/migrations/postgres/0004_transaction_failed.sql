@@ -0,0 +1,7 @@
+-- Failed transactions are kept, transactions indexed before then have no Failed field.
+
+ALTER TABLE transactions
+    ADD COLUMN failed      boolean GENERATED ALWAYS AS (coalesce((doc->>'Failed')::boolean, false)) STORED,
+    ADD COLUMN sender_addr text GENERATED ALWAYS AS (doc->>'SenderAddr') STORED;
+CREATE INDEX transactions_failed_contract_address_idx ON transactions (contract_address, block_num) WHERE failed;
+CREATE INDEX transactions_failed_sender_addr_idx ON transactions (sender_addr, block_num) WHERE failed;
@@ -0,0 +1,12 @@
+-- reconciledAt is always written in UTC, so its text sorts in time order to the second. A timestamptz column
+-- cannot be generated from it, the cast is not immutable.
+
+CREATE TABLE addresses (
+    id            text PRIMARY KEY,
+    doc           jsonb NOT NULL,
+    address       text GENERATED ALWAYS AS (doc->>'address') STORED,
+    block_num     bigint GENERATED ALWAYS AS ((doc->>'blockNum')::bigint) STORED,
+    reconciled_at text GENERATED ALWAYS AS (doc->>'reconciledAt') STORED
+);
+CREATE INDEX addresses_address_idx ON addresses (address);
+CREATE INDEX addresses_reconciled_at_idx ON addresses (reconciled_at);
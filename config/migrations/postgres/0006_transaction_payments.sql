@@ -0,0 +1,7 @@
+-- Payments are indexed from now on, transactions indexed before then have no Payment field.
+
+ALTER TABLE transactions
+    ADD COLUMN payment        boolean GENERATED ALWAYS AS (coalesce((doc->>'Payment')::boolean, false)) STORED,
+    ADD COLUMN recipient_addr text GENERATED ALWAYS AS (doc->>'RecipientAddr') STORED;
+CREATE INDEX transactions_payment_sender_addr_idx ON transactions (sender_addr, block_num) WHERE payment;
+CREATE INDEX transactions_payment_recipient_addr_idx ON transactions (recipient_addr, block_num) WHERE payment;
@@ -0,0 +1,22 @@
+CREATE TABLE token_transfers (
+    id         text PRIMARY KEY,
+    doc        jsonb NOT NULL,
+    contract   text GENERATED ALWAYS AS (doc->>'contract') STORED,
+    block_num  bigint GENERATED ALWAYS AS ((doc->>'blockNum')::bigint) STORED,
+    from_addr  text GENERATED ALWAYS AS (doc->>'from') STORED,
+    to_addr    text GENERATED ALWAYS AS (doc->>'to') STORED
+);
+CREATE INDEX token_transfers_contract_idx ON token_transfers (contract, block_num);
+CREATE INDEX token_transfers_block_num_idx ON token_transfers (block_num);
+CREATE INDEX token_transfers_from_addr_idx ON token_transfers (from_addr, block_num);
+CREATE INDEX token_transfers_to_addr_idx ON token_transfers (to_addr, block_num);
+
+CREATE TABLE token_balances (
+    id       text PRIMARY KEY,
+    doc      jsonb NOT NULL,
+    contract text GENERATED ALWAYS AS (doc->>'contract') STORED,
+    holder   text GENERATED ALWAYS AS (doc->>'holder') STORED,
+    balance  text GENERATED ALWAYS AS (doc->>'balance') STORED
+);
+CREATE INDEX token_balances_contract_idx ON token_balances (contract, holder);
+CREATE INDEX token_balances_holder_idx ON token_balances (holder, contract);
@@ -0,0 +1,10 @@
+CREATE TABLE operators (
+    id       text PRIMARY KEY,
+    doc      jsonb NOT NULL,
+    contract text GENERATED ALWAYS AS (doc->>'contract') STORED,
+    owner    text GENERATED ALWAYS AS (doc->>'owner') STORED,
+    active   boolean GENERATED ALWAYS AS ((doc->>'active')::boolean) STORED
+);
+CREATE INDEX operators_owner_idx ON operators (owner, contract) WHERE active;
+
+CREATE INDEX nfts_spender_idx ON nfts (owner) WHERE coalesce(doc->>'spender', '') <> '';
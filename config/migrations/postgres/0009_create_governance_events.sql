@@ -0,0 +1,10 @@
+CREATE TABLE governance_events (
+    id          text PRIMARY KEY,
+    doc         jsonb NOT NULL,
+    contract    text GENERATED ALWAYS AS (doc->>'contract') STORED,
+    action      text GENERATED ALWAYS AS (doc->>'action') STORED,
+    block_num   bigint GENERATED ALWAYS AS ((doc->>'blockNum')::bigint) STORED,
+    event_index integer GENERATED ALWAYS AS ((doc->>'eventIndex')::integer) STORED
+);
+CREATE INDEX governance_events_contract_idx ON governance_events (contract, action, block_num);
+CREATE INDEX governance_events_block_num_idx ON governance_events (block_num);
@@ -0,0 +1,8 @@
+CREATE TABLE nft_discrepancies (
+    id       text PRIMARY KEY,
+    doc      jsonb NOT NULL,
+    contract text GENERATED ALWAYS AS (doc->>'contract') STORED,
+    token_id numeric(20) GENERATED ALWAYS AS ((doc->>'tokenId')::numeric) STORED,
+    status   text GENERATED ALWAYS AS (doc->>'status') STORED
+);
+CREATE INDEX nft_discrepancies_contract_idx ON nft_discrepancies (contract, status, token_id);
@@ -0,0 +1,14 @@
+-- Error code names were read one code out, from before NOT_PRESENT was listed as code 0
+UPDATE transactions SET doc = jsonb_set(doc, '{Receipt,error_codes}', (
+    SELECT jsonb_agg(e || jsonb_build_object('name', coalesce(
+        (ARRAY['NOT_PRESENT', 'CHECKER_FAILED', 'RUNNER_FAILED', 'BALANCE_TRANSFER_FAILED',
+        'EXECUTE_CMD_FAILED', 'EXECUTE_CMD_TIMEOUT', 'NO_GAS_REMAINING_FOUND', 'NO_ACCEPTED_FOUND',
+        'CALL_CONTRACT_FAILED', 'CREATE_CONTRACT_FAILED', 'JSON_OUTPUT_CORRUPTED', 'CONTRACT_NOT_EXIST',
+        'STATE_CORRUPTED', 'LOG_ENTRY_INSTALL_FAILED', 'MESSAGE_CORRUPTED', 'RECEIPT_IS_NULL',
+        'MAX_EDGES_REACHED', 'CHAIN_CALL_DIFF_SHARD', 'PREPARATION_FAILED', 'NO_OUTPUT',
+        'OUTPUT_ILLEGAL', 'MAP_DEPTH_MISSING', 'GAS_NOT_SUFFICIENT', 'INTERNAL_ERROR',
+        'LIBRARY_AS_RECIPIENT', 'VERSION_INCONSISTENT', 'LIBRARY_EXTRACTION_FAILED'])[(e->>'code')::int + 1],
+        'UNKNOWN_' || (e->>'code'))) ORDER BY ord)
+    FROM jsonb_array_elements(doc->'Receipt'->'error_codes') WITH ORDINALITY AS codes(e, ord)
+))
+WHERE failed AND jsonb_typeof(doc->'Receipt'->'error_codes') = 'array' AND jsonb_array_length(doc->'Receipt'->'error_codes') > 0;
@@ -9,8 +9,10 @@ require (
 	github.com/coreos/etcd v3.3.10+incompatible // indirect
 	github.com/coreos/go-etcd v2.0.0+incompatible // indirect
 	github.com/gorilla/mux v1.8.0
+	github.com/gorilla/websocket v1.4.1
 	github.com/gosimple/slug v1.12.0
 	github.com/hashicorp/go-retryablehttp v0.7.1
+	github.com/lib/pq v1.10.9
 	github.com/mattn/go-colorable v0.1.12
 	github.com/nu7hatch/gouuid v0.0.0-20131221200532-179d4d0c4d8d
 	github.com/olivere/elastic/v7 v7.0.32
@@ -45,7 +45,7 @@ func (s Server) handleGetAsset(w http.ResponseWriter, r *http.Request) {
 	contractAddr, _ := mux.Vars(r)["contractAddr"]
 	tokenId, _ := getTokenId(r)
 
-	nft, err := s.nftRepo.GetNft(contractAddr, tokenId)
+	nft, err := s.nftRepo.GetNft(r.Context(), contractAddr, tokenId)
 	if err != nil {
 		zap.L().With(zap.Error(err)).Warn("NFT not available")
 
@@ -95,7 +95,7 @@ func (s Server) handleGetAsset(w http.ResponseWriter, r *http.Request) {
 func (s Server) handleGetContract(w http.ResponseWriter, r *http.Request) {
 	contractAddr, _ := mux.Vars(r)["contractAddr"]
 
-	md, err := s.contractMetadataRepo.GetMetadataByAddress(contractAddr)
+	md, err := s.contractMetadataRepo.GetMetadataByAddress(r.Context(), contractAddr)
 	if err != nil {
 		zap.L().With(zap.Error(err)).Warn("Metadata not available")
 
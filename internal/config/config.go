@@ -23,6 +23,8 @@ type Config struct {
 	BulkIndex struct {
 		Active             bool
 		Size               uint64
+		Workers            int
+		MaxPendingBatches  int
 		IndexContractsFrom *uint64
 		IndexNftsFrom      *uint64
 	}
@@ -36,6 +38,7 @@ type Config struct {
 	}
 	EventsSupported bool
 
+	ContractShapeDir         string
 	AdditionalZrc1           []string
 	AdditionalZrc6           []string
 	ContractsWithoutMetadata map[string]string
@@ -43,10 +46,41 @@ type Config struct {
 	AssetPort  string
 	HealthPort string
 
+	ShutdownTimeout int
+
+	Quarantine struct {
+		Retries        int
+		RepairInterval int
+	}
+
+	Addresses struct {
+		ReconcileInterval int
+		ReconcileSize     int
+		ReconcileAge      int
+	}
+
+	Nfts struct {
+		ReconcileMaxLag uint64
+	}
+
 	Zilliqa struct {
-		Url     string
-		Debug   bool
-		Timeout int
+		Url                 string
+		Urls                []string
+		Debug               bool
+		Timeout             int
+		MaxBlockLag         uint64
+		HealthCheckInterval int
+		WebsocketUrl        string
+		PollInterval        int
+		ReconnectMaxBackoff int
+		SkipBlocks          []uint64
+	}
+	Storage struct {
+		Driver   string
+		Postgres struct {
+			Dsn          string
+			MigrationDir string
+		}
 	}
 	ElasticSearch struct {
 		Hosts            []string
@@ -56,7 +90,10 @@ type Config struct {
 		Username         string
 		Password         string
 		MappingDir       string
+		MigrationDir     string
 		BulkPersistCount int
+		BufferSizeMb     int
+		RetainVersions   int
 		Refresh          string
 	}
 	Aws struct {
@@ -90,6 +127,11 @@ func Init(command string) {
 	log.NewLogger(config.Debug, fmt.Sprintf("%s/%s.log",config.LogPath, command))
 }
 
+// EnableDryRun stores everything in memory, so indexing leaves the configured storage untouched
+func EnableDryRun() {
+	config.Storage.Driver = "memory"
+}
+
 func Get() Config {
 	return config
 }
@@ -1,16 +1,24 @@
 package di
 
 import (
+	"context"
 	"crypto/tls"
+	"database/sql"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/bunny"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/config"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/daemon"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/factory"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/handler"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/indexer"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/memory"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/messenger"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/metadata"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/postgres"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/rollback"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/shutdown"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/zilliqa"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
@@ -28,6 +36,24 @@ var Definitions = []dingo.Def{
 	{
 		Name: "elastic",
 		Build: func() (elastic_search.Index, error) {
+			if config.Get().Storage.Driver == "memory" {
+				return memory.New(), nil
+			}
+
+			if config.Get().Storage.Driver == "postgres" {
+				db, err := sql.Open("postgres", config.Get().Storage.Postgres.Dsn)
+				if err != nil {
+					zap.L().With(zap.Error(err)).Fatal("Failed to open postgres")
+				}
+
+				index, err := postgres.New(db)
+				if err != nil {
+					zap.L().With(zap.Error(err)).Fatal("Failed to start postgres")
+				}
+
+				return index, nil
+			}
+
 			elastic, err := elastic_search.New()
 			if err != nil {
 				zap.L().With(zap.Error(err)).Fatal("Failed to start ES")
@@ -42,19 +68,46 @@ var Definitions = []dingo.Def{
 			return cache.New(5*time.Minute, 10*time.Minute), nil
 		},
 	},
+	{
+		// Cancelled when the process is asked to stop, for the background work of the services
+		Name: "shutdown.context",
+		Build: func() (context.Context, error) {
+			ctx, _ := shutdown.Notify()
+			return ctx, nil
+		},
+	},
 	{
 		Name: "zilliqa",
-		Build: func() (zilliqa.Service, error) {
+		Build: func(ctx context.Context) (zilliqa.Service, error) {
+			urls := config.Get().Zilliqa.Urls
+			if len(urls) == 0 {
+				urls = []string{config.Get().Zilliqa.Url}
+			}
+
 			rpcClient, err := zilliqa.NewClient(
-				config.Get().Zilliqa.Url,
+				ctx,
+				urls,
 				config.Get().Zilliqa.Timeout,
+				config.Get().Zilliqa.MaxBlockLag,
+				config.Get().Zilliqa.HealthCheckInterval,
 				config.Get().Zilliqa.Debug,
 			)
 			if err != nil {
 				return nil, err
 			}
 
-			return zilliqa.NewZilliqaService(zilliqa.NewProvider(rpcClient)), nil
+			return zilliqa.NewZilliqaService(zilliqa.NewProvider(rpcClient), config.Get().Zilliqa.SkipBlocks), nil
+		},
+	},
+	{
+		Name: "zilliqa.subscriber",
+		Build: func(zilliqaService zilliqa.Service) (zilliqa.Subscriber, error) {
+			return zilliqa.NewSubscriber(
+				config.Get().Zilliqa.WebsocketUrl,
+				zilliqaService,
+				time.Duration(config.Get().Zilliqa.PollInterval)*time.Second,
+				time.Duration(config.Get().Zilliqa.ReconnectMaxBackoff)*time.Second,
+			), nil
 		},
 	},
 	{
@@ -80,16 +133,21 @@ var Definitions = []dingo.Def{
 			elastic elastic_search.Index,
 			indexer indexer.Indexer,
 			zilliqa zilliqa.Service,
+			subscriber zilliqa.Subscriber,
 			txRepo repository.TransactionRepository,
 			nftRepo repository.NftRepository,
 			contractRepo repository.ContractRepository,
+			checkpointRepo repository.CheckpointRepository,
 			contractIndexer indexer.ContractIndexer,
 			zrc1Indexer indexer.Zrc1Indexer,
 			zrc6Indexer indexer.Zrc6Indexer,
+			zrc2Indexer indexer.Zrc2Indexer,
 			marketplaceIndexer indexer.MarketplaceIndexer,
 			metadataIndexer indexer.MetadataIndexer,
+			addressIndexer indexer.AddressIndexer,
+			rollbackService rollback.Service,
 		) (*daemon.Daemon, error) {
-			return daemon.NewDaemon(elastic, config.Get().FirstBlockNum, indexer, zilliqa, txRepo, nftRepo, contractRepo, contractIndexer, zrc1Indexer, zrc6Indexer, marketplaceIndexer, metadataIndexer), nil
+			return daemon.NewDaemon(elastic, config.Get().FirstBlockNum, indexer, zilliqa, subscriber, txRepo, nftRepo, contractRepo, checkpointRepo, contractIndexer, zrc1Indexer, zrc6Indexer, zrc2Indexer, marketplaceIndexer, metadataIndexer, addressIndexer, rollbackService), nil
 		},
 	},
 	{
@@ -100,11 +158,32 @@ var Definitions = []dingo.Def{
 			contractIndexer indexer.ContractIndexer,
 			zrc1Indexer indexer.Zrc1Indexer,
 			zrc6Indexer indexer.Zrc6Indexer,
+			zrc2Indexer indexer.Zrc2Indexer,
 			marketplaceIndexer indexer.MarketplaceIndexer,
+			addressIndexer indexer.AddressIndexer,
 			txRepo repository.TransactionRepository,
+			checkpointRepo repository.CheckpointRepository,
+			skippedBlockRepo repository.SkippedBlockRepository,
 			cache *cache.Cache,
 		) (indexer.Indexer, error) {
-			return indexer.NewIndexer(config.Get().BulkIndex.Size, elastic, txIndexer, contractIndexer, zrc1Indexer, zrc6Indexer, marketplaceIndexer, txRepo, cache), nil
+			return indexer.NewIndexer(
+				config.Get().BulkIndex.Size,
+				config.Get().BulkIndex.Workers,
+				config.Get().BulkIndex.MaxPendingBatches,
+				config.Get().Quarantine.Retries,
+				elastic,
+				txIndexer,
+				contractIndexer,
+				zrc1Indexer,
+				zrc6Indexer,
+				zrc2Indexer,
+				marketplaceIndexer,
+				addressIndexer,
+				txRepo,
+				checkpointRepo,
+				skippedBlockRepo,
+				cache,
+			), nil
 		},
 	},
 	{
@@ -114,8 +193,9 @@ var Definitions = []dingo.Def{
 			elastic elastic_search.Index,
 			transactionFactory factory.TransactionFactory,
 			txRepo repository.TransactionRepository,
+			cache *cache.Cache,
 		) (indexer.TransactionIndexer, error) {
-			return indexer.NewTransactionIndexer(zilliqa, elastic, transactionFactory, txRepo), nil
+			return indexer.NewTransactionIndexer(zilliqa, elastic, transactionFactory, txRepo, cache), nil
 		},
 	},
 	{
@@ -137,12 +217,12 @@ var Definitions = []dingo.Def{
 		Build: func(
 			elastic elastic_search.Index,
 			contractRepo repository.ContractRepository,
-			contractStateRepo repository.ContractStateRepository,
 			nftRepo repository.NftRepository,
 			txRepo repository.TransactionRepository,
 			factory factory.Zrc1Factory,
+			handlers handler.Registry,
 		) (indexer.Zrc1Indexer, error) {
-			return indexer.NewZrc1Indexer(elastic, contractRepo, contractStateRepo, nftRepo, txRepo, factory), nil
+			return indexer.NewZrc1Indexer(elastic, contractRepo, nftRepo, txRepo, factory, handlers), nil
 		},
 	},
 	{
@@ -152,9 +232,23 @@ var Definitions = []dingo.Def{
 			contractRepo repository.ContractRepository,
 			nftRepo repository.NftRepository,
 			txRepo repository.TransactionRepository,
+			operatorRepo repository.OperatorRepository,
 			factory factory.Zrc6Factory,
+			handlers handler.Registry,
 		) (indexer.Zrc6Indexer, error) {
-			return indexer.NewZrc6Indexer(elastic, contractRepo, nftRepo, txRepo, factory), nil
+			return indexer.NewZrc6Indexer(elastic, contractRepo, nftRepo, txRepo, operatorRepo, factory, handlers), nil
+		},
+	},
+	{
+		Name: "zrc2.indexer",
+		Build: func(
+			elastic elastic_search.Index,
+			contractRepo repository.ContractRepository,
+			tokenTransferRepo repository.TokenTransferRepository,
+			tokenBalanceRepo repository.TokenBalanceRepository,
+			factory factory.Zrc2Factory,
+		) (indexer.Zrc2Indexer, error) {
+			return indexer.NewZrc2Indexer(elastic, contractRepo, tokenTransferRepo, tokenBalanceRepo, factory), nil
 		},
 	},
 	{
@@ -165,8 +259,9 @@ var Definitions = []dingo.Def{
 			contractRepo repository.ContractRepository,
 			messageService messenger.MessageService,
 			metadataService metadata.Service,
+			handlers handler.Registry,
 		) (indexer.MetadataIndexer, error) {
-			return indexer.NewMetadataIndexer(elastic, nftRepo, contractRepo, messageService, metadataService), nil
+			return indexer.NewMetadataIndexer(elastic, nftRepo, contractRepo, messageService, metadataService, handlers), nil
 		},
 	},
 	{
@@ -184,40 +279,245 @@ var Definitions = []dingo.Def{
 			return indexer.NewMarketplaceIndexer(elastic, nftRepo, contractRepo, contractStateRepo, zilkroadMarketplaceFactory, okimotoMarketplaceFactory, arkyMarketplaceFactory, mintableMarketplaceFactory), nil
 		},
 	},
+	{
+		Name: "address.indexer",
+		Build: func(
+			elastic elastic_search.Index,
+			zilliqa zilliqa.Service,
+			addressRepo repository.AddressRepository,
+			txRepo repository.TransactionRepository,
+		) (indexer.AddressIndexer, error) {
+			return indexer.NewAddressIndexer(
+				elastic,
+				zilliqa,
+				addressRepo,
+				txRepo,
+				config.Get().Addresses.ReconcileSize,
+				time.Duration(config.Get().Addresses.ReconcileAge)*time.Second,
+			), nil
+		},
+	},
 	{
 		Name: "tx.repo",
 		Build: func(elastic elastic_search.Index) (repository.TransactionRepository, error) {
-			return repository.NewTransactionRepository(elastic), nil
+			switch index := elastic.(type) {
+			case postgres.Index:
+				return postgres.NewTransactionRepository(index), nil
+			case memory.Index:
+				return memory.NewTransactionRepository(index), nil
+			}
+			return repository.NewTransactionRepository(elastic.(elastic_search.ElasticIndex)), nil
 		},
 	},
 	{
 		Name: "contract.repo",
 		Build: func(elastic elastic_search.Index) (repository.ContractRepository, error) {
-			return repository.NewContractRepository(elastic), nil
+			switch index := elastic.(type) {
+			case postgres.Index:
+				return postgres.NewContractRepository(index), nil
+			case memory.Index:
+				return memory.NewContractRepository(index), nil
+			}
+			return repository.NewContractRepository(elastic.(elastic_search.ElasticIndex)), nil
 		},
 	},
 	{
 		Name: "contractMetadata.repo",
 		Build: func(elastic elastic_search.Index) (repository.ContractMetadataRepository, error) {
-			return repository.NewContractMetadataRepository(elastic), nil
+			switch index := elastic.(type) {
+			case postgres.Index:
+				return postgres.NewContractMetadataRepository(index), nil
+			case memory.Index:
+				return memory.NewContractMetadataRepository(index), nil
+			}
+			return repository.NewContractMetadataRepository(elastic.(elastic_search.ElasticIndex)), nil
 		},
 	},
 	{
 		Name: "contractState.repo",
 		Build: func(elastic elastic_search.Index) (repository.ContractStateRepository, error) {
-			return repository.NewContractStateRepository(elastic), nil
+			switch index := elastic.(type) {
+			case postgres.Index:
+				return postgres.NewContractStateRepository(index), nil
+			case memory.Index:
+				return memory.NewContractStateRepository(index), nil
+			}
+			return repository.NewContractStateRepository(elastic.(elastic_search.ElasticIndex)), nil
 		},
 	},
 	{
 		Name: "nft.repo",
 		Build: func(elastic elastic_search.Index) (repository.NftRepository, error) {
-			return repository.NewNftRepository(elastic), nil
+			switch index := elastic.(type) {
+			case postgres.Index:
+				return postgres.NewNftRepository(index), nil
+			case memory.Index:
+				return memory.NewNftRepository(index), nil
+			}
+			return repository.NewNftRepository(elastic.(elastic_search.ElasticIndex)), nil
 		},
 	},
 	{
 		Name: "nftAction.repo",
 		Build: func(elastic elastic_search.Index) (repository.NftActionRepository, error) {
-			return repository.NewNftActionRepository(elastic), nil
+			switch index := elastic.(type) {
+			case postgres.Index:
+				return postgres.NewNftActionRepository(index), nil
+			case memory.Index:
+				return memory.NewNftActionRepository(index), nil
+			}
+			return repository.NewNftActionRepository(elastic.(elastic_search.ElasticIndex)), nil
+		},
+	},
+	{
+		Name: "deadLetter.repo",
+		Build: func(elastic elastic_search.Index) (repository.DeadLetterRepository, error) {
+			switch index := elastic.(type) {
+			case postgres.Index:
+				return postgres.NewDeadLetterRepository(index), nil
+			case memory.Index:
+				return memory.NewDeadLetterRepository(index), nil
+			}
+			return repository.NewDeadLetterRepository(elastic.(elastic_search.ElasticIndex)), nil
+		},
+	},
+	{
+		Name: "checkpoint.repo",
+		Build: func(elastic elastic_search.Index) (repository.CheckpointRepository, error) {
+			switch index := elastic.(type) {
+			case postgres.Index:
+				return postgres.NewCheckpointRepository(index), nil
+			case memory.Index:
+				return memory.NewCheckpointRepository(index), nil
+			}
+			return repository.NewCheckpointRepository(elastic.(elastic_search.ElasticIndex)), nil
+		},
+	},
+	{
+		Name: "skippedBlock.repo",
+		Build: func(elastic elastic_search.Index) (repository.SkippedBlockRepository, error) {
+			switch index := elastic.(type) {
+			case postgres.Index:
+				return postgres.NewSkippedBlockRepository(index), nil
+			case memory.Index:
+				return memory.NewSkippedBlockRepository(index), nil
+			}
+			return repository.NewSkippedBlockRepository(elastic.(elastic_search.ElasticIndex)), nil
+		},
+	},
+	{
+		Name: "address.repo",
+		Build: func(elastic elastic_search.Index) (repository.AddressRepository, error) {
+			switch index := elastic.(type) {
+			case postgres.Index:
+				return postgres.NewAddressRepository(index), nil
+			case memory.Index:
+				return memory.NewAddressRepository(index), nil
+			}
+			return repository.NewAddressRepository(elastic.(elastic_search.ElasticIndex)), nil
+		},
+	},
+	{
+		Name: "tokenTransfer.repo",
+		Build: func(elastic elastic_search.Index) (repository.TokenTransferRepository, error) {
+			switch index := elastic.(type) {
+			case postgres.Index:
+				return postgres.NewTokenTransferRepository(index), nil
+			case memory.Index:
+				return memory.NewTokenTransferRepository(index), nil
+			}
+			return repository.NewTokenTransferRepository(elastic.(elastic_search.ElasticIndex)), nil
+		},
+	},
+	{
+		Name: "tokenBalance.repo",
+		Build: func(elastic elastic_search.Index) (repository.TokenBalanceRepository, error) {
+			switch index := elastic.(type) {
+			case postgres.Index:
+				return postgres.NewTokenBalanceRepository(index), nil
+			case memory.Index:
+				return memory.NewTokenBalanceRepository(index), nil
+			}
+			return repository.NewTokenBalanceRepository(elastic.(elastic_search.ElasticIndex)), nil
+		},
+	},
+	{
+		Name: "operator.repo",
+		Build: func(elastic elastic_search.Index) (repository.OperatorRepository, error) {
+			switch index := elastic.(type) {
+			case postgres.Index:
+				return postgres.NewOperatorRepository(index), nil
+			case memory.Index:
+				return memory.NewOperatorRepository(index), nil
+			}
+			return repository.NewOperatorRepository(elastic.(elastic_search.ElasticIndex)), nil
+		},
+	},
+	{
+		Name: "block.repo",
+		Build: func(elastic elastic_search.Index) (repository.BlockRepository, error) {
+			switch index := elastic.(type) {
+			case postgres.Index:
+				return postgres.NewBlockRepository(index), nil
+			case memory.Index:
+				return memory.NewBlockRepository(index), nil
+			}
+			return repository.NewBlockRepository(elastic.(elastic_search.ElasticIndex)), nil
+		},
+	},
+	{
+		Name: "governance.repo",
+		Build: func(elastic elastic_search.Index) (repository.GovernanceRepository, error) {
+			switch index := elastic.(type) {
+			case postgres.Index:
+				return postgres.NewGovernanceRepository(index), nil
+			case memory.Index:
+				return memory.NewGovernanceRepository(index), nil
+			}
+			return repository.NewGovernanceRepository(elastic.(elastic_search.ElasticIndex)), nil
+		},
+	},
+	{
+		Name: "nftDiscrepancy.repo",
+		Build: func(elastic elastic_search.Index) (repository.NftDiscrepancyRepository, error) {
+			switch index := elastic.(type) {
+			case postgres.Index:
+				return postgres.NewNftDiscrepancyRepository(index), nil
+			case memory.Index:
+				return memory.NewNftDiscrepancyRepository(index), nil
+			}
+			return repository.NewNftDiscrepancyRepository(elastic.(elastic_search.ElasticIndex)), nil
+		},
+	},
+	{
+		Name: "nft.reconciler",
+		Build: func(
+			elastic elastic_search.Index,
+			zilliqa zilliqa.Service,
+			nftRepo repository.NftRepository,
+			discrepancyRepo repository.NftDiscrepancyRepository,
+			checkpointRepo repository.CheckpointRepository,
+		) (indexer.NftReconciler, error) {
+			return indexer.NewNftReconciler(elastic, zilliqa, nftRepo, discrepancyRepo, checkpointRepo, config.Get().Nfts.ReconcileMaxLag), nil
+		},
+	},
+	{
+		Name: "rollback.service",
+		Build: func(
+			elastic elastic_search.Index,
+			txRepo repository.TransactionRepository,
+			contractRepo repository.ContractRepository,
+			nftRepo repository.NftRepository,
+			nftActionRepo repository.NftActionRepository,
+			checkpointRepo repository.CheckpointRepository,
+			tokenTransferRepo repository.TokenTransferRepository,
+			tokenBalanceRepo repository.TokenBalanceRepository,
+			governanceRepo repository.GovernanceRepository,
+			operatorRepo repository.OperatorRepository,
+			blockRepo repository.BlockRepository,
+			addressIndexer indexer.AddressIndexer,
+		) (rollback.Service, error) {
+			return rollback.NewService(elastic, txRepo, contractRepo, nftRepo, nftActionRepo, checkpointRepo, tokenTransferRepo, tokenBalanceRepo, governanceRepo, operatorRepo, blockRepo, addressIndexer), nil
 		},
 	},
 	{
@@ -228,8 +528,47 @@ var Definitions = []dingo.Def{
 	},
 	{
 		Name: "contract.factory",
-		Build: func(zilliqa zilliqa.Service) (factory.ContractFactory, error) {
-			return factory.NewContractFactory(zilliqa), nil
+		Build: func(zilliqa zilliqa.Service, shapes factory.ContractShapeRegistry) (factory.ContractFactory, error) {
+			return factory.NewContractFactory(zilliqa, shapes), nil
+		},
+	},
+	{
+		Name: "contract.shapes",
+		Build: func() (factory.ContractShapeRegistry, error) {
+			return factory.NewContractShapeRegistry(config.Get().ContractShapeDir, config.Get().Index, map[entity.ZrcStandard][]string{
+				entity.ZRC1: config.Get().AdditionalZrc1,
+				entity.ZRC6: config.Get().AdditionalZrc6,
+			})
+		},
+	},
+	{
+		Name: "contract.handlers",
+		Build: func(
+			elastic elastic_search.Index,
+			contractStateRepo repository.ContractStateRepository,
+			nftRepo repository.NftRepository,
+			shapes factory.ContractShapeRegistry,
+		) (handler.Registry, error) {
+			return handler.NewRegistry(
+				config.Get().Index,
+				shapes,
+				handler.Registration{
+					Handler:   handler.NewDuckHandler(elastic, nftRepo),
+					Addresses: map[string][]string{"mainnet": {"0x06f70655d4aa5819e711563eb2383655449f24e9"}},
+				},
+				handler.Registration{
+					Handler:   handler.NewZilMorphsHandler(elastic, contractStateRepo, nftRepo),
+					Addresses: map[string][]string{"mainnet": {"0x852c4105660ab288d0df8b2491f7462c66a1c0ae"}},
+				},
+				handler.Registration{
+					Handler: handler.NewNoRefreshHandler(),
+					Addresses: map[string][]string{"mainnet": {
+						"0x3fe64e8b3e9e110db331b32ea26e191c07f14f80",
+						"0x32e4df3cd46c30862b0a30cdb187045b11ee8753",
+						"0x821aea19180b0868f22301147f0c28204283d167",
+					}},
+				},
+			), nil
 		},
 	},
 	{
@@ -244,10 +583,27 @@ var Definitions = []dingo.Def{
 			return factory.NewZrc6Factory(config.Get().ContractsWithoutMetadata), nil
 		},
 	},
+	{
+		Name: "zrc2.factory",
+		Build: func() (factory.Zrc2Factory, error) {
+			return factory.NewZrc2Factory(), nil
+		},
+	},
+	{
+		Name: "royalty.resolver",
+		Build: func(
+			governanceRepo repository.GovernanceRepository,
+			contractRepo repository.ContractRepository,
+			stateRepo repository.ContractStateRepository,
+			cache *cache.Cache,
+		) (factory.RoyaltyResolver, error) {
+			return factory.NewRoyaltyResolver(governanceRepo, contractRepo, stateRepo, cache), nil
+		},
+	},
 	{
 		Name: "marketplace.zilkroad.factory",
-		Build: func(nftRepo repository.NftRepository, contractRepo repository.ContractRepository, stateRepo repository.ContractStateRepository) (factory.ZilkroadMarketplaceFactory, error) {
-			return factory.NewZilkroadMarketplaceFactory(nftRepo, contractRepo, stateRepo), nil
+		Build: func(nftRepo repository.NftRepository, contractRepo repository.ContractRepository, royaltyResolver factory.RoyaltyResolver) (factory.ZilkroadMarketplaceFactory, error) {
+			return factory.NewZilkroadMarketplaceFactory(nftRepo, contractRepo, royaltyResolver), nil
 		},
 	},
 	{
@@ -258,8 +614,8 @@ var Definitions = []dingo.Def{
 	},
 	{
 		Name: "marketplace.arky.factory",
-		Build: func(nftRepo repository.NftRepository, stateRepo repository.ContractStateRepository) (factory.ArkyMarketplaceFactory, error) {
-			return factory.NewArkyMarketplaceFactory(nftRepo, stateRepo), nil
+		Build: func(nftRepo repository.NftRepository, royaltyResolver factory.RoyaltyResolver) (factory.ArkyMarketplaceFactory, error) {
+			return factory.NewArkyMarketplaceFactory(nftRepo, royaltyResolver), nil
 		},
 	},
 	{
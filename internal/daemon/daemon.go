@@ -1,13 +1,17 @@
 package daemon
 
 import (
+	"context"
 	"errors"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/config"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/factory"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/indexer"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/indexer/IndexOption"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/rollback"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/shutdown"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/zilliqa"
 	"go.uber.org/zap"
 	"strconv"
@@ -19,14 +23,19 @@ type Daemon struct {
 	firstBlockNum      uint64
 	indexer            indexer.Indexer
 	zilliqa            zilliqa.Service
+	subscriber         zilliqa.Subscriber
 	txRepo             repository.TransactionRepository
 	nftRepo            repository.NftRepository
 	contractRepo       repository.ContractRepository
+	checkpointRepo     repository.CheckpointRepository
 	contractIndexer    indexer.ContractIndexer
 	zrc1Indexer        indexer.Zrc1Indexer
 	zrc6Indexer        indexer.Zrc6Indexer
+	zrc2Indexer        indexer.Zrc2Indexer
 	marketplaceIndexer indexer.MarketplaceIndexer
 	metadataIndexer    indexer.MetadataIndexer
+	addressIndexer     indexer.AddressIndexer
+	rollback           rollback.Service
 }
 
 func NewDaemon(
@@ -34,46 +43,65 @@ func NewDaemon(
 	firstBlockNum uint64,
 	indexer indexer.Indexer,
 	zilliqa zilliqa.Service,
+	subscriber zilliqa.Subscriber,
 	txRepo repository.TransactionRepository,
 	nftRepo repository.NftRepository,
 	contractRepo repository.ContractRepository,
+	checkpointRepo repository.CheckpointRepository,
 	contractIndexer indexer.ContractIndexer,
 	zrc1Indexer indexer.Zrc1Indexer,
 	zrc6Indexer indexer.Zrc6Indexer,
+	zrc2Indexer indexer.Zrc2Indexer,
 	marketplaceIndexer indexer.MarketplaceIndexer,
 	metadataIndexer indexer.MetadataIndexer,
+	addressIndexer indexer.AddressIndexer,
+	rollback rollback.Service,
 ) *Daemon {
 	return &Daemon{
 		elastic,
 		firstBlockNum,
 		indexer,
 		zilliqa,
+		subscriber,
 		txRepo,
 		nftRepo,
 		contractRepo,
+		checkpointRepo,
 		contractIndexer,
 		zrc1Indexer,
 		zrc6Indexer,
+		zrc2Indexer,
 		marketplaceIndexer,
 		metadataIndexer,
+		addressIndexer,
+		rollback,
 	}
 }
 
-func (d *Daemon) Execute() {
+// Execute indexes until ctx is cancelled. Work in flight at that point is finished, then persisted along with
+// the checkpoints recording it.
+func (d *Daemon) Execute(ctx context.Context) {
 	d.elastic.InstallMappings()
 
-	if config.Get().Reindex == true {
+	work, cancel := shutdown.Grace(ctx)
+	bestBlock := d.rewind(work)
+	cancel()
+
+	d.bulkIndex(ctx, bestBlock)
+
+	if config.Get().Reindex == true && ctx.Err() == nil {
+		d.elastic.PromoteIndices()
 		zap.L().Info("Reindex complete")
-		return
 	}
 
-	bestBlock := d.rewind()
-	d.bulkIndex(bestBlock)
-	d.subscribe()
+	d.subscribe(ctx)
+
+	d.elastic.Persist()
+	zap.L().Info("Indexer stopped")
 }
 
-func (d *Daemon) rewind() uint64 {
-	bestBlockNum, err := d.txRepo.GetBestBlockNum()
+func (d *Daemon) rewind(ctx context.Context) uint64 {
+	bestBlockNum, err := d.indexer.GetLastBlockNumIndexed(ctx)
 	if err != nil {
 		if err == repository.ErrBestBlockNumFound {
 			d.indexer.SetLastBlockNumIndexed(d.firstBlockNum)
@@ -84,6 +112,12 @@ func (d *Daemon) rewind() uint64 {
 
 	targetHeight := d.targetHeight(bestBlockNum)
 
+	if targetHeight < bestBlockNum {
+		if err := d.rollback.RollbackTo(ctx, targetHeight); err != nil {
+			zap.L().With(zap.Error(err), zap.Uint64("height", targetHeight)).Fatal("Failed to rollback to target height")
+		}
+	}
+
 	d.indexer.SetLastBlockNumIndexed(targetHeight)
 
 	zap.L().With(
@@ -93,23 +127,32 @@ func (d *Daemon) rewind() uint64 {
 	return targetHeight
 }
 
-func (d *Daemon) bulkIndex(bestBlockNum uint64) {
-	if !config.Get().BulkIndex.Active {
+func (d *Daemon) bulkIndex(ctx context.Context, bestBlockNum uint64) {
+	if !config.Get().BulkIndex.Active && !config.Get().Reindex {
 		return
 	}
 
 	zap.S().Infof("Bulk indexing from %d", bestBlockNum)
 
-	d.bulkIndexTxs()
-	d.bulkIndexContracts(bestBlockNum)
-	d.bulkIndexNfts(bestBlockNum)
-	d.bulkIndexMarketPlaceSales(bestBlockNum)
+	for _, stage := range []func(ctx context.Context, bestBlockNum uint64){
+		d.bulkIndexTxs,
+		d.bulkIndexContracts,
+		d.bulkIndexNfts,
+		d.bulkIndexTokens,
+		d.bulkIndexMarketPlaceSales,
+	} {
+		if ctx.Err() != nil {
+			zap.L().Info("Bulk indexing stopped")
+			return
+		}
+		stage(ctx, bestBlockNum)
+	}
 
 	zap.L().Info("Bulk indexing complete")
 }
 
-func (d *Daemon) getTargetHeight() (targetHeight uint64) {
-	latestCoreTxBlock, err := d.zilliqa.GetLatestTxBlock()
+func (d *Daemon) getTargetHeight(ctx context.Context) (targetHeight uint64) {
+	latestCoreTxBlock, err := d.zilliqa.GetLatestTxBlock(ctx)
 	if err != nil {
 		zap.L().With(zap.Error(err)).Fatal("Failed to get latest block from zilliqa")
 	}
@@ -124,8 +167,14 @@ func (d *Daemon) getTargetHeight() (targetHeight uint64) {
 	return
 }
 
-func (d *Daemon) bulkIndexTxs() {
-	if err := d.indexer.Index(IndexOption.BatchIndex, d.getTargetHeight()); err != nil {
+func (d *Daemon) bulkIndexTxs(ctx context.Context, _ uint64) {
+	work, cancel := shutdown.Grace(ctx)
+	defer cancel()
+
+	if err := d.indexer.Index(ctx, IndexOption.BatchIndex, d.getTargetHeight(work)); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return
+		}
 		zap.L().With(zap.Error(err)).Fatal("Failed to bulk index transactions")
 	}
 
@@ -133,40 +182,51 @@ func (d *Daemon) bulkIndexTxs() {
 	time.Sleep(2 * time.Second)
 }
 
-func (d *Daemon) bulkIndexContracts(bestBlockNum uint64) {
-	if err := d.contractIndexer.BulkIndex(d.bulkIndexContractsFrom(bestBlockNum)); err != nil {
-		zap.L().With(zap.Error(err)).Error("Failed to bulk index contracts")
+func (d *Daemon) bulkIndexContracts(ctx context.Context, bestBlockNum uint64) {
+	work, cancel := shutdown.Grace(ctx)
+	defer cancel()
+
+	if err := d.contractIndexer.BulkIndex(ctx, d.bulkIndexContractsFrom(work, bestBlockNum)); err != nil {
+		if !errors.Is(err, context.Canceled) {
+			zap.L().With(zap.Error(err)).Error("Failed to bulk index contracts")
+		}
+		return
 	}
 
+	d.checkpoint(entity.ContractStage, d.lastBlockNumIndexed(work), 0)
 	d.elastic.Persist()
 	time.Sleep(2 * time.Second)
 }
 
-func (d *Daemon) bulkIndexContractsFrom(bestBlockNum uint64) uint64 {
+func (d *Daemon) bulkIndexContractsFrom(ctx context.Context, bestBlockNum uint64) uint64 {
 	bulkIndexFrom := config.Get().BulkIndex.IndexContractsFrom
 	if bulkIndexFrom == nil {
-		cBestBlockNum, err := d.contractRepo.GetBestBlockNum()
-		if err == nil && cBestBlockNum < bestBlockNum {
-			return cBestBlockNum
-		}
-		return bestBlockNum
+		return d.stageFrom(ctx, entity.ContractStage, bestBlockNum, d.contractRepo.GetBestBlockNum)
 	}
 
 	return *bulkIndexFrom
 }
 
-func (d *Daemon) bulkIndexNfts(bestBlockNum uint64) {
-	bulkIndexNftsFrom := d.bulkIndexNftsFrom(bestBlockNum)
+func (d *Daemon) bulkIndexNfts(ctx context.Context, bestBlockNum uint64) {
+	// A page of contracts being indexed when ctx is cancelled is finished, and the import resumes after it
+	work, cancel := shutdown.Grace(ctx)
+	defer cancel()
+
+	bulkIndexNftsFrom := d.bulkIndexNftsFrom(work, bestBlockNum)
 	size := 100
-	contractPage := 1
+	contractPage := d.resumePage(work, entity.NftImportStage, bulkIndexNftsFrom)
 
-	zap.L().With(zap.Uint64("bestBlockNum", bulkIndexNftsFrom)).Info("Bulk index NFTs")
+	zap.L().With(zap.Uint64("bestBlockNum", bulkIndexNftsFrom), zap.Int("page", contractPage)).Info("Bulk index NFTs")
 
 	for {
-		contracts, total, err := d.contractRepo.GetAllNftContracts(size, contractPage)
+		if ctx.Err() != nil {
+			return
+		}
+
+		contracts, total, err := d.contractRepo.GetAllNftContracts(work, size, contractPage)
 		if err != nil {
 			zap.L().With(zap.Error(err)).Error("Failed to get contracts when bulk indexing nfts")
-			break
+			return
 		}
 
 		if contractPage == 1 {
@@ -177,108 +237,340 @@ func (d *Daemon) bulkIndexNfts(bestBlockNum uint64) {
 			break
 		}
 
+		// The page is not checkpointed when one of its contracts fails, so the next run indexes it again
 		for _, c := range contracts {
-			txPage := 1
-			for {
-				txs, total, err := d.txRepo.GetContractExecutionsByContractFrom(c, bulkIndexNftsFrom, size, txPage)
-				if err != nil {
-					zap.L().With(zap.Error(err)).Error("Failed to get txs when bulk indexing nfts")
-				}
-				if txPage == 1 && total != 0 {
-					zap.S().Infof("Found %d nfts for contract %s", total, c.Address)
-				}
-				if len(txs) == 0 {
-					break
-				}
+			if err := d.bulkIndexContractNfts(work, c, bulkIndexNftsFrom, size); err != nil {
+				zap.L().With(zap.Error(err), zap.String("contract", c.Address), zap.Int("page", contractPage)).
+					Error("Failed to get txs when bulk indexing nfts")
+				return
+			}
+		}
+
+		contractPage++
+		d.checkpoint(entity.NftImportStage, bulkIndexNftsFrom, contractPage)
+		d.elastic.BatchPersist()
+	}
 
-				for _, tx := range txs {
-					if c.MatchesStandard(entity.ZRC1) {
-						if err := d.zrc1Indexer.IndexTx(tx, c); err != nil {
-							zap.L().With(zap.Error(err)).Error("Failed to bulk index Zrc1")
-						}
-					}
-					if c.MatchesStandard(entity.ZRC6) {
-						if err := d.zrc6Indexer.IndexTx(tx, c); err != nil {
-							zap.L().With(zap.Error(err)).Error("Failed to bulk index Zrc6")
-						}
-					}
+	lastBlockNumIndexed := d.lastBlockNumIndexed(work)
+	d.checkpoint(entity.Zrc1Stage, lastBlockNumIndexed, 0)
+	d.checkpoint(entity.Zrc6Stage, lastBlockNumIndexed, 0)
+	d.checkpoint(entity.NftImportStage, lastBlockNumIndexed, 0)
+	d.elastic.Persist()
+	time.Sleep(2 * time.Second)
+}
+
+// bulkIndexContractNfts applies the txs of an nft contract from a block, returning when they cannot be read
+func (d *Daemon) bulkIndexContractNfts(ctx context.Context, c entity.Contract, fromBlockNum uint64, size int) error {
+	for txPage := 1; ; txPage++ {
+		txs, total, err := d.txRepo.GetContractExecutionsByContractFrom(ctx, c, fromBlockNum, size, txPage)
+		if err != nil {
+			return err
+		}
+		if txPage == 1 && total != 0 {
+			zap.S().Infof("Found %d nfts for contract %s", total, c.Address)
+		}
+		if len(txs) == 0 {
+			break
+		}
+
+		for _, tx := range txs {
+			if c.MatchesStandard(entity.ZRC1) {
+				if err := d.zrc1Indexer.IndexTx(ctx, tx, c); err != nil {
+					zap.L().With(zap.Error(err)).Error("Failed to bulk index Zrc1")
+				}
+			}
+			if c.MatchesStandard(entity.ZRC6) {
+				if err := d.zrc6Indexer.IndexTx(ctx, tx, c); err != nil {
+					zap.L().With(zap.Error(err)).Error("Failed to bulk index Zrc6")
 				}
-				d.elastic.BatchPersist()
-				txPage++
 			}
 		}
 		d.elastic.BatchPersist()
+	}
+
+	if c.MatchesStandard(entity.ZRC6) {
+		if err := d.zrc6Indexer.UpdateStats(ctx, c.Address); err != nil {
+			zap.L().With(zap.Error(err)).Error("Failed to update Zrc6 stats")
+		}
+	}
+
+	return nil
+}
+
+// bulkIndexTokens applies the transfers of every ZRC2 contract, skipping those already indexed
+func (d *Daemon) bulkIndexTokens(ctx context.Context, bestBlockNum uint64) {
+	work, cancel := shutdown.Grace(ctx)
+	defer cancel()
+
+	bulkIndexFrom := d.bulkIndexTokensFrom(work, bestBlockNum)
+	size := 100
+	contractPage := d.resumePage(work, entity.Zrc2ImportStage, bulkIndexFrom)
+
+	zap.L().With(zap.Uint64("bestBlockNum", bulkIndexFrom), zap.Int("page", contractPage)).Info("Bulk index tokens")
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		contracts, total, err := d.contractRepo.GetAllZrc2Contracts(work, size, contractPage)
+		if err != nil {
+			zap.L().With(zap.Error(err)).Error("Failed to get contracts when bulk indexing tokens")
+			return
+		}
+
+		if contractPage == 1 {
+			zap.S().Infof("Found %d token contracts", total)
+		}
+
+		if len(contracts) == 0 {
+			break
+		}
+
+		// The page is not checkpointed when one of its contracts fails, so the next run indexes it again. Transfers
+		// already applied are skipped then.
+		for _, c := range contracts {
+			if err := d.bulkIndexContractTokens(work, c, bulkIndexFrom, size); err != nil {
+				zap.L().With(zap.Error(err), zap.String("contract", c.Address), zap.Int("page", contractPage)).
+					Error("Failed to bulk index tokens")
+				return
+			}
+		}
 
 		contractPage++
+		d.checkpoint(entity.Zrc2ImportStage, bulkIndexFrom, contractPage)
+		d.elastic.BatchPersist()
 	}
 
+	lastBlockNumIndexed := d.lastBlockNumIndexed(work)
+	d.checkpoint(entity.Zrc2Stage, lastBlockNumIndexed, 0)
+	d.checkpoint(entity.Zrc2ImportStage, lastBlockNumIndexed, 0)
 	d.elastic.Persist()
-	time.Sleep(2 * time.Second)
 }
 
-func (d *Daemon) bulkIndexMarketPlaceSales(bestBlockNum uint64) {
-	bulkIndexFrom := d.bulkIndexNftsFrom(bestBlockNum)
-	page := 1
+// bulkIndexContractTokens applies the initial supply and the transfers of a token contract from a block
+func (d *Daemon) bulkIndexContractTokens(ctx context.Context, c entity.Contract, fromBlockNum uint64, size int) error {
+	if c.Zrc2 == nil {
+		c.Zrc2 = factory.CreateZrc2Token(c)
+		d.elastic.AddUpdateRequest(elastic_search.ContractIndex.Get(), c, elastic_search.Zrc2Supply)
+	}
+
+	// The initial supply is credited once, so tokens indexed without it are credited on their next bulk index
+	if tx, err := d.txRepo.GetContractCreationForContract(ctx, c.Address); err == nil {
+		if err := d.zrc2Indexer.IndexContractCreation(ctx, *tx, c); err != nil {
+			return err
+		}
+	}
+
+	for txPage := 1; ; txPage++ {
+		txs, _, err := d.txRepo.GetContractExecutionsByContractFrom(ctx, c, fromBlockNum, size, txPage)
+		if err != nil {
+			return err
+		}
+		if len(txs) == 0 {
+			return nil
+		}
+
+		for _, tx := range txs {
+			if err := d.zrc2Indexer.IndexTx(ctx, tx, c); err != nil {
+				return err
+			}
+		}
+		d.elastic.BatchPersist()
+	}
+}
+
+func (d *Daemon) bulkIndexMarketPlaceSales(ctx context.Context, bestBlockNum uint64) {
+	work, cancel := shutdown.Grace(ctx)
+	defer cancel()
+
+	bulkIndexFrom := d.bulkIndexMarketplaceFrom(work, bestBlockNum)
+	page := d.resumePage(work, entity.MarketplaceImportStage, bulkIndexFrom)
 	size := 100
 
-	zap.L().With(zap.Uint64("bestBlockNum", bulkIndexFrom)).Info("Bulk index Marketplace sales")
+	zap.L().With(zap.Uint64("bestBlockNum", bulkIndexFrom), zap.Int("page", page)).Info("Bulk index Marketplace sales")
 
 	for {
-		txs, _, err := d.txRepo.GetNftMarketplaceExecutionTxs(bulkIndexFrom, size, page)
+		if ctx.Err() != nil {
+			return
+		}
+
+		txs, _, err := d.txRepo.GetNftMarketplaceExecutionTxs(work, bulkIndexFrom, size, page)
 		if err != nil {
-			break
+			zap.L().With(zap.Error(err)).Error("Failed to get txs when bulk indexing marketplace sales")
+			return
 		}
 
 		if len(txs) == 0 {
 			break
 		}
-		d.marketplaceIndexer.IndexTxs(txs)
-		d.elastic.BatchPersist()
+		d.marketplaceIndexer.IndexTxs(work, txs)
 		page++
+		d.checkpoint(entity.MarketplaceImportStage, bulkIndexFrom, page)
+		d.elastic.BatchPersist()
 	}
+
+	lastBlockNumIndexed := d.lastBlockNumIndexed(work)
+	d.checkpoint(entity.MarketplaceStage, lastBlockNumIndexed, 0)
+	d.checkpoint(entity.MarketplaceImportStage, lastBlockNumIndexed, 0)
 	d.elastic.Persist()
 }
 
-func (d *Daemon) bulkIndexNftsFrom(bestBlockNum uint64) uint64 {
+func (d *Daemon) bulkIndexNftsFrom(ctx context.Context, bestBlockNum uint64) uint64 {
 	bulkIndexFrom := config.Get().BulkIndex.IndexNftsFrom
 	if bulkIndexFrom == nil {
-		cBestBlockNum, err := d.nftRepo.GetBestBlockNum()
-		if err == nil && cBestBlockNum < bestBlockNum {
-			return cBestBlockNum
+		zrc1From := d.stageFrom(ctx, entity.Zrc1Stage, bestBlockNum, d.nftRepo.GetBestBlockNum)
+		zrc6From := d.stageFrom(ctx, entity.Zrc6Stage, bestBlockNum, d.nftRepo.GetBestBlockNum)
+		if zrc1From < zrc6From {
+			return zrc1From
 		}
-		return bestBlockNum
+		return zrc6From
 	}
 
 	return *bulkIndexFrom
 }
 
-func (d *Daemon) subscribe() {
+// Token transfers were not indexed before their checkpoint, so without one they are indexed from the start
+func (d *Daemon) bulkIndexTokensFrom(ctx context.Context, bestBlockNum uint64) uint64 {
+	return d.stageFrom(ctx, entity.Zrc2Stage, bestBlockNum, func(ctx context.Context) (uint64, error) {
+		return d.firstBlockNum, nil
+	})
+}
+
+func (d *Daemon) bulkIndexMarketplaceFrom(ctx context.Context, bestBlockNum uint64) uint64 {
+	bulkIndexFrom := config.Get().BulkIndex.IndexNftsFrom
+	if bulkIndexFrom == nil {
+		return d.stageFrom(ctx, entity.MarketplaceStage, bestBlockNum, d.nftRepo.GetBestBlockNum)
+	}
+
+	return *bulkIndexFrom
+}
+
+// stageFrom returns the block a stage should resume from, falling back to the legacy best block
+// lookup for indices created before checkpoints were introduced.
+func (d *Daemon) stageFrom(ctx context.Context, stage entity.Stage, bestBlockNum uint64, legacyBestBlockNum func(ctx context.Context) (uint64, error)) uint64 {
+	blockNum, err := d.checkpointRepo.GetBlockNum(ctx, stage)
+	if err != nil {
+		if err != repository.ErrCheckpointNotFound {
+			zap.L().With(zap.Error(err), zap.String("stage", string(stage))).Fatal("Failed to get checkpoint")
+		}
+
+		blockNum, err = legacyBestBlockNum(ctx)
+		if err != nil {
+			return bestBlockNum
+		}
+	}
+
+	if blockNum < bestBlockNum {
+		return blockNum
+	}
+
+	return bestBlockNum
+}
+
+// resumePage returns the page an interrupted bulk import stopped at, provided it was paging from the same block
+func (d *Daemon) resumePage(ctx context.Context, stage entity.Stage, fromBlockNum uint64) int {
+	checkpoint, err := d.checkpointRepo.GetCheckpoint(ctx, stage)
+	if err != nil {
+		if err != repository.ErrCheckpointNotFound {
+			zap.L().With(zap.Error(err), zap.String("stage", string(stage))).Fatal("Failed to get checkpoint")
+		}
+		return 1
+	}
+
+	if checkpoint.Page == 0 || checkpoint.BlockNum != fromBlockNum {
+		return 1
+	}
+
+	zap.L().With(zap.String("stage", string(stage)), zap.Int("page", checkpoint.Page)).Info("Resuming bulk import")
+
+	return checkpoint.Page
+}
+
+func (d *Daemon) checkpoint(stage entity.Stage, blockNum uint64, page int) {
+	d.elastic.AddIndexRequest(elastic_search.CheckpointIndex.Get(), factory.CreateCheckpoint(stage, blockNum, page), elastic_search.Checkpoint)
+}
+
+func (d *Daemon) lastBlockNumIndexed(ctx context.Context) uint64 {
+	blockNum, err := d.indexer.GetLastBlockNumIndexed(ctx)
+	if err != nil {
+		zap.L().With(zap.Error(err)).Fatal("Failed to get last block num indexed")
+	}
+
+	return blockNum
+}
+
+func (d *Daemon) subscribe(ctx context.Context) {
 	if !config.Get().Subscribe {
 		return
 	}
 
 	zap.L().Info("Starting subscriber")
+
+	blocks := make(chan uint64)
+	go d.subscriber.Subscribe(ctx, blocks)
+
+	repairInterval := time.Duration(config.Get().Quarantine.RepairInterval) * time.Second
+	if repairInterval <= 0 {
+		repairInterval = 10 * time.Minute
+	}
+	repair := time.NewTicker(repairInterval)
+	defer repair.Stop()
+
+	reconcileInterval := time.Duration(config.Get().Addresses.ReconcileInterval) * time.Second
+	if reconcileInterval <= 0 {
+		reconcileInterval = 5 * time.Minute
+	}
+	reconcile := time.NewTicker(reconcileInterval)
+	defer reconcile.Stop()
+
 	for {
-		latestCoreTxBlock, err := d.zilliqa.GetLatestTxBlock()
-		if err == nil {
-			targetHeight, err := strconv.ParseUint(latestCoreTxBlock.Header.BlockNum, 0, 64)
-			if err != nil {
-				zap.L().With(zap.Error(err)).Fatal("Failed to parse latest block num")
-			}
+		var targetHeight uint64
+		select {
+		case <-ctx.Done():
+			zap.L().Info("Subscriber stopped")
+			return
+		case <-repair.C:
+			d.repair(ctx)
+			continue
+		case <-reconcile.C:
+			d.reconcile(ctx)
+			continue
+		case targetHeight = <-blocks:
+		}
 
-			if err = d.indexer.Index(IndexOption.SingleIndex, targetHeight); err != nil {
-				if !errors.Is(err, indexer.ErrBlockNotReady) {
-					zap.L().With(zap.Error(err)).Fatal("Failed to index from subscriber")
-				}
+		if err := d.indexer.Index(ctx, IndexOption.SingleIndex, targetHeight); err != nil {
+			if !errors.Is(err, indexer.ErrBlockNotReady) && !errors.Is(err, context.Canceled) {
+				zap.L().With(zap.Error(err)).Fatal("Failed to index from subscriber")
 			}
-
-			d.elastic.Persist()
 		}
 
-		time.Sleep(5 * time.Second)
+		d.elastic.Persist()
+	}
+}
+
+// repair retries the blocks quarantined because the node could not serve them
+func (d *Daemon) repair(ctx context.Context) {
+	work, cancel := shutdown.Grace(ctx)
+	defer cancel()
+
+	if err := d.indexer.Repair(work); err != nil && !errors.Is(err, context.Canceled) {
+		zap.L().With(zap.Error(err)).Error("Failed to repair quarantined blocks")
 	}
 }
 
+// reconcile corrects the indexed address balances against the node, which reports them at the chain tip
+func (d *Daemon) reconcile(ctx context.Context) {
+	work, cancel := shutdown.Grace(ctx)
+	defer cancel()
+
+	if err := d.addressIndexer.Reconcile(work); err != nil && !errors.Is(err, context.Canceled) {
+		zap.L().With(zap.Error(err)).Error("Failed to reconcile address balances")
+	}
+
+	d.elastic.Persist()
+}
+
 func (d Daemon) targetHeight(bestBlockNum uint64) uint64 {
 	if config.Get().RewindToHeight != nil {
 		zap.L().With(zap.Uint64("height", *config.Get().RewindToHeight)).Info("Rewinding to height from config")
@@ -8,15 +8,36 @@ import (
 type Indices string
 
 var (
+	BlockIndex            Indices = "block"
+	DsBlockIndex          Indices = "dsblock"
 	TransactionIndex      Indices = "transaction"
 	ContractIndex         Indices = "contract"
 	ContractMetadataIndex Indices = "contractmetadata"
 	ContractStateIndex    Indices = "contractstate"
 	NftIndex              Indices = "nft"
 	NftActionIndex        Indices = "nftaction"
+	CheckpointIndex       Indices = "checkpoint"
+	DeadLetterIndex       Indices = "deadletter"
+	MigrationIndex        Indices = "migration"
+	SkippedBlockIndex     Indices = "skippedblock"
+	AddressIndex          Indices = "address"
+	TokenTransferIndex    Indices = "tokentransfer"
+	TokenBalanceIndex     Indices = "tokenbalance"
+	OperatorIndex         Indices = "operator"
+	GovernanceIndex       Indices = "governance"
+	NftDiscrepancyIndex   Indices = "nftdiscrepancy"
 )
 
-// Sets the network and returns the full string
+// Returns the index to read and write, the version being built while reindexing and the alias otherwise
 func (i *Indices) Get() string {
+	if physical, found := versions.getPhysical(i.Alias()); found {
+		return physical
+	}
+
+	return i.Alias()
+}
+
+// Sets the network and returns the full string
+func (i *Indices) Alias() string {
 	return fmt.Sprintf("%s.%s.%s", config.Get().Network, config.Get().Index, string(*i))
 }
@@ -0,0 +1,138 @@
+package elastic_search
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// buffer holds pending requests until they are persisted. Requests are keyed by index and id, never
+// expire, and are returned in the order they were first written.
+type buffer struct {
+	mu       sync.RWMutex
+	order    []bufferKey
+	entries  map[bufferKey]bufferEntry
+	size     int
+	maxSize  int
+	sequence uint64
+}
+
+type bufferKey struct {
+	index string
+	id    string
+}
+
+type bufferEntry struct {
+	request  Request
+	size     int
+	sequence uint64
+}
+
+func newBuffer(maxSize int) *buffer {
+	return &buffer{
+		order:   make([]bufferKey, 0),
+		entries: make(map[bufferKey]bufferEntry),
+		maxSize: maxSize,
+	}
+}
+
+func (b *buffer) Set(req Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := bufferKey{req.Index, req.Entity.Slug()}
+	if existing, found := b.entries[key]; found {
+		b.size -= existing.size
+	} else {
+		b.order = append(b.order, key)
+	}
+
+	b.sequence++
+	entry := bufferEntry{request: req, size: requestSize(req), sequence: b.sequence}
+	b.entries[key] = entry
+	b.size += entry.size
+}
+
+func (b *buffer) Get(index, id string) (Request, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	entry, found := b.entries[bufferKey{index, id}]
+
+	return entry.request, found
+}
+
+func (b *buffer) Requests() []Request {
+	requests, _ := b.snapshot()
+
+	return requests
+}
+
+// snapshot returns the pending requests in write order, along with the sequence each was written at
+// so that release only drops requests that have not been rewritten since.
+func (b *buffer) snapshot() ([]Request, []uint64) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	requests := make([]Request, 0, len(b.order))
+	sequences := make([]uint64, 0, len(b.order))
+	for _, key := range b.order {
+		entry := b.entries[key]
+		requests = append(requests, entry.request)
+		sequences = append(sequences, entry.sequence)
+	}
+
+	return requests, sequences
+}
+
+func (b *buffer) release(requests []Request, sequences []uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for idx, req := range requests {
+		key := bufferKey{req.Index, req.Entity.Slug()}
+		if entry, found := b.entries[key]; found && entry.sequence == sequences[idx] {
+			b.size -= entry.size
+			delete(b.entries, key)
+		}
+	}
+
+	order := make([]bufferKey, 0, len(b.entries))
+	for _, key := range b.order {
+		if _, found := b.entries[key]; found {
+			order = append(order, key)
+		}
+	}
+	b.order = order
+}
+
+func (b *buffer) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return len(b.order)
+}
+
+func (b *buffer) Full() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.maxSize > 0 && b.size >= b.maxSize
+}
+
+func (b *buffer) Clear() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.order = make([]bufferKey, 0)
+	b.entries = make(map[bufferKey]bufferEntry)
+	b.size = 0
+}
+
+func requestSize(req Request) int {
+	doc, err := json.Marshal(req.Entity)
+	if err != nil {
+		return 0
+	}
+
+	return len(doc)
+}
@@ -0,0 +1,42 @@
+package elastic_search
+
+import (
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"time"
+)
+
+func CreateDeadLetter(req Request, errorType, reason string) (entity.DeadLetter, error) {
+	document, err := json.Marshal(req.Entity)
+	if err != nil {
+		return entity.DeadLetter{}, err
+	}
+
+	return entity.DeadLetter{
+		Index:       req.Index,
+		DocId:       req.Entity.Slug(),
+		RequestType: string(req.Type),
+		Action:      string(req.Action),
+		Document:    document,
+		ErrorType:   errorType,
+		Reason:      reason,
+		BlockNum:    documentBlockNum(document),
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// Entities name their block number field inconsistently, BlockNum on transactions and blockNum elsewhere
+func documentBlockNum(document []byte) uint64 {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(document, &fields); err != nil {
+		return 0
+	}
+
+	for _, key := range []string{"blockNum", "BlockNum"} {
+		if blockNum, ok := fields[key].(float64); ok {
+			return uint64(blockNum)
+		}
+	}
+
+	return 0
+}
@@ -7,26 +7,28 @@ import (
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/event"
 	"github.com/olivere/elastic/v7"
-	"github.com/patrickmn/go-cache"
 	"go.uber.org/zap"
 	"io/ioutil"
+	"net/http"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 )
 
 type Index interface {
-	GetClient() *elastic.Client
-
 	InstallMappings()
+	PromoteIndices()
+	GetMigrations() ([]Migration, error)
+	Migrate() error
 
 	AddIndexRequest(index string, entity entity.Entity, reqAction RequestAction)
 	AddUpdateRequest(index string, entity entity.Entity, reqAction RequestAction)
-	HasRequest(entity entity.Entity) bool
+	HasRequest(index string, entity entity.Entity) bool
 	AddRequest(index string, entity entity.Entity, reqType RequestType, reqAction RequestAction)
 	GetEntitiesByIndex(index string) []entity.Entity
 	GetRequests() []Request
-	GetRequest(id string) *Request
+	GetRequest(index, id string) *Request
 	ClearRequests()
 
 	Save(index string, entity entity.Entity)
@@ -34,10 +36,16 @@ type Index interface {
 	Persist() int
 }
 
+type ElasticIndex interface {
+	Index
+	GetClient() *elastic.Client
+}
+
 type index struct {
-	client  *elastic.Client
-	cache   *cache.Cache
-	refresh string
+	*Requests
+	client    *elastic.Client
+	refresh   string
+	persistMu *sync.Mutex
 }
 
 type Request struct {
@@ -57,12 +65,15 @@ const (
 type RequestAction string
 
 const (
+	BlockCreate       RequestAction = "BlockCreate"
+	DsBlockCreate     RequestAction = "DsBlockCreate"
 	TransactionCreate RequestAction = "TransactionCreate"
 
 	ContractCreate     RequestAction = "ContractCreate"
 	ContractState      RequestAction = "ContractState"
 	ContractSetBaseUri RequestAction = "ContractSetBaseUri"
 	ContractMetadata   RequestAction = "ContractMetadata"
+	ContractNftStats   RequestAction = "ContractNftStats"
 
 	Zrc1Mint             RequestAction = "Zrc1Mint"
 	Zrc1DuckRegeneration RequestAction = "Zrc1DuckRegeneration"
@@ -70,26 +81,57 @@ const (
 	Zrc1Transfer         RequestAction = "Zrc1Transfer"
 	Zrc1Burn             RequestAction = "Zrc1Burn"
 
+	Zrc2Transfer RequestAction = "Zrc2Transfer"
+	Zrc2Balance  RequestAction = "Zrc2Balance"
+	Zrc2Supply   RequestAction = "Zrc2Supply"
+	Zrc2Rollback RequestAction = "Zrc2Rollback"
+
 	Zrc6Mint        RequestAction = "Zrc6Mint"
 	Zrc6SetBaseUri  RequestAction = "Zrc6SetBaseUri"
 	Zrc6SetTokenUri RequestAction = "Zrc6SetTokenUri"
 	Zrc6Transfer    RequestAction = "Zrc6Transfer"
 	Zrc6Burn        RequestAction = "Zrc6Burn"
+	Zrc6SetSpender  RequestAction = "Zrc6SetSpender"
+	Zrc6Operator    RequestAction = "Zrc6Operator"
+	Zrc6Governance  RequestAction = "Zrc6Governance"
 	NftDelegate     RequestAction = "NftDelegate"
 
-	NftMetadata RequestAction = "NftMetadata"
-	NftAction   RequestAction = "NftAction"
+	NftMetadata  RequestAction = "NftMetadata"
+	NftAction    RequestAction = "NftAction"
+	NftReconcile RequestAction = "NftReconcile"
+	NftRollback  RequestAction = "NftRollback"
+
+	Checkpoint RequestAction = "Checkpoint"
+	DeadLetter RequestAction = "DeadLetter"
+
+	SkippedBlock RequestAction = "SkippedBlock"
+
+	AddressBalance   RequestAction = "AddressBalance"
+	AddressReconcile RequestAction = "AddressReconcile"
+	AddressRollback  RequestAction = "AddressRollback"
 )
 
 const saveAttempts int = 3
 
-func New() (Index, error) {
+// A bulk that fails as a whole, or the items of it rejected as too many requests, are retried persistAttempts times,
+// waiting persistBackoff and doubling it each time
+const (
+	persistAttempts int = 5
+	persistBackoff      = 1 * time.Second
+)
+
+func New() (ElasticIndex, error) {
 	client, err := newClient()
 	if err != nil {
 		zap.L().With(zap.Error(err)).Fatal("ElasticCache: Failed to create client")
 	}
 
-	return index{client, cache.New(5*time.Minute, 10*time.Minute), config.Get().ElasticSearch.Refresh}, err
+	bufferSize := config.Get().ElasticSearch.BufferSizeMb * 1024 * 1024
+
+	i := index{nil, client, config.Get().ElasticSearch.Refresh, &sync.Mutex{}}
+	i.Requests = NewRequests(bufferSize, func() int { return i.Persist() })
+
+	return i, err
 }
 
 func newClient() (*elastic.Client, error) {
@@ -125,6 +167,7 @@ func (i index) InstallMappings() {
 		zap.L().With(zap.Error(err)).Fatal("ElasticCache: Elastic mappings directory error")
 	}
 
+	created := make(map[string]bool)
 	for _, f := range files {
 		if f.IsDir() {
 			continue
@@ -135,11 +178,15 @@ func (i index) InstallMappings() {
 			zap.L().With(zap.Error(err)).With(zap.String("file", f.Name())).Fatal("ElasticCache: Elastic mappings file error")
 		}
 
-		index := fmt.Sprintf("%s.%s.%s", config.Get().Network, config.Get().Index, f.Name()[0:len(f.Name())-len(filepath.Ext(f.Name()))])
-		if err = i.createIndex(index, b); err != nil {
-			zap.S().With(zap.Error(err)).Fatalf("ElasticCache: Failed to create index %s", index)
+		alias := aliasPrefix() + f.Name()[0:len(f.Name())-len(filepath.Ext(f.Name()))]
+		if created[alias], err = i.installIndex(alias, b); err != nil {
+			zap.S().With(zap.Error(err)).Fatalf("ElasticCache: Failed to install index %s", alias)
 		}
 	}
+
+	if err := i.migrate(created); err != nil {
+		zap.L().With(zap.Error(err)).Fatal("ElasticCache: Failed to apply migrations")
+	}
 }
 
 func (i index) createIndex(index string, mapping []byte) error {
@@ -151,15 +198,6 @@ func (i index) createIndex(index string, mapping []byte) error {
 		return err
 	}
 
-	if exists && config.Get().Reindex {
-		zap.S().Infof("ElasticCache: Deleting index %s", index)
-		_, err = client.DeleteIndex(index).Do(ctx)
-		if err != nil {
-			return err
-		}
-		exists = false
-	}
-
 	if !exists {
 		createIndex, err := client.CreateIndex(index).BodyString(string(mapping)).Do(ctx)
 		if err != nil {
@@ -174,181 +212,175 @@ func (i index) createIndex(index string, mapping []byte) error {
 	return nil
 }
 
-func (i index) AddIndexRequest(index string, entity entity.Entity, reqAction RequestAction) {
-	zap.L().With(
-		zap.String("index", index),
-		zap.String("slug", entity.Slug()),
-		zap.String("action", string(reqAction)),
-	).Debug("ElasticCache: AddIndexRequest")
-
-	i.AddRequest(index, entity, IndexRequest, reqAction)
+func (i index) Save(index string, entity entity.Entity) {
+	i.save(Request{index, entity, IndexRequest, ""})
 }
 
-func (i index) AddUpdateRequest(index string, entity entity.Entity, reqAction RequestAction) {
-	zap.L().With(
-		zap.String("index", index),
-		zap.String("slug", entity.Slug()),
-		zap.String("action", string(reqAction)),
-	).Debug("ElasticCache: AddUpdateRequest")
-
-	if cached, found := i.cache.Get(entity.Slug()); found == true {
-		entity = mergeRequests(index, cached.(Request), reqAction, entity)
-		if cached.(Request).Type == IndexRequest {
-			i.AddRequest(index, entity, IndexRequest, reqAction)
+func (i index) save(req Request) {
+	var err error
+	for attempt := 1; attempt <= saveAttempts; attempt++ {
+		_, err = i.client.Index().
+			Index(req.Index).
+			Id(req.Entity.Slug()).
+			BodyJson(req.Entity).
+			Do(context.Background())
+		if err == nil {
 			return
 		}
+
+		zap.L().With(zap.Error(err), zap.String("index", req.Index), zap.String("slug", req.Entity.Slug())).
+			Error("ElasticCache: Failed to save entity")
+		time.Sleep(1 * time.Second)
 	}
 
-	i.AddRequest(index, entity, UpdateRequest, reqAction)
+	i.deadLetter(req, "save_failed", err.Error())
 }
 
-func (i index) HasRequest(entity entity.Entity) bool {
-	_, found := i.cache.Get(entity.Slug())
+// deadLetter parks a request that cannot be persisted so one bad document does not stop the indexer
+func (i index) deadLetter(req Request, errorType, reason string) {
+	if req.Index == DeadLetterIndex.Get() {
+		zap.L().With(zap.String("slug", req.Entity.Slug()), zap.String("reason", reason)).
+			Fatal("ElasticCache: Failed to save dead letter")
+	}
 
-	return found
-}
+	deadLetter, err := CreateDeadLetter(req, errorType, reason)
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.String("index", req.Index), zap.String("slug", req.Entity.Slug())).
+			Fatal("ElasticCache: Failed to create dead letter")
+	}
 
-func (i index) AddRequest(index string, entity entity.Entity, reqType RequestType, reqAction RequestAction) {
+	zap.L().With(
+		zap.String("index", req.Index),
+		zap.String("slug", req.Entity.Slug()),
+		zap.String("errorType", errorType),
+		zap.String("reason", reason),
+	).Warn("ElasticCache: Request moved to dead letters")
 
-	i.cache.Set(entity.Slug(), Request{index, entity, reqType, reqAction}, cache.DefaultExpiration)
+	i.save(Request{DeadLetterIndex.Get(), deadLetter, IndexRequest, DeadLetter})
 }
 
-func (i index) GetEntitiesByIndex(index string) []entity.Entity {
-	entities := make([]entity.Entity, 0)
-	for _, req := range i.GetRequests() {
-		if req.Index == index {
-			entities = append(entities, req.Entity)
-		}
-	}
+func (i index) Persist() int {
+	i.persistMu.Lock()
+	defer i.persistMu.Unlock()
 
-	return entities
-}
+	pending, sequences := i.Pending()
 
-func (i index) GetRequests() []Request {
 	requests := make([]Request, 0)
-
-	for _, item := range i.cache.Items() {
-		requests = append(requests, item.Object.(Request))
+	checkpoints := make([]Request, 0)
+	for _, r := range pending {
+		if r.Action == Checkpoint {
+			checkpoints = append(checkpoints, r)
+		} else {
+			requests = append(requests, r)
+		}
 	}
 
-	return requests
+	// Checkpoints are only written once the work they record has been persisted
+	actions := i.persistRequests(requests)
+	actions += i.persistRequests(checkpoints)
+
+	i.Release(pending, sequences)
+	EmitEvents(pending)
+
+	return actions
 }
 
-func (i index) GetRequest(id string) *Request {
-	if item, found := i.cache.Get(id); found == true {
-		req := item.(Request)
-		return &req
-	} else {
-		return nil
+func (i index) persistRequests(requests []Request) int {
+	batchSize := config.Get().ElasticSearch.BulkPersistCount
+	if batchSize < 1 {
+		batchSize = len(requests)
 	}
-}
 
-func (i index) ClearRequests() {
-	i.cache.Flush()
-}
+	for start := 0; start < len(requests); start += batchSize {
+		end := start + batchSize
+		if end > len(requests) {
+			end = len(requests)
+		}
+		i.persist(requests[start:end])
+	}
 
-func (i index) Save(index string, entity entity.Entity) {
-	i.save(index, entity, 1)
+	return len(requests)
 }
 
-func (i index) save(index string, entity entity.Entity, attempt int) {
-	if attempt > saveAttempts {
-		zap.L().With(zap.String("index", index), zap.String("slug", entity.Slug())).
-			Fatal("ElasticCache: Failed to save entity, Too many attempts")
-	}
+// persist writes a batch of requests in one bulk. When the bulk keeps failing as a whole, or items keep being rejected
+// as too many requests, they are moved to the dead letters so they can be replayed once the cluster recovers.
+func (i index) persist(requests []Request) {
+	for attempt := 1; ; attempt++ {
+		zap.S().Debugf("ElasticCache: Persisting %d actions", len(requests))
 
-	_, err := i.client.Index().
-		Index(index).
-		Id(entity.Slug()).
-		BodyJson(entity).
-		Do(context.Background())
+		errorType, reason := "bulk_failed", ""
+		response, err := i.newBulk(requests).Refresh(i.refresh).Do(context.Background())
+		if err == nil {
+			if requests = i.persistFailed(response); len(requests) == 0 {
+				return
+			}
+			errorType, reason = "too_many_requests", fmt.Sprintf("rejected %d times", attempt)
+		} else {
+			reason = err.Error()
+		}
 
-	if err != nil {
-		zap.L().With(zap.Error(err), zap.String("index", index), zap.String("slug", entity.Slug())).
-			Error("ElasticCache: Failed to save entity")
-		time.Sleep(1 * time.Second)
+		if attempt == persistAttempts {
+			zap.L().With(zap.String("errorType", errorType), zap.String("reason", reason), zap.Int("actions", len(requests))).
+				Error("ElasticCache: Failed to persist requests")
+			for _, req := range requests {
+				i.deadLetter(req, errorType, reason)
+			}
+			return
+		}
 
-		i.save(index, entity, attempt+1)
+		backoff := persistBackoff << (attempt - 1)
+		zap.L().With(
+			zap.String("errorType", errorType),
+			zap.String("reason", reason),
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff),
+		).Warn("ElasticCache: Failed to persist requests. Retrying...")
+		time.Sleep(backoff)
 	}
 }
 
-func (i index) BatchPersist() bool {
-	if len(i.GetRequests()) < 250 {
-		return false
-	}
+// persistFailed moves the items of a bulk that failed to the dead letters, returning those rejected as too many
+// requests to be retried
+func (i index) persistFailed(response *elastic.BulkResponse) []Request {
+	rejected := make([]Request, 0)
+	for _, failed := range response.Failed() {
+		req := i.GetRequest(failed.Index, failed.Id)
+		if req == nil {
+			zap.L().With(zap.String("index", failed.Index), zap.String("id", failed.Id)).
+				Error("ElasticCache: Failed request no longer buffered")
+			continue
+		}
 
-	actions := len(i.GetRequests())
-	start := time.Now()
-	i.Persist()
+		if failed.Status == http.StatusTooManyRequests {
+			rejected = append(rejected, *req)
+			continue
+		}
 
-	zap.L().With(
-		zap.Duration("elapsed", time.Since(start)),
-		zap.Int("actions", actions),
-	).Info("ElasticCache: Persisting data")
+		errorType, reason := "unknown", ""
+		if failed.Error != nil {
+			errorType, reason = failed.Error.Type, failed.Error.Reason
+		}
+		i.deadLetter(*req, errorType, reason)
+	}
 
-	return true
+	return rejected
 }
 
-func (i index) Persist() int {
+func (i index) newBulk(requests []Request) *elastic.BulkService {
 	bulk := i.client.Bulk()
-	for _, r := range i.GetRequests() {
+	for _, r := range requests {
 		if r.Type == IndexRequest {
 			bulk.Add(elastic.NewBulkIndexRequest().Index(r.Index).Id(r.Entity.Slug()).Doc(r.Entity))
 		} else if r.Type == UpdateRequest {
 			bulk.Add(elastic.NewBulkUpdateRequest().Index(r.Index).Id(r.Entity.Slug()).Doc(r.Entity))
 		}
-
-		actions := bulk.NumberOfActions()
-		if actions >= config.Get().ElasticSearch.BulkPersistCount {
-			i.persist(bulk)
-			bulk = i.client.Bulk()
-		}
-	}
-
-	actions := bulk.NumberOfActions()
-	if actions != 0 {
-		i.persist(bulk)
-	}
-
-	return actions
-}
-
-func (i index) persist(bulk *elastic.BulkService) {
-	actions := bulk.NumberOfActions()
-	zap.S().Debugf("ElasticCache: Persisting %d actions", actions)
-
-	response, err := bulk.Refresh(i.refresh).Do(context.Background())
-	if err != nil {
-		time.Sleep(1 * time.Second)
-		response, err = bulk.Refresh(i.refresh).Do(context.Background())
-		if err != nil {
-			if err.Error() == "elastic: Error 429 (Too Many Requests)" {
-				zap.L().With(zap.Error(err)).Warn("ElasticCache: 429 (Too Many Requests)")
-				time.Sleep(5 * time.Second)
-				i.persist(bulk)
-				return
-			}
-			zap.L().With(zap.Error(err)).Fatal("ElasticCache: Failed to persist requests")
-		}
-	}
-
-	if len(response.Failed()) != 0 {
-		for _, failed := range response.Failed() {
-			zap.L().With(
-				zap.Any("error", failed.Error),
-				zap.String("index", failed.Index),
-				zap.String("id", failed.Id),
-			).Error("ElasticCache: Failed to persist request. Retying...")
-
-			i.Save(failed.Index, i.GetRequest(failed.Id).Entity)
-		}
 	}
 
-	i.flush()
+	return bulk
 }
 
-func (i index) flush() {
-	for _, req := range i.GetRequests() {
+func EmitEvents(requests []Request) {
+	for _, req := range requests {
 		if req.Action == Zrc1Mint || req.Action == Zrc1DuckRegeneration || req.Action == Zrc1UpdateTokenUri || req.Action == Zrc6Mint {
 			event.EmitEvent(event.NftMintedEvent, req.Entity)
 		}
@@ -367,5 +399,4 @@ func (i index) flush() {
 	}
 
 	zap.L().Debug("ElasticCache: Flushing ES cache")
-	i.cache.Flush()
 }
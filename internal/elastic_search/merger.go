@@ -1,82 +1,134 @@
 package elastic_search
 
 import (
+	"fmt"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
 	"go.uber.org/zap"
+	"reflect"
 )
 
-func mergeRequests(index string, cached Request, action RequestAction, e entity.Entity) entity.Entity {
-	switch {
-	case index == TransactionIndex.Get():
-		return cached.Entity.(entity.Transaction)
-
-	case index == ContractIndex.Get():
-		result := cached.Entity.(entity.Contract)
-		if action == ContractSetBaseUri {
-			result.BaseUri = e.(entity.Contract).BaseUri
-		} else {
-			result = e.(entity.Contract)
-		}
-		return result
-	case index == ContractStateIndex.Get():
-		result := cached.Entity.(entity.ContractState)
-		result.State = e.(entity.ContractState).State
-	case index == NftIndex.Get():
-		result := cached.Entity.(entity.Nft)
-		if action == Zrc1Transfer {
-			result.Owner = e.(entity.Nft).Owner
-		}
-
-		if action == Zrc1UpdateTokenUri {
-			result.TokenUri = e.(entity.Nft).TokenUri
-			result.Metadata.Uri = e.(entity.Nft).Metadata.Uri
-			result.Metadata.IsIpfs = e.(entity.Nft).Metadata.IsIpfs
-			result.Metadata.Status = e.(entity.Nft).Metadata.Status
-		}
-
-		if action == Zrc1DuckRegeneration {
-			result.AssetUri = e.(entity.Nft).AssetUri
-			result.TokenUri = e.(entity.Nft).TokenUri
-			result.Metadata = e.(entity.Nft).Metadata
-		}
-
-		if action == Zrc1Burn {
-			result.BurnedAt = e.(entity.Nft).BurnedAt
-		}
-
-		if action == Zrc6SetBaseUri {
-			result.TokenUri = e.(entity.Nft).TokenUri
-		}
-
-		if action == Zrc6SetTokenUri {
-			result.TokenUri = e.(entity.Nft).TokenUri
-			result.Metadata.Uri = e.(entity.Nft).Metadata.Uri
-			result.Metadata.IsIpfs = e.(entity.Nft).Metadata.IsIpfs
-			result.Metadata.Status = e.(entity.Nft).Metadata.Status
-		}
-
-		if action == Zrc6Transfer {
-			result.Owner = e.(entity.Nft).Owner
-		}
-
-		if action == Zrc6Burn {
-			result.BurnedAt = e.(entity.Nft).BurnedAt
-		}
-
-		if action == NftMetadata {
-			result.Metadata.Attempts = e.(entity.Nft).Metadata.Attempts
-			result.Metadata.Error = e.(entity.Nft).Metadata.Error
-			result.Metadata.Properties = e.(entity.Nft).Metadata.Properties
-		}
-
-		if action == NftDelegate {
-			result.IsDelegated = e.(entity.Nft).IsDelegated
-			result.DelegatedOwner = e.(entity.Nft).DelegatedOwner
-		}
-
-		return result
+// MergeFunc folds an update into the request already buffered for the same entity
+type MergeFunc func(cached entity.Entity, update entity.Entity, action RequestAction) entity.Entity
+
+var mergeFuncs = map[reflect.Type]MergeFunc{}
+
+func RegisterMergeFunc(e entity.Entity, merge MergeFunc) {
+	mergeFuncs[reflect.TypeOf(e)] = merge
+}
+
+func init() {
+	RegisterMergeFunc(entity.Transaction{}, mergeTransaction)
+	RegisterMergeFunc(entity.Contract{}, mergeContract)
+	RegisterMergeFunc(entity.ContractState{}, mergeContractState)
+	RegisterMergeFunc(entity.Nft{}, mergeNft)
+}
+
+func mergeRequests(cached Request, action RequestAction, e entity.Entity) entity.Entity {
+	merge, found := mergeFuncs[reflect.TypeOf(e)]
+	if !found {
+		zap.L().With(
+			zap.String("index", cached.Index),
+			zap.String("slug", e.Slug()),
+			zap.String("type", fmt.Sprintf("%T", e)),
+		).Warn("ElasticCache: No merge function registered, replacing request")
+		return e
+	}
+
+	return merge(cached.Entity, e, action)
+}
+
+func mergeTransaction(cached entity.Entity, _ entity.Entity, _ RequestAction) entity.Entity {
+	return cached.(entity.Transaction)
+}
+
+func mergeContract(cached entity.Entity, update entity.Entity, action RequestAction) entity.Entity {
+	result := cached.(entity.Contract)
+	if action == ContractSetBaseUri {
+		result.BaseUri = update.(entity.Contract).BaseUri
+	} else if action == Zrc2Supply {
+		result.Zrc2 = update.(entity.Contract).Zrc2
+	} else if action == ContractNftStats {
+		result.NftStats = update.(entity.Contract).NftStats
+	} else {
+		result = update.(entity.Contract)
+	}
+
+	return result
+}
+
+func mergeContractState(cached entity.Entity, update entity.Entity, _ RequestAction) entity.Entity {
+	result := cached.(entity.ContractState)
+	result.State = update.(entity.ContractState).State
+
+	return result
+}
+
+func mergeNft(cached entity.Entity, update entity.Entity, action RequestAction) entity.Entity {
+	result := cached.(entity.Nft)
+	if action == Zrc1Transfer {
+		result.Owner = update.(entity.Nft).Owner
+	}
+
+	if action == Zrc1UpdateTokenUri {
+		result.TokenUri = update.(entity.Nft).TokenUri
+		result.Metadata.Uri = update.(entity.Nft).Metadata.Uri
+		result.Metadata.IsIpfs = update.(entity.Nft).Metadata.IsIpfs
+		result.Metadata.Status = update.(entity.Nft).Metadata.Status
+	}
+
+	if action == Zrc1DuckRegeneration {
+		result.AssetUri = update.(entity.Nft).AssetUri
+		result.TokenUri = update.(entity.Nft).TokenUri
+		result.Metadata = update.(entity.Nft).Metadata
+	}
+
+	if action == Zrc1Burn {
+		result.BurnedAt = update.(entity.Nft).BurnedAt
+	}
+
+	if action == Zrc6SetBaseUri {
+		result.TokenUri = update.(entity.Nft).TokenUri
+	}
+
+	if action == Zrc6SetTokenUri {
+		result.TokenUri = update.(entity.Nft).TokenUri
+		result.Metadata.Uri = update.(entity.Nft).Metadata.Uri
+		result.Metadata.IsIpfs = update.(entity.Nft).Metadata.IsIpfs
+		result.Metadata.Status = update.(entity.Nft).Metadata.Status
+	}
+
+	if action == Zrc6Transfer {
+		result.Owner = update.(entity.Nft).Owner
+		result.Spender = update.(entity.Nft).Spender
+	}
+
+	if action == Zrc6Burn {
+		result.BurnedAt = update.(entity.Nft).BurnedAt
+		result.Spender = update.(entity.Nft).Spender
+	}
+
+	if action == Zrc6SetSpender {
+		result.Spender = update.(entity.Nft).Spender
+	}
+
+	if action == NftMetadata {
+		result.Metadata.Attempts = update.(entity.Nft).Metadata.Attempts
+		result.Metadata.Error = update.(entity.Nft).Metadata.Error
+		result.Metadata.Properties = update.(entity.Nft).Metadata.Properties
+	}
+
+	if action == NftDelegate {
+		result.IsDelegated = update.(entity.Nft).IsDelegated
+		result.DelegatedOwner = update.(entity.Nft).DelegatedOwner
+		result.Spender = update.(entity.Nft).Spender
+	}
+
+	if action == NftReconcile {
+		result.Owner = update.(entity.Nft).Owner
+		result.IsDelegated = update.(entity.Nft).IsDelegated
+		result.DelegatedOwner = update.(entity.Nft).DelegatedOwner
+		result.Spender = update.(entity.Nft).Spender
 	}
 
-	zap.L().Fatal("Failed to merge request")
-	return nil
+	return result
 }
@@ -0,0 +1,274 @@
+package elastic_search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/config"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/olivere/elastic/v7"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type MigrationType string
+
+const (
+	PutMappingMigration    MigrationType = "put_mapping"
+	ReindexMigration       MigrationType = "reindex"
+	UpdateByQueryMigration MigrationType = "update_by_query"
+)
+
+var (
+	ErrInvalidMigration = errors.New("invalid migration")
+)
+
+// Migration is a numbered step read from the migrations directory, e.g. 0002_nft_spender.json
+type Migration struct {
+	Version   int             `json:"-"`
+	Name      string          `json:"-"`
+	Index     Indices         `json:"index"`
+	Type      MigrationType   `json:"type"`
+	Mapping   json.RawMessage `json:"mapping"`
+	Query     json.RawMessage `json:"query"`
+	Script    string          `json:"script"`
+	AppliedAt *time.Time      `json:"-"`
+}
+
+func (i index) GetMigrations() ([]Migration, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := i.getMigrationState()
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := range migrations {
+		for _, applied := range state.Applied {
+			if applied.Version == migrations[idx].Version {
+				appliedAt := applied.AppliedAt
+				migrations[idx].AppliedAt = &appliedAt
+			}
+		}
+	}
+
+	return migrations, nil
+}
+
+func (i index) Migrate() error {
+	return i.migrate(map[string]bool{})
+}
+
+// migrate applies pending migrations in order. Migrations for an index created from the current mapping
+// files in this run are recorded without being applied, as the mapping already includes them.
+func (i index) migrate(created map[string]bool) error {
+	migrations, err := i.GetMigrations()
+	if err != nil {
+		return err
+	}
+
+	state, err := i.getMigrationState()
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		if migration.AppliedAt != nil {
+			continue
+		}
+
+		skipped := created[migration.Index.Alias()]
+		if skipped {
+			zap.L().With(zap.Int("version", migration.Version), zap.String("name", migration.Name)).
+				Info("ElasticCache: Migration not required for new index")
+		} else {
+			zap.L().With(zap.Int("version", migration.Version), zap.String("name", migration.Name), zap.String("type", string(migration.Type))).
+				Info("ElasticCache: Applying migration")
+			if err := i.applyMigration(migration); err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+		}
+
+		state.Version = migration.Version
+		state.Applied = append(state.Applied, entity.AppliedMigration{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Skipped:   skipped,
+			AppliedAt: time.Now(),
+		})
+		state.UpdatedAt = time.Now()
+
+		if err := i.saveMigrationState(*state); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i index) applyMigration(migration Migration) error {
+	ctx := context.Background()
+
+	switch migration.Type {
+	case PutMappingMigration:
+		_, err := i.client.PutMapping().
+			Index(migration.Index.Get()).
+			BodyString(string(migration.Mapping)).
+			Do(ctx)
+		return err
+
+	case UpdateByQueryMigration:
+		updateByQuery := i.client.UpdateByQuery(migration.Index.Get()).
+			Script(elastic.NewScript(migration.Script)).
+			ProceedOnVersionConflict().
+			WaitForCompletion(true).
+			Refresh("true")
+		if len(migration.Query) != 0 {
+			updateByQuery.Query(elastic.NewRawStringQuery(string(migration.Query)))
+		}
+		_, err := updateByQuery.Do(ctx)
+		return err
+
+	case ReindexMigration:
+		return i.reindexIntoNewVersion(migration)
+	}
+
+	return ErrInvalidMigration
+}
+
+// reindexIntoNewVersion copies the live index into a new version created from the current mapping file,
+// then moves the alias over to it.
+func (i index) reindexIntoNewVersion(migration Migration) error {
+	ctx := context.Background()
+	alias := migration.Index.Alias()
+
+	mapping, err := ioutil.ReadFile(fmt.Sprintf("%s/%s.json", config.Get().ElasticSearch.MappingDir, string(migration.Index)))
+	if err != nil {
+		return err
+	}
+
+	state, err := i.getAliasState(alias)
+	if err != nil {
+		return err
+	}
+
+	source := versionedIndex(alias, state.live)
+	target := versionedIndex(alias, state.latest()+1)
+	if err := i.createIndex(target, mapping); err != nil {
+		return err
+	}
+
+	reindex := i.client.Reindex().
+		SourceIndex(source).
+		DestinationIndex(target).
+		WaitForCompletion(true).
+		Refresh("true")
+	if migration.Script != "" {
+		reindex.Script(elastic.NewScript(migration.Script))
+	}
+	if _, err := reindex.Do(ctx); err != nil {
+		return err
+	}
+
+	_, err = i.client.Alias().Action(
+		elastic.NewAliasAddAction(alias).Index(target).IsWriteIndex(true),
+		elastic.NewAliasRemoveAction(alias).Index(source),
+	).Do(ctx)
+	if err != nil {
+		return err
+	}
+	versions.setPhysical(alias, target)
+
+	return i.pruneVersions(alias)
+}
+
+func (i index) getMigrationState() (*entity.MigrationState, error) {
+	state := &entity.MigrationState{Applied: make([]entity.AppliedMigration, 0)}
+
+	result, err := i.client.Get().
+		Index(MigrationIndex.Get()).
+		Id(state.Slug()).
+		Do(context.Background())
+	if err != nil {
+		if elastic.IsNotFound(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(result.Source, state); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+func (i index) saveMigrationState(state entity.MigrationState) error {
+	_, err := i.client.Index().
+		Index(MigrationIndex.Get()).
+		Id(state.Slug()).
+		BodyJson(state).
+		Refresh("true").
+		Do(context.Background())
+
+	return err
+}
+
+func loadMigrations() ([]Migration, error) {
+	migrations := make([]Migration, 0)
+
+	dir := config.Get().ElasticSearch.MigrationDir
+	if dir == "" {
+		return migrations, nil
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".json" {
+			continue
+		}
+
+		name := strings.TrimSuffix(f.Name(), filepath.Ext(f.Name()))
+		parts := strings.SplitN(name, "_", 2)
+		version, err := strconv.Atoi(parts[0])
+		if err != nil || len(parts) != 2 {
+			return nil, fmt.Errorf("%w: %s is not named <version>_<name>.json", ErrInvalidMigration, f.Name())
+		}
+
+		b, err := ioutil.ReadFile(fmt.Sprintf("%s/%s", dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration := Migration{Version: version, Name: parts[1]}
+		if err := json.Unmarshal(b, &migration); err != nil {
+			return nil, fmt.Errorf("%w: %s: %s", ErrInvalidMigration, f.Name(), err.Error())
+		}
+		migrations = append(migrations, migration)
+	}
+
+	sort.Slice(migrations, func(a, b int) bool {
+		return migrations[a].Version < migrations[b].Version
+	})
+
+	for idx := 1; idx < len(migrations); idx++ {
+		if migrations[idx].Version == migrations[idx-1].Version {
+			return nil, fmt.Errorf("%w: version %d is used twice", ErrInvalidMigration, migrations[idx].Version)
+		}
+	}
+
+	return migrations, nil
+}
@@ -0,0 +1,114 @@
+package elastic_search
+
+import (
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Requests buffers writes until the storage backend persists them. Backends embed it to share the
+// buffering, merging and read-your-writes lookups.
+type Requests struct {
+	buffer  *buffer
+	persist func() int
+}
+
+func NewRequests(maxSize int, persist func() int) *Requests {
+	return &Requests{newBuffer(maxSize), persist}
+}
+
+func (r *Requests) AddIndexRequest(index string, entity entity.Entity, reqAction RequestAction) {
+	zap.L().With(
+		zap.String("index", index),
+		zap.String("slug", entity.Slug()),
+		zap.String("action", string(reqAction)),
+	).Debug("ElasticCache: AddIndexRequest")
+
+	r.AddRequest(index, entity, IndexRequest, reqAction)
+}
+
+func (r *Requests) AddUpdateRequest(index string, entity entity.Entity, reqAction RequestAction) {
+	zap.L().With(
+		zap.String("index", index),
+		zap.String("slug", entity.Slug()),
+		zap.String("action", string(reqAction)),
+	).Debug("ElasticCache: AddUpdateRequest")
+
+	if cached, found := r.buffer.Get(index, entity.Slug()); found == true {
+		entity = mergeRequests(cached, reqAction, entity)
+		if cached.Type == IndexRequest {
+			r.AddRequest(index, entity, IndexRequest, reqAction)
+			return
+		}
+	}
+
+	r.AddRequest(index, entity, UpdateRequest, reqAction)
+}
+
+func (r *Requests) HasRequest(index string, entity entity.Entity) bool {
+	_, found := r.buffer.Get(index, entity.Slug())
+
+	return found
+}
+
+func (r *Requests) AddRequest(index string, entity entity.Entity, reqType RequestType, reqAction RequestAction) {
+	r.buffer.Set(Request{index, entity, reqType, reqAction})
+
+	if r.buffer.Full() {
+		zap.L().With(zap.Int("actions", r.buffer.Len())).Info("ElasticCache: Buffer full, persisting data")
+		r.persist()
+	}
+}
+
+func (r *Requests) GetEntitiesByIndex(index string) []entity.Entity {
+	entities := make([]entity.Entity, 0)
+	for _, req := range r.GetRequests() {
+		if req.Index == index {
+			entities = append(entities, req.Entity)
+		}
+	}
+
+	return entities
+}
+
+func (r *Requests) GetRequests() []Request {
+	return r.buffer.Requests()
+}
+
+func (r *Requests) GetRequest(index, id string) *Request {
+	if req, found := r.buffer.Get(index, id); found == true {
+		return &req
+	} else {
+		return nil
+	}
+}
+
+func (r *Requests) ClearRequests() {
+	r.buffer.Clear()
+}
+
+func (r *Requests) BatchPersist() bool {
+	if r.buffer.Len() < 250 {
+		return false
+	}
+
+	actions := r.buffer.Len()
+	start := time.Now()
+	r.persist()
+
+	zap.L().With(
+		zap.Duration("elapsed", time.Since(start)),
+		zap.Int("actions", actions),
+	).Info("ElasticCache: Persisting data")
+
+	return true
+}
+
+// Pending returns the buffered requests in write order, to be handed back to Release once persisted
+func (r *Requests) Pending() ([]Request, []uint64) {
+	return r.buffer.snapshot()
+}
+
+func (r *Requests) Release(requests []Request, sequences []uint64) {
+	r.buffer.release(requests, sequences)
+}
@@ -0,0 +1,270 @@
+package elastic_search
+
+import (
+	"context"
+	"fmt"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/config"
+	"github.com/olivere/elastic/v7"
+	"go.uber.org/zap"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Each index is served through an alias (zilliqa.mainnet.nft) pointing at a versioned physical index
+// (zilliqa.mainnet.nft.v7), which is its write index. Processes read and write through the alias, so they follow
+// it when a reindex swaps it. Only the reindexing process writes to the physical index it is building, and goes
+// back to the alias once that index is promoted.
+type indexVersions struct {
+	sync.RWMutex
+	physical map[string]string
+	pending  map[string]string
+}
+
+var versions = indexVersions{
+	physical: map[string]string{},
+	pending:  map[string]string{},
+}
+
+func (v *indexVersions) getPhysical(alias string) (string, bool) {
+	v.RLock()
+	defer v.RUnlock()
+
+	physical, found := v.physical[alias]
+
+	return physical, found
+}
+
+func (v *indexVersions) setPhysical(alias, physical string) {
+	v.Lock()
+	defer v.Unlock()
+
+	v.physical[alias] = physical
+}
+
+func (v *indexVersions) unsetPhysical(alias string) {
+	v.Lock()
+	defer v.Unlock()
+
+	delete(v.physical, alias)
+}
+
+func (v *indexVersions) setPending(alias, physical string) {
+	v.Lock()
+	defer v.Unlock()
+
+	v.pending[alias] = physical
+}
+
+func (v *indexVersions) takePending() map[string]string {
+	v.Lock()
+	defer v.Unlock()
+
+	pending := v.pending
+	v.pending = map[string]string{}
+
+	return pending
+}
+
+func versionedIndex(alias string, version int) string {
+	return fmt.Sprintf("%s.v%d", alias, version)
+}
+
+func aliasPrefix() string {
+	return fmt.Sprintf("%s.%s.", config.Get().Network, config.Get().Index)
+}
+
+type aliasState struct {
+	legacy   bool
+	live     int
+	versions []int
+	aliased  []string
+}
+
+func (s aliasState) latest() int {
+	if len(s.versions) == 0 {
+		return 0
+	}
+
+	return s.versions[len(s.versions)-1]
+}
+
+func (i index) getAliasState(alias string) (*aliasState, error) {
+	result, err := i.client.Aliases().Index(alias + "*").Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	state := &aliasState{versions: make([]int, 0), aliased: make([]string, 0)}
+	for physical, info := range result.Indices {
+		if physical == alias {
+			state.legacy = true
+			continue
+		}
+
+		version, err := strconv.Atoi(strings.TrimPrefix(physical, alias+".v"))
+		if err != nil || !strings.HasPrefix(physical, alias+".v") {
+			continue
+		}
+		state.versions = append(state.versions, version)
+
+		if info.HasAlias(alias) {
+			state.aliased = append(state.aliased, physical)
+			if version > state.live {
+				state.live = version
+			}
+		}
+	}
+	sort.Ints(state.versions)
+
+	return state, nil
+}
+
+// installIndex creates the physical index of an alias when required, and when reindexing creates the next version
+// to build into. Returns true when the index written to was created from the current mapping.
+func (i index) installIndex(alias string, mapping []byte) (bool, error) {
+	state, err := i.getAliasState(alias)
+	if err != nil {
+		return false, err
+	}
+
+	if state.legacy {
+		if err := i.migrateLegacyIndex(alias, mapping, state.latest()+1); err != nil {
+			return false, err
+		}
+		if state, err = i.getAliasState(alias); err != nil {
+			return false, err
+		}
+	}
+
+	if state.live == 0 {
+		physical := versionedIndex(alias, state.latest()+1)
+		if err := i.createIndex(physical, mapping); err != nil {
+			return false, err
+		}
+		if _, err := i.client.Alias().Action(elastic.NewAliasAddAction(alias).Index(physical).IsWriteIndex(true)).Do(context.Background()); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	if !config.Get().Reindex {
+		return false, nil
+	}
+
+	build := state.latest()
+	if build > state.live {
+		zap.S().Infof("ElasticCache: Resuming reindex into %s", versionedIndex(alias, build))
+	} else {
+		build++
+		if err := i.createIndex(versionedIndex(alias, build), mapping); err != nil {
+			return false, err
+		}
+	}
+
+	versions.setPhysical(alias, versionedIndex(alias, build))
+	versions.setPending(alias, versionedIndex(alias, build))
+
+	return true, nil
+}
+
+// migrateLegacyIndex copies an index created before aliases were introduced into its first version,
+// then replaces the legacy index with the alias in a single step.
+func (i index) migrateLegacyIndex(alias string, mapping []byte, version int) error {
+	physical := versionedIndex(alias, version)
+	zap.S().Infof("ElasticCache: Migrating %s to %s", alias, physical)
+
+	if err := i.createIndex(physical, mapping); err != nil {
+		return err
+	}
+
+	_, err := i.client.Reindex().
+		SourceIndex(alias).
+		DestinationIndex(physical).
+		WaitForCompletion(true).
+		Refresh("true").
+		Do(context.Background())
+	if err != nil {
+		return err
+	}
+
+	_, err = i.client.Alias().Action(
+		elastic.NewAliasAddAction(alias).Index(physical).IsWriteIndex(true),
+		elastic.NewAliasRemoveIndexAction(alias),
+	).Do(context.Background())
+
+	return err
+}
+
+func (i index) PromoteIndices() {
+	pending := versions.takePending()
+	if len(pending) == 0 {
+		return
+	}
+
+	aliasService := i.client.Alias()
+	for alias, physical := range pending {
+		state, err := i.getAliasState(alias)
+		if err != nil {
+			zap.L().With(zap.Error(err), zap.String("alias", alias)).Fatal("ElasticCache: Failed to get alias state")
+		}
+
+		aliasService.Action(elastic.NewAliasAddAction(alias).Index(physical).IsWriteIndex(true))
+		for _, previous := range state.aliased {
+			if previous != physical {
+				aliasService.Action(elastic.NewAliasRemoveAction(alias).Index(previous))
+			}
+		}
+	}
+
+	if _, err := aliasService.Do(context.Background()); err != nil {
+		zap.L().With(zap.Error(err)).Fatal("ElasticCache: Failed to swap aliases")
+	}
+
+	for alias, physical := range pending {
+		versions.unsetPhysical(alias)
+		zap.S().Infof("ElasticCache: %s now serves %s", alias, physical)
+		if err := i.pruneVersions(alias); err != nil {
+			zap.L().With(zap.Error(err), zap.String("alias", alias)).Error("ElasticCache: Failed to prune old indices")
+		}
+	}
+}
+
+// Previous versions kept after a reindex when RetainVersions is not configured, so a process still holding one is
+// not left writing to a deleted index
+const defaultRetainVersions = 1
+
+// pruneVersions deletes versions older than the live index, keeping the most recent ones as configured
+func (i index) pruneVersions(alias string) error {
+	state, err := i.getAliasState(alias)
+	if err != nil {
+		return err
+	}
+
+	previous := make([]int, 0)
+	for _, version := range state.versions {
+		if version < state.live {
+			previous = append(previous, version)
+		}
+	}
+
+	retain := config.Get().ElasticSearch.RetainVersions
+	if retain < 0 {
+		return nil
+	}
+	if retain == 0 {
+		retain = defaultRetainVersions
+	}
+
+	for idx := 0; idx < len(previous)-retain; idx++ {
+		physical := versionedIndex(alias, previous[idx])
+		zap.S().Infof("ElasticCache: Deleting index %s", physical)
+		if _, err := i.client.DeleteIndex(physical).Do(context.Background()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"fmt"
+	"github.com/gosimple/slug"
+	"time"
+)
+
+// Address holds the running ZIL balance and nonce of an account, applied from the transactions indexed up to
+// BlockNum and corrected against the node when reconciled
+type Address struct {
+	Address      string    `json:"address"`
+	Bech32       string    `json:"bech32"`
+	Balance      string    `json:"balance"`
+	Nonce        uint64    `json:"nonce"`
+	BlockNum     uint64    `json:"blockNum"`
+	ReconciledAt time.Time `json:"reconciledAt"`
+}
+
+func (a Address) Slug() string {
+	return CreateAddressSlug(a.Address)
+}
+
+func CreateAddressSlug(address string) string {
+	return slug.Make(fmt.Sprintf("address-%s", address))
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"fmt"
+	"github.com/gosimple/slug"
+	"time"
+)
+
+// Block is the header of a tx block
+type Block struct {
+	BlockNum       uint64    `json:"blockNum"`
+	DsBlockNum     uint64    `json:"dsBlockNum"`
+	Hash           string    `json:"hash"`
+	PrevBlockHash  string    `json:"prevBlockHash"`
+	MinerPubKey    string    `json:"minerPubKey"`
+	GasLimit       uint64    `json:"gasLimit"`
+	GasUsed        uint64    `json:"gasUsed"`
+	Rewards        string    `json:"rewards"`
+	NumMicroBlocks int       `json:"numMicroBlocks"`
+	NumTxns        int       `json:"numTxns"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+func (b Block) Slug() string {
+	return CreateBlockSlug(b.BlockNum)
+}
+
+func CreateBlockSlug(blockNum uint64) string {
+	return slug.Make(fmt.Sprintf("block-%d", blockNum))
+}
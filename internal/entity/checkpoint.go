@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"fmt"
+	"github.com/gosimple/slug"
+	"time"
+)
+
+// Checkpoint records how far a pipeline stage has indexed. Page is only set while a bulk import is
+// paging through the documents from BlockNum, so an interrupted import can resume where it stopped.
+type Checkpoint struct {
+	Stage     Stage     `json:"stage"`
+	BlockNum  uint64    `json:"blockNum"`
+	Page      int       `json:"page"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type Stage string
+
+const (
+	TransactionStage Stage = "transactions"
+	ContractStage    Stage = "contracts"
+	Zrc1Stage        Stage = "zrc1"
+	Zrc6Stage        Stage = "zrc6"
+	Zrc2Stage        Stage = "zrc2"
+	MarketplaceStage Stage = "marketplace"
+
+	// Page cursors for the bulk imports
+	NftImportStage         Stage = "nftImport"
+	MarketplaceImportStage Stage = "marketplaceImport"
+	Zrc2ImportStage        Stage = "zrc2Import"
+)
+
+var (
+	Stages = []Stage{
+		TransactionStage,
+		ContractStage,
+		Zrc1Stage,
+		Zrc6Stage,
+		Zrc2Stage,
+		MarketplaceStage,
+		NftImportStage,
+		MarketplaceImportStage,
+		Zrc2ImportStage,
+	}
+)
+
+func (c Checkpoint) Slug() string {
+	return CreateCheckpointSlug(c.Stage)
+}
+
+func CreateCheckpointSlug(stage Stage) string {
+	return slug.Make(fmt.Sprintf("checkpoint-%s", stage))
+}
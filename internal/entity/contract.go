@@ -15,10 +15,14 @@ type Contract struct {
 	MutableParams   Params               `json:"mutableParams"`
 	ImmutableParams Params               `json:"immutableParams"`
 	Transitions     []ContractTransition `json:"transitions"`
+	Procedures      []ContractTransition `json:"procedures"`
+	Events          []ContractEvent      `json:"events"`
 	Standards       map[ZrcStandard]bool `json:"standards"`
 
 	//mutable
-	BaseUri string `json:"baseuri"`
+	BaseUri  string     `json:"baseuri"`
+	Zrc2     *Zrc2Token `json:"zrc2,omitempty"`
+	NftStats *NftStats  `json:"nftStats,omitempty"`
 
 	CustomIpfs *string `json:"customIpfs"`
 }
@@ -38,6 +42,21 @@ func (c Contract) MatchesStandard(standard ZrcStandard) bool {
 	return false
 }
 
+// NftStats counts the live and burned tokens of an NFT contract, and the addresses holding the live ones
+type NftStats struct {
+	Supply  int64 `json:"supply"`
+	Burned  int64 `json:"burned"`
+	Holders int64 `json:"holders"`
+}
+
+// Zrc2Token holds the details of a fungible token, amounts being in its smallest unit
+type Zrc2Token struct {
+	Symbol      string `json:"symbol"`
+	Decimals    uint32 `json:"decimals"`
+	InitSupply  string `json:"initSupply"`
+	TotalSupply string `json:"totalSupply"`
+}
+
 type ContractTransition struct {
 	Index     int                          `json:"index"`
 	Name      string                       `json:"name"`
@@ -49,6 +68,14 @@ type ContractTransitionArgument struct {
 	Value string `json:"value"`
 }
 
+// ContractEvent is an event the contract's code emits, with the names of its params. Component is the transition
+// or procedure building it, empty when built in the library.
+type ContractEvent struct {
+	Name      string   `json:"name"`
+	Params    []string `json:"params"`
+	Component string   `json:"component"`
+}
+
 type Event string
 
 const (
@@ -59,11 +86,29 @@ const (
 	ZRC1BurnEvent             Event = "BurnSuccess"
 	ZRC1MorphTokenURIsUpdated Event = "TokenURIsUpdated"
 
-	ZRC6MintEvent         Event = "Mint"
-	ZRC6BatchMintEvent    Event = "BatchMint"
-	ZRC6SetBaseURIEvent   Event = "SetBaseURI"
-	ZRC6TransferFromEvent Event = "TransferFrom"
-	ZRC6BurnEvent         Event = "Burn"
+	ZRC2TransferEvent     Event = "TransferSuccess"
+	ZRC2TransferFromEvent Event = "TransferFromSuccess"
+	ZRC2MintedEvent       Event = "Minted"
+	ZRC2BurntEvent        Event = "Burnt"
+
+	ZRC6MintEvent                          Event = "Mint"
+	ZRC6BatchMintEvent                     Event = "BatchMint"
+	ZRC6SetBaseURIEvent                    Event = "SetBaseURI"
+	ZRC6TransferFromEvent                  Event = "TransferFrom"
+	ZRC6BatchTransferFromEvent             Event = "BatchTransferFrom"
+	ZRC6BurnEvent                          Event = "Burn"
+	ZRC6BatchBurnEvent                     Event = "BatchBurn"
+	ZRC6SetSpenderEvent                    Event = "SetSpender"
+	ZRC6AddOperatorEvent                   Event = "AddOperator"
+	ZRC6RemoveOperatorEvent                Event = "RemoveOperator"
+	ZRC6SetRoyaltyRecipientEvent           Event = "SetRoyaltyRecipient"
+	ZRC6SetRoyaltyFeeBPSEvent              Event = "SetRoyaltyFeeBPS"
+	ZRC6AddMinterEvent                     Event = "AddMinter"
+	ZRC6RemoveMinterEvent                  Event = "RemoveMinter"
+	ZRC6SetContractOwnershipRecipientEvent Event = "SetContractOwnershipRecipient"
+	ZRC6AcceptContractOwnershipEvent       Event = "AcceptContractOwnership"
+	ZRC6PauseEvent                         Event = "Pause"
+	ZRC6UnpauseEvent                       Event = "Unpause"
 
 	MpOkiListingEvent     Event = "TransferSuccess"
 	MpOkiDelistingEvent   Event = "TransferSuccess"
@@ -87,14 +132,26 @@ const (
 	ZRC1RecipientAcceptTransfer Callback = "RecipientAcceptTransfer"
 	ZRC1BurnCallBack            Callback = "BurnCallBack"
 
-	ZRC6MintCallback                Callback = "ZRC6_MintCallback"
-	ZRC6BatchMintCallback           Callback = "ZRC6_BatchMintCallback"
-	ZRC6SetBaseURICallback          Callback = "ZRC6_SetBaseURICallback"
-	ZRC6RecipientAcceptTransferFrom Callback = "ZRC6_RecipientAcceptTransferFrom"
-	ZRC6BurnCallback                Callback = "ZRC6_BurnCallback"
-	ZRC6BatchBurnCallback           Callback = "ZRC6_BatchBurnCallback"
-	ZRC6SetTokenURICallback        Callback = "ZRC6_SetTokenURICallback"
-	ZRC6BatchSetTokenURICallback   Callback = "ZRC6_BatchSetTokenURICallback"
+	ZRC6MintCallback                          Callback = "ZRC6_MintCallback"
+	ZRC6BatchMintCallback                     Callback = "ZRC6_BatchMintCallback"
+	ZRC6SetBaseURICallback                    Callback = "ZRC6_SetBaseURICallback"
+	ZRC6RecipientAcceptTransferFrom           Callback = "ZRC6_RecipientAcceptTransferFrom"
+	ZRC6BatchTransferFromCallback             Callback = "ZRC6_BatchTransferFromCallback"
+	ZRC6BurnCallback                          Callback = "ZRC6_BurnCallback"
+	ZRC6BatchBurnCallback                     Callback = "ZRC6_BatchBurnCallback"
+	ZRC6SetTokenURICallback                   Callback = "ZRC6_SetTokenURICallback"
+	ZRC6BatchSetTokenURICallback              Callback = "ZRC6_BatchSetTokenURICallback"
+	ZRC6SetSpenderCallback                    Callback = "ZRC6_SetSpenderCallback"
+	ZRC6AddOperatorCallback                   Callback = "ZRC6_AddOperatorCallback"
+	ZRC6RemoveOperatorCallback                Callback = "ZRC6_RemoveOperatorCallback"
+	ZRC6SetRoyaltyRecipientCallback           Callback = "ZRC6_SetRoyaltyRecipientCallback"
+	ZRC6SetRoyaltyFeeBPSCallback              Callback = "ZRC6_SetRoyaltyFeeBPSCallback"
+	ZRC6AddMinterCallback                     Callback = "ZRC6_AddMinterCallback"
+	ZRC6RemoveMinterCallback                  Callback = "ZRC6_RemoveMinterCallback"
+	ZRC6SetContractOwnershipRecipientCallback Callback = "ZRC6_SetContractOwnershipRecipientCallback"
+	ZRC6AcceptContractOwnershipCallback       Callback = "ZRC6_AcceptContractOwnershipCallback"
+	ZRC6PauseCallback                         Callback = "ZRC6_PauseCallback"
+	ZRC6UnpauseCallback                       Callback = "ZRC6_UnpauseCallback"
 )
 
 
@@ -115,9 +172,21 @@ var (
 		ZRC6BatchMintCallback,
 		ZRC6SetBaseURICallback,
 		ZRC6RecipientAcceptTransferFrom,
+		ZRC6BatchTransferFromCallback,
 		ZRC6BurnCallback,
 		ZRC6BatchBurnCallback,
 		ZRC6SetTokenURICallback,
 		ZRC6BatchSetTokenURICallback,
+		ZRC6SetSpenderCallback,
+		ZRC6AddOperatorCallback,
+		ZRC6RemoveOperatorCallback,
+		ZRC6SetRoyaltyRecipientCallback,
+		ZRC6SetRoyaltyFeeBPSCallback,
+		ZRC6AddMinterCallback,
+		ZRC6RemoveMinterCallback,
+		ZRC6SetContractOwnershipRecipientCallback,
+		ZRC6AcceptContractOwnershipCallback,
+		ZRC6PauseCallback,
+		ZRC6UnpauseCallback,
 	}
 )
\ No newline at end of file
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DeadLetter holds a request that could not be persisted, so it can be fixed and replayed
+type DeadLetter struct {
+	Index       string          `json:"index"`
+	DocId       string          `json:"docId"`
+	RequestType string          `json:"requestType"`
+	Action      string          `json:"action"`
+	Document    json.RawMessage `json:"document"`
+	ErrorType   string          `json:"errorType"`
+	Reason      string          `json:"reason"`
+	BlockNum    uint64          `json:"blockNum"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
+func (d DeadLetter) Slug() string {
+	return CreateDeadLetterSlug(d.Index, d.DocId)
+}
+
+func CreateDeadLetterSlug(index, docId string) string {
+	data := []byte(fmt.Sprintf("deadletter-%s-%s", index, docId))
+	return fmt.Sprintf("%x", md5.Sum(data))
+}
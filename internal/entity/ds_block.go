@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"fmt"
+	"github.com/gosimple/slug"
+	"time"
+)
+
+// DsBlock is the header of a directory service block, which starts each epoch of tx blocks
+type DsBlock struct {
+	BlockNum     uint64    `json:"blockNum"`
+	Difficulty   int       `json:"difficulty"`
+	DifficultyDS int       `json:"difficultyDs"`
+	GasPrice     string    `json:"gasPrice"`
+	LeaderPubKey string    `json:"leaderPubKey"`
+	PoWWinners   []string  `json:"powWinners"`
+	PrevHash     string    `json:"prevHash"`
+	Signature    string    `json:"signature"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+func (b DsBlock) Slug() string {
+	return CreateDsBlockSlug(b.BlockNum)
+}
+
+func CreateDsBlockSlug(blockNum uint64) string {
+	return slug.Make(fmt.Sprintf("dsblock-%d", blockNum))
+}
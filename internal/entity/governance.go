@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"crypto/md5"
+	"fmt"
+	"time"
+)
+
+// GovernanceEvent is a change an owner made to the administration of a ZRC6 contract. Value holds the address or
+// fee set by the event, and is empty for pause and unpause.
+type GovernanceEvent struct {
+	Contract   string           `json:"contract"`
+	TxID       string           `json:"txId"`
+	EventIndex int              `json:"eventIndex"`
+	BlockNum   uint64           `json:"blockNum"`
+	Timestamp  time.Time        `json:"timestamp"`
+	Action     GovernanceAction `json:"action"`
+	Value      string           `json:"value"`
+}
+
+type GovernanceAction string
+
+const (
+	RoyaltyRecipientAction   GovernanceAction = "royaltyRecipient"
+	RoyaltyFeeBpsAction      GovernanceAction = "royaltyFeeBps"
+	AddMinterAction          GovernanceAction = "addMinter"
+	RemoveMinterAction       GovernanceAction = "removeMinter"
+	OwnershipRecipientAction GovernanceAction = "ownershipRecipient"
+	AcceptOwnershipAction    GovernanceAction = "acceptOwnership"
+	PauseAction              GovernanceAction = "pause"
+	UnpauseAction            GovernanceAction = "unpause"
+)
+
+func (g GovernanceEvent) Slug() string {
+	return CreateGovernanceEventSlug(g.Contract, g.TxID, g.EventIndex)
+}
+
+func CreateGovernanceEventSlug(contract, txId string, eventIndex int) string {
+	data := []byte(fmt.Sprintf("governance-%s-%s-%d", contract, txId, eventIndex))
+	return fmt.Sprintf("%x", md5.Sum(data))
+}
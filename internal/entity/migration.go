@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// MigrationState records which mapping migrations have been applied to the indices of a network
+type MigrationState struct {
+	Version   int                `json:"version"`
+	Applied   []AppliedMigration `json:"applied"`
+	UpdatedAt time.Time          `json:"updatedAt"`
+}
+
+type AppliedMigration struct {
+	Version   int       `json:"version"`
+	Name      string    `json:"name"`
+	Skipped   bool      `json:"skipped"`
+	AppliedAt time.Time `json:"appliedAt"`
+}
+
+func (m MigrationState) Slug() string {
+	return "migration-state"
+}
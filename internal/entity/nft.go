@@ -3,18 +3,21 @@ package entity
 import (
 	"fmt"
 	"github.com/gosimple/slug"
+	"time"
 )
 
 type Nft struct {
 	Contract    string    `json:"contract"`
 	TxID        string    `json:"txId"`
 	BlockNum    uint64    `json:"blockNum"`
+	MintedAt    time.Time `json:"mintedAt"`
 	Name        string    `json:"name"`
 	Symbol      string    `json:"symbol"`
 	TokenId     uint64    `json:"tokenId"`
 	BaseUri     string    `json:"baseUri"`
 	TokenUri    string    `json:"tokenUri"`
 	Owner       string    `json:"owner"`
+	Spender     string    `json:"spender"`
 	BurnedAt    uint64    `json:"burnedAt"`
 	Zrc1        bool      `json:"zrc1"`
 	Zrc6        bool      `json:"zrc6"`
@@ -3,6 +3,7 @@ package entity
 import (
 	"crypto/md5"
 	"fmt"
+	"time"
 )
 
 type NftAction struct {
@@ -10,6 +11,7 @@ type NftAction struct {
 	TokenId     uint64     `json:"tokenId"`
 	TxID        string     `json:"txId"`
 	BlockNum    uint64     `json:"blockNum"`
+	Timestamp   time.Time  `json:"timestamp"`
 	Action      ActionType `json:"action"`
 	From        string     `json:"from"`
 	To          string     `json:"to"`
@@ -32,8 +34,14 @@ const (
 	MarketplaceSaleAction      ActionType = "sale"
 	MarketplaceListingAction   ActionType = "listing"
 	MarketplaceDelistingAction ActionType = "delisting"
-)
+	ReconcileAction            ActionType = "reconcile"
 
+	// Changes which are not transfers record the previous value in From and the new value in To
+	DelegateAction ActionType = "delegate"
+	SpenderAction  ActionType = "spender"
+	TokenUriAction ActionType = "tokenUri"
+	BaseUriAction  ActionType = "baseUri"
+)
 
 func (n NftAction) Slug() string {
 	return CreateNftActionSlug(n.TokenId, n.Contract, n.TxID, string(n.Action))
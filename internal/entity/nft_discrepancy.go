@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"fmt"
+	"github.com/gosimple/slug"
+	"time"
+)
+
+// NftDiscrepancy is a token whose indexed owner differs from the contract's token_owners, as last seen when the
+// contract was reconciled
+type NftDiscrepancy struct {
+	Contract     string            `json:"contract"`
+	TokenId      uint64            `json:"tokenId"`
+	Kind         DiscrepancyKind   `json:"kind"`
+	Status       DiscrepancyStatus `json:"status"`
+	IndexedOwner string            `json:"indexedOwner"`
+	ChainOwner   string            `json:"chainOwner"`
+	BlockNum     uint64            `json:"blockNum"`
+	FirstSeenAt  time.Time         `json:"firstSeenAt"`
+	LastSeenAt   time.Time         `json:"lastSeenAt"`
+	RepairedAt   time.Time         `json:"repairedAt"`
+}
+
+type DiscrepancyKind string
+
+const (
+	// DiscrepancyOwner is an nft indexed with another owner
+	DiscrepancyOwner DiscrepancyKind = "owner"
+	// DiscrepancyNotIndexed is a token on chain missing from the nft index
+	DiscrepancyNotIndexed DiscrepancyKind = "notIndexed"
+	// DiscrepancyNotOnChain is an nft indexed as unburned which the contract no longer has
+	DiscrepancyNotOnChain DiscrepancyKind = "notOnChain"
+)
+
+type DiscrepancyStatus string
+
+const (
+	DiscrepancyOpen     DiscrepancyStatus = "open"
+	DiscrepancyRepaired DiscrepancyStatus = "repaired"
+	DiscrepancyResolved DiscrepancyStatus = "resolved"
+)
+
+func (d NftDiscrepancy) Slug() string {
+	return CreateNftDiscrepancySlug(d.TokenId, d.Contract)
+}
+
+func CreateNftDiscrepancySlug(tokenId uint64, contract string) string {
+	return slug.Make(fmt.Sprintf("nftdiscrepancy-%d-%s", tokenId, contract))
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"fmt"
+	"github.com/gosimple/slug"
+)
+
+// Operator is an address an owner allowed to move all of their tokens of a ZRC6 contract. Grants are kept once
+// removed, no longer active, and hold every change made to them so a rollback can restore an earlier one.
+type Operator struct {
+	Contract string           `json:"contract"`
+	Owner    string           `json:"owner"`
+	Operator string           `json:"operator"`
+	Active   bool             `json:"active"`
+	TxID     string           `json:"txId"`
+	BlockNum uint64           `json:"blockNum"`
+	History  []OperatorChange `json:"history,omitempty"`
+}
+
+type OperatorChange struct {
+	Active   bool   `json:"active"`
+	TxID     string `json:"txId"`
+	BlockNum uint64 `json:"blockNum"`
+}
+
+func (o Operator) Slug() string {
+	return CreateOperatorSlug(o.Contract, o.Owner, o.Operator)
+}
+
+func CreateOperatorSlug(contract, owner, operator string) string {
+	return slug.Make(fmt.Sprintf("operator-%s-%s-%s", contract, owner, operator))
+}
+
+// Approval is an address allowed to move the tokens of an owner, either a single token as its spender or all of
+// them as an operator
+type Approval struct {
+	Contract   string `json:"contract"`
+	Owner      string `json:"owner"`
+	Approved   string `json:"approved"`
+	TokenId    uint64 `json:"tokenId"`
+	IsOperator bool   `json:"isOperator"`
+}
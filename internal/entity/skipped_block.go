@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"fmt"
+	"github.com/gosimple/slug"
+	"time"
+)
+
+// SkippedBlock records a block the node could not serve after retrying, so indexing can continue without it
+// and the block can be repaired once the node recovers
+type SkippedBlock struct {
+	BlockNum      uint64    `json:"blockNum"`
+	Reason        string    `json:"reason"`
+	Attempts      int       `json:"attempts"`
+	CreatedAt     time.Time `json:"createdAt"`
+	LastAttemptAt time.Time `json:"lastAttemptAt"`
+}
+
+func (s SkippedBlock) Slug() string {
+	return CreateSkippedBlockSlug(s.BlockNum)
+}
+
+func CreateSkippedBlockSlug(blockNum uint64) string {
+	return slug.Make(fmt.Sprintf("skippedblock-%d", blockNum))
+}
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"fmt"
+	"github.com/gosimple/slug"
+)
+
+// TokenBalance is the ZRC2 balance of a holder, in the token's smallest unit
+type TokenBalance struct {
+	Contract string `json:"contract"`
+	Holder   string `json:"holder"`
+	Balance  string `json:"balance"`
+	BlockNum uint64 `json:"blockNum"`
+}
+
+func (b TokenBalance) Slug() string {
+	return CreateTokenBalanceSlug(b.Contract, b.Holder)
+}
+
+func CreateTokenBalanceSlug(contract, holder string) string {
+	return slug.Make(fmt.Sprintf("tokenbalance-%s-%s", contract, holder))
+}
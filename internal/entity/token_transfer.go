@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"crypto/md5"
+	"fmt"
+	"time"
+)
+
+// TokenTransfer is a movement of ZRC2 tokens, a mint having no sender and a burn no recipient. The initial supply is
+// credited to the contract owner by an init transfer at the deploy, which has no event so an EventIndex of -1.
+type TokenTransfer struct {
+	Contract   string          `json:"contract"`
+	TxID       string          `json:"txId"`
+	EventIndex int             `json:"eventIndex"`
+	BlockNum   uint64          `json:"blockNum"`
+	Timestamp  time.Time       `json:"timestamp"`
+	Action     TokenActionType `json:"action"`
+	Initiator  string          `json:"initiator"`
+	From       string          `json:"from"`
+	To         string          `json:"to"`
+	Amount     string          `json:"amount"`
+
+	// Reverted is set by a rollback once the transfer is taken back off the balances, until it is purged
+	Reverted bool `json:"reverted,omitempty"`
+}
+
+type TokenActionType string
+
+const (
+	TokenMintAction     TokenActionType = "mint"
+	TokenTransferAction TokenActionType = "transfer"
+	TokenBurnAction     TokenActionType = "burn"
+	TokenInitAction     TokenActionType = "init"
+)
+
+func (t TokenTransfer) Slug() string {
+	return CreateTokenTransferSlug(t.Contract, t.TxID, t.EventIndex)
+}
+
+func CreateTokenTransferSlug(contract, txId string, eventIndex int) string {
+	data := []byte(fmt.Sprintf("tokentransfer-%s-%s-%d", contract, txId, eventIndex))
+	return fmt.Sprintf("%x", md5.Sum(data))
+}
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/zilliqa"
 	"github.com/gosimple/slug"
+	"time"
 )
 
 type Transaction struct {
 	zilliqa.Transaction
 
-	BlockNum uint64 `json:"BlockNum"`
+	BlockNum  uint64    `json:"BlockNum"`
+	Timestamp time.Time `json:"Timestamp"`
 
 	Code    string             `json:"Code,omitempty"`
 	Data    Data               `json:"Data,omitempty"`
@@ -18,11 +20,18 @@ type Transaction struct {
 
 	IsContractCreation    bool   `json:"ContractCreation"`
 	IsContractExecution   bool   `json:"ContractExecution"`
+	IsPayment             bool   `json:"Payment"`
+	IsFailed              bool   `json:"Failed"`
 	ContractAddress       string `json:"ContractAddress,omitempty"`
 	ContractAddressBech32 string `json:"ContractAddressBech32,omitempty"`
 
-	SenderAddr   string `json:"SenderAddr"`
-	SenderBech32 string `json:"SenderBech32"`
+	SenderAddr      string `json:"SenderAddr"`
+	SenderBech32    string `json:"SenderBech32"`
+	RecipientAddr   string `json:"RecipientAddr,omitempty"`
+	RecipientBech32 string `json:"RecipientBech32,omitempty"`
+
+	// Fee is the gas used multiplied by the gas price, in Qa
+	Fee string `json:"Fee"`
 }
 
 type Data struct {
@@ -32,8 +41,16 @@ type Data struct {
 
 type TransactionReceipt struct {
 	zilliqa.TransactionReceipt
-	Transitions []Transition `json:"transitions"`
-	EventLogs   []EventLog   `json:"event_logs"`
+	Transitions []Transition   `json:"transitions"`
+	EventLogs   []EventLog     `json:"event_logs"`
+	ErrorCodes  []ReceiptError `json:"error_codes,omitempty"`
+}
+
+// ReceiptError is an error the node raised for a failed call, at the depth of the call chain it was raised
+type ReceiptError struct {
+	Depth int    `json:"depth"`
+	Code  int    `json:"code"`
+	Name  string `json:"name"`
 }
 
 type EventLog struct {
@@ -73,6 +90,25 @@ func (tx Transaction) GetZrc1EventLogs() []EventLog {
 	return eventLogs
 }
 
+// GetZrc2Addresses returns the addresses of the contracts that emitted ZRC2 events
+func (tx Transaction) GetZrc2Addresses() []string {
+	addresses := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, event := range tx.Receipt.EventLogs {
+		if event.EventName != string(ZRC2TransferEvent) &&
+			event.EventName != string(ZRC2TransferFromEvent) &&
+			event.EventName != string(ZRC2MintedEvent) &&
+			event.EventName != string(ZRC2BurntEvent) {
+			continue
+		}
+		if !seen[event.Address] {
+			seen[event.Address] = true
+			addresses = append(addresses, event.Address)
+		}
+	}
+	return addresses
+}
+
 func (tx Transaction) GetEventLogs(eventName Event) []EventLog {
 	eventLogs := make([]EventLog, 0)
 	for _, event := range tx.Receipt.EventLogs {
@@ -1,71 +1,79 @@
 package factory
 
-import "github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
-
+import (
+	"fmt"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"time"
+)
 
 func CreateMintAction(nft entity.Nft) entity.NftAction {
 	return entity.NftAction{
-		Contract: nft.Contract,
-		TokenId:  nft.TokenId,
-		TxID:     nft.TxID,
-		BlockNum: nft.BlockNum,
-		Action:   entity.MintAction,
-		From:     "",
-		To:       nft.Owner,
-		Zrc1:     nft.Zrc1,
-		Zrc6:     nft.Zrc6,
+		Contract:  nft.Contract,
+		TokenId:   nft.TokenId,
+		TxID:      nft.TxID,
+		BlockNum:  nft.BlockNum,
+		Timestamp: nft.MintedAt,
+		Action:    entity.MintAction,
+		From:      "",
+		To:        nft.Owner,
+		Zrc1:      nft.Zrc1,
+		Zrc6:      nft.Zrc6,
 	}
 }
 
-func CreateTransferAction(nft entity.Nft, blockNum uint64, txId, buyer, seller string) entity.NftAction {
+func CreateTransferAction(nft entity.Nft, tx entity.Transaction, buyer, seller string) entity.NftAction {
 	return entity.NftAction{
-		Contract: nft.Contract,
-		TokenId:  nft.TokenId,
-		TxID:     txId,
-		BlockNum: blockNum,
-		Action:   entity.TransferAction,
-		From:     seller,
-		To:       buyer,
-		Zrc1:     nft.Zrc1,
-		Zrc6:     nft.Zrc6,
+		Contract:  nft.Contract,
+		TokenId:   nft.TokenId,
+		TxID:      tx.ID,
+		BlockNum:  tx.BlockNum,
+		Timestamp: tx.Timestamp,
+		Action:    entity.TransferAction,
+		From:      seller,
+		To:        buyer,
+		Zrc1:      nft.Zrc1,
+		Zrc6:      nft.Zrc6,
 	}
 }
 
-func CreateMarketplaceListingAction(marketplace entity.Marketplace, nft entity.Nft, blockNum uint64, txId string, cost string, fungible string) entity.NftAction {
+func CreateMarketplaceListingAction(marketplace entity.Marketplace, nft entity.Nft, tx entity.Transaction, cost string, fungible string) entity.NftAction {
 	return entity.NftAction{
 		Marketplace: string(marketplace),
-		Contract: nft.Contract,
-		TokenId:  nft.TokenId,
-		TxID:     txId,
-		BlockNum: blockNum,
-		Action:   entity.MarketplaceListingAction,
-		Zrc1:     nft.Zrc1,
-		Zrc6:     nft.Zrc6,
-		Cost:     cost,
-		Fungible: fungible,
+		Contract:    nft.Contract,
+		TokenId:     nft.TokenId,
+		TxID:        tx.ID,
+		BlockNum:    tx.BlockNum,
+		Timestamp:   tx.Timestamp,
+		Action:      entity.MarketplaceListingAction,
+		Zrc1:        nft.Zrc1,
+		Zrc6:        nft.Zrc6,
+		Cost:        cost,
+		Fungible:    fungible,
 	}
 }
 
-func CreateMarketplaceDelistingAction(marketplace entity.Marketplace, nft entity.Nft, blockNum uint64, txId string) entity.NftAction {
+func CreateMarketplaceDelistingAction(marketplace entity.Marketplace, nft entity.Nft, tx entity.Transaction) entity.NftAction {
 	return entity.NftAction{
 		Marketplace: string(marketplace),
-		Contract: nft.Contract,
-		TokenId:  nft.TokenId,
-		TxID:     txId,
-		BlockNum: blockNum,
-		Action:   entity.MarketplaceDelistingAction,
-		Zrc1:     nft.Zrc1,
-		Zrc6:     nft.Zrc6,
+		Contract:    nft.Contract,
+		TokenId:     nft.TokenId,
+		TxID:        tx.ID,
+		BlockNum:    tx.BlockNum,
+		Timestamp:   tx.Timestamp,
+		Action:      entity.MarketplaceDelistingAction,
+		Zrc1:        nft.Zrc1,
+		Zrc6:        nft.Zrc6,
 	}
 }
 
-func CreateMarketplaceSaleAction(marketplace entity.Marketplace, nft entity.Nft, blockNum uint64, txId, buyer, seller, cost, fee, royalty, royaltyBps, fungible string) entity.NftAction {
+func CreateMarketplaceSaleAction(marketplace entity.Marketplace, nft entity.Nft, tx entity.Transaction, buyer, seller, cost, fee, royalty, royaltyBps, fungible string) entity.NftAction {
 	return entity.NftAction{
 		Marketplace: string(marketplace),
 		Contract:    nft.Contract,
 		TokenId:     nft.TokenId,
-		TxID:        txId,
-		BlockNum:    blockNum,
+		TxID:        tx.ID,
+		BlockNum:    tx.BlockNum,
+		Timestamp:   tx.Timestamp,
 		Action:      entity.MarketplaceSaleAction,
 		From:        seller,
 		To:          buyer,
@@ -81,14 +89,48 @@ func CreateMarketplaceSaleAction(marketplace entity.Marketplace, nft entity.Nft,
 
 func CreateBurnAction(nft entity.Nft, tx entity.Transaction) entity.NftAction {
 	return entity.NftAction{
-		Contract: nft.Contract,
-		TokenId:  nft.TokenId,
-		TxID:     tx.ID,
-		BlockNum: tx.BlockNum,
-		Action:   entity.BurnAction,
-		From:     nft.Owner,
-		To:       "",
-		Zrc1:     nft.Zrc1,
-		Zrc6:     nft.Zrc6,
+		Contract:  nft.Contract,
+		TokenId:   nft.TokenId,
+		TxID:      tx.ID,
+		BlockNum:  tx.BlockNum,
+		Timestamp: tx.Timestamp,
+		Action:    entity.BurnAction,
+		From:      nft.Owner,
+		To:        "",
+		Zrc1:      nft.Zrc1,
+		Zrc6:      nft.Zrc6,
+	}
+}
+
+// CreateChangeAction records a change to an nft other than its owner, such as its delegation, spender or uris
+func CreateChangeAction(action entity.ActionType, nft entity.Nft, tx entity.Transaction, from, to string) entity.NftAction {
+	return entity.NftAction{
+		Contract:  nft.Contract,
+		TokenId:   nft.TokenId,
+		TxID:      tx.ID,
+		BlockNum:  tx.BlockNum,
+		Timestamp: tx.Timestamp,
+		Action:    action,
+		From:      from,
+		To:        to,
+		Zrc1:      nft.Zrc1,
+		Zrc6:      nft.Zrc6,
+	}
+}
+
+// CreateReconcileAction records an owner corrected against the contract state. Having no transaction, the action is
+// identified by the block the state was read at.
+func CreateReconcileAction(nft entity.Nft, blockNum uint64, from, to string) entity.NftAction {
+	return entity.NftAction{
+		Contract:  nft.Contract,
+		TokenId:   nft.TokenId,
+		TxID:      fmt.Sprintf("reconcile-%d", blockNum),
+		BlockNum:  blockNum,
+		Timestamp: time.Now().UTC(),
+		Action:    entity.ReconcileAction,
+		From:      from,
+		To:        to,
+		Zrc1:      nft.Zrc1,
+		Zrc6:      nft.Zrc6,
 	}
 }
@@ -0,0 +1,15 @@
+package factory
+
+import (
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+)
+
+// CreateAddress creates an address first seen in the indexed transactions. It has never been reconciled, so is
+// the first to be checked against the node.
+func CreateAddress(address string) entity.Address {
+	return entity.Address{
+		Address: address,
+		Bech32:  GetBech32Address(address),
+		Balance: "0",
+	}
+}
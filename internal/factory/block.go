@@ -0,0 +1,65 @@
+package factory
+
+import (
+	"fmt"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/zilliqa"
+	"go.uber.org/zap"
+	"strconv"
+	"time"
+)
+
+func CreateBlock(txBlock zilliqa.TxBlock) entity.Block {
+	return entity.Block{
+		BlockNum:       parseUint(txBlock.Header.BlockNum),
+		DsBlockNum:     parseUint(txBlock.Header.DSBlockNum),
+		Hash:           txBlock.Body.BlockHash,
+		PrevBlockHash:  txBlock.Header.PrevBlockHash,
+		MinerPubKey:    txBlock.Header.MinerPubKey,
+		GasLimit:       parseUint(txBlock.Header.GasLimit),
+		GasUsed:        parseUint(txBlock.Header.GasUsed),
+		Rewards:        txBlock.Header.Rewards,
+		NumMicroBlocks: txBlock.Header.NumMicroBlocks,
+		NumTxns:        txBlock.Header.NumTxns,
+		Timestamp:      parseTimestamp(txBlock.Header.Timestamp),
+	}
+}
+
+func CreateDsBlock(dsBlock zilliqa.DSBlock) entity.DsBlock {
+	powWinners := make([]string, 0, len(dsBlock.Header.PoWWinners))
+	for _, powWinner := range dsBlock.Header.PoWWinners {
+		powWinners = append(powWinners, fmt.Sprintf("%v", powWinner))
+	}
+
+	return entity.DsBlock{
+		BlockNum:     parseUint(dsBlock.Header.BlockNum),
+		Difficulty:   dsBlock.Header.Difficulty,
+		DifficultyDS: dsBlock.Header.DifficultyDS,
+		GasPrice:     dsBlock.Header.GasPrice,
+		LeaderPubKey: dsBlock.Header.LeaderPubKey,
+		PoWWinners:   powWinners,
+		PrevHash:     dsBlock.Header.PrevHash,
+		Signature:    dsBlock.Signature,
+		Timestamp:    parseTimestamp(dsBlock.Header.Timestamp),
+	}
+}
+
+func parseUint(value string) uint64 {
+	number, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.String("value", value)).Warn("Failed to parse block header number")
+	}
+
+	return number
+}
+
+// Block timestamps are microseconds since the epoch
+func parseTimestamp(value string) time.Time {
+	micros, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.String("value", value)).Warn("Failed to parse block timestamp")
+		return time.Time{}
+	}
+
+	return time.Unix(0, micros*int64(time.Microsecond)).UTC()
+}
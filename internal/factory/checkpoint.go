@@ -0,0 +1,15 @@
+package factory
+
+import (
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"time"
+)
+
+func CreateCheckpoint(stage entity.Stage, blockNum uint64, page int) entity.Checkpoint {
+	return entity.Checkpoint{
+		Stage:     stage,
+		BlockNum:  blockNum,
+		Page:      page,
+		UpdatedAt: time.Now(),
+	}
+}
@@ -1,9 +1,11 @@
 package factory
 
 import (
+	"context"
 	"errors"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/scilla"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/zilliqa"
 	"go.uber.org/zap"
 	"regexp"
@@ -11,26 +13,25 @@ import (
 )
 
 type ContractFactory interface {
-	CreateContractFromTx(tx entity.Transaction) (*entity.Contract, error)
+	CreateContractFromTx(ctx context.Context, tx entity.Transaction) (*entity.Contract, error)
 }
 
 type contractFactory struct {
 	zilliqa zilliqa.Service
+	shapes  ContractShapeRegistry
 }
 
-func NewContractFactory(zilliqa zilliqa.Service) ContractFactory {
-	return contractFactory{zilliqa}
+func NewContractFactory(zilliqa zilliqa.Service, shapes ContractShapeRegistry) ContractFactory {
+	return contractFactory{zilliqa, shapes}
 }
 
-func (f contractFactory) CreateContractFromTx(tx entity.Transaction) (*entity.Contract, error) {
-	contractName := f.getContractName(tx.Code)
-
+func (f contractFactory) CreateContractFromTx(ctx context.Context, tx entity.Transaction) (*entity.Contract, error) {
 	if tx.ContractAddress == "" || tx.ContractAddress == "0x" {
 		zap.L().With(zap.String("txId", tx.ID)).Warn("ContractAddr Missing from Tx")
 		return nil, errors.New("missing contract addr")
 	}
 
-	contractValues, err := f.getSmartContractInit(tx, 1, nil)
+	contractValues, err := f.getSmartContractInit(ctx, tx, 1, nil)
 	if err != nil {
 		zap.L().With(
 			zap.Error(err),
@@ -45,18 +46,27 @@ func (f contractFactory) CreateContractFromTx(tx entity.Transaction) (*entity.Co
 		BlockNum:        tx.BlockNum,
 		Code:            tx.Code,
 		Data:            tx.Data,
-		Name:            contractName,
 		ImmutableParams: f.getImmutableParams(contractValues),
-		MutableParams:   f.getMutableParams(tx.Code),
-		Transitions:     f.getTransitions(tx.Code),
-		Standards:       map[entity.ZrcStandard]bool{},
 	}
 
-	c.Standards[entity.ZRC1] = IsZrc1(*c)
-	c.Standards[entity.ZRC2] = IsZrc2(*c)
-	c.Standards[entity.ZRC3] = IsZrc3(*c)
-	c.Standards[entity.ZRC4] = IsZrc4(*c)
-	c.Standards[entity.ZRC6] = IsZrc6(*c)
+	if source, err := scilla.Parse(tx.Code); err == nil {
+		c.Name = source.Name
+		c.MutableParams = f.getFields(source)
+		c.Transitions = f.getComponents(source.Transitions)
+		c.Procedures = f.getComponents(source.Procedures)
+		c.Events = f.getEvents(source)
+	} else {
+		zap.L().With(zap.Error(err), zap.String("contract", tx.ContractAddress)).
+			Warn("Failed to parse contract code, matching its declarations instead")
+		c.Name = f.getContractName(tx.Code)
+		c.MutableParams = f.getMutableParams(tx.Code)
+		c.Transitions = f.getTransitions(tx.Code)
+	}
+	c.Standards = f.shapes.GetStandards(*c)
+
+	if c.MatchesStandard(entity.ZRC2) {
+		c.Zrc2 = CreateZrc2Token(*c)
+	}
 
 	if c.MatchesStandard(entity.ZRC6) {
 		if initialBaseUri, err := tx.Data.Params.GetParam("initial_base_uri"); err == nil {
@@ -67,7 +77,7 @@ func (f contractFactory) CreateContractFromTx(tx entity.Transaction) (*entity.Co
 	return c, nil
 }
 
-func (f contractFactory) getSmartContractInit(tx entity.Transaction, attempt int, err error) ([]zilliqa.ContractValue, error) {
+func (f contractFactory) getSmartContractInit(ctx context.Context, tx entity.Transaction, attempt int, err error) ([]zilliqa.ContractValue, error) {
 	zap.L().With(zap.String("contract", tx.ContractAddress)).Debug("Get contract state")
 	if attempt >= repository.MaxRetries {
 		if err == nil {
@@ -77,12 +87,12 @@ func (f contractFactory) getSmartContractInit(tx entity.Transaction, attempt int
 	}
 
 	contractValues := make([]zilliqa.ContractValue, 0)
-	if contractValues, err = f.zilliqa.GetSmartContractInit(tx.ContractAddress[2:]); err != nil {
+	if contractValues, err = f.zilliqa.GetSmartContractInit(ctx, tx.ContractAddress[2:]); err != nil {
 		zap.L().With(zap.Error(err), zap.String("txID", tx.ID), zap.String("contract", tx.ContractAddress)).Warn("GetSmartContractInit")
 
 		if err.Error() == "-5:Address does not exist" {
 			time.Sleep(2 * time.Second)
-			return f.getSmartContractInit(tx, attempt+1, err)
+			return f.getSmartContractInit(ctx, tx, attempt+1, err)
 		}
 		return nil, err
 	}
@@ -112,6 +122,43 @@ func (f contractFactory) getImmutableParams(coreParams []zilliqa.ContractValue)
 	return
 }
 
+func (f contractFactory) getFields(source *scilla.Contract) (params entity.Params) {
+	for _, field := range source.Fields {
+		params = append(params, entity.Param{
+			VName: field.Name,
+			Type:  field.Type.String(),
+		})
+	}
+	return
+}
+
+func (f contractFactory) getComponents(components []scilla.Component) []entity.ContractTransition {
+	transitions := make([]entity.ContractTransition, 0)
+	for idx, component := range components {
+		transition := entity.ContractTransition{
+			Index:     idx,
+			Name:      component.Name,
+			Arguments: make([]entity.ContractTransitionArgument, 0),
+		}
+		for _, param := range component.Params {
+			transition.Arguments = append(transition.Arguments, entity.ContractTransitionArgument{Key: param.Name, Value: param.Type.String()})
+		}
+		transitions = append(transitions, transition)
+	}
+
+	return transitions
+}
+
+func (f contractFactory) getEvents(source *scilla.Contract) []entity.ContractEvent {
+	events := make([]entity.ContractEvent, 0)
+	for _, event := range source.Events {
+		events = append(events, entity.ContractEvent{Name: event.Name, Params: event.Params, Component: event.Component})
+	}
+
+	return events
+}
+
+// getMutableParams, getTransitions and getContractName match the declarations of code the parser cannot read
 func (f contractFactory) getMutableParams(code string) (params entity.Params) {
 	r := regexp.MustCompile("(?m)^field ([a-zA-Z0-9_]*)( :|:) ([a-zA-Z0-9][\\(a-zA-Z0-9 ]*[a-zA-Z0-9\\)])")
 	for _, field := range r.FindAllStringSubmatch(code, -1) {
@@ -1,224 +1,225 @@
 package factory
 
 import (
-	"github.com/ZilDuck/zilliqa-chain-indexer/internal/config"
+	"errors"
+	"fmt"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/spf13/viper"
 	"go.uber.org/zap"
+	"io/ioutil"
+	"path/filepath"
 	"strings"
 )
 
-func CreateContractTransition(name string, args ...string) entity.ContractTransition {
-	transition := entity.ContractTransition{
-		Name:      name,
-		Arguments: make([]entity.ContractTransitionArgument, 0),
-	}
-	for _, arg := range args {
-		s := strings.Split(arg, ":")
-		transition.Arguments = append(transition.Arguments, entity.ContractTransitionArgument{Key: s[0], Value: s[1]})
-	}
+// ContractShape lists the params and transitions a contract declares when it implements a standard. Contracts
+// listed in its addresses for the configured index implement the standard whatever their shape.
+type ContractShape struct {
+	Name            string
+	Standard        entity.ZrcStandard
+	ImmutableParams []string
+	MutableParams   []string
+	Transitions     []ContractShapeTransition
+	Addresses       map[string][]string
+}
 
-	return transition
+type ContractShapeTransition struct {
+	Name      string
+	Arguments []string
 }
 
-func IsZrc1(c entity.Contract) bool {
-	for _, additionals := range config.Get().AdditionalZrc1 {
-		if additionals == c.Address {
-			return true
-		}
-	}
+// ShapeMatch is the result of checking a contract against a shape, with the rules it failed
+type ShapeMatch struct {
+	Shape     string
+	Standard  entity.ZrcStandard
+	Matched   bool
+	ByAddress bool
+	Failures  []string
+}
 
-	if c.Address == "0xd793f378a925b9f0d3c4b6ee544d31c707899386" {
-		// The Bear Market
-		return true
-	}
-	if c.Address == "0x06f70655d4aa5819e711563eb2383655449f24e9" {
-		// NFD
-		return true
-	}
-	if c.Address == "0xea4757c8ba3c8063878c35d0b0eb05c7bce892a1" {
-		// Unicutes
-		return true
-	}
-	if c.Address == "0x852c4105660ab288d0df8b2491f7462c66a1c0ae" {
-		// Zilmorphs
-		return true
-	}
+type ContractShapeRegistry interface {
+	Match(c entity.Contract) []ShapeMatch
+	GetStandards(c entity.Contract) map[entity.ZrcStandard]bool
+}
+
+type contractShapeRegistry struct {
+	shapes     []ContractShape
+	network    string
+	additional map[entity.ZrcStandard][]string
+}
 
-	if !c.ImmutableParams.HasParamWithType("contract_owner", "ByStr20") ||
-		!c.ImmutableParams.HasParamWithType("name", "String") ||
-		!c.ImmutableParams.HasParamWithType("symbol", "String") {
-		zap.L().Warn("Fails immutables")
-		return false
+// NewContractShapeRegistry loads the shapes from the yaml files of a directory. The additional addresses implement
+// their standard on any network.
+func NewContractShapeRegistry(dir, network string, additional map[entity.ZrcStandard][]string) (ContractShapeRegistry, error) {
+	shapes, err := LoadContractShapes(dir)
+	if err != nil {
+		return nil, err
 	}
 
-	if !c.MutableParams.HasParamWithType("minters", "Map ByStr20 Dummy") ||
-		!c.MutableParams.HasParamWithType("token_owners", "Map Uint256 ByStr20") ||
-		!c.MutableParams.HasParamWithType("owned_token_count", "Map ByStr20 Uint256") ||
-		!c.MutableParams.HasParamWithType("token_approvals", "Map Uint256 ByStr20") ||
-		!c.MutableParams.HasParamWithType("operator_approvals", "Map ByStr20 (Map ByStr20 Dummy)") ||
-		!c.MutableParams.HasParamWithType("token_uris", "Map Uint256 String") ||
-		!c.MutableParams.HasParamWithType("total_supply", "Uint256") {
-		zap.L().Warn("Fails mutables")
-		return false
+	return contractShapeRegistry{shapes, network, additional}, nil
+}
+
+func LoadContractShapes(dir string) ([]ContractShape, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
 	}
 
-	if !hasTransition(c, CreateContractTransition("Mint", "to:ByStr20", "token_uri:String")) ||
-		!hasTransition(c, CreateContractTransition("Transfer", "to:ByStr20", "token_id:Uint256")) ||
-		!hasTransition(c, CreateContractTransition("Burn", "token_id:Uint256")) ||
-		!hasTransition(c, CreateContractTransition("TransferFrom", "to:ByStr20", "token_id:Uint256")) {
-		zap.L().Warn("Fails transitions")
-		if hasTransition(c, CreateContractTransition("Mint", "to:ByStr20", "token_id:Uint256", "token_uri:String")) &&
-			hasTransition(c, CreateContractTransition("Transfer", "to:ByStr20", "token_id:Uint256")) &&
-			hasTransition(c, CreateContractTransition("Burn", "token_id:Uint256")) &&
-			hasTransition(c, CreateContractTransition("TransferFrom", "to:ByStr20", "token_id:Uint256")) {
-			zap.L().Warn("Fixes transitions")
-			// It's an old mintable zrc1
-			return true
+	shapes := make([]ContractShape, 0)
+	for _, f := range files {
+		if f.IsDir() || (filepath.Ext(f.Name()) != ".yaml" && filepath.Ext(f.Name()) != ".yml") {
+			continue
+		}
+
+		v := viper.New()
+		v.SetConfigFile(filepath.Join(dir, f.Name()))
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read contract shapes %s: %w", f.Name(), err)
 		}
 
-		return false
+		var file struct {
+			Shapes []ContractShape
+		}
+		if err := v.Unmarshal(&file); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal contract shapes %s: %w", f.Name(), err)
+		}
+
+		for _, shape := range file.Shapes {
+			if err := validateShape(shape); err != nil {
+				return nil, fmt.Errorf("invalid contract shape in %s: %w", f.Name(), err)
+			}
+		}
+		shapes = append(shapes, file.Shapes...)
 	}
 
-	return true
+	if len(shapes) == 0 {
+		return nil, errors.New("no contract shapes found in " + dir)
+	}
+
+	return shapes, nil
 }
 
-func IsZrc2(c entity.Contract) bool {
-	if !c.ImmutableParams.HasParamWithType("contract_owner", "ByStr20") ||
-		!c.ImmutableParams.HasParamWithType("name", "String") ||
-		!c.ImmutableParams.HasParamWithType("symbol", "String") ||
-		!c.ImmutableParams.HasParamWithType("decimals", "Uint32") ||
-		!c.ImmutableParams.HasParamWithType("init_supply", "Uint128") {
-		return false
+func validateShape(shape ContractShape) error {
+	if shape.Name == "" || shape.Standard == "" {
+		return errors.New("shape requires a name and a standard")
 	}
 
-	if !c.MutableParams.HasParamWithType("total_supply", "Uint128") ||
-		!c.MutableParams.HasParamWithType("balances", "Map ByStr20 Uint128") ||
-		!c.MutableParams.HasParamWithType("allowances", "Map ByStr20 (Map ByStr20 Uint128)") {
-		return false
+	rules := append(append([]string{}, shape.ImmutableParams...), shape.MutableParams...)
+	for _, transition := range shape.Transitions {
+		rules = append(rules, transition.Arguments...)
 	}
-
-	if !hasTransition(c, CreateContractTransition("IncreaseAllowance", "spender:ByStr20", "amount:Uint128")) ||
-	    !hasTransition(c, CreateContractTransition("DecreaseAllowance", "spender:ByStr20", "amount:Uint128")) ||
-		!hasTransition(c, CreateContractTransition("Transfer", "to:ByStr20", "amount:Uint128")) ||
-		!hasTransition(c, CreateContractTransition("TransferFrom", "from:ByStr20", "to:ByStr20", "amount:Uint128")) {
-		return false
+	for _, rule := range rules {
+		if !strings.Contains(rule, ":") {
+			return fmt.Errorf("%s: %s is not name:type", shape.Name, rule)
+		}
 	}
 
-	return true
+	return nil
 }
 
-func IsZrc3(c entity.Contract) bool {
-	if !c.ImmutableParams.HasParamWithType("contract_owner", "ByStr20") ||
-		!c.ImmutableParams.HasParamWithType("name", "String") ||
-		!c.ImmutableParams.HasParamWithType("symbol", "String") ||
-		!c.ImmutableParams.HasParamWithType("decimals", "Uint32") ||
-		!c.ImmutableParams.HasParamWithType("init_supply", "Uint128") {
-		return false
-	}
+func (r contractShapeRegistry) Match(c entity.Contract) []ShapeMatch {
+	matches := make([]ShapeMatch, 0)
 
-	if !c.MutableParams.HasParamWithType("total_supply", "Uint128") ||
-		!c.MutableParams.HasParamWithType("balances", "Map ByStr20 Uint128") ||
-		!c.MutableParams.HasParamWithType("allowances", "Map ByStr20 (Map ByStr20 Uint128)") ||
-		!c.MutableParams.HasParamWithType("void_cheques", "Map ByStr ByStr20") {
-		return false
+	for standard, addresses := range r.additional {
+		if containsAddress(addresses, c.Address) {
+			matches = append(matches, ShapeMatch{Shape: "additional", Standard: standard, Matched: true, ByAddress: true})
+		}
 	}
 
-	if !hasTransition(c, CreateContractTransition("IncreaseAllowance", "spender:ByStr20", "amount:Uint128")) ||
-	    !hasTransition(c, CreateContractTransition("DecreaseAllowance", "spender:ByStr20", "amount:Uint128")) ||
-		!hasTransition(c, CreateContractTransition("Transfer", "to:ByStr20", "amount:Uint128")) ||
-		!hasTransition(c, CreateContractTransition("TransferFrom", "from:ByStr20", "to:ByStr20", "amount:Uint128")) ||
-		!hasTransition(c, CreateContractTransition("ChequeSend", "pubkey:ByStr20", "to:ByStr20", "amount:Uint128", "fee:Uint128", "nonce:Uint218", "signature:ByStr64")) {
-		return false
+	for _, shape := range r.shapes {
+		match := ShapeMatch{Shape: shape.Name, Standard: shape.Standard}
+
+		if containsAddress(shape.Addresses[r.network], c.Address) {
+			match.Matched = true
+			match.ByAddress = true
+			matches = append(matches, match)
+			continue
+		}
+
+		for _, param := range shape.ImmutableParams {
+			s := strings.SplitN(param, ":", 2)
+			if !c.ImmutableParams.HasParamWithType(s[0], s[1]) {
+				match.Failures = append(match.Failures, "immutable param "+param)
+			}
+		}
+		for _, param := range shape.MutableParams {
+			s := strings.SplitN(param, ":", 2)
+			if !c.MutableParams.HasParamWithType(s[0], s[1]) {
+				match.Failures = append(match.Failures, "mutable param "+param)
+			}
+		}
+		for _, transition := range shape.Transitions {
+			if !hasTransition(c, CreateContractTransition(transition.Name, transition.Arguments...)) {
+				match.Failures = append(match.Failures, fmt.Sprintf("transition %s(%s)", transition.Name, strings.Join(transition.Arguments, ", ")))
+			}
+		}
+
+		match.Matched = len(match.Failures) == 0
+		matches = append(matches, match)
 	}
 
-	return true
+	return matches
 }
 
-func IsZrc4(c entity.Contract) bool {
-	if !c.ImmutableParams.HasParamWithType("owners_list", "List ByStr20") ||
-		!c.ImmutableParams.HasParamWithType("required_signatures", "Uint32") {
-		return false
-	}
+// GetStandards returns whether the contract implements each standard, being so when any of its shapes matched
+func (r contractShapeRegistry) GetStandards(c entity.Contract) map[entity.ZrcStandard]bool {
+	standards := map[entity.ZrcStandard]bool{}
 
-	if !c.MutableParams.HasParamWithType("owners", "Map ByStr20 Bool") ||
-		!c.MutableParams.HasParamWithType("transactionCount", "Uint32") ||
-		!c.MutableParams.HasParamWithType("signatures", "Map Uint32 (Map ByStr20 Bool)") ||
-		!c.MutableParams.HasParamWithType("signature_counts", "Map Uint32 Uint32") ||
-		!c.MutableParams.HasParamWithType("transactions", "Map Uint32 Transaction") {
-		return false
-	}
+	for _, match := range r.Match(c) {
+		standards[match.Standard] = standards[match.Standard] || match.Matched
 
-	if !hasTransition(c, CreateContractTransition("SubmitTransaction", "recipient:ByStr20", "amount:Uint128", "tag:String)")) ||
-	    !hasTransition(c, CreateContractTransition("SignTransaction", "transactionId:Uint32")) ||
-		!hasTransition(c, CreateContractTransition("ExecuteTransaction", "transactionId:Uint32")) ||
-		!hasTransition(c, CreateContractTransition("RevokeSignature", "transactionId:Uint32")) ||
-		!hasTransition(c, CreateContractTransition("AddFunds")) {
-		return false
+		if !match.Matched {
+			zap.L().With(zap.String("contract", c.Address), zap.String("shape", match.Shape), zap.Strings("failures", match.Failures)).
+				Debug("Contract does not match shape")
+		}
 	}
 
-	return true
+	return standards
 }
 
-func IsZrc6(c entity.Contract) bool {
-	for _, additionals := range config.Get().AdditionalZrc6 {
-		if additionals == c.Address {
+func containsAddress(addresses []string, address string) bool {
+	for _, a := range addresses {
+		if strings.ToLower(a) == strings.ToLower(address) {
 			return true
 		}
 	}
 
-	if c.Address == "0xd2b54e791930dd7d06ea51f3c2a6cf2c00f165ea" {
-		// beanterra
-		return true
-	}
-
-	if !c.ImmutableParams.HasParamWithType("initial_contract_owner", "ByStr20") ||
-		!c.ImmutableParams.HasParamWithType("initial_base_uri", "String") {
-		return false
-	}
+	return false
+}
 
-	if !c.MutableParams.HasParamWithType("contract_owner", "ByStr20") ||
-		!c.MutableParams.HasParamWithType("base_uri", "String") ||
-		!c.MutableParams.HasParamWithType("minters", "Map ByStr20 Bool") ||
-		!c.MutableParams.HasParamWithType("token_owners", "Map Uint256 ByStr20") ||
-		!c.MutableParams.HasParamWithType("spenders", "Map Uint256 ByStr20") ||
-		!c.MutableParams.HasParamWithType("operators", "Map ByStr20 (Map ByStr20 Bool)") ||
-		!c.MutableParams.HasParamWithType("token_id_count", "Uint256") ||
-		!c.MutableParams.HasParamWithType("balances", "Map ByStr20 Uint256") ||
-		!c.MutableParams.HasParamWithType("total_supply", "Uint256") {
-		return false
+func CreateContractTransition(name string, args ...string) entity.ContractTransition {
+	transition := entity.ContractTransition{
+		Name:      name,
+		Arguments: make([]entity.ContractTransitionArgument, 0),
 	}
-
-	if	!hasTransition(c, CreateContractTransition("Mint", "to:ByStr20", "token_uri:String")) ||
-		!hasTransition(c, CreateContractTransition("AddMinter", "minter:ByStr20")) ||
-		!hasTransition(c, CreateContractTransition("RemoveMinter", "minter:ByStr20")) ||
-		!hasTransition(c, CreateContractTransition("SetSpender", "spender:ByStr20", "token_id:Uint256")) ||
-		!hasTransition(c, CreateContractTransition("AddOperator", "operator:ByStr20")) ||
-		!hasTransition(c, CreateContractTransition("RemoveOperator", "operator:ByStr20")) ||
-		!hasTransition(c, CreateContractTransition("TransferFrom", "to:ByStr20", "token_id:Uint256")) {
-		return false
+	for _, arg := range args {
+		s := strings.Split(arg, ":")
+		transition.Arguments = append(transition.Arguments, entity.ContractTransitionArgument{Key: s[0], Value: s[1]})
 	}
 
-	return true
+	return transition
 }
 
+// hasTransition is whether the contract declares the transition with all its arguments. An address argument
+// satisfies the type it is an address of.
 func hasTransition(c entity.Contract, transition entity.ContractTransition) bool {
 	for _, t := range c.Transitions {
-		if t.Name != transition.Name {
-			continue
-		}
-
-		if len(t.Arguments) != len(transition.Arguments) {
+		if t.Name != transition.Name || len(t.Arguments) != len(transition.Arguments) {
 			continue
 		}
 
+		matched := 0
 		for _, arg := range transition.Arguments {
 			for _, targ := range t.Arguments {
-				if arg.Key == targ.Key && arg.Value == targ.Value {
-					return true
+				if arg.Key == targ.Key && (arg.Value == targ.Value || strings.HasPrefix(targ.Value, arg.Value+" with ")) {
+					matched++
+					break
 				}
 			}
 		}
+		if matched == len(transition.Arguments) {
+			return true
+		}
 	}
 
 	return false
-}
\ No newline at end of file
+}
@@ -0,0 +1,185 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/scilla"
+	"github.com/patrickmn/go-cache"
+	"go.uber.org/zap"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var governanceEvents = map[entity.Event]struct {
+	action entity.GovernanceAction
+	param  string
+}{
+	entity.ZRC6SetRoyaltyRecipientEvent:           {entity.RoyaltyRecipientAction, "to"},
+	entity.ZRC6SetRoyaltyFeeBPSEvent:              {entity.RoyaltyFeeBpsAction, "royalty_fee_bps"},
+	entity.ZRC6AddMinterEvent:                     {entity.AddMinterAction, "minter"},
+	entity.ZRC6RemoveMinterEvent:                  {entity.RemoveMinterAction, "minter"},
+	entity.ZRC6SetContractOwnershipRecipientEvent: {entity.OwnershipRecipientAction, "to"},
+	entity.ZRC6AcceptContractOwnershipEvent:       {entity.AcceptOwnershipAction, "contract_owner"},
+	entity.ZRC6PauseEvent:                         {entity.PauseAction, ""},
+	entity.ZRC6UnpauseEvent:                       {entity.UnpauseAction, ""},
+}
+
+// CreateGovernanceEvents creates a governance event for each change the contract's owner made to it in the tx
+func CreateGovernanceEvents(tx entity.Transaction, c entity.Contract) []entity.GovernanceEvent {
+	events := make([]entity.GovernanceEvent, 0)
+
+	for idx, event := range tx.Receipt.EventLogs {
+		if event.Address != c.Address {
+			continue
+		}
+
+		governance, ok := governanceEvents[entity.Event(event.EventName)]
+		if !ok {
+			continue
+		}
+
+		value := ""
+		if governance.param != "" {
+			var err error
+			if value, err = getPrimitiveParam(event.Params, governance.param); err != nil {
+				zap.L().With(zap.Error(err), zap.String("txId", tx.ID), zap.String("contract", c.Address), zap.String("event", event.EventName)).
+					Warn("Failed to get the value of a ZRC6 governance event")
+				continue
+			}
+			value = strings.ToLower(value)
+		}
+
+		events = append(events, entity.GovernanceEvent{
+			Contract:   c.Address,
+			TxID:       tx.ID,
+			EventIndex: idx,
+			BlockNum:   tx.BlockNum,
+			Timestamp:  tx.Timestamp,
+			Action:     governance.action,
+			Value:      value,
+		})
+	}
+
+	return events
+}
+
+type RoyaltyResolver interface {
+	// GetRoyaltyFeeBps returns the royalty fee of a contract in force at a block
+	GetRoyaltyFeeBps(ctx context.Context, contractAddr string, blockNum uint64) (uint, error)
+}
+
+type royaltyResolver struct {
+	governanceRepo    repository.GovernanceRepository
+	contractRepo      repository.ContractRepository
+	contractStateRepo repository.ContractStateRepository
+	cache             *cache.Cache
+}
+
+// initialRoyaltyFee is the royalty fee a contract was deployed with, found is false when its code does not tell
+type initialRoyaltyFee struct {
+	fee   uint
+	found bool
+}
+
+func NewRoyaltyResolver(
+	governanceRepo repository.GovernanceRepository,
+	contractRepo repository.ContractRepository,
+	contractStateRepo repository.ContractStateRepository,
+	cache *cache.Cache,
+) RoyaltyResolver {
+	return royaltyResolver{governanceRepo, contractRepo, contractStateRepo, cache}
+}
+
+// GetRoyaltyFeeBps takes the fee from the last change at or before the block. When the fee was only changed after
+// the block, the contract was still charging the fee it was deployed with. Contracts without any recorded change
+// fall back to their current state.
+func (r royaltyResolver) GetRoyaltyFeeBps(ctx context.Context, contractAddr string, blockNum uint64) (uint, error) {
+	event, err := r.governanceRepo.GetLatestEvent(ctx, contractAddr, entity.RoyaltyFeeBpsAction, blockNum)
+	if err == nil {
+		return parseRoyaltyFeeBps(event.Value)
+	}
+	if err != repository.ErrGovernanceEventNotFound {
+		return 0, err
+	}
+
+	if _, err := r.governanceRepo.GetLatestEvent(ctx, contractAddr, entity.RoyaltyFeeBpsAction, math.MaxUint64); err == nil {
+		initial, err := r.getInitialRoyaltyFee(ctx, contractAddr)
+		if err != nil {
+			return 0, err
+		}
+		if initial.found {
+			return initial.fee, nil
+		}
+		zap.L().With(zap.String("contract", contractAddr), zap.Uint64("blockNum", blockNum)).
+			Warn("Failed to find the initial royalty fee, using the current fee")
+	}
+
+	return r.contractStateRepo.GetRoyaltyFeeBps(ctx, contractAddr)
+}
+
+// getInitialRoyaltyFee parses the code of a contract once, the fee it was deployed with never changes
+func (r royaltyResolver) getInitialRoyaltyFee(ctx context.Context, contractAddr string) (initialRoyaltyFee, error) {
+	key := initialRoyaltyFeeKey(contractAddr)
+	if cached, ok := r.cache.Get(key); ok {
+		return cached.(initialRoyaltyFee), nil
+	}
+
+	c, err := r.contractRepo.GetContractByAddress(ctx, contractAddr)
+	if err != nil {
+		return initialRoyaltyFee{}, err
+	}
+
+	var initial initialRoyaltyFee
+	initial.fee, initial.found = GetInitialRoyaltyFeeBps(*c)
+	r.cache.Set(key, initial, cache.NoExpiration)
+
+	return initial, nil
+}
+
+func initialRoyaltyFeeKey(contractAddr string) string {
+	return fmt.Sprintf("initialRoyaltyFeeBps:%s", contractAddr)
+}
+
+// GetInitialRoyaltyFeeBps reads the royalty fee a contract's code initialises its royalty_fee_bps field with, which
+// may be a literal, a library let or one of the params the contract was deployed with
+func GetInitialRoyaltyFeeBps(c entity.Contract) (uint, bool) {
+	source, err := scilla.Parse(c.Code)
+	if err != nil {
+		return 0, false
+	}
+
+	field, ok := source.GetField("royalty_fee_bps")
+	if !ok {
+		return 0, false
+	}
+
+	params := make(map[string]string)
+	for _, param := range c.Data.Params {
+		if param.Value != nil {
+			if value, ok := param.Value.Primitive.(string); ok {
+				params[param.VName] = value
+			}
+		}
+	}
+
+	value, ok := source.Literal(field.Value, params)
+	if !ok {
+		return 0, false
+	}
+
+	fee, err := parseRoyaltyFeeBps(value)
+
+	return fee, err == nil
+}
+
+func parseRoyaltyFeeBps(value string) (uint, error) {
+	fee, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(fee), nil
+}
@@ -0,0 +1,74 @@
+package factory
+
+import (
+	"context"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/memory"
+	"github.com/patrickmn/go-cache"
+	"io/ioutil"
+	"testing"
+)
+
+const (
+	royaltyContract   = "0x6666666666666666666666666666666666666666"
+	unchangedContract = "0x7777777777777777777777777777777777777777"
+)
+
+func TestRoyaltyResolverUsesTheFeeInForceAtTheSale(t *testing.T) {
+	code, err := ioutil.ReadFile("../scilla/testdata/zrc6.scilla")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index := memory.New()
+	for _, addr := range []string{royaltyContract, unchangedContract} {
+		index.Save(elastic_search.ContractIndex.Get(), entity.Contract{Address: addr, BlockNum: 1, Code: string(code)})
+		index.Save(elastic_search.ContractStateIndex.Get(), entity.ContractState{
+			Address: addr,
+			State:   []entity.StateElement{{Key: "royalty_fee_bps", Value: "500"}},
+		})
+	}
+	index.Save(elastic_search.GovernanceIndex.Get(), entity.GovernanceEvent{
+		Contract: royaltyContract,
+		TxID:     "set-royalty",
+		BlockNum: 20,
+		Action:   entity.RoyaltyFeeBpsAction,
+		Value:    "500",
+	})
+
+	r := NewRoyaltyResolver(
+		memory.NewGovernanceRepository(index),
+		memory.NewContractRepository(index),
+		memory.NewContractStateRepository(index),
+		cache.New(cache.NoExpiration, 0),
+	)
+
+	for _, tc := range []struct {
+		name     string
+		contract string
+		blockNum uint64
+		fee      uint
+	}{
+		{"sold before the change", royaltyContract, 10, 1000},
+		{"sold in the block of the change", royaltyContract, 20, 500},
+		{"sold after the change", royaltyContract, 30, 500},
+		{"never changed", unchangedContract, 10, 500},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fee, err := r.GetRoyaltyFeeBps(context.Background(), tc.contract, tc.blockNum)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fee != tc.fee {
+				t.Errorf("royalty fee %d, expected %d", fee, tc.fee)
+			}
+		})
+	}
+
+	// The initial fee is parsed once per contract
+	index.DeleteDocuments(elastic_search.ContractIndex.Get(), entity.CreateContractSlug(royaltyContract))
+	if fee, err := r.GetRoyaltyFeeBps(context.Background(), royaltyContract, 10); err != nil || fee != 1000 {
+		t.Errorf("royalty fee %d without the contract, expected the cached 1000: %v", fee, err)
+	}
+}
@@ -1,6 +1,7 @@
 package factory
 
 import (
+	"context"
 	"fmt"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
@@ -9,15 +10,15 @@ import (
 )
 
 type ArkyMarketplaceFactory struct {
-	nftRepo           repository.NftRepository
-	contractStateRepo repository.ContractStateRepository
+	nftRepo         repository.NftRepository
+	royaltyResolver RoyaltyResolver
 }
 
-func NewArkyMarketplaceFactory(nftRepo repository.NftRepository, contractStateRepo repository.ContractStateRepository) ArkyMarketplaceFactory {
-	return ArkyMarketplaceFactory{nftRepo, contractStateRepo}
+func NewArkyMarketplaceFactory(nftRepo repository.NftRepository, royaltyResolver RoyaltyResolver) ArkyMarketplaceFactory {
+	return ArkyMarketplaceFactory{nftRepo, royaltyResolver}
 }
 
-func (f ArkyMarketplaceFactory) CreateSale(tx entity.Transaction) (*entity.MarketplaceSale, error) {
+func (f ArkyMarketplaceFactory) CreateSale(ctx context.Context, tx entity.Transaction) (*entity.MarketplaceSale, error) {
 	salesEvent := tx.GetEventLogs(entity.MpArkySaleEvent)[0]
 
 	token, err := salesEvent.Params.GetParam("token")
@@ -37,7 +38,7 @@ func (f ArkyMarketplaceFactory) CreateSale(tx entity.Transaction) (*entity.Marke
 		}
 	}
 
-	nft, err := f.nftRepo.GetNft(contractAddr, tokenId)
+	nft, err := f.nftRepo.GetNft(ctx, contractAddr, tokenId)
 	if err != nil {
 		zap.L().With(zap.String("txId", tx.ID), zap.Error(err)).Error("Arky trade: NFT not found")
 		return nil, err
@@ -83,7 +84,7 @@ func (f ArkyMarketplaceFactory) CreateSale(tx entity.Transaction) (*entity.Marke
 		cost = fmt.Sprintf("%d", costInt + feeInt)
 	}
 
-	platformFee, royaltyFee, royaltyBps, err := f.getRoyaltyForContract(contractAddr, costInt, feeInt)
+	platformFee, royaltyFee, royaltyBps, err := f.getRoyaltyForContract(ctx, contractAddr, tx.BlockNum, costInt, feeInt)
 	if err != nil {
 		zap.L().With(zap.String("txId", tx.ID), zap.Error(err)).Error("Arky trade: Failed to get royalty")
 		return nil, err
@@ -103,13 +104,13 @@ func (f ArkyMarketplaceFactory) CreateSale(tx entity.Transaction) (*entity.Marke
 	}, nil
 }
 
-func (f ArkyMarketplaceFactory) getRoyaltyForContract(contractAddr string, cost, totalFee uint64) (string, string, string, error) {
+func (f ArkyMarketplaceFactory) getRoyaltyForContract(ctx context.Context, contractAddr string, blockNum, cost, totalFee uint64) (string, string, string, error) {
 	cost = cost+totalFee
 	totalFeePercent := uint((float64(totalFee)/float64(cost))*10000)
 	platformFeePercent := entity.ArkyPlatformFee
 	royaltyFeePercent := totalFeePercent - entity.ArkyPlatformFee
 
-	royaltyFeeBps, err := f.contractStateRepo.GetRoyaltyFeeBps(contractAddr)
+	royaltyFeeBps, err := f.royaltyResolver.GetRoyaltyFeeBps(ctx, contractAddr, blockNum)
 	if err != nil {
 		return fmt.Sprintf("%d", totalFee), "0", "0", err
 	}
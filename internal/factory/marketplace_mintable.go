@@ -1,6 +1,7 @@
 package factory
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
@@ -18,7 +19,7 @@ func NewMintableMarketplaceFactory(nftRepo repository.NftRepository, nftActionRe
 	return MintableMarketplaceFactory{nftRepo, nftActionRepo}
 }
 
-func (f MintableMarketplaceFactory) CreateListing(tx entity.Transaction) (*entity.MarketplaceListing, error) {
+func (f MintableMarketplaceFactory) CreateListing(ctx context.Context, tx entity.Transaction) (*entity.MarketplaceListing, error) {
 	listingEvent := tx.GetEventLogs(entity.MpMintableListingEvent)[0]
 
 	orderInfo, err := listingEvent.Params.GetParam("order_info")
@@ -38,7 +39,7 @@ func (f MintableMarketplaceFactory) CreateListing(tx entity.Transaction) (*entit
 		return nil, err
 	}
 
-	nft, err := f.nftRepo.GetNft(arguments[2].String(), tokenId)
+	nft, err := f.nftRepo.GetNft(ctx, arguments[2].String(), tokenId)
 	if err != nil {
 		zap.L().With(zap.String("txId", tx.ID), zap.Error(err)).Error("Mintable listing: Failed to get nft")
 		return nil, err
@@ -53,7 +54,7 @@ func (f MintableMarketplaceFactory) CreateListing(tx entity.Transaction) (*entit
 	}, nil
 }
 
-func (f MintableMarketplaceFactory) CreateDelisting(tx entity.Transaction) (*entity.MarketplaceDelisting, error) {
+func (f MintableMarketplaceFactory) CreateDelisting(ctx context.Context, tx entity.Transaction) (*entity.MarketplaceDelisting, error) {
 	delistingEvents := tx.GetEventLogs("TransferSuccess")
 	if len(delistingEvents) != 1 {
 		zap.L().With(zap.String("txId", tx.ID)).Error("Mintable delisting: Failed to transfer event")
@@ -68,7 +69,7 @@ func (f MintableMarketplaceFactory) CreateDelisting(tx entity.Transaction) (*ent
 		return nil, err
 	}
 
-	nft, err := f.nftRepo.GetNft(delistingEvent.Address, tokenId)
+	nft, err := f.nftRepo.GetNft(ctx, delistingEvent.Address, tokenId)
 	if err != nil {
 		zap.L().With(zap.String("txId", tx.ID), zap.Error(err)).Error("Mintable delisting: Failed to get nft")
 		return nil, err
@@ -81,7 +82,7 @@ func (f MintableMarketplaceFactory) CreateDelisting(tx entity.Transaction) (*ent
 	}, nil
 }
 
-func (f MintableMarketplaceFactory) CreateSale(tx entity.Transaction) (*entity.MarketplaceSale, error) {
+func (f MintableMarketplaceFactory) CreateSale(ctx context.Context, tx entity.Transaction) (*entity.MarketplaceSale, error) {
 	salesEvent := tx.GetEventLogs(entity.MpMintableSaleEvent)[0]
 	transferEvents := tx.GetEventLogs("TransferSuccess")
 	if len(transferEvents) != 1 {
@@ -102,7 +103,7 @@ func (f MintableMarketplaceFactory) CreateSale(tx entity.Transaction) (*entity.M
 		return nil, err
 	}
 
-	nft, err := f.nftRepo.GetNft(transferEvent.Address, tokenId)
+	nft, err := f.nftRepo.GetNft(ctx, transferEvent.Address, tokenId)
 	if err != nil {
 		zap.L().With(
 			zap.String("txId", tx.ID),
@@ -124,7 +125,7 @@ func (f MintableMarketplaceFactory) CreateSale(tx entity.Transaction) (*entity.M
 		return nil, err
 	}
 
-	seller, err := f.nftActionRepo.GetNftOwnerBeforeBlockNum(*nft, tx.BlockNum)
+	seller, err := f.nftActionRepo.GetNftOwnerBeforeBlockNum(ctx, *nft, tx.BlockNum)
 	if err != nil {
 		zap.L().With(
 			zap.String("txId", tx.ID),
@@ -1,6 +1,7 @@
 package factory
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
@@ -18,7 +19,7 @@ func NewOkimotoMarketplaceFactory(nftRepo repository.NftRepository, nftActionRep
 	return OkimotoMarketplaceFactory{nftRepo, nftActionRepo, contractStateRepo}
 }
 
-func (f OkimotoMarketplaceFactory) CreateListing(tx entity.Transaction) (*entity.MarketplaceListing, error) {
+func (f OkimotoMarketplaceFactory) CreateListing(ctx context.Context, tx entity.Transaction) (*entity.MarketplaceListing, error) {
 	listingId, err := tx.Data.Params.GetParam("listing_id")
 	if err != nil {
 		zap.L().With(zap.String("txId", tx.ID), zap.Error(err)).Error("Okimoto listing: Failed to get listing id")
@@ -31,7 +32,7 @@ func (f OkimotoMarketplaceFactory) CreateListing(tx entity.Transaction) (*entity
 		return nil, err
 	}
 
-	state, err := f.contractStateRepo.GetStateByAddress(tx.ContractAddress)
+	state, err := f.contractStateRepo.GetStateByAddress(ctx, tx.ContractAddress)
 	if err != nil {
 		zap.L().With(zap.String("txId", tx.ID), zap.Error(err)).Error("Okimoto listing: Failed to get state")
 		return nil, err
@@ -74,7 +75,7 @@ func (f OkimotoMarketplaceFactory) CreateListing(tx entity.Transaction) (*entity
 		return nil, err
 	}
 
-	nft, err := f.nftRepo.GetNft(listing.Arguments[1].String(), tokenId)
+	nft, err := f.nftRepo.GetNft(ctx, listing.Arguments[1].String(), tokenId)
 	if err != nil {
 		zap.L().With(zap.String("txId", tx.ID), zap.Error(err)).Error("Okimoto listing: Failed to get nft")
 		return nil, err
@@ -89,7 +90,7 @@ func (f OkimotoMarketplaceFactory) CreateListing(tx entity.Transaction) (*entity
 	}, nil
 }
 
-func (f OkimotoMarketplaceFactory) CreateDelisting(tx entity.Transaction) (*entity.MarketplaceDelisting, error) {
+func (f OkimotoMarketplaceFactory) CreateDelisting(ctx context.Context, tx entity.Transaction) (*entity.MarketplaceDelisting, error) {
 	delistingEvent := tx.GetEventLogs(entity.MpOkiDelistingEvent)[0]
 	tokenId, err := GetTokenId(delistingEvent.Params)
 	if err != nil {
@@ -97,7 +98,7 @@ func (f OkimotoMarketplaceFactory) CreateDelisting(tx entity.Transaction) (*enti
 		return nil, err
 	}
 
-	nft, err := f.nftRepo.GetNft(delistingEvent.Address, tokenId)
+	nft, err := f.nftRepo.GetNft(ctx, delistingEvent.Address, tokenId)
 	if err != nil {
 		zap.L().With(zap.String("txId", tx.ID), zap.Error(err)).Error("Okimoto delisting: Failed to get nft")
 		return nil, err
@@ -110,7 +111,7 @@ func (f OkimotoMarketplaceFactory) CreateDelisting(tx entity.Transaction) (*enti
 	}, nil
 }
 
-func (f OkimotoMarketplaceFactory) CreateSale(tx entity.Transaction) (*entity.MarketplaceSale, error) {
+func (f OkimotoMarketplaceFactory) CreateSale(ctx context.Context, tx entity.Transaction) (*entity.MarketplaceSale, error) {
 	salesEvent := tx.GetEventLogs(entity.MpOkiSaleEvent)[0]
 
 	tokenId, err := GetTokenId(salesEvent.Params)
@@ -119,7 +120,7 @@ func (f OkimotoMarketplaceFactory) CreateSale(tx entity.Transaction) (*entity.Ma
 		return nil, err
 	}
 
-	nft, err := f.nftRepo.GetNft(salesEvent.Address, tokenId)
+	nft, err := f.nftRepo.GetNft(ctx, salesEvent.Address, tokenId)
 	if err != nil {
 		zap.L().With(zap.String("txId", tx.ID), zap.Error(err)).Error("Okimoto sale: Failed to get nft")
 		return nil, err
@@ -131,7 +132,7 @@ func (f OkimotoMarketplaceFactory) CreateSale(tx entity.Transaction) (*entity.Ma
 		return nil, err
 	}
 
-	seller, err := f.nftActionRepo.GetNftOwnerBeforeBlockNum(*nft, tx.BlockNum)
+	seller, err := f.nftActionRepo.GetNftOwnerBeforeBlockNum(ctx, *nft, tx.BlockNum)
 	if err != nil {
 		zap.L().With(zap.String("txId", tx.ID), zap.Error(err)).Error("Okimoto sale: Failed to get seller")
 		return nil, err
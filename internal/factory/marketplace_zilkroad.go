@@ -1,6 +1,7 @@
 package factory
 
 import (
+	"context"
 	"fmt"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
@@ -8,16 +9,16 @@ import (
 )
 
 type ZilkroadMarketplaceFactory struct {
-	nftRepo           repository.NftRepository
-	contractRepo      repository.ContractRepository
-	contractStateRepo repository.ContractStateRepository
+	nftRepo         repository.NftRepository
+	contractRepo    repository.ContractRepository
+	royaltyResolver RoyaltyResolver
 }
 
-func NewZilkroadMarketplaceFactory(nftRepo repository.NftRepository, contractRepo repository.ContractRepository, contractStateRepo repository.ContractStateRepository) ZilkroadMarketplaceFactory {
-	return ZilkroadMarketplaceFactory{nftRepo, contractRepo,contractStateRepo}
+func NewZilkroadMarketplaceFactory(nftRepo repository.NftRepository, contractRepo repository.ContractRepository, royaltyResolver RoyaltyResolver) ZilkroadMarketplaceFactory {
+	return ZilkroadMarketplaceFactory{nftRepo, contractRepo, royaltyResolver}
 }
 
-func (f ZilkroadMarketplaceFactory) CreateListing(tx entity.Transaction) (*entity.MarketplaceListing, error) {
+func (f ZilkroadMarketplaceFactory) CreateListing(ctx context.Context, tx entity.Transaction) (*entity.MarketplaceListing, error) {
 	listingEvent := tx.GetEventLogs(entity.MpZilkroadListingEvent)[0]
 
 	tokenId, err := GetTokenId(listingEvent.Params)
@@ -32,7 +33,7 @@ func (f ZilkroadMarketplaceFactory) CreateListing(tx entity.Transaction) (*entit
 		return nil, err
 	}
 
-	nft, err := f.nftRepo.GetNft(contractAddr.Value.String(), tokenId)
+	nft, err := f.nftRepo.GetNft(ctx, contractAddr.Value.String(), tokenId)
 	if err != nil {
 		zap.L().With(zap.String("txId", tx.ID), zap.Error(err)).Error("Zilkroad listing: Failed to get nft")
 		return nil, err
@@ -50,7 +51,7 @@ func (f ZilkroadMarketplaceFactory) CreateListing(tx entity.Transaction) (*entit
 		return nil, err
 	}
 
-	fungibleContract, err := f.contractRepo.GetContractByAddress(fungibleToken.Value.String())
+	fungibleContract, err := f.contractRepo.GetContractByAddress(ctx, fungibleToken.Value.String())
 	if err != nil {
 		zap.L().With(zap.String("txId", tx.ID), zap.String("contract", fungibleToken.Value.String()), zap.Error(err)).Error("Zilkroad listing: Failed to get fungible contract")
 		return nil, err
@@ -71,7 +72,7 @@ func (f ZilkroadMarketplaceFactory) CreateListing(tx entity.Transaction) (*entit
 	}, nil
 }
 
-func (f ZilkroadMarketplaceFactory) CreateDelisting(tx entity.Transaction) (*entity.MarketplaceDelisting, error) {
+func (f ZilkroadMarketplaceFactory) CreateDelisting(ctx context.Context, tx entity.Transaction) (*entity.MarketplaceDelisting, error) {
 	delistingEvent := tx.GetEventLogs(entity.MpZilkroadDelistingEvent)[0]
 	tokenId, err := GetTokenId(delistingEvent.Params)
 	if err != nil {
@@ -85,7 +86,7 @@ func (f ZilkroadMarketplaceFactory) CreateDelisting(tx entity.Transaction) (*ent
 		return nil, err
 	}
 
-	nft, err := f.nftRepo.GetNft(contractAddr.Value.String(), tokenId)
+	nft, err := f.nftRepo.GetNft(ctx, contractAddr.Value.String(), tokenId)
 	if err != nil {
 		zap.L().With(zap.String("txId", tx.ID), zap.Error(err)).Error("Zilkroad listing: Failed to get nft")
 		return nil, err
@@ -98,7 +99,7 @@ func (f ZilkroadMarketplaceFactory) CreateDelisting(tx entity.Transaction) (*ent
 	}, nil
 }
 
-func (f ZilkroadMarketplaceFactory) CreateSale(tx entity.Transaction) (*entity.MarketplaceSale, error) {
+func (f ZilkroadMarketplaceFactory) CreateSale(ctx context.Context, tx entity.Transaction) (*entity.MarketplaceSale, error) {
 	salesEvent := tx.GetEventLogs(entity.MpZilkroadSaleEvent)[0]
 
 	buyer, err := salesEvent.Params.GetParam("buyer")
@@ -125,7 +126,7 @@ func (f ZilkroadMarketplaceFactory) CreateSale(tx entity.Transaction) (*entity.M
 		return nil, err
 	}
 
-	nft, err := f.nftRepo.GetNft(contractAddr.Value.String(), tokenId)
+	nft, err := f.nftRepo.GetNft(ctx, contractAddr.Value.String(), tokenId)
 	if err != nil {
 		zap.L().With(zap.String("txId", tx.ID), zap.Error(err)).Error("Arky trade: NFT not found")
 		return nil, err
@@ -144,9 +145,9 @@ func (f ZilkroadMarketplaceFactory) CreateSale(tx entity.Transaction) (*entity.M
 		return nil, err
 	}
 	royaltyFee := royaltyAsString.Value.String()
-	royaltyFeeBps, err := f.getRoyaltyForContract(contractAddr.Value.String())
+	royaltyFeeBps, err := f.getRoyaltyForContract(ctx, contractAddr.Value.String(), tx.BlockNum)
 	if err != nil {
-		zap.L().With(zap.String("txId", tx.ID), zap.Error(err)).Error("Arky trade: Failed to get royalty fee bps from contract state")
+		zap.L().With(zap.String("txId", tx.ID), zap.Error(err)).Error("Zilkroad Sale: Failed to get royalty fee bps")
 		return nil, err
 	}
 
@@ -156,7 +157,7 @@ func (f ZilkroadMarketplaceFactory) CreateSale(tx entity.Transaction) (*entity.M
 		return nil, err
 	}
 
-	fungibleContract, err := f.contractRepo.GetContractByAddress(fungibleToken.Value.String())
+	fungibleContract, err := f.contractRepo.GetContractByAddress(ctx, fungibleToken.Value.String())
 	if err != nil {
 		zap.L().With(zap.String("txId", tx.ID), zap.String("contract", fungibleToken.Value.String()), zap.Error(err)).Error("Zilkroad listing: Failed to get fungible contract")
 		return nil, err
@@ -182,8 +183,8 @@ func (f ZilkroadMarketplaceFactory) CreateSale(tx entity.Transaction) (*entity.M
 	}, nil
 }
 
-func (f ZilkroadMarketplaceFactory) getRoyaltyForContract(contractAddr string) (string, error) {
-	royaltyFeeBps, err := f.contractStateRepo.GetRoyaltyFeeBps(contractAddr)
+func (f ZilkroadMarketplaceFactory) getRoyaltyForContract(ctx context.Context, contractAddr string, blockNum uint64) (string, error) {
+	royaltyFeeBps, err := f.royaltyResolver.GetRoyaltyFeeBps(ctx, contractAddr, blockNum)
 	if err != nil {
 		return "0", err
 	}
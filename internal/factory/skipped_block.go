@@ -0,0 +1,18 @@
+package factory
+
+import (
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"time"
+)
+
+func CreateSkippedBlock(blockNum uint64, err error, attempts int) entity.SkippedBlock {
+	now := time.Now()
+
+	return entity.SkippedBlock{
+		BlockNum:      blockNum,
+		Reason:        err.Error(),
+		Attempts:      attempts,
+		CreatedAt:     now,
+		LastAttemptAt: now,
+	}
+}
@@ -10,7 +10,9 @@ import (
 	"github.com/Zilliqa/gozilliqa-sdk/util"
 	"go.uber.org/zap"
 	"log"
+	"math/big"
 	"reflect"
+	"sort"
 	"strconv"
 )
 
@@ -38,6 +40,14 @@ func (f transactionFactory) CreateTransaction(coreTx zilliqa.Transaction, blockN
 		BlockNum:            f.stringToUint64(blockNum),
 		IsContractCreation:  coreTx.Code != "",
 		IsContractExecution: len(coreTx.Receipt.Transitions) > 0 || len(coreTx.Receipt.EventLogs) > 0 || coreTx.Data != nil,
+		IsFailed:            !coreTx.Receipt.Success,
+		Fee:                 f.createFee(coreTx.GasPrice, coreTx.Receipt.CumulativeGas),
+	}
+	tx.IsPayment = !tx.IsContractCreation && !tx.IsContractExecution
+
+	if tx.IsPayment && coreTx.ToAddr != "" {
+		tx.RecipientAddr = fmt.Sprintf("0x%s", coreTx.ToAddr)
+		tx.RecipientBech32 = GetBech32Address(tx.RecipientAddr)
 	}
 
 	if tx.IsContractExecution && coreTx.ToAddr != "" {
@@ -54,12 +64,63 @@ func (f transactionFactory) CreateTransaction(coreTx zilliqa.Transaction, blockN
 	return tx
 }
 
+// createFee multiplies the gas used by the gas price, the receipt's cumulative gas being the gas used by the tx
+func (f transactionFactory) createFee(gasPrice, gasUsed string) string {
+	price, ok := new(big.Int).SetString(gasPrice, 10)
+	if !ok {
+		return "0"
+	}
+	used, ok := new(big.Int).SetString(gasUsed, 10)
+	if !ok {
+		return "0"
+	}
+
+	return new(big.Int).Mul(price, used).String()
+}
+
 func (f transactionFactory) createReceipt(coreReceipt zilliqa.TransactionReceipt) entity.TransactionReceipt {
 	return entity.TransactionReceipt{
 		TransactionReceipt: coreReceipt,
 		Transitions:        f.createTransitions(coreReceipt.Transitions),
 		EventLogs:          f.createEventLogs(coreReceipt.EventLogs),
+		ErrorCodes:         f.createReceiptErrors(coreReceipt.Errors),
+	}
+}
+
+// createReceiptErrors reads the error codes of a failed call, which the node keys by call depth, e.g. {"0": [7]}
+func (f transactionFactory) createReceiptErrors(coreErrors interface{}) []entity.ReceiptError {
+	depths, ok := coreErrors.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	receiptErrors := make([]entity.ReceiptError, 0)
+	for depth, codes := range depths {
+		codeList, ok := codes.([]interface{})
+		if !ok {
+			continue
+		}
+		depthNum, _ := strconv.Atoi(depth)
+
+		for _, code := range codeList {
+			number, ok := code.(float64)
+			if !ok {
+				continue
+			}
+
+			receiptErrors = append(receiptErrors, entity.ReceiptError{
+				Depth: depthNum,
+				Code:  int(number),
+				Name:  zilliqa.ReceiptErrorName(int(number)),
+			})
+		}
 	}
+
+	sort.SliceStable(receiptErrors, func(a, b int) bool {
+		return receiptErrors[a].Depth < receiptErrors[b].Depth
+	})
+
+	return receiptErrors
 }
 
 func (f transactionFactory) createTransitions(coreTransitions []zilliqa.Transition) (transitions []entity.Transition) {
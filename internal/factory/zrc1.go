@@ -55,6 +55,7 @@ func (f zrc1Factory) createNftFromZrc1MintEvent(event entity.EventLog, tx entity
 		Contract: c.Address,
 		TxID:     tx.ID,
 		BlockNum: tx.BlockNum,
+		MintedAt: tx.Timestamp,
 		Name:     name.Value.String(),
 		Symbol:   symbol.Value.String(),
 		TokenId:  tokenId,
@@ -0,0 +1,159 @@
+package factory
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"go.uber.org/zap"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+type Zrc2Factory interface {
+	CreateTransfers(tx entity.Transaction, c entity.Contract) []entity.TokenTransfer
+}
+
+type zrc2Factory struct{}
+
+func NewZrc2Factory() Zrc2Factory {
+	return zrc2Factory{}
+}
+
+var (
+	ErrInvalidUint128 = errors.New("invalid Uint128")
+
+	maxUint128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+)
+
+// ParseUint128 parses a Scilla Uint128, which does not fit in any native integer type
+func ParseUint128(value string) (*big.Int, error) {
+	number, ok := new(big.Int).SetString(value, 10)
+	if !ok || number.Sign() < 0 || number.Cmp(maxUint128) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidUint128, value)
+	}
+
+	return number, nil
+}
+
+// CreateZrc2Token reads the symbol, decimals and initial supply from the contract's init params. The total supply
+// starts at the initial supply and follows the mints and burns indexed after.
+func CreateZrc2Token(c entity.Contract) *entity.Zrc2Token {
+	token := &entity.Zrc2Token{InitSupply: "0"}
+
+	if symbol, err := c.Data.Params.GetParam("symbol"); err == nil && symbol.Value != nil {
+		token.Symbol = symbol.Value.String()
+	}
+
+	if decimals, err := c.Data.Params.GetParam("decimals"); err == nil && decimals.Value != nil {
+		if number, err := strconv.ParseUint(decimals.Value.String(), 10, 32); err == nil {
+			token.Decimals = uint32(number)
+		}
+	}
+
+	if initSupply, err := c.Data.Params.GetParam("init_supply"); err == nil && initSupply.Value != nil {
+		if number, err := ParseUint128(initSupply.Value.String()); err == nil {
+			token.InitSupply = number.String()
+		}
+	}
+	token.TotalSupply = token.InitSupply
+
+	return token
+}
+
+// CreateInitTransfer credits the initial supply to the contract_owner of a token deployed by the tx, being nil when
+// there is no supply or owner
+func CreateInitTransfer(tx entity.Transaction, c entity.Contract) *entity.TokenTransfer {
+	if c.Zrc2 == nil || c.Zrc2.InitSupply == "" || c.Zrc2.InitSupply == "0" {
+		return nil
+	}
+
+	owner := getAddressParam(c.Data.Params, "contract_owner")
+	if owner == "" {
+		return nil
+	}
+
+	return &entity.TokenTransfer{
+		Contract:   c.Address,
+		TxID:       tx.ID,
+		EventIndex: -1,
+		BlockNum:   tx.BlockNum,
+		Timestamp:  tx.Timestamp,
+		Action:     entity.TokenInitAction,
+		Initiator:  strings.ToLower(tx.SenderAddr),
+		To:         owner,
+		Amount:     c.Zrc2.InitSupply,
+	}
+}
+
+// CreateTransfers creates a transfer for each ZRC2 event the contract emitted in the tx
+func (f zrc2Factory) CreateTransfers(tx entity.Transaction, c entity.Contract) []entity.TokenTransfer {
+	transfers := make([]entity.TokenTransfer, 0)
+
+	for idx, event := range tx.Receipt.EventLogs {
+		if event.Address != c.Address {
+			continue
+		}
+
+		transfer := entity.TokenTransfer{
+			Contract:   c.Address,
+			TxID:       tx.ID,
+			EventIndex: idx,
+			BlockNum:   tx.BlockNum,
+			Timestamp:  tx.Timestamp,
+		}
+
+		switch entity.Event(event.EventName) {
+		case entity.ZRC2TransferEvent:
+			transfer.Action = entity.TokenTransferAction
+			transfer.Initiator = getAddressParam(event.Params, "sender")
+			transfer.From = transfer.Initiator
+			transfer.To = getAddressParam(event.Params, "recipient")
+		case entity.ZRC2TransferFromEvent:
+			transfer.Action = entity.TokenTransferAction
+			transfer.Initiator = getAddressParam(event.Params, "initiator")
+			transfer.From = getAddressParam(event.Params, "sender")
+			transfer.To = getAddressParam(event.Params, "recipient")
+		case entity.ZRC2MintedEvent:
+			transfer.Action = entity.TokenMintAction
+			transfer.Initiator = getAddressParam(event.Params, "minter")
+			transfer.To = getAddressParam(event.Params, "recipient")
+		case entity.ZRC2BurntEvent:
+			transfer.Action = entity.TokenBurnAction
+			transfer.Initiator = getAddressParam(event.Params, "burner")
+			transfer.From = getAddressParam(event.Params, "burn_account")
+		default:
+			continue
+		}
+
+		amount, err := getUint128Param(event.Params, "amount")
+		if err != nil {
+			zap.L().With(zap.Error(err), zap.String("txId", tx.ID), zap.String("contract", c.Address), zap.String("event", event.EventName)).
+				Warn("Failed to get the amount of a ZRC2 event")
+			continue
+		}
+		transfer.Amount = amount.String()
+
+		transfers = append(transfers, transfer)
+	}
+
+	return transfers
+}
+
+func getUint128Param(params entity.Params, name string) (*big.Int, error) {
+	value, err := getPrimitiveParam(params, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseUint128(value)
+}
+
+func getAddressParam(params entity.Params, name string) string {
+	address, err := params.GetParam(name)
+	if err != nil || address.Value == nil {
+		return ""
+	}
+
+	return strings.ToLower(address.Value.String())
+}
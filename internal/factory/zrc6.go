@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/helper"
+	"fmt"
 	"go.uber.org/zap"
 	"strconv"
 	"strings"
@@ -55,6 +56,7 @@ func (f zrc6Factory) CreateFromMintTx(tx entity.Transaction, c entity.Contract)
 			Contract: c.Address,
 			TxID:     tx.ID,
 			BlockNum: tx.BlockNum,
+			MintedAt: tx.Timestamp,
 			Name:     name.Value.Primitive.(string),
 			Symbol:   symbol.Value.Primitive.(string),
 			TokenId:  tokenId,
@@ -134,6 +136,7 @@ func (f zrc6Factory) CreateFromBatchMint(tx entity.Transaction, c entity.Contrac
 						Contract: c.Address,
 						TxID:     tx.ID,
 						BlockNum: tx.BlockNum,
+						MintedAt: tx.Timestamp,
 						Name:     name.Value.Primitive.(string),
 						Symbol:   symbol.Value.Primitive.(string),
 						TokenId:  nextTokenId,
@@ -184,6 +187,7 @@ func (f zrc6Factory) CreateFromBatchMint(tx entity.Transaction, c entity.Contrac
 						Contract: c.Address,
 						TxID:     tx.ID,
 						BlockNum: tx.BlockNum,
+						MintedAt: tx.Timestamp,
 						Name:     name.Value.Primitive.(string),
 						Symbol:   symbol.Value.Primitive.(string),
 						TokenId:  nextTokenId,
@@ -204,6 +208,75 @@ func (f zrc6Factory) CreateFromBatchMint(tx entity.Transaction, c entity.Contrac
 	return nfts, nil
 }
 
+// ToTokenIdPair is an entry of the to_token_id_pair_list of a ZRC6 BatchTransferFrom
+type ToTokenIdPair struct {
+	To      string
+	TokenId uint64
+}
+
+// GetToTokenIdPairs reads the to_token_id_pair_list ADT, a List (Pair ByStr20 Uint256)
+func GetToTokenIdPairs(params entity.Params) ([]ToTokenIdPair, error) {
+	pairList, err := getPrimitiveParam(params, "to_token_id_pair_list")
+	if err != nil {
+		return nil, err
+	}
+
+	var adts []toTokenUri
+	if err := json.Unmarshal([]byte(pairList), &adts); err != nil {
+		return nil, err
+	}
+
+	pairs := make([]ToTokenIdPair, 0, len(adts))
+	for _, adt := range adts {
+		arguments, ok := adt.Arguments.([]interface{})
+		if !ok || len(arguments) != 2 {
+			return nil, fmt.Errorf("incorrectly formatted to_token_id_pair_list: %v", adt.Arguments)
+		}
+
+		to, ok := arguments[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("incorrectly formatted to_token_id_pair_list recipient: %v", arguments[0])
+		}
+		tokenId, ok := arguments[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("incorrectly formatted to_token_id_pair_list token id: %v", arguments[1])
+		}
+
+		tokenIdInt, err := strconv.ParseUint(tokenId, 0, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		pairs = append(pairs, ToTokenIdPair{strings.ToLower(to), tokenIdInt})
+	}
+
+	return pairs, nil
+}
+
+// GetTokenIds reads a List Uint256 of token ids
+func GetTokenIds(params entity.Params, name string) ([]uint64, error) {
+	list, err := getPrimitiveParam(params, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []string
+	if err := json.Unmarshal([]byte(list), &values); err != nil {
+		return nil, err
+	}
+
+	tokenIds := make([]uint64, 0, len(values))
+	for _, value := range values {
+		tokenId, err := strconv.ParseUint(value, 0, 64)
+		if err != nil {
+			return nil, err
+		}
+		tokenIds = append(tokenIds, tokenId)
+	}
+
+	return tokenIds, nil
+}
+
 func (f zrc6Factory) contractHasMetadata(c entity.Contract) bool {
 	_, exists := f.contractsWithoutMetadata[c.Address]
 	return !exists
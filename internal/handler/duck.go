@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/factory"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/helper"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+)
+
+// duckHandler replaces the asset and metadata of a Non-Fungible Duck when it is regenerated
+type duckHandler struct {
+	BaseHandler
+	elastic elastic_search.Index
+	nftRepo repository.NftRepository
+}
+
+func NewDuckHandler(elastic elastic_search.Index, nftRepo repository.NftRepository) ContractHandler {
+	return duckHandler{elastic: elastic, nftRepo: nftRepo}
+}
+
+func (h duckHandler) Name() string {
+	return "duck"
+}
+
+func (h duckHandler) IndexTx(ctx context.Context, tx entity.Transaction, c entity.Contract) error {
+	for _, transition := range tx.GetTransition("regenerateDuck") {
+		if !transition.Msg.Params.HasParam("token_id") {
+			continue
+		}
+		tokenId, err := factory.GetTokenId(transition.Msg.Params)
+		if err != nil {
+			zap.L().With(zap.Error(err), zap.String("txId", tx.ID), zap.String("contract", c.Address)).Debug("Failed to get token id for zrc1:regenerateDuck")
+			continue
+		}
+
+		nft, err := h.nftRepo.GetNft(ctx, c.Address, tokenId)
+		if err != nil {
+			zap.L().With(zap.Uint64("tokenId", tokenId)).Error("Failed to get the nft from the index on duck regeneration")
+			return err
+		}
+
+		newDuckUri, err := transition.Msg.Params.GetParam("new_duck_uri")
+		if err != nil {
+			zap.L().Error("Failed to get the new duck metadata on duck regeneration")
+			return err
+		}
+		assetUri := helper.GetIpfs(newDuckUri.Value.String(), nil)
+		nft.AssetUri = *assetUri
+
+		newDuckMetaData, err := transition.Msg.Params.GetParam("new_duck_metadata")
+		if err != nil {
+			zap.L().Error("Failed to get the new duck metadata on duck regeneration")
+			return err
+		}
+		oldTokenUri := nft.TokenUri
+		nft.TokenUri = newDuckMetaData.Value.String()
+		nft.Metadata = factory.GetMetadata(*nft)
+
+		zap.L().With(zap.String("txID", tx.ID), zap.String("contract", c.Address), zap.Uint64("tokenId", nft.TokenId)).Info("Regenerate NFD")
+		h.elastic.AddUpdateRequest(elastic_search.NftIndex.Get(), *nft, elastic_search.Zrc1DuckRegeneration)
+		h.elastic.AddIndexRequest(elastic_search.NftActionIndex.Get(),
+			factory.CreateChangeAction(entity.TokenUriAction, *nft, tx, oldTokenUri, nft.TokenUri), elastic_search.NftAction)
+	}
+
+	return nil
+}
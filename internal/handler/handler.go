@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/factory"
+	"go.uber.org/zap"
+	"strings"
+)
+
+// ContractHandler holds the behaviour of a collection which does not follow its standard
+type ContractHandler interface {
+	Name() string
+
+	// IndexTx is called after the standard indexer has indexed a transaction of the contract
+	IndexTx(ctx context.Context, tx entity.Transaction, c entity.Contract) error
+
+	// PostProcessMetadata is called with an nft of the contract after its metadata has been fetched
+	PostProcessMetadata(nft *entity.Nft)
+
+	// CanRefresh is whether the metadata of an nft of the contract may be refreshed
+	CanRefresh(nft entity.Nft) bool
+}
+
+// BaseHandler implements the hooks of a ContractHandler as no-ops, for handlers to embed
+type BaseHandler struct{}
+
+func (BaseHandler) IndexTx(ctx context.Context, tx entity.Transaction, c entity.Contract) error {
+	return nil
+}
+
+func (BaseHandler) PostProcessMetadata(nft *entity.Nft) {}
+
+func (BaseHandler) CanRefresh(nft entity.Nft) bool {
+	return true
+}
+
+// Registration binds a handler to the contracts with one of its addresses on the configured index, or matching one
+// of its shapes from the contract shape registry
+type Registration struct {
+	Handler   ContractHandler
+	Addresses map[string][]string
+	Shapes    []string
+}
+
+type Registry interface {
+	GetHandlers(c entity.Contract) []ContractHandler
+	IndexTx(ctx context.Context, tx entity.Transaction, c entity.Contract) error
+	PostProcessMetadata(c entity.Contract, nft *entity.Nft)
+	CanRefresh(c entity.Contract, nft entity.Nft) bool
+}
+
+type registry struct {
+	network       string
+	shapes        factory.ContractShapeRegistry
+	registrations []Registration
+}
+
+func NewRegistry(network string, shapes factory.ContractShapeRegistry, registrations ...Registration) Registry {
+	return registry{network, shapes, registrations}
+}
+
+func (r registry) GetHandlers(c entity.Contract) []ContractHandler {
+	handlers := make([]ContractHandler, 0)
+
+	var matchedShapes map[string]bool
+	for _, registration := range r.registrations {
+		if hasAddress(registration.Addresses[r.network], c.Address) {
+			handlers = append(handlers, registration.Handler)
+			continue
+		}
+
+		if len(registration.Shapes) == 0 {
+			continue
+		}
+		if matchedShapes == nil {
+			matchedShapes = map[string]bool{}
+			for _, match := range r.shapes.Match(c) {
+				matchedShapes[match.Shape] = matchedShapes[match.Shape] || match.Matched
+			}
+		}
+		for _, shape := range registration.Shapes {
+			if matchedShapes[shape] {
+				handlers = append(handlers, registration.Handler)
+				break
+			}
+		}
+	}
+
+	return handlers
+}
+
+func (r registry) IndexTx(ctx context.Context, tx entity.Transaction, c entity.Contract) error {
+	for _, h := range r.GetHandlers(c) {
+		if err := h.IndexTx(ctx, tx, c); err != nil {
+			zap.L().With(zap.Error(err), zap.String("handler", h.Name()), zap.String("txId", tx.ID), zap.String("contract", c.Address)).
+				Error("Contract handler failed to index tx")
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r registry) PostProcessMetadata(c entity.Contract, nft *entity.Nft) {
+	for _, h := range r.GetHandlers(c) {
+		h.PostProcessMetadata(nft)
+	}
+}
+
+func (r registry) CanRefresh(c entity.Contract, nft entity.Nft) bool {
+	for _, h := range r.GetHandlers(c) {
+		if !h.CanRefresh(nft) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func hasAddress(addresses []string, address string) bool {
+	for _, a := range addresses {
+		if strings.EqualFold(a, address) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"context"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/memory"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/zilliqa"
+	"testing"
+	"time"
+)
+
+const (
+	duckContract      = "0x1111111111111111111111111111111111111111"
+	morphContract     = "0x2222222222222222222222222222222222222222"
+	noRefreshContract = "0x3333333333333333333333333333333333333333"
+	otherContract     = "0x4444444444444444444444444444444444444444"
+
+	oldDuckUri  = "ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/1.json"
+	newDuckUri  = "https://ipfs.io/ipfs/QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o/1.png"
+	newDuckMeta = "ipfs://QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o/1.json"
+)
+
+// testStore wires the handlers to one in-memory index, registered as the container does on mainnet
+type testStore struct {
+	index         memory.Index
+	nftRepo       repository.NftRepository
+	nftActionRepo repository.NftActionRepository
+	handlers      Registry
+}
+
+func newTestStore() testStore {
+	index := memory.New()
+	nftRepo := memory.NewNftRepository(index)
+
+	return testStore{
+		index:         index,
+		nftRepo:       nftRepo,
+		nftActionRepo: memory.NewNftActionRepository(index),
+		handlers: NewRegistry("mainnet", nil,
+			Registration{
+				Handler:   NewDuckHandler(index, nftRepo),
+				Addresses: map[string][]string{"mainnet": {duckContract}},
+			},
+			Registration{
+				Handler:   NewZilMorphsHandler(index, memory.NewContractStateRepository(index), nftRepo),
+				Addresses: map[string][]string{"mainnet": {morphContract}},
+			},
+			Registration{
+				Handler:   NewNoRefreshHandler(),
+				Addresses: map[string][]string{"mainnet": {noRefreshContract}},
+			},
+		),
+	}
+}
+
+func (s testStore) getNft(t *testing.T, contract string, tokenId uint64) entity.Nft {
+	t.Helper()
+
+	nft, err := s.nftRepo.GetNft(context.Background(), contract, tokenId)
+	if err != nil {
+		t.Fatalf("nft %s %d: %v", contract, tokenId, err)
+	}
+
+	return *nft
+}
+
+func param(name, value string) entity.Param {
+	return entity.Param{VName: name, Type: "String", Value: &entity.Value{Primitive: value}}
+}
+
+func regenerateDuckTx(contract string) entity.Transaction {
+	tx := entity.Transaction{BlockNum: 10, Timestamp: time.Unix(10, 0).UTC(), IsContractExecution: true, ContractAddress: contract}
+	tx.ID = "regenerate"
+	tx.Receipt.Transitions = []entity.Transition{{
+		Transition: zilliqa.Transition{Addr: contract},
+		Msg: entity.TransitionMessage{
+			TransactionMessage: zilliqa.TransactionMessage{Tag: "regenerateDuck", Recipient: contract},
+			Params:             entity.Params{param("token_id", "1"), param("new_duck_uri", newDuckUri), param("new_duck_metadata", newDuckMeta)},
+		},
+	}}
+
+	return tx
+}
+
+func morphUrisUpdatedTx(contract string) entity.Transaction {
+	tx := entity.Transaction{BlockNum: 10, Timestamp: time.Unix(10, 0).UTC(), IsContractExecution: true, ContractAddress: contract}
+	tx.ID = "morph"
+	tx.Receipt.EventLogs = []entity.EventLog{{
+		EventName: string(entity.ZRC1MorphTokenURIsUpdated),
+		Address:   contract,
+		Params:    entity.Params{param("token_ids", `["1"]`)},
+	}}
+
+	return tx
+}
+
+func TestIndexTxRefreshesMetadata(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		contract string
+		tx       func(contract string) entity.Transaction
+		tokenUri string
+		assetUri string
+		metadata string
+		status   entity.MetadataStatus
+	}{
+		{"duck regenerated", duckContract, regenerateDuckTx, newDuckMeta, "ipfs://QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o/1.png", newDuckMeta, entity.MetadataPending},
+		{"morph uris updated", morphContract, morphUrisUpdatedTx, "https://zilmorphs.com/meta/1", "", "https://zilmorphs.com/meta/1", entity.MetadataSuccess},
+		{"duck transition on a no-refresh contract", noRefreshContract, regenerateDuckTx, oldDuckUri, "", oldDuckUri, entity.MetadataSuccess},
+		{"morph event on an unhandled contract", otherContract, morphUrisUpdatedTx, oldDuckUri, "", oldDuckUri, entity.MetadataSuccess},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTestStore()
+			s.index.Save(elastic_search.ContractStateIndex.Get(), entity.ContractState{
+				Address: tc.contract,
+				State:   []entity.StateElement{{Key: "base_uri", Value: "https://zilmorphs.com/meta/"}},
+			})
+			s.index.Save(elastic_search.NftIndex.Get(), entity.Nft{
+				Contract: tc.contract,
+				TokenId:  1,
+				Zrc1:     true,
+				TokenUri: oldDuckUri,
+				Metadata: &entity.Metadata{Uri: oldDuckUri, IsIpfs: true, Status: entity.MetadataSuccess},
+			})
+
+			if err := s.handlers.IndexTx(context.Background(), tc.tx(tc.contract), entity.Contract{Address: tc.contract}); err != nil {
+				t.Fatal(err)
+			}
+			s.index.Persist()
+
+			nft := s.getNft(t, tc.contract, 1)
+			if nft.TokenUri != tc.tokenUri || nft.AssetUri != tc.assetUri {
+				t.Errorf("token uri %q and asset uri %q, expected %q and %q", nft.TokenUri, nft.AssetUri, tc.tokenUri, tc.assetUri)
+			}
+			if nft.Metadata == nil || nft.Metadata.Uri != tc.metadata || nft.Metadata.Status != tc.status {
+				t.Fatalf("metadata %+v, expected %s from %q", nft.Metadata, tc.status, tc.metadata)
+			}
+
+			actions, err := s.nftActionRepo.GetNftActions(context.Background(), nft, nil, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.tokenUri == oldDuckUri {
+				if len(actions) != 0 {
+					t.Errorf("actions %+v, expected none", actions)
+				}
+				return
+			}
+			if len(actions) != 1 || actions[0].Action != entity.TokenUriAction || actions[0].From != oldDuckUri || actions[0].To != tc.tokenUri {
+				t.Errorf("actions %+v, expected a token uri change from %s to %s", actions, oldDuckUri, tc.tokenUri)
+			}
+		})
+	}
+}
+
+func TestMetadataRefreshByHandler(t *testing.T) {
+	s := newTestStore()
+	for _, contract := range []string{duckContract, morphContract, noRefreshContract, otherContract} {
+		s.index.Save(elastic_search.NftIndex.Get(), entity.Nft{
+			Contract: contract,
+			TokenId:  7,
+			Zrc6:     true,
+			Metadata: &entity.Metadata{Uri: "https://example.com/7", Status: entity.MetadataFailure, Attempts: 1},
+		})
+	}
+
+	// The failed nfts are read back as the refresh does, the handlers decide which are refreshed
+	failed, _, err := s.nftRepo.GetMetadata(context.Background(), 10, 1, entity.MetadataFailure)
+	if err != nil {
+		t.Fatal(err)
+	}
+	canRefresh := map[string]bool{}
+	for _, nft := range failed {
+		canRefresh[nft.Contract] = s.handlers.CanRefresh(entity.Contract{Address: nft.Contract}, nft)
+	}
+
+	for _, tc := range []struct {
+		name       string
+		contract   string
+		canRefresh bool
+		image      string
+	}{
+		{"duck", duckContract, true, "https://example.com/7.png"},
+		{"zilmorphs", morphContract, true, "https://zilmorphs.com/morph/7.png"},
+		{"no-refresh", noRefreshContract, false, "https://example.com/7.png"},
+		{"unhandled", otherContract, true, "https://example.com/7.png"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			refresh, ok := canRefresh[tc.contract]
+			if !ok {
+				t.Fatalf("failed nft of %s not found", tc.contract)
+			}
+			if refresh != tc.canRefresh {
+				t.Errorf("can refresh %t, expected %t", refresh, tc.canRefresh)
+			}
+
+			nft := s.getNft(t, tc.contract, 7)
+			nft.Metadata.Properties = map[string]interface{}{"image": "https://example.com/7.png"}
+			s.handlers.PostProcessMetadata(entity.Contract{Address: tc.contract}, &nft)
+			if image := nft.Metadata.Properties["image"]; image != tc.image {
+				t.Errorf("image %v, expected %s", image, tc.image)
+			}
+		})
+	}
+}
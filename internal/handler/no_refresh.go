@@ -0,0 +1,20 @@
+package handler
+
+import "github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+
+// noRefreshHandler keeps the nfts of a contract out of the pending and failed metadata refreshes
+type noRefreshHandler struct {
+	BaseHandler
+}
+
+func NewNoRefreshHandler() ContractHandler {
+	return noRefreshHandler{}
+}
+
+func (h noRefreshHandler) Name() string {
+	return "no-refresh"
+}
+
+func (h noRefreshHandler) CanRefresh(nft entity.Nft) bool {
+	return false
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/factory"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+	"strconv"
+)
+
+// zilMorphsHandler rebuilds the token uris of morphs from the contract base uri when they are updated, and serves
+// their images from zilmorphs.com rather than their metadata
+type zilMorphsHandler struct {
+	elastic           elastic_search.Index
+	contractStateRepo repository.ContractStateRepository
+	nftRepo           repository.NftRepository
+}
+
+func NewZilMorphsHandler(
+	elastic elastic_search.Index,
+	contractStateRepo repository.ContractStateRepository,
+	nftRepo repository.NftRepository,
+) ContractHandler {
+	return zilMorphsHandler{elastic, contractStateRepo, nftRepo}
+}
+
+func (h zilMorphsHandler) Name() string {
+	return "zilmorphs"
+}
+
+func (h zilMorphsHandler) IndexTx(ctx context.Context, tx entity.Transaction, c entity.Contract) error {
+	for _, event := range tx.GetEventLogs(entity.ZRC1MorphTokenURIsUpdated) {
+		tokenIdsParam, err := event.Params.GetParam("token_ids")
+		if err != nil {
+			zap.L().With(zap.Error(err)).Error("Failed to get token_ids from ZRC1MorphTokenURIsUpdated")
+			continue
+		}
+		var tokenIds []string
+
+		err = json.Unmarshal([]byte(tokenIdsParam.Value.String()), &tokenIds)
+		if err != nil {
+			zap.L().With(zap.Error(err)).Error("Failed to unmarshal token_ids from ZRC1MorphTokenURIsUpdated")
+			continue
+		}
+
+		state, err := h.contractStateRepo.GetStateByAddress(ctx, c.Address)
+		if err != nil {
+			zap.L().With(zap.Error(err)).Error("Failed to get contract state from ZRC1MorphTokenURIsUpdated")
+			continue
+		}
+		baseUri, exists := state.GetElement("base_uri")
+		if !exists {
+			zap.L().With(zap.Error(err)).Error("Failed to get baseUri from ZRC1MorphTokenURIsUpdated")
+			continue
+		}
+
+		for _, tokenIdString := range tokenIds {
+			tokenId, err := strconv.ParseUint(tokenIdString, 10, 64)
+			if err != nil {
+				zap.L().With(zap.Error(err), zap.String("tokenId", tokenIdString)).Error("Failed to parse token_id from ZRC1MorphTokenURIsUpdated")
+				continue
+			}
+			nft, err := h.nftRepo.GetNft(ctx, c.Address, tokenId)
+			if err != nil {
+				zap.L().With(zap.Error(err), zap.Uint64("tokenId", tokenId)).Error("Failed to find nft from ZRC1MorphTokenURIsUpdated")
+				continue
+			}
+
+			oldTokenUri := nft.TokenUri
+			nft.TokenUri = fmt.Sprintf("%s%d", baseUri, tokenId)
+			nft.Metadata.Uri = factory.GetMetadataUri(*nft)
+
+			zap.L().With(
+				zap.String("contract", c.Address),
+				zap.Uint64("tokenId", nft.TokenId),
+				zap.String("from", oldTokenUri),
+				zap.String("to", nft.TokenUri),
+			).Info("Update token URI")
+			h.elastic.AddUpdateRequest(elastic_search.NftIndex.Get(), *nft, elastic_search.Zrc1UpdateTokenUri)
+			h.elastic.AddIndexRequest(elastic_search.NftActionIndex.Get(),
+				factory.CreateChangeAction(entity.TokenUriAction, *nft, tx, oldTokenUri, nft.TokenUri), elastic_search.NftAction)
+		}
+	}
+
+	return nil
+}
+
+func (h zilMorphsHandler) PostProcessMetadata(nft *entity.Nft) {
+	if nft.Metadata == nil || nft.Metadata.Properties == nil {
+		return
+	}
+	nft.Metadata.Properties["image"] = fmt.Sprintf("https://zilmorphs.com/morph/%d.png", nft.TokenId)
+}
+
+func (h zilMorphsHandler) CanRefresh(nft entity.Nft) bool {
+	return true
+}
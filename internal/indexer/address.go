@@ -0,0 +1,314 @@
+package indexer
+
+import (
+	"context"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/factory"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/zilliqa"
+	"go.uber.org/zap"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type AddressIndexer interface {
+	IndexTxs(ctx context.Context, txs []entity.Transaction) error
+	Reconcile(ctx context.Context) error
+	Rollback(ctx context.Context, blockNum uint64) error
+}
+
+type addressIndexer struct {
+	elastic       elastic_search.Index
+	zilliqa       zilliqa.Service
+	addressRepo   repository.AddressRepository
+	txRepo        repository.TransactionRepository
+	reconcileSize int
+	reconcileAge  time.Duration
+}
+
+// A movement of ZIL in or out of an address made by a transaction
+type movement struct {
+	address  string
+	blockNum uint64
+	amount   *big.Int
+	nonce    uint64
+}
+
+func NewAddressIndexer(
+	elastic elastic_search.Index,
+	zilliqa zilliqa.Service,
+	addressRepo repository.AddressRepository,
+	txRepo repository.TransactionRepository,
+	reconcileSize int,
+	reconcileAge time.Duration,
+) AddressIndexer {
+	if reconcileSize < 1 {
+		reconcileSize = 100
+	}
+	if reconcileAge <= 0 {
+		reconcileAge = 24 * time.Hour
+	}
+
+	return addressIndexer{elastic, zilliqa, addressRepo, txRepo, reconcileSize, reconcileAge}
+}
+
+// IndexTxs applies the fees, payments and contract transfers of a range of transactions to the balances of the
+// addresses involved. Transactions at or below the block an address was last updated at have already been applied,
+// so they are skipped and the address is left for reconciliation to correct instead.
+func (i addressIndexer) IndexTxs(ctx context.Context, txs []entity.Transaction) error {
+	movements := make([]movement, 0)
+	for _, tx := range txs {
+		movements = append(movements, createMovements(tx)...)
+	}
+	if len(movements) == 0 {
+		return nil
+	}
+
+	addrs := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, m := range movements {
+		if !seen[m.address] {
+			seen[m.address] = true
+			addrs = append(addrs, m.address)
+		}
+	}
+
+	addresses, err := i.addressRepo.GetAddresses(ctx, addrs)
+	if err != nil {
+		zap.L().With(zap.Error(err)).Error("Failed to get addresses")
+		return err
+	}
+
+	appliedTo := make(map[string]uint64, len(addrs))
+	for _, addr := range addrs {
+		if _, ok := addresses[addr]; !ok {
+			addresses[addr] = factory.CreateAddress(addr)
+		}
+		appliedTo[addr] = addresses[addr].BlockNum
+	}
+
+	for _, m := range movements {
+		address := addresses[m.address]
+		if m.blockNum <= appliedTo[m.address] {
+			address.ReconciledAt = time.Time{}
+			addresses[m.address] = address
+			continue
+		}
+
+		balance, ok := new(big.Int).SetString(address.Balance, 10)
+		if !ok {
+			balance = new(big.Int)
+		}
+		address.Balance = balance.Add(balance, m.amount).String()
+
+		if m.nonce > address.Nonce {
+			address.Nonce = m.nonce
+		}
+		if m.blockNum > address.BlockNum {
+			address.BlockNum = m.blockNum
+		}
+		addresses[m.address] = address
+	}
+
+	for _, addr := range addrs {
+		i.elastic.AddIndexRequest(elastic_search.AddressIndex.Get(), addresses[addr], elastic_search.AddressBalance)
+	}
+
+	return nil
+}
+
+// createMovements debits the fee from the sender of every transaction, and moves the amounts that were accepted
+// by their recipient. Failed transactions only cost their fee.
+func createMovements(tx entity.Transaction) []movement {
+	sender := strings.ToLower(tx.SenderAddr)
+	if sender == "" {
+		return nil
+	}
+
+	nonce, _ := strconv.ParseUint(tx.Nonce, 10, 64)
+	movements := []movement{{sender, tx.BlockNum, new(big.Int).Neg(parseQa(tx.Fee)), nonce}}
+
+	if tx.IsFailed {
+		return movements
+	}
+
+	recipient := tx.ContractAddress
+	if tx.IsPayment {
+		recipient = tx.RecipientAddr
+	}
+	if amount := parseQa(tx.Amount); amount.Sign() > 0 && recipient != "" && (tx.IsPayment || tx.IsContractCreation || tx.Receipt.Accept) {
+		movements = append(movements, transfer(tx.BlockNum, sender, strings.ToLower(recipient), amount)...)
+	}
+
+	for _, transition := range tx.Receipt.Transitions {
+		if amount := parseQa(transition.Msg.Amount); amount.Sign() > 0 && transition.Accept && transition.Msg.Recipient != "" {
+			movements = append(movements, transfer(tx.BlockNum, strings.ToLower(transition.Addr), strings.ToLower(transition.Msg.Recipient), amount)...)
+		}
+	}
+
+	return movements
+}
+
+func transfer(blockNum uint64, from, to string, amount *big.Int) []movement {
+	return []movement{
+		{address: from, blockNum: blockNum, amount: new(big.Int).Neg(amount)},
+		{address: to, blockNum: blockNum, amount: amount},
+	}
+}
+
+func parseQa(value string) *big.Int {
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return new(big.Int)
+	}
+
+	return amount
+}
+
+// Reconcile replaces the balances and nonces of the least recently reconciled addresses with those of the node.
+// The node reports them at the chain tip, so the addresses are moved to the tip and the transactions up to it are
+// not applied to them again. The tip is read after the balances, so a transaction landing in between is skipped
+// rather than applied twice, and leaves the address to be reconciled again.
+func (i addressIndexer) Reconcile(ctx context.Context) error {
+	addresses, err := i.addressRepo.GetAddressesToReconcile(ctx, time.Now().Add(-i.reconcileAge), i.reconcileSize)
+	if err != nil {
+		return err
+	}
+
+	balances := make(map[string]*zilliqa.BalanceAndNonce, len(addresses))
+	for _, address := range addresses {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		balance, err := i.zilliqa.GetBalance(ctx, address.Address)
+		if err != nil {
+			zap.L().With(zap.Error(err), zap.String("address", address.Address)).Warn("Failed to get balance")
+			continue
+		}
+		balances[address.Address] = balance
+	}
+
+	tip, err := i.zilliqa.GetLatestTxBlock(ctx)
+	if err != nil {
+		return err
+	}
+	tipNum, err := strconv.ParseUint(tip.Header.BlockNum, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	for _, address := range addresses {
+		balance, ok := balances[address.Address]
+		if !ok {
+			continue
+		}
+
+		if balance.Balance != address.Balance {
+			zap.L().With(
+				zap.String("address", address.Address),
+				zap.String("indexed", address.Balance),
+				zap.String("node", balance.Balance),
+			).Debug("Reconciled address balance")
+		}
+
+		address.Balance = balance.Balance
+		address.Nonce = uint64(balance.Nonce)
+		if tipNum > address.BlockNum {
+			address.BlockNum = tipNum
+		}
+		address.ReconciledAt = time.Now().UTC()
+		i.elastic.AddIndexRequest(elastic_search.AddressIndex.Get(), address, elastic_search.AddressReconcile)
+	}
+
+	zap.L().With(zap.Int("count", len(balances)), zap.Uint64("blockNum", tipNum)).Info("Reconciled addresses")
+
+	return nil
+}
+
+// Rollback takes the movements of the transactions after the block back off the balances they were applied to,
+// leaving the addresses as they were at the block. An address reconciled beyond the indexed transactions holds
+// movements which cannot be taken back, so it is left due for reconciliation.
+func (i addressIndexer) Rollback(ctx context.Context, blockNum uint64) error {
+	size := 1000
+
+	addresses := make(map[string]entity.Address)
+	appliedTo := make(map[string]uint64)
+
+	var after *entity.Transaction
+	for {
+		txs, err := i.txRepo.GetTxsAfterBlockNum(ctx, blockNum, after, size)
+		if err != nil {
+			return err
+		}
+		if len(txs) == 0 {
+			break
+		}
+
+		movements := make([]movement, 0)
+		unseen := make([]string, 0)
+		for _, tx := range txs {
+			for _, m := range createMovements(tx) {
+				movements = append(movements, m)
+				if _, seen := appliedTo[m.address]; !seen {
+					appliedTo[m.address] = 0
+					unseen = append(unseen, m.address)
+				}
+			}
+		}
+
+		if len(unseen) != 0 {
+			found, err := i.addressRepo.GetAddresses(ctx, unseen)
+			if err != nil {
+				zap.L().With(zap.Error(err)).Error("Failed to get addresses")
+				return err
+			}
+			for addr, address := range found {
+				addresses[addr] = address
+				appliedTo[addr] = address.BlockNum
+			}
+		}
+
+		for _, m := range movements {
+			address, ok := addresses[m.address]
+			if !ok || m.blockNum > appliedTo[m.address] {
+				continue
+			}
+
+			balance, ok := new(big.Int).SetString(address.Balance, 10)
+			if !ok {
+				balance = new(big.Int)
+			}
+			address.Balance = balance.Sub(balance, m.amount).String()
+
+			if m.nonce != 0 && m.nonce-1 < address.Nonce {
+				address.Nonce = m.nonce - 1
+			}
+			addresses[m.address] = address
+		}
+
+		after = &txs[len(txs)-1]
+	}
+
+	reverted := 0
+	for addr, address := range addresses {
+		if appliedTo[addr] <= blockNum {
+			continue
+		}
+		if after != nil && appliedTo[addr] > after.BlockNum {
+			address.ReconciledAt = time.Time{}
+		}
+		address.BlockNum = blockNum
+
+		i.elastic.AddIndexRequest(elastic_search.AddressIndex.Get(), address, elastic_search.AddressRollback)
+		reverted++
+	}
+	i.elastic.Persist()
+
+	zap.L().With(zap.Int("count", reverted), zap.Uint64("blockNum", blockNum)).Info("Rolled back addresses")
+
+	return nil
+}
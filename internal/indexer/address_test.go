@@ -0,0 +1,95 @@
+package indexer
+
+import (
+	"context"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/zilliqa"
+	"testing"
+)
+
+const addressContract = "0xacacacacacacacacacacacacacacacacacacacac"
+
+func addressTestTxs() []entity.Transaction {
+	payment := entity.Transaction{BlockNum: 1, IsPayment: true, SenderAddr: alice, RecipientAddr: bob, Fee: "10"}
+	payment.ID, payment.Nonce, payment.Amount = "payment", "1", "1000"
+
+	call := callTx("call", 2, bob, addressContract, "Withdraw")
+	call.Fee, call.Amount = "5", "100"
+	call.Receipt.Accept = true
+	call.Receipt.Transitions = []entity.Transition{{
+		Transition: zilliqa.Transition{Addr: addressContract, Accept: true},
+		Msg:        entity.TransitionMessage{TransactionMessage: zilliqa.TransactionMessage{Recipient: carol, Amount: "40"}},
+	}}
+
+	failed := callTx("failed", 3, carol, addressContract, "Withdraw")
+	failed.Fee, failed.Amount, failed.IsFailed = "3", "20", true
+
+	return []entity.Transaction{payment, call, failed}
+}
+
+func TestAddressIndexTxsAndRollback(t *testing.T) {
+	s := newTestStore()
+	ctx := context.Background()
+
+	s.save(elastic_search.AddressIndex, entity.Address{Address: alice, Balance: "5000"})
+	txs := addressTestTxs()
+	for _, tx := range txs {
+		s.save(elastic_search.TransactionIndex, tx)
+	}
+
+	i := NewAddressIndexer(s.index, nil, s.addressRepo, s.txRepo, 0, 0)
+
+	// Transactions at or below the block of an address are not applied again
+	for run := 0; run < 2; run++ {
+		if err := i.IndexTxs(ctx, txs); err != nil {
+			t.Fatal(err)
+		}
+		s.index.Persist()
+	}
+
+	assertAddresses(t, s, map[string]entity.Address{
+		alice:           {Balance: "3990", Nonce: 1, BlockNum: 1},
+		bob:             {Balance: "895", Nonce: 1, BlockNum: 2},
+		carol:           {Balance: "37", Nonce: 1, BlockNum: 3},
+		addressContract: {Balance: "60", BlockNum: 2},
+	})
+
+	if err := i.Rollback(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	s.index.Persist()
+
+	assertAddresses(t, s, map[string]entity.Address{
+		alice:           {Balance: "3990", Nonce: 1, BlockNum: 1},
+		bob:             {Balance: "1000", Nonce: 0, BlockNum: 1},
+		carol:           {Balance: "0", Nonce: 0, BlockNum: 1},
+		addressContract: {Balance: "0", BlockNum: 1},
+	})
+}
+
+func assertAddresses(t *testing.T, s testStore, expected map[string]entity.Address) {
+	t.Helper()
+
+	addrs := make([]string, 0, len(expected))
+	for addr := range expected {
+		addrs = append(addrs, addr)
+	}
+
+	addresses, err := s.addressRepo.GetAddresses(context.Background(), addrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for addr, want := range expected {
+		got, ok := addresses[addr]
+		if !ok {
+			t.Errorf("address %s not indexed", addr)
+			continue
+		}
+		if got.Balance != want.Balance || got.Nonce != want.Nonce || got.BlockNum != want.BlockNum {
+			t.Errorf("address %s has balance %s, nonce %d at block %d, expected balance %s, nonce %d at block %d",
+				addr, got.Balance, got.Nonce, got.BlockNum, want.Balance, want.Nonce, want.BlockNum)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package indexer
+
+import (
+	"context"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/shutdown"
+	"go.uber.org/zap"
+	"sync"
+)
+
+type txBatch struct {
+	height  uint64
+	size    uint64
+	blocks  Blocks
+	skipped []entity.SkippedBlock
+	err     error
+}
+
+// bulkIndex fetches block ranges concurrently and writes them strictly in block order. Batches fetched ahead
+// of the writer wait in a reorder buffer, which is bounded so fetching stalls while persistence is behind.
+// Cancelling ctx stops new ranges being fetched, while those in flight are still written.
+func (i indexer) bulkIndex(ctx context.Context, height, target uint64) error {
+	if height > target {
+		zap.L().With(zap.Uint64("target", target)).Info("Transactions indexed to target")
+		return nil
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	work, cancel := shutdown.Grace(ctx)
+	defer cancel()
+
+	window := make(chan struct{}, i.maxPendingBatches)
+	ranges := make(chan txBatch)
+	go func() {
+		defer close(ranges)
+		for from := height; from <= target; from += i.bulkIndexSize {
+			size := i.bulkIndexSize
+			if from+size > target {
+				size = target - from + 1
+			}
+
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			}
+
+			select {
+			case ranges <- txBatch{height: from, size: size}:
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	results := make(chan txBatch, i.bulkIndexWorkers)
+	wg := sync.WaitGroup{}
+	for w := 0; w < i.bulkIndexWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range ranges {
+				batch.blocks, batch.skipped, batch.err = i.fetch(work, batch.height, batch.size)
+				select {
+				case results <- batch:
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	next := height
+	pending := map[uint64]txBatch{}
+	for batch := range results {
+		pending[batch.height] = batch
+
+		for {
+			batch, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+
+			if batch.err != nil {
+				zap.L().With(zap.Error(batch.err), zap.Uint64("height", batch.height), zap.Uint64("size", batch.size)).Debug("Failed to index transactions")
+				return indexError(batch.err)
+			}
+
+			for _, skippedBlock := range batch.skipped {
+				i.quarantine(skippedBlock)
+			}
+			i.txIndexer.IndexBlocks(work, batch.blocks)
+			if err := i.addressIndexer.IndexTxs(work, batch.blocks.Txs); err != nil {
+				return err
+			}
+			i.SetLastBlockNumIndexed(batch.height + batch.size - 1)
+			i.checkpoint(entity.TransactionStage, batch.height+batch.size-1)
+			i.elastic.BatchPersist()
+
+			next += batch.size
+			<-window
+		}
+	}
+
+	if next <= target {
+		zap.L().With(zap.Uint64("height", next-1)).Info("Transaction indexing stopped")
+		return ctx.Err()
+	}
+
+	zap.L().With(zap.Uint64("target", target)).Info("Transactions indexed to target")
+
+	return nil
+}
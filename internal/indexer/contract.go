@@ -1,12 +1,14 @@
 package indexer
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/factory"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/metadata"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/shutdown"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/zilliqa"
 	"github.com/Zilliqa/gozilliqa-sdk/bech32"
 	"go.uber.org/zap"
@@ -14,8 +16,8 @@ import (
 )
 
 type ContractIndexer interface {
-	Index(txs []entity.Transaction) error
-	BulkIndex(fromBlockNum uint64) error
+	Index(ctx context.Context, txs []entity.Transaction) error
+	BulkIndex(ctx context.Context, fromBlockNum uint64) error
 	IndexContractMetadata(contract *entity.Contract)
 }
 
@@ -41,21 +43,21 @@ func NewContractIndexer(
 	return contractIndexer{elastic, zilliqa, factory, txRepo, contractRepo, nftRepo, metadataService}
 }
 
-func (i contractIndexer) Index(txs []entity.Transaction) error {
+func (i contractIndexer) Index(ctx context.Context, txs []entity.Transaction) error {
 	for _, tx := range txs {
 		if tx.Receipt.Success == false {
 			continue
 		}
 
 		if tx.IsContractCreation {
-			c, err := i.factory.CreateContractFromTx(tx)
+			c, err := i.factory.CreateContractFromTx(ctx, tx)
 			if err == nil {
 				zap.L().With(
 					zap.Uint64("blockNum", c.BlockNum),
 					zap.String("name", c.Name),
 					zap.String("address", c.Address),
 				).Info("Index contract")
-				_ = i.indexContractState(c)
+				_ = i.indexContractState(ctx, c)
 				i.IndexContractMetadata(c)
 
 				i.elastic.AddIndexRequest(elastic_search.ContractIndex.Get(), *c, elastic_search.ContractCreate)
@@ -68,9 +70,9 @@ func (i contractIndexer) Index(txs []entity.Transaction) error {
 				wg.Add(1)
 				go func(addr string) {
 					defer wg.Done()
-					c, err := i.contractRepo.GetContractByAddress(addr)
+					c, err := i.contractRepo.GetContractByAddress(ctx, addr)
 					if err == nil {
-						_ = i.indexContractState(c)
+						_ = i.indexContractState(ctx, c)
 						i.elastic.AddIndexRequest(elastic_search.ContractIndex.Get(), *c, elastic_search.ContractState)
 					}
 				}(contractAddr)
@@ -82,13 +84,21 @@ func (i contractIndexer) Index(txs []entity.Transaction) error {
 	return nil
 }
 
-func (i contractIndexer) BulkIndex(fromBlockNum uint64) error {
+func (i contractIndexer) BulkIndex(ctx context.Context, fromBlockNum uint64) error {
 	zap.L().With(zap.Uint64("from", fromBlockNum)).Info("Bulk index contracts")
 	size := 100
 	page := 1
 
+	// A page being indexed when ctx is cancelled is finished, so its checkpoint is written
+	work, cancel := shutdown.Grace(ctx)
+	defer cancel()
+
 	for {
-		txs, _, err := i.txRepo.GetContractCreationTxs(fromBlockNum, size, page)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		txs, _, err := i.txRepo.GetContractCreationTxs(work, fromBlockNum, size, page)
 		if err != nil {
 			zap.L().With(zap.Error(err)).Error("Failed to get contract txs")
 			return err
@@ -98,11 +108,11 @@ func (i contractIndexer) BulkIndex(fromBlockNum uint64) error {
 		}
 
 		for _, tx := range txs {
-			if _, err := i.contractRepo.GetContractByAddress(tx.ContractAddress); err == nil {
+			if _, err := i.contractRepo.GetContractByAddress(work, tx.ContractAddress); err == nil {
 				//continue
 			}
 
-			c, err := i.factory.CreateContractFromTx(tx)
+			c, err := i.factory.CreateContractFromTx(work, tx)
 			if err != nil {
 				continue
 			}
@@ -113,13 +123,15 @@ func (i contractIndexer) BulkIndex(fromBlockNum uint64) error {
 				zap.String("address", c.Address),
 			).Info("Index contract")
 
-			_ = i.indexContractState(c)
+			_ = i.indexContractState(work, c)
 
 			i.elastic.AddIndexRequest(elastic_search.ContractIndex.Get(), *c, elastic_search.ContractCreate)
 
 			i.elastic.BatchPersist()
 		}
 
+		checkpoint := factory.CreateCheckpoint(entity.ContractStage, txs[len(txs)-1].BlockNum, 0)
+		i.elastic.AddIndexRequest(elastic_search.CheckpointIndex.Get(), checkpoint, elastic_search.Checkpoint)
 		i.elastic.Persist()
 
 		page++
@@ -147,10 +159,10 @@ func (i contractIndexer) IndexContractMetadata(contract *entity.Contract) {
 	}
 }
 
-func (i contractIndexer) indexContractState(c *entity.Contract) error {
+func (i contractIndexer) indexContractState(ctx context.Context, c *entity.Contract) error {
 	bech32Addr, _ := bech32.ToBech32Address(c.Address)
 
-	state, err := i.zilliqa.GetContractState(bech32Addr)
+	state, err := i.zilliqa.GetContractState(ctx, bech32Addr)
 	if err != nil {
 		return err
 	}
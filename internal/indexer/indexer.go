@@ -1,31 +1,44 @@
 package indexer
 
 import (
+	"context"
 	"errors"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/factory"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/indexer/IndexOption"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/shutdown"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/zilliqa"
 	"github.com/patrickmn/go-cache"
 	"go.uber.org/zap"
 	"time"
 )
 
 type Indexer interface {
-	Index(option IndexOption.IndexOption, target uint64) error
+	Index(ctx context.Context, option IndexOption.IndexOption, target uint64) error
+	Repair(ctx context.Context) error
 
 	SetLastBlockNumIndexed(blockNum uint64)
-	GetLastBlockNumIndexed() (uint64, error)
+	GetLastBlockNumIndexed(ctx context.Context) (uint64, error)
 }
 
 type indexer struct {
 	bulkIndexSize      uint64
+	bulkIndexWorkers   int
+	maxPendingBatches  int
+	maxBlockRetries    int
 	elastic            elastic_search.Index
 	txIndexer          TransactionIndexer
 	contractIndexer    ContractIndexer
 	zrc1Indexer        Zrc1Indexer
 	zrc6Indexer        Zrc6Indexer
+	zrc2Indexer        Zrc2Indexer
 	marketplaceIndexer MarketplaceIndexer
+	addressIndexer     AddressIndexer
 	txRepo             repository.TransactionRepository
+	checkpointRepo     repository.CheckpointRepository
+	skippedBlockRepo   repository.SkippedBlockRepository
 	cache              *cache.Cache
 }
 
@@ -35,127 +48,215 @@ var (
 
 func NewIndexer(
 	bulkIndexSize uint64,
+	bulkIndexWorkers int,
+	maxPendingBatches int,
+	maxBlockRetries int,
 	elastic elastic_search.Index,
 	txIndexer TransactionIndexer,
 	contractIndexer ContractIndexer,
 	zrc1Indexer Zrc1Indexer,
 	zrc6Indexer Zrc6Indexer,
+	zrc2Indexer Zrc2Indexer,
 	marketplaceIndexer MarketplaceIndexer,
+	addressIndexer AddressIndexer,
 	txRepo repository.TransactionRepository,
+	checkpointRepo repository.CheckpointRepository,
+	skippedBlockRepo repository.SkippedBlockRepository,
 	cache *cache.Cache,
 ) Indexer {
+	if bulkIndexWorkers < 1 {
+		bulkIndexWorkers = 1
+	}
+	if maxPendingBatches < bulkIndexWorkers {
+		maxPendingBatches = bulkIndexWorkers * 2
+	}
+	if maxBlockRetries < 1 {
+		maxBlockRetries = 1
+	}
+
 	return indexer{
 		bulkIndexSize,
+		bulkIndexWorkers,
+		maxPendingBatches,
+		maxBlockRetries,
 		elastic,
 		txIndexer,
 		contractIndexer,
 		zrc1Indexer,
 		zrc6Indexer,
+		zrc2Indexer,
 		marketplaceIndexer,
+		addressIndexer,
 		txRepo,
+		checkpointRepo,
+		skippedBlockRepo,
 		cache,
 	}
 }
 
-func (i indexer) Index(option IndexOption.IndexOption, target uint64) error {
-	lastBlockIndexed, err := i.GetLastBlockNumIndexed()
+func (i indexer) Index(ctx context.Context, option IndexOption.IndexOption, target uint64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	lastBlockIndexed, err := i.GetLastBlockNumIndexed(ctx)
 	if err != nil {
 		time.Sleep(5 * time.Second)
 		zap.L().With(zap.Error(err)).Fatal("Failed to get last block num from txs")
 	}
 
 	height := lastBlockIndexed + 1
-	if target != 0 && height == target {
+	if target != 0 && height > target {
 		return nil
 	}
 
-	return i.index(height, target, option)
+	return i.index(ctx, height, target, option)
 }
 
-func (i indexer) index(height, target uint64, option IndexOption.IndexOption) error {
-	size := uint64(1)
+func (i indexer) index(ctx context.Context, height, target uint64, option IndexOption.IndexOption) error {
 	if option == IndexOption.BatchIndex {
-		if height > target {
-			zap.L().With(zap.Uint64("target", target)).Info("Transactions indexed to target")
-			return nil
+		return i.bulkIndex(ctx, height, target)
+	}
+
+	// A block being indexed when ctx is cancelled is finished, so it is persisted along with its checkpoints
+	work, cancel := shutdown.Grace(ctx)
+	defer cancel()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
 		}
 
-		size = i.bulkIndexSize
-		if height+i.bulkIndexSize > target {
-			size = target - height + 1
+		if err := i.indexBlock(work, height, target); err != nil {
+			return err
 		}
+
+		if target != 0 && height >= target {
+			return nil
+		}
+		height++
 	}
+}
 
-	txs, err := i.txIndexer.Index(height, size)
+// indexBlock indexes a single block, quarantining it once it has failed maxBlockRetries times behind the target
+func (i indexer) indexBlock(ctx context.Context, height, target uint64) error {
+	blocks, err := i.txIndexer.CreateBlocks(ctx, height, 1)
 	if err != nil {
-		zap.L().With(zap.Error(err), zap.Uint64("height", height), zap.Uint64("size", size)).Debug("Failed to index transactions")
-		if err.Error()[:7] == "-32602:" || err.Error()[:4] == "-20:" {
-			return ErrBlockNotReady
+		zap.L().With(zap.Error(err), zap.Uint64("height", height)).Debug("Failed to index transactions")
+		if !zilliqa.IsBlockUnavailable(err) || !i.failed(height, target) {
+			return indexError(err)
 		}
+		i.quarantine(factory.CreateSkippedBlock(height, err, i.maxBlockRetries))
+		blocks = Blocks{}
+	}
+	i.txIndexer.IndexBlocks(ctx, blocks)
+	i.SetLastBlockNumIndexed(height)
 
+	if err := i.indexTxs(ctx, height, blocks.Txs); err != nil {
 		return err
 	}
-	i.SetLastBlockNumIndexed(height + size - 1)
 
-	if option == IndexOption.SingleIndex {
-		if err := i.contractIndexer.Index(txs); err != nil {
-			zap.L().With(zap.Error(err), zap.Uint64("height", height), zap.Uint64("size", size)).Error("Failed to index Contacts")
-			return err
-		}
+	i.checkpoint(entity.TransactionStage, height)
+	i.checkpoint(entity.ContractStage, height)
+	i.checkpoint(entity.Zrc1Stage, height)
+	i.checkpoint(entity.Zrc6Stage, height)
+	i.checkpoint(entity.Zrc2Stage, height)
+	i.checkpoint(entity.MarketplaceStage, height)
 
-		if err := i.zrc1Indexer.IndexTxs(txs); err != nil {
-			zap.L().With(zap.Error(err), zap.Uint64("height", height), zap.Uint64("size", size)).Error("Failed to index ZRC1s")
-			return err
-		}
+	i.elastic.Persist()
 
-		if err := i.zrc6Indexer.IndexTxs(txs); err != nil {
-			zap.L().With(zap.Error(err), zap.Uint64("height", height), zap.Uint64("size", size)).Error("Failed to index ZRC6s")
-			return err
-		}
+	return nil
+}
 
-		if err := i.marketplaceIndexer.IndexTxs(txs); err != nil {
-			zap.L().With(zap.Error(err), zap.Uint64("height", height), zap.Uint64("size", size)).Error("Failed to index marketplace actions")
-			return err
-		}
+// indexTxs applies the transactions of a block to address balances, then runs the contract, NFT, token and marketplace
+// indexers over the successful ones
+func (i indexer) indexTxs(ctx context.Context, height uint64, txs []entity.Transaction) error {
+	if err := i.addressIndexer.IndexTxs(ctx, txs); err != nil {
+		zap.L().With(zap.Error(err), zap.Uint64("height", height)).Error("Failed to index addresses")
+		return err
 	}
 
-	if option == IndexOption.BatchIndex {
-		i.elastic.BatchPersist()
-	} else {
-		i.elastic.Persist()
+	txs = successful(txs)
+
+	if err := i.contractIndexer.Index(ctx, txs); err != nil {
+		zap.L().With(zap.Error(err), zap.Uint64("height", height)).Error("Failed to index Contacts")
+		return err
 	}
 
-	if target != 0 && height > target {
-		return nil
+	if err := i.zrc1Indexer.IndexTxs(ctx, txs); err != nil {
+		zap.L().With(zap.Error(err), zap.Uint64("height", height)).Error("Failed to index ZRC1s")
+		return err
 	}
 
-	if option == IndexOption.BatchIndex {
-		height = height + i.bulkIndexSize
-	} else {
-		height++
+	if err := i.zrc6Indexer.IndexTxs(ctx, txs); err != nil {
+		zap.L().With(zap.Error(err), zap.Uint64("height", height)).Error("Failed to index ZRC6s")
+		return err
 	}
 
-	return i.index(height, target, option)
+	if err := i.zrc2Indexer.IndexTxs(ctx, txs); err != nil {
+		zap.L().With(zap.Error(err), zap.Uint64("height", height)).Error("Failed to index ZRC2s")
+		return err
+	}
+
+	if err := i.marketplaceIndexer.IndexTxs(ctx, txs); err != nil {
+		zap.L().With(zap.Error(err), zap.Uint64("height", height)).Error("Failed to index marketplace actions")
+		return err
+	}
+
+	return nil
+}
+
+// successful drops the failed calls, which changed no state for the derived indexers to follow
+func successful(txs []entity.Transaction) []entity.Transaction {
+	successfulTxs := make([]entity.Transaction, 0, len(txs))
+	for _, tx := range txs {
+		if !tx.IsFailed {
+			successfulTxs = append(successfulTxs, tx)
+		}
+	}
+
+	return successfulTxs
+}
+
+// indexError maps the node's errors for blocks it cannot serve yet to ErrBlockNotReady
+func indexError(err error) error {
+	if zilliqa.IsBlockUnavailable(err) {
+		return ErrBlockNotReady
+	}
+
+	return err
 }
 
 func (i indexer) SetLastBlockNumIndexed(blockNum uint64) {
 	i.cache.Set("lastBlockNumIndexed", blockNum, cache.NoExpiration)
 }
 
-func (i indexer) GetLastBlockNumIndexed() (uint64, error) {
+func (i indexer) GetLastBlockNumIndexed(ctx context.Context) (uint64, error) {
 	if lastBlockNumIndexed, exists := i.cache.Get("lastBlockNumIndexed"); exists {
 		blockNum := lastBlockNumIndexed.(uint64)
 		return blockNum, nil
 	}
 
-	blockNum, err := i.txRepo.GetBestBlockNum()
+	blockNum, err := i.checkpointRepo.GetBlockNum(ctx, entity.TransactionStage)
 	if err != nil {
-		if err == repository.ErrBestBlockNumFound {
-			return 0, err
+		if err != repository.ErrCheckpointNotFound {
+			zap.L().With(zap.Error(err)).Fatal("Failed to get the transaction checkpoint")
+		}
+
+		// Indices created before checkpoints were introduced
+		blockNum, err = i.txRepo.GetBestBlockNum(ctx)
+		if err != nil {
+			if err == repository.ErrBestBlockNumFound {
+				return 0, err
+			}
+			zap.L().With(zap.Error(err)).Fatal("Failed to find the best block num")
 		}
-		zap.L().With(zap.Error(err)).Fatal("Failed to find the best block num")
 	}
 	i.SetLastBlockNumIndexed(blockNum)
 
 	return blockNum, nil
 }
+
+func (i indexer) checkpoint(stage entity.Stage, blockNum uint64) {
+	i.elastic.AddIndexRequest(elastic_search.CheckpointIndex.Get(), factory.CreateCheckpoint(stage, blockNum, 0), elastic_search.Checkpoint)
+}
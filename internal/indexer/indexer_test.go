@@ -0,0 +1,94 @@
+package indexer
+
+import (
+	"context"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/memory"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/zilliqa"
+	"testing"
+	"time"
+)
+
+const (
+	alice = "0x1111111111111111111111111111111111111111"
+	bob   = "0x2222222222222222222222222222222222222222"
+	carol = "0x3333333333333333333333333333333333333333"
+)
+
+// testStore wires the repositories of one in-memory index, as the container does for the memory driver
+type testStore struct {
+	index             memory.Index
+	txRepo            repository.TransactionRepository
+	contractRepo      repository.ContractRepository
+	nftRepo           repository.NftRepository
+	nftActionRepo     repository.NftActionRepository
+	operatorRepo      repository.OperatorRepository
+	tokenTransferRepo repository.TokenTransferRepository
+	tokenBalanceRepo  repository.TokenBalanceRepository
+	addressRepo       repository.AddressRepository
+}
+
+func newTestStore() testStore {
+	index := memory.New()
+
+	return testStore{
+		index:             index,
+		txRepo:            memory.NewTransactionRepository(index),
+		contractRepo:      memory.NewContractRepository(index),
+		nftRepo:           memory.NewNftRepository(index),
+		nftActionRepo:     memory.NewNftActionRepository(index),
+		operatorRepo:      memory.NewOperatorRepository(index),
+		tokenTransferRepo: memory.NewTokenTransferRepository(index),
+		tokenBalanceRepo:  memory.NewTokenBalanceRepository(index),
+		addressRepo:       memory.NewAddressRepository(index),
+	}
+}
+
+func (s testStore) save(index elastic_search.Indices, docs ...entity.Entity) {
+	for _, doc := range docs {
+		s.index.Save(index.Get(), doc)
+	}
+}
+
+func (s testStore) getContract(t *testing.T, addr string) entity.Contract {
+	t.Helper()
+
+	c, err := s.contractRepo.GetContractByAddress(context.Background(), addr)
+	if err != nil {
+		t.Fatalf("contract %s: %v", addr, err)
+	}
+
+	return *c
+}
+
+func param(name, value string) entity.Param {
+	return entity.Param{VName: name, Type: "String", Value: &entity.Value{Primitive: value}}
+}
+
+func eventLog(contract string, name entity.Event, params ...entity.Param) entity.EventLog {
+	return entity.EventLog{EventName: string(name), Address: contract, Params: params}
+}
+
+// callTx is a successful call of a contract at a block, notifying the sender with the callback
+func callTx(id string, blockNum uint64, sender, contract string, callback entity.Callback, events ...entity.EventLog) entity.Transaction {
+	tx := entity.Transaction{
+		BlockNum:            blockNum,
+		Timestamp:           time.Unix(int64(blockNum), 0).UTC(),
+		IsContractExecution: true,
+		ContractAddress:     contract,
+		SenderAddr:          sender,
+		Fee:                 "0",
+	}
+	tx.ID = id
+	tx.Nonce = "1"
+	tx.Amount = "0"
+	tx.Receipt.EventLogs = events
+	tx.Receipt.Transitions = []entity.Transition{{
+		Transition: zilliqa.Transition{Addr: contract},
+		Msg:        entity.TransitionMessage{TransactionMessage: zilliqa.TransactionMessage{Tag: string(callback), Recipient: sender}},
+	}}
+
+	return tx
+}
@@ -1,6 +1,7 @@
 package indexer
 
 import (
+	"context"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/factory"
@@ -9,7 +10,7 @@ import (
 )
 
 type MarketplaceIndexer interface {
-	IndexTxs(txs []entity.Transaction) error
+	IndexTxs(ctx context.Context, txs []entity.Transaction) error
 }
 
 type marketplaceIndexer struct {
@@ -45,21 +46,21 @@ func NewMarketplaceIndexer(
 	}
 }
 
-func (i marketplaceIndexer) IndexTxs(txs []entity.Transaction) error {
+func (i marketplaceIndexer) IndexTxs(ctx context.Context, txs []entity.Transaction) error {
 	for _, tx := range txs {
 		if !tx.IsContractExecution {
 			continue
 		}
 
-		if err := i.indexListings(tx); err != nil {
+		if err := i.indexListings(ctx, tx); err != nil {
 			continue
 			//return err
 		}
-		if err := i.indexDelistings(tx); err != nil {
+		if err := i.indexDelistings(ctx, tx); err != nil {
 			continue
 			//return err
 		}
-		if err := i.indexSales(tx); err != nil {
+		if err := i.indexSales(ctx, tx); err != nil {
 			continue
 			//return err
 		}
@@ -68,16 +69,16 @@ func (i marketplaceIndexer) IndexTxs(txs []entity.Transaction) error {
 	return nil
 }
 
-func (i marketplaceIndexer) indexListings(tx entity.Transaction) (err error) {
+func (i marketplaceIndexer) indexListings(ctx context.Context, tx entity.Transaction) (err error) {
 	var listing *entity.MarketplaceListing
 
 	switch {
 	case tx.IsMarketplaceListing(entity.OkimotoMarketplace):
-		listing, err = i.okimotoMarketplaceFactory.CreateListing(tx)
+		listing, err = i.okimotoMarketplaceFactory.CreateListing(ctx, tx)
 	case tx.IsMarketplaceListing(entity.ZilkroadMarketplace):
-		listing, err = i.zilkroadMarketplaceFactory.CreateListing(tx)
+		listing, err = i.zilkroadMarketplaceFactory.CreateListing(ctx, tx)
 	case tx.IsMarketplaceListing(entity.MintableMarketplace):
-		listing, err = i.mintableMarketplaceFactory.CreateListing(tx)
+		listing, err = i.mintableMarketplaceFactory.CreateListing(ctx, tx)
 	}
 
 	if err != nil {
@@ -92,16 +93,16 @@ func (i marketplaceIndexer) indexListings(tx entity.Transaction) (err error) {
 	return
 }
 
-func (i marketplaceIndexer) indexDelistings(tx entity.Transaction) (err error) {
+func (i marketplaceIndexer) indexDelistings(ctx context.Context, tx entity.Transaction) (err error) {
 	var delisting *entity.MarketplaceDelisting
 
 	switch {
 	case tx.IsMarketplaceDelisting(entity.OkimotoMarketplace):
-		delisting, err = i.okimotoMarketplaceFactory.CreateDelisting(tx)
+		delisting, err = i.okimotoMarketplaceFactory.CreateDelisting(ctx, tx)
 	case tx.IsMarketplaceDelisting(entity.ZilkroadMarketplace):
-		delisting, err = i.zilkroadMarketplaceFactory.CreateDelisting(tx)
+		delisting, err = i.zilkroadMarketplaceFactory.CreateDelisting(ctx, tx)
 	case tx.IsMarketplaceDelisting(entity.MintableMarketplace):
-		delisting, err = i.mintableMarketplaceFactory.CreateDelisting(tx)
+		delisting, err = i.mintableMarketplaceFactory.CreateDelisting(ctx, tx)
 	}
 
 	if err != nil {
@@ -116,18 +117,18 @@ func (i marketplaceIndexer) indexDelistings(tx entity.Transaction) (err error) {
 	return nil
 }
 
-func (i marketplaceIndexer) indexSales(tx entity.Transaction) (err error) {
+func (i marketplaceIndexer) indexSales(ctx context.Context, tx entity.Transaction) (err error) {
 	var sale *entity.MarketplaceSale
 
 	switch {
 	case tx.IsMarketplaceSale(entity.ZilkroadMarketplace):
-		sale, err = i.zilkroadMarketplaceFactory.CreateSale(tx)
+		sale, err = i.zilkroadMarketplaceFactory.CreateSale(ctx, tx)
 	case tx.IsMarketplaceSale(entity.OkimotoMarketplace):
-		sale, err = i.okimotoMarketplaceFactory.CreateSale(tx)
+		sale, err = i.okimotoMarketplaceFactory.CreateSale(ctx, tx)
 	case tx.IsMarketplaceSale(entity.ArkyMarketplace):
-		sale, err = i.arkyMarketplaceFactory.CreateSale(tx)
+		sale, err = i.arkyMarketplaceFactory.CreateSale(ctx, tx)
 	case tx.IsMarketplaceSale(entity.MintableMarketplace):
-		sale, err = i.mintableMarketplaceFactory.CreateSale(tx)
+		sale, err = i.mintableMarketplaceFactory.CreateSale(ctx, tx)
 	}
 
 	if err != nil {
@@ -154,7 +155,7 @@ func (i marketplaceIndexer) executeListing(listing entity.MarketplaceListing) {
 
 
 	i.elastic.AddIndexRequest(elastic_search.NftActionIndex.Get(),
-		factory.CreateMarketplaceListingAction(listing.Marketplace, listing.Nft, listing.Tx.BlockNum, listing.Tx.ID,
+		factory.CreateMarketplaceListingAction(listing.Marketplace, listing.Nft, listing.Tx,
 			listing.Cost, listing.Fungible), elastic_search.NftAction)
 }
 
@@ -167,7 +168,7 @@ func (i marketplaceIndexer) executeDelisting(delisting entity.MarketplaceDelisti
 	).Info("Marketplace delisting")
 
 	i.elastic.AddIndexRequest(elastic_search.NftActionIndex.Get(), factory.CreateMarketplaceDelistingAction(delisting.Marketplace,
-		delisting.Nft, delisting.Tx.BlockNum, delisting.Tx.ID), elastic_search.NftAction)
+		delisting.Nft, delisting.Tx), elastic_search.NftAction)
 }
 
 func (i marketplaceIndexer) executeSale(sale entity.MarketplaceSale) {
@@ -189,8 +190,8 @@ func (i marketplaceIndexer) executeSale(sale entity.MarketplaceSale) {
 	i.elastic.AddUpdateRequest(elastic_search.NftIndex.Get(), sale.Nft, elastic_search.Zrc6Transfer)
 
 	i.elastic.AddIndexRequest(elastic_search.NftActionIndex.Get(), factory.CreateTransferAction(sale.Nft,
-		sale.Tx.BlockNum, sale.Tx.ID, sale.Buyer, sale.Seller), elastic_search.Zrc6Transfer)
+		sale.Tx, sale.Buyer, sale.Seller), elastic_search.Zrc6Transfer)
 	i.elastic.AddIndexRequest(elastic_search.NftActionIndex.Get(), factory.CreateMarketplaceSaleAction(sale.Marketplace,
-		sale.Nft, sale.Tx.BlockNum, sale.Tx.ID, sale.Buyer, sale.Seller, sale.Cost, sale.Fee, sale.Royalty,
+		sale.Nft, sale.Tx, sale.Buyer, sale.Seller, sale.Cost, sale.Fee, sale.Royalty,
 		sale.RoyaltyBps, sale.Fungible), elastic_search.NftAction)
 }
\ No newline at end of file
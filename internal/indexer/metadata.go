@@ -1,6 +1,7 @@
 package indexer
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/event"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/handler"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/helper"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/messenger"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/metadata"
@@ -20,8 +22,8 @@ import (
 type MetadataIndexer interface {
 	TriggerContractMetadataRefresh(el interface{})
 	TriggerMetadataRefresh(el interface{})
-	RefreshMetadata(contractAddr string, tokenId uint64) (*entity.Nft, error)
-	RefreshByStatus(status entity.MetadataStatus) error
+	RefreshMetadata(ctx context.Context, contractAddr string, tokenId uint64) (*entity.Nft, error)
+	RefreshByStatus(ctx context.Context, status entity.MetadataStatus) error
 }
 
 type metadataIndexer struct {
@@ -30,6 +32,7 @@ type metadataIndexer struct {
 	contractRepo    repository.ContractRepository
 	messageService  messenger.MessageService
 	metadataService metadata.Service
+	handlers        handler.Registry
 }
 
 func NewMetadataIndexer(
@@ -38,8 +41,9 @@ func NewMetadataIndexer(
 	contractRepo repository.ContractRepository,
 	messageService messenger.MessageService,
 	metadataService metadata.Service,
+	handlers handler.Registry,
 ) MetadataIndexer {
-	i := metadataIndexer{elastic, nftRepo, contractRepo, messageService, metadataService}
+	i := metadataIndexer{elastic, nftRepo, contractRepo, messageService, metadataService, handlers}
 
 	event.AddEventListener(event.NftMintedEvent, i.TriggerMetadataRefresh)
 	event.AddEventListener(event.ContractBaseUriUpdatedEvent, i.TriggerContractMetadataRefresh)
@@ -79,15 +83,15 @@ func (i metadataIndexer) TriggerMetadataRefresh(el interface{}) {
 	}
 }
 
-func (i metadataIndexer) RefreshMetadata(contractAddr string, tokenId uint64) (*entity.Nft, error) {
+func (i metadataIndexer) RefreshMetadata(ctx context.Context, contractAddr string, tokenId uint64) (*entity.Nft, error) {
 	zap.L().With(zap.String("contract", contractAddr), zap.Uint64("tokenId", tokenId)).Info("NFT Refresh Metadata")
 
-	nft, err := i.nftRepo.GetNft(contractAddr, tokenId)
+	nft, err := i.nftRepo.GetNft(ctx, contractAddr, tokenId)
 	if err != nil {
 		return nil, err
 	}
 
-	c, err := i.contractRepo.GetContractByAddress(contractAddr)
+	c, err := i.contractRepo.GetContractByAddress(ctx, contractAddr)
 	if err != nil {
 		return nil, err
 	}
@@ -140,10 +144,7 @@ func (i metadataIndexer) RefreshMetadata(contractAddr string, tokenId uint64) (*
 	nft.Metadata.Status = entity.MetadataSuccess
 	nft.HasMetadata = true
 
-	// ZilMorphs are fudge
-	if nft.Contract == "0x852c4105660ab288d0df8b2491f7462c66a1c0ae" {
-		nft.Metadata.Properties["image"] = fmt.Sprintf("https://zilmorphs.com/morph/%d.png", nft.TokenId)
-	}
+	i.handlers.PostProcessMetadata(*c, nft)
 
 	if assetUri, err := nft.Metadata.GetAssetUri(); err == nil {
 		if helper.IsIpfs(assetUri) {
@@ -173,12 +174,13 @@ func (i metadataIndexer) RefreshMetadata(contractAddr string, tokenId uint64) (*
 	return nft, nil
 }
 
-func (i metadataIndexer) RefreshByStatus(status entity.MetadataStatus) error {
+func (i metadataIndexer) RefreshByStatus(ctx context.Context, status entity.MetadataStatus) error {
 	size := 100
 	page := 1
+	contracts := map[string]entity.Contract{}
 
 	for {
-		nfts, total, err := i.nftRepo.GetMetadata(size, page, status)
+		nfts, total, err := i.nftRepo.GetMetadata(ctx, size, page, status)
 		if err != nil || len(nfts) == 0 {
 			break
 		}
@@ -187,9 +189,16 @@ func (i metadataIndexer) RefreshByStatus(status entity.MetadataStatus) error {
 		}
 
 		for _, nft := range nfts {
-			if nft.Contract == "0x3fe64e8b3e9e110db331b32ea26e191c07f14f80" ||
-				nft.Contract == "0x32e4df3cd46c30862b0a30cdb187045b11ee8753" ||
-				nft.Contract == "0x821aea19180b0868f22301147f0c28204283d167" {
+			c, ok := contracts[nft.Contract]
+			if !ok {
+				if contract, err := i.contractRepo.GetContractByAddress(ctx, nft.Contract); err == nil {
+					c = *contract
+				} else {
+					c = entity.Contract{Address: nft.Contract}
+				}
+				contracts[nft.Contract] = c
+			}
+			if !i.handlers.CanRefresh(c, nft) {
 				continue
 			}
 			if nft.Metadata.Attempts == 0 || rand.Intn(nft.Metadata.Attempts+1) == 0 {
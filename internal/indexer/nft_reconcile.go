@@ -0,0 +1,284 @@
+package indexer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/factory"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/zilliqa"
+	"go.uber.org/zap"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotNftContract   = errors.New("contract is not zrc1 or zrc6")
+	ErrIndexBehindChain = errors.New("index is too far behind the chain to reconcile")
+)
+
+type NftReconciler interface {
+	// Reconcile compares the owners of a contract's nfts with its token_owners, recording the discrepancies found
+	// and resolving those no longer found. With repair, owners which differed on the previous reconcile as well are
+	// corrected with a reconcile action.
+	Reconcile(ctx context.Context, c entity.Contract, repair bool) ([]entity.NftDiscrepancy, error)
+}
+
+type nftReconciler struct {
+	elastic         elastic_search.Index
+	zilliqa         zilliqa.Service
+	nftRepo         repository.NftRepository
+	discrepancyRepo repository.NftDiscrepancyRepository
+	checkpointRepo  repository.CheckpointRepository
+	maxLag          uint64
+}
+
+// NewNftReconciler refuses to reconcile while the transaction checkpoint is more than maxLag blocks behind the chain,
+// as the node reports token_owners at the chain tip
+func NewNftReconciler(
+	elastic elastic_search.Index,
+	zilliqa zilliqa.Service,
+	nftRepo repository.NftRepository,
+	discrepancyRepo repository.NftDiscrepancyRepository,
+	checkpointRepo repository.CheckpointRepository,
+	maxLag uint64,
+) NftReconciler {
+	return nftReconciler{elastic, zilliqa, nftRepo, discrepancyRepo, checkpointRepo, maxLag}
+}
+
+func (r nftReconciler) Reconcile(ctx context.Context, c entity.Contract, repair bool) ([]entity.NftDiscrepancy, error) {
+	if !c.MatchesStandard(entity.ZRC1) && !c.MatchesStandard(entity.ZRC6) {
+		return nil, ErrNotNftContract
+	}
+
+	blockNum, err := r.checkLag(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	chainOwners, err := r.getTokenOwners(ctx, c.Address)
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.String("contract", c.Address)).Error("Failed to get token_owners")
+		return nil, err
+	}
+
+	nfts, err := r.getNfts(ctx, c.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := r.getOpenDiscrepancies(ctx, c.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	discrepancies := make([]entity.NftDiscrepancy, 0)
+
+	record := func(d entity.NftDiscrepancy, nft *entity.Nft) {
+		d.Contract = c.Address
+		d.Status = entity.DiscrepancyOpen
+		d.BlockNum = blockNum
+		d.FirstSeenAt = now
+		d.LastSeenAt = now
+
+		prev, seen := previous[d.TokenId]
+		stable := seen && prev.Kind == d.Kind && prev.ChainOwner == d.ChainOwner
+		if stable {
+			d.FirstSeenAt = prev.FirstSeenAt
+		}
+		delete(previous, d.TokenId)
+
+		if repair && stable && d.Kind == entity.DiscrepancyOwner {
+			r.repairOwner(*nft, d, blockNum)
+			d.Status = entity.DiscrepancyRepaired
+			d.RepairedAt = now
+		}
+
+		zap.L().With(
+			zap.String("contract", d.Contract),
+			zap.Uint64("tokenId", d.TokenId),
+			zap.String("kind", string(d.Kind)),
+			zap.String("indexed", d.IndexedOwner),
+			zap.String("chain", d.ChainOwner),
+			zap.String("status", string(d.Status)),
+		).Info("NFT ownership discrepancy")
+
+		r.elastic.AddIndexRequest(elastic_search.NftDiscrepancyIndex.Get(), d, elastic_search.NftReconcile)
+		discrepancies = append(discrepancies, d)
+	}
+
+	for tokenId, nft := range nfts {
+		if nft.BurnedAt != 0 {
+			continue
+		}
+
+		owner := indexedOwner(nft)
+		chainOwner, onChain := chainOwners[tokenId]
+		if !onChain {
+			record(entity.NftDiscrepancy{TokenId: tokenId, Kind: entity.DiscrepancyNotOnChain, IndexedOwner: owner}, &nft)
+		} else if !strings.EqualFold(owner, chainOwner) {
+			record(entity.NftDiscrepancy{TokenId: tokenId, Kind: entity.DiscrepancyOwner, IndexedOwner: owner, ChainOwner: chainOwner}, &nft)
+		}
+	}
+
+	for tokenId, chainOwner := range chainOwners {
+		if nft, indexed := nfts[tokenId]; !indexed || nft.BurnedAt != 0 {
+			record(entity.NftDiscrepancy{TokenId: tokenId, Kind: entity.DiscrepancyNotIndexed, ChainOwner: chainOwner}, nil)
+		}
+	}
+
+	for _, d := range previous {
+		d.Status = entity.DiscrepancyResolved
+		d.LastSeenAt = now
+		r.elastic.AddIndexRequest(elastic_search.NftDiscrepancyIndex.Get(), d, elastic_search.NftReconcile)
+	}
+
+	r.elastic.BatchPersist()
+
+	zap.L().With(
+		zap.String("contract", c.Address),
+		zap.Int("tokens", len(chainOwners)),
+		zap.Int("discrepancies", len(discrepancies)),
+		zap.Int("resolved", len(previous)),
+	).Info("Reconciled NFT ownership")
+
+	return discrepancies, nil
+}
+
+// checkLag returns the block the index has reached, when close enough to the chain tip to compare against it
+func (r nftReconciler) checkLag(ctx context.Context) (uint64, error) {
+	blockNum, err := r.checkpointRepo.GetBlockNum(ctx, entity.TransactionStage)
+	if err != nil {
+		return 0, err
+	}
+
+	tip, err := r.zilliqa.GetLatestTxBlock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	tipNum, err := strconv.ParseUint(tip.Header.BlockNum, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if tipNum > blockNum+r.maxLag {
+		zap.L().With(zap.Uint64("indexed", blockNum), zap.Uint64("tip", tipNum)).Warn("Index is behind the chain, not reconciling")
+		return 0, ErrIndexBehindChain
+	}
+
+	return blockNum, nil
+}
+
+func (r nftReconciler) getTokenOwners(ctx context.Context, contractAddr string) (map[uint64]string, error) {
+	resp, err := r.zilliqa.GetContractSubState(ctx, strings.TrimPrefix(contractAddr, "0x"), "token_owners", []string{})
+	if err != nil {
+		return nil, err
+	}
+
+	var subState struct {
+		Result *struct {
+			TokenOwners map[string]string `json:"token_owners"`
+		} `json:"result"`
+		Error *zilliqa.RPCError `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(resp), &subState); err != nil {
+		return nil, err
+	}
+	if subState.Error != nil {
+		return nil, subState.Error
+	}
+	if subState.Result == nil {
+		return nil, fmt.Errorf("no token_owners in the state of %s", contractAddr)
+	}
+
+	owners := make(map[uint64]string, len(subState.Result.TokenOwners))
+	for tokenIdString, owner := range subState.Result.TokenOwners {
+		tokenId, err := strconv.ParseUint(tokenIdString, 10, 64)
+		if err != nil {
+			zap.L().With(zap.Error(err), zap.String("contract", contractAddr), zap.String("tokenId", tokenIdString)).
+				Warn("Failed to parse token id from token_owners")
+			continue
+		}
+		owners[tokenId] = strings.ToLower(owner)
+	}
+
+	return owners, nil
+}
+
+func (r nftReconciler) getNfts(ctx context.Context, contractAddr string) (map[uint64]entity.Nft, error) {
+	nfts := map[uint64]entity.Nft{}
+
+	size := 100
+	page := 1
+	for {
+		results, _, err := r.nftRepo.GetNfts(ctx, contractAddr, size, page)
+		if err != nil {
+			zap.L().With(zap.Error(err), zap.String("contract", contractAddr)).Error("Failed to get nfts")
+			return nil, err
+		}
+		if len(results) == 0 {
+			break
+		}
+		for _, nft := range results {
+			nfts[nft.TokenId] = nft
+		}
+		page++
+	}
+
+	return nfts, nil
+}
+
+func (r nftReconciler) getOpenDiscrepancies(ctx context.Context, contractAddr string) (map[uint64]entity.NftDiscrepancy, error) {
+	discrepancies := map[uint64]entity.NftDiscrepancy{}
+
+	size := 100
+	page := 1
+	for {
+		results, _, err := r.discrepancyRepo.GetDiscrepancies(ctx, contractAddr, entity.DiscrepancyOpen, size, page)
+		if err != nil {
+			zap.L().With(zap.Error(err), zap.String("contract", contractAddr)).Error("Failed to get nft discrepancies")
+			return nil, err
+		}
+		if len(results) == 0 {
+			break
+		}
+		for _, d := range results {
+			discrepancies[d.TokenId] = d
+		}
+		page++
+	}
+
+	return discrepancies, nil
+}
+
+// repairOwner moves an nft to its owner on chain, out of any marketplace and without a spender, as a transfer would
+func (r nftReconciler) repairOwner(nft entity.Nft, d entity.NftDiscrepancy, blockNum uint64) {
+	nft.Owner = d.ChainOwner
+	nft.IsDelegated = false
+	nft.DelegatedOwner = ""
+	nft.Spender = ""
+
+	zap.L().With(
+		zap.String("contract", nft.Contract),
+		zap.Uint64("tokenId", nft.TokenId),
+		zap.String("from", d.IndexedOwner),
+		zap.String("to", d.ChainOwner),
+	).Info("Repair NFT owner")
+
+	r.elastic.AddUpdateRequest(elastic_search.NftIndex.Get(), nft, elastic_search.NftReconcile)
+	r.elastic.AddIndexRequest(elastic_search.NftActionIndex.Get(), factory.CreateReconcileAction(nft, blockNum, d.IndexedOwner, d.ChainOwner), elastic_search.NftReconcile)
+}
+
+// indexedOwner is the holder of the nft on chain according to the index, the marketplace while delegated
+func indexedOwner(nft entity.Nft) string {
+	if nft.IsDelegated && nft.DelegatedOwner != "" {
+		return nft.DelegatedOwner
+	}
+
+	return nft.Owner
+}
@@ -0,0 +1,39 @@
+package indexer
+
+import (
+	"context"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"go.uber.org/zap"
+)
+
+// changesNftStats is whether a tx may change the supply or holders of its contract
+func changesNftStats(tx entity.Transaction) bool {
+	return tx.HasEventLog(entity.ZRC6MintEvent) ||
+		tx.HasEventLog(entity.ZRC6BatchMintEvent) ||
+		tx.HasEventLog(entity.ZRC6TransferFromEvent) ||
+		tx.HasEventLog(entity.ZRC6BatchTransferFromEvent) ||
+		tx.HasEventLog(entity.ZRC6BurnEvent) ||
+		tx.HasTransition(string(entity.ZRC6BatchBurnCallback))
+}
+
+// UpdateStats counts the supply, burned tokens and holders of a contract, including the nfts not yet persisted. It is
+// run once for each contract changed by a batch of txs rather than for each token.
+func (i zrc6Indexer) UpdateStats(ctx context.Context, contractAddr string) error {
+	c, err := i.contractRepo.GetContractByAddress(ctx, contractAddr)
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.String("contract", contractAddr)).Error("Failed to get contract for nft stats")
+		return err
+	}
+
+	stats, err := i.nftRepo.GetNftStats(ctx, contractAddr)
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.String("contract", contractAddr)).Error("Failed to count nft stats")
+		return err
+	}
+
+	c.NftStats = &stats
+	i.elastic.AddUpdateRequest(elastic_search.ContractIndex.Get(), *c, elastic_search.ContractNftStats)
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/factory"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/zilliqa"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Quarantined blocks retried by each repair
+const repairSize = 100
+
+// fetch creates the blocks of a range, retrying while the node cannot serve it. Blocks still failing after
+// maxBlockRetries attempts are returned to be quarantined, so the rest of the range is indexed without them.
+func (i indexer) fetch(ctx context.Context, height, size uint64) (Blocks, []entity.SkippedBlock, error) {
+	blocks, err := i.fetchWithRetries(ctx, height, size)
+	if err == nil || !zilliqa.IsBlockUnavailable(err) {
+		return blocks, nil, err
+	}
+
+	if size == 1 {
+		return Blocks{}, []entity.SkippedBlock{factory.CreateSkippedBlock(height, err, i.maxBlockRetries)}, nil
+	}
+
+	// Fetch the blocks one at a time to find those the node cannot serve
+	blocks = Blocks{}
+	skipped := make([]entity.SkippedBlock, 0)
+	for blockNum := height; blockNum < height+size; blockNum++ {
+		block, err := i.fetchWithRetries(ctx, blockNum, 1)
+		if err != nil {
+			if !zilliqa.IsBlockUnavailable(err) {
+				return Blocks{}, nil, err
+			}
+			skipped = append(skipped, factory.CreateSkippedBlock(blockNum, err, i.maxBlockRetries))
+			continue
+		}
+		blocks.append(block)
+	}
+
+	return blocks, skipped, nil
+}
+
+func (i indexer) fetchWithRetries(ctx context.Context, height, size uint64) (Blocks, error) {
+	for attempt := 1; ; attempt++ {
+		blocks, err := i.txIndexer.CreateBlocks(ctx, height, size)
+		if err == nil || !zilliqa.IsBlockUnavailable(err) || attempt >= i.maxBlockRetries {
+			return blocks, err
+		}
+
+		zap.L().With(zap.Error(err), zap.Uint64("height", height), zap.Uint64("size", size), zap.Int("attempt", attempt)).
+			Warn("Retrying blocks the node could not serve")
+
+		select {
+		case <-time.After(time.Duration(attempt) * time.Second):
+		case <-ctx.Done():
+			return Blocks{}, ctx.Err()
+		}
+	}
+}
+
+// failed counts the failures of a block behind the target, and is true once it has failed maxBlockRetries times.
+// A block at the target is expected to fail until the node has caught up with the chain.
+func (i indexer) failed(height, target uint64) bool {
+	if target == 0 || height >= target {
+		return false
+	}
+
+	key := fmt.Sprintf("blockFailures:%d", height)
+	_ = i.cache.Add(key, 0, time.Hour)
+
+	attempts, err := i.cache.IncrementInt(key, 1)
+	if err != nil || attempts < i.maxBlockRetries {
+		return false
+	}
+	i.cache.Delete(key)
+
+	return true
+}
+
+func (i indexer) quarantine(skippedBlock entity.SkippedBlock) {
+	zap.L().With(zap.Uint64("height", skippedBlock.BlockNum), zap.String("reason", skippedBlock.Reason)).
+		Warn("Quarantined a block the node could not serve")
+
+	i.elastic.AddIndexRequest(elastic_search.SkippedBlockIndex.Get(), skippedBlock, elastic_search.SkippedBlock)
+}
+
+// Repair retries the quarantined blocks, indexing those the node can now serve. Their contracts and NFT actions
+// are applied after those of the blocks indexed since.
+func (i indexer) Repair(ctx context.Context) error {
+	skippedBlocks, _, err := i.skippedBlockRepo.GetSkippedBlocks(ctx, repairSize, 1)
+	if err != nil {
+		return err
+	}
+
+	for _, skippedBlock := range skippedBlocks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		blocks, err := i.txIndexer.CreateBlocks(ctx, skippedBlock.BlockNum, 1)
+		if err != nil {
+			if !zilliqa.IsBlockUnavailable(err) {
+				return err
+			}
+
+			skippedBlock.Reason = err.Error()
+			skippedBlock.Attempts++
+			skippedBlock.LastAttemptAt = time.Now()
+			i.elastic.AddIndexRequest(elastic_search.SkippedBlockIndex.Get(), skippedBlock, elastic_search.SkippedBlock)
+			continue
+		}
+
+		i.txIndexer.IndexBlocks(ctx, blocks)
+		if err := i.indexTxs(ctx, skippedBlock.BlockNum, blocks.Txs); err != nil {
+			return err
+		}
+		i.elastic.Persist()
+
+		if err := i.skippedBlockRepo.Delete(ctx, skippedBlock); err != nil {
+			return err
+		}
+		zap.L().With(zap.Uint64("height", skippedBlock.BlockNum)).Info("Repaired a quarantined block")
+	}
+
+	i.elastic.Persist()
+
+	return nil
+}
@@ -1,18 +1,37 @@
 package indexer
 
 import (
+	"context"
 	"fmt"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/factory"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/zilliqa"
+	"github.com/patrickmn/go-cache"
 	"go.uber.org/zap"
+	"time"
 )
 
 type TransactionIndexer interface {
-	Index(height, size uint64) ([]entity.Transaction, error)
-	CreateTransactions(height uint64, size uint64) ([]entity.Transaction, error)
+	Index(ctx context.Context, height, size uint64) ([]entity.Transaction, error)
+	IndexBlocks(ctx context.Context, blocks Blocks)
+	IndexTxs(ctx context.Context, txs []entity.Transaction)
+	CreateBlocks(ctx context.Context, height, size uint64) (Blocks, error)
+	CreateTransactions(ctx context.Context, height uint64, size uint64) ([]entity.Transaction, error)
+}
+
+// Blocks holds the headers of a range of tx blocks, the ds blocks they belong to and their transactions
+type Blocks struct {
+	TxBlocks []entity.Block
+	DsBlocks []entity.DsBlock
+	Txs      []entity.Transaction
+}
+
+func (b *Blocks) append(blocks Blocks) {
+	b.TxBlocks = append(b.TxBlocks, blocks.TxBlocks...)
+	b.DsBlocks = append(b.DsBlocks, blocks.DsBlocks...)
+	b.Txs = append(b.Txs, blocks.Txs...)
 }
 
 type transactionIndexer struct {
@@ -20,6 +39,7 @@ type transactionIndexer struct {
 	elastic   elastic_search.Index
 	txFactory factory.TransactionFactory
 	txRepo    repository.TransactionRepository
+	cache     *cache.Cache
 }
 
 func NewTransactionIndexer(
@@ -27,26 +47,95 @@ func NewTransactionIndexer(
 	elastic elastic_search.Index,
 	factory factory.TransactionFactory,
 	txRepo repository.TransactionRepository,
+	cache *cache.Cache,
 ) TransactionIndexer {
-	return transactionIndexer{zilliqa, elastic, factory, txRepo}
+	return transactionIndexer{zilliqa, elastic, factory, txRepo, cache}
 }
 
-func (i transactionIndexer) Index(height, size uint64) ([]entity.Transaction, error) {
-	txs, err := i.CreateTransactions(height, size)
+func (i transactionIndexer) Index(ctx context.Context, height, size uint64) ([]entity.Transaction, error) {
+	blocks, err := i.CreateBlocks(ctx, height, size)
 	if err != nil {
 		return nil, err
 	}
+	i.IndexBlocks(ctx, blocks)
+
+	return blocks.Txs, nil
+}
 
+func (i transactionIndexer) IndexBlocks(ctx context.Context, blocks Blocks) {
+	for _, dsBlock := range blocks.DsBlocks {
+		i.elastic.AddIndexRequest(elastic_search.DsBlockIndex.Get(), dsBlock, elastic_search.DsBlockCreate)
+		i.cache.Set(dsBlockKey(dsBlock.BlockNum), true, cache.NoExpiration)
+	}
+	for _, block := range blocks.TxBlocks {
+		i.elastic.AddIndexRequest(elastic_search.BlockIndex.Get(), block, elastic_search.BlockCreate)
+	}
+	i.IndexTxs(ctx, blocks.Txs)
+}
+
+func (i transactionIndexer) IndexTxs(ctx context.Context, txs []entity.Transaction) {
 	for _, tx := range txs {
 		zap.L().With(zap.String("txID", tx.ID)).Debug("Create Transaction")
 		i.elastic.AddIndexRequest(elastic_search.TransactionIndex.Get(), tx, elastic_search.TransactionCreate)
 	}
+}
 
-	return txs, nil
+// CreateBlocks creates a range of tx blocks, with the ds blocks not seen before and the transactions stamped with
+// the time of their block
+func (i transactionIndexer) CreateBlocks(ctx context.Context, height, size uint64) (Blocks, error) {
+	txs, err := i.createTransactions(ctx, height, size)
+	if err != nil {
+		return Blocks{}, err
+	}
+
+	txBlocks, err := i.zilliqa.GetTxBlocks(ctx, height, uint(size))
+	if err != nil {
+		return Blocks{}, err
+	}
+
+	blocks := Blocks{
+		TxBlocks: make([]entity.Block, 0, len(txBlocks)),
+		DsBlocks: make([]entity.DsBlock, 0),
+		Txs:      txs,
+	}
+
+	timestamps := make(map[uint64]time.Time, len(txBlocks))
+	seen := make(map[uint64]bool)
+	for _, txBlock := range txBlocks {
+		block := factory.CreateBlock(txBlock)
+		blocks.TxBlocks = append(blocks.TxBlocks, block)
+		timestamps[block.BlockNum] = block.Timestamp
+
+		if _, indexed := i.cache.Get(dsBlockKey(block.DsBlockNum)); indexed || seen[block.DsBlockNum] {
+			continue
+		}
+		seen[block.DsBlockNum] = true
+
+		dsBlock, err := i.zilliqa.GetDSBlock(ctx, block.DsBlockNum)
+		if err != nil {
+			return Blocks{}, err
+		}
+		blocks.DsBlocks = append(blocks.DsBlocks, factory.CreateDsBlock(*dsBlock))
+	}
+
+	for idx := range blocks.Txs {
+		blocks.Txs[idx].Timestamp = timestamps[blocks.Txs[idx].BlockNum]
+	}
+
+	return blocks, nil
+}
+
+func (i transactionIndexer) CreateTransactions(ctx context.Context, height uint64, size uint64) ([]entity.Transaction, error) {
+	blocks, err := i.CreateBlocks(ctx, height, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return blocks.Txs, nil
 }
 
-func (i transactionIndexer) CreateTransactions(height uint64, size uint64) ([]entity.Transaction, error) {
-	coreTxGroups, err := i.zilliqa.GetTxnBodiesForTxBlocks(height, size)
+func (i transactionIndexer) createTransactions(ctx context.Context, height uint64, size uint64) ([]entity.Transaction, error) {
+	coreTxGroups, err := i.zilliqa.GetTxnBodiesForTxBlocks(ctx, height, size)
 	if err != nil {
 		return nil, err
 	}
@@ -55,24 +144,19 @@ func (i transactionIndexer) CreateTransactions(height uint64, size uint64) ([]en
 	contractCreationTxs := make([]entity.Transaction, 0)
 	for blockNum, coreTxs := range coreTxGroups {
 		for _, coreTx := range coreTxs {
-			if coreTx.Receipt.Success == false {
-				continue
-			}
-
 			tx := i.txFactory.CreateTransaction(coreTx, blockNum)
-			if tx.IsContractCreation {
-				contractCreationTxs = append(contractCreationTxs, tx)
-			}
 
-			if !tx.IsContractCreation && !tx.IsContractExecution {
-				continue
+			// Failed transactions are kept so users can see why they failed and their fees are charged, but a
+			// failed deployment never created a contract to look up
+			if tx.IsContractCreation && !tx.IsFailed {
+				contractCreationTxs = append(contractCreationTxs, tx)
 			}
 
 			txs = append(txs, tx)
 		}
 	}
 
-	contractAddrs, err := i.zilliqa.GetContractAddressFromTransactionIDs(getTxIds(contractCreationTxs))
+	contractAddrs, err := i.zilliqa.GetContractAddressFromTransactionIDs(ctx, getTxIds(contractCreationTxs))
 	if err != nil {
 		zap.L().With(zap.Error(err)).Error("Failed to get contract addresses")
 		return nil, err
@@ -91,6 +175,11 @@ func (i transactionIndexer) CreateTransactions(height uint64, size uint64) ([]en
 	return txs, nil
 }
 
+// A ds block spans many tx blocks, so is only fetched with the first of them
+func dsBlockKey(blockNum uint64) string {
+	return fmt.Sprintf("dsBlock:%d", blockNum)
+}
+
 func getTxIds(txs []entity.Transaction) []string {
 	var txIds []string
 	for _, tx := range txs {
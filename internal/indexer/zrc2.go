@@ -0,0 +1,197 @@
+package indexer
+
+import (
+	"context"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/factory"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+	"math/big"
+)
+
+type Zrc2Indexer interface {
+	IndexTxs(ctx context.Context, txs []entity.Transaction) error
+	IndexTx(ctx context.Context, tx entity.Transaction, c entity.Contract) error
+	IndexContractCreation(ctx context.Context, tx entity.Transaction, c entity.Contract) error
+}
+
+type zrc2Indexer struct {
+	elastic           elastic_search.Index
+	contractRepo      repository.ContractRepository
+	tokenTransferRepo repository.TokenTransferRepository
+	tokenBalanceRepo  repository.TokenBalanceRepository
+	factory           factory.Zrc2Factory
+}
+
+func NewZrc2Indexer(
+	elastic elastic_search.Index,
+	contractRepo repository.ContractRepository,
+	tokenTransferRepo repository.TokenTransferRepository,
+	tokenBalanceRepo repository.TokenBalanceRepository,
+	factory factory.Zrc2Factory,
+) Zrc2Indexer {
+	return zrc2Indexer{elastic, contractRepo, tokenTransferRepo, tokenBalanceRepo, factory}
+}
+
+func (i zrc2Indexer) IndexTxs(ctx context.Context, txs []entity.Transaction) error {
+	for _, tx := range txs {
+		if tx.IsContractCreation && !tx.IsFailed {
+			c, err := i.contractRepo.GetContractByAddress(ctx, tx.ContractAddress)
+			if err != nil {
+				continue
+			}
+
+			if err := i.IndexContractCreation(ctx, tx, *c); err != nil {
+				return err
+			}
+		}
+
+		if !tx.IsContractExecution {
+			continue
+		}
+
+		for _, addr := range tx.GetZrc2Addresses() {
+			c, err := i.contractRepo.GetContractByAddress(ctx, addr)
+			if err != nil {
+				continue
+			}
+
+			if err := i.IndexTx(ctx, tx, *c); err != nil {
+				return err
+			}
+		}
+
+		i.elastic.BatchPersist()
+	}
+
+	return nil
+}
+
+// IndexTx applies the transfers of a tx to the balances of their holders and to the supply of the token. Transfers
+// already indexed have been applied, so they are skipped.
+func (i zrc2Indexer) IndexTx(ctx context.Context, tx entity.Transaction, c entity.Contract) error {
+	if !c.MatchesStandard(entity.ZRC2) {
+		return nil
+	}
+
+	for _, transfer := range i.factory.CreateTransfers(tx, c) {
+		if err := i.applyTransfer(ctx, c, transfer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IndexContractCreation credits the initial supply of a token to its contract_owner at the deploy block
+func (i zrc2Indexer) IndexContractCreation(ctx context.Context, tx entity.Transaction, c entity.Contract) error {
+	if !c.MatchesStandard(entity.ZRC2) {
+		return nil
+	}
+
+	transfer := factory.CreateInitTransfer(tx, c)
+	if transfer == nil {
+		return nil
+	}
+
+	return i.applyTransfer(ctx, c, *transfer)
+}
+
+func (i zrc2Indexer) applyTransfer(ctx context.Context, c entity.Contract, transfer entity.TokenTransfer) error {
+	exists, err := i.tokenTransferRepo.Exists(ctx, transfer)
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.String("txId", transfer.TxID), zap.String("contract", c.Address)).
+			Error("Failed to check whether the token transfer is indexed")
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	zap.L().With(
+		zap.String("txId", transfer.TxID),
+		zap.String("contract", c.Address),
+		zap.String("action", string(transfer.Action)),
+		zap.String("amount", transfer.Amount),
+	).Debug("Zrc2Indexer: Transfer")
+
+	amount, err := factory.ParseUint128(transfer.Amount)
+	if err != nil {
+		return err
+	}
+
+	if transfer.From != "" {
+		if err := i.addToBalance(ctx, c.Address, transfer.From, transfer.BlockNum, new(big.Int).Neg(amount)); err != nil {
+			return err
+		}
+	}
+	if transfer.To != "" {
+		if err := i.addToBalance(ctx, c.Address, transfer.To, transfer.BlockNum, amount); err != nil {
+			return err
+		}
+	}
+
+	// The initial supply is already counted in the total supply of the token
+	switch transfer.Action {
+	case entity.TokenMintAction:
+		if err := i.addToSupply(ctx, c.Address, amount); err != nil {
+			return err
+		}
+	case entity.TokenBurnAction:
+		if err := i.addToSupply(ctx, c.Address, new(big.Int).Neg(amount)); err != nil {
+			return err
+		}
+	}
+
+	i.elastic.AddIndexRequest(elastic_search.TokenTransferIndex.Get(), transfer, elastic_search.Zrc2Transfer)
+
+	return nil
+}
+
+func (i zrc2Indexer) addToBalance(ctx context.Context, contract, holder string, blockNum uint64, amount *big.Int) error {
+	tokenBalance, err := i.tokenBalanceRepo.GetBalance(ctx, contract, holder)
+	if err != nil {
+		if err != repository.ErrTokenBalanceNotFound {
+			zap.L().With(zap.Error(err), zap.String("contract", contract), zap.String("holder", holder)).Error("Failed to get token balance")
+			return err
+		}
+		tokenBalance = &entity.TokenBalance{Contract: contract, Holder: holder, Balance: "0"}
+	}
+
+	balance := parseQa(tokenBalance.Balance)
+	balance.Add(balance, amount)
+	if balance.Sign() < 0 {
+		zap.L().With(zap.String("contract", contract), zap.String("holder", holder), zap.String("balance", balance.String())).
+			Warn("Token balance is negative")
+	}
+
+	tokenBalance.Balance = balance.String()
+	if blockNum > tokenBalance.BlockNum {
+		tokenBalance.BlockNum = blockNum
+	}
+	i.elastic.AddIndexRequest(elastic_search.TokenBalanceIndex.Get(), *tokenBalance, elastic_search.Zrc2Balance)
+
+	return nil
+}
+
+func (i zrc2Indexer) addToSupply(ctx context.Context, contractAddr string, amount *big.Int) error {
+	c, err := i.contractRepo.GetContractByAddress(ctx, contractAddr)
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.String("contract", contractAddr)).Error("Failed to get token contract")
+		return err
+	}
+
+	// Copied, as the contract may share the token with a pending request
+	token := factory.CreateZrc2Token(*c)
+	if c.Zrc2 != nil {
+		*token = *c.Zrc2
+	}
+
+	supply := parseQa(token.TotalSupply)
+	token.TotalSupply = supply.Add(supply, amount).String()
+	c.Zrc2 = token
+	i.elastic.AddUpdateRequest(elastic_search.ContractIndex.Get(), *c, elastic_search.Zrc2Supply)
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package indexer
+
+import (
+	"context"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/factory"
+	"testing"
+)
+
+const zrc2Contract = "0x2020202020202020202020202020202020202020"
+
+func TestZrc2IndexTxs(t *testing.T) {
+	s := newTestStore()
+	ctx := context.Background()
+
+	c := entity.Contract{
+		Address:  zrc2Contract,
+		BlockNum: 5,
+		Data: entity.Data{Params: entity.Params{
+			param("contract_owner", alice),
+			param("symbol", "TKN"),
+			param("decimals", "6"),
+			param("init_supply", "1000"),
+		}},
+		Standards: map[entity.ZrcStandard]bool{entity.ZRC2: true},
+	}
+	c.Zrc2 = factory.CreateZrc2Token(c)
+	s.save(elastic_search.ContractIndex, c)
+
+	deploy := entity.Transaction{BlockNum: 5, IsContractCreation: true, ContractAddress: zrc2Contract, SenderAddr: bob}
+	deploy.ID = "deploy"
+
+	txs := []entity.Transaction{
+		deploy,
+		callTx("transfer", 6, alice, zrc2Contract, "TransferSuccessCallBack",
+			eventLog(zrc2Contract, entity.ZRC2TransferEvent, param("sender", alice), param("recipient", bob), param("amount", "300"))),
+		callTx("mint", 7, alice, zrc2Contract, "RecipientAcceptMint",
+			eventLog(zrc2Contract, entity.ZRC2MintedEvent, param("minter", alice), param("recipient", carol), param("amount", "50"))),
+		callTx("burn", 8, bob, zrc2Contract, "BurnSuccessCallBack",
+			eventLog(zrc2Contract, entity.ZRC2BurntEvent, param("burner", bob), param("burn_account", bob), param("amount", "100"))),
+	}
+
+	i := NewZrc2Indexer(s.index, s.contractRepo, s.tokenTransferRepo, s.tokenBalanceRepo, factory.NewZrc2Factory())
+
+	// Transfers already indexed are not applied twice
+	for run := 0; run < 2; run++ {
+		if err := i.IndexTxs(ctx, txs); err != nil {
+			t.Fatal(err)
+		}
+		s.index.Persist()
+	}
+
+	for holder, expected := range map[string]string{alice: "700", bob: "200", carol: "50"} {
+		balance, err := s.tokenBalanceRepo.GetBalance(ctx, zrc2Contract, holder)
+		if err != nil {
+			t.Fatalf("balance of %s: %v", holder, err)
+		}
+		if balance.Balance != expected {
+			t.Errorf("balance of %s is %s, expected %s", holder, balance.Balance, expected)
+		}
+	}
+
+	token := s.getContract(t, zrc2Contract).Zrc2
+	if token == nil || token.Symbol != "TKN" || token.Decimals != 6 || token.InitSupply != "1000" || token.TotalSupply != "950" {
+		t.Errorf("token %+v, expected TKN with 6 decimals, an initial supply of 1000 and a total supply of 950", token)
+	}
+}
@@ -1,20 +1,28 @@
 package indexer
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/factory"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/handler"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/helper"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
 	"go.uber.org/zap"
 	"strconv"
+	"strings"
 )
 
+// ZRC6 removes a token's spender by setting it to the zero address
+const zeroAddress = "0x0000000000000000000000000000000000000000"
+
 type Zrc6Indexer interface {
-	IndexTxs(tx []entity.Transaction) error
-	IndexTx(tx entity.Transaction, c entity.Contract) error
-	IndexContract(c entity.Contract) error
+	IndexTxs(ctx context.Context, tx []entity.Transaction) error
+	IndexTx(ctx context.Context, tx entity.Transaction, c entity.Contract) error
+	IndexContract(ctx context.Context, c entity.Contract) error
+	IndexGovernance(ctx context.Context, c entity.Contract) error
+	UpdateStats(ctx context.Context, contractAddr string) error
 }
 
 type zrc6Indexer struct {
@@ -22,7 +30,9 @@ type zrc6Indexer struct {
 	contractRepo repository.ContractRepository
 	nftRepo      repository.NftRepository
 	txRepo       repository.TransactionRepository
+	operatorRepo repository.OperatorRepository
 	factory      factory.Zrc6Factory
+	handlers     handler.Registry
 }
 
 func NewZrc6Indexer(
@@ -30,12 +40,15 @@ func NewZrc6Indexer(
 	contractRepo repository.ContractRepository,
 	nftRepo repository.NftRepository,
 	txRepo repository.TransactionRepository,
+	operatorRepo repository.OperatorRepository,
 	factory factory.Zrc6Factory,
+	handlers handler.Registry,
 ) Zrc6Indexer {
-	return zrc6Indexer{elastic, contractRepo, nftRepo, txRepo, factory}
+	return zrc6Indexer{elastic, contractRepo, nftRepo, txRepo, operatorRepo, factory, handlers}
 }
 
-func (i zrc6Indexer) IndexTxs(txs []entity.Transaction) error {
+func (i zrc6Indexer) IndexTxs(ctx context.Context, txs []entity.Transaction) error {
+	changed := make(map[string]bool)
 	for _, tx := range txs {
 		if !tx.IsContractExecution {
 			continue
@@ -46,56 +59,75 @@ func (i zrc6Indexer) IndexTxs(txs []entity.Transaction) error {
 			continue
 		}
 
-		c, err := i.contractRepo.GetContractByAddress(transitions[0].Addr)
+		c, err := i.contractRepo.GetContractByAddress(ctx, transitions[0].Addr)
 		if err != nil {
 			continue
 		}
 
-		if err := i.IndexTx(tx, *c); err != nil {
+		if err := i.IndexTx(ctx, tx, *c); err != nil {
 			return err
 		}
+		if c.MatchesStandard(entity.ZRC6) && changesNftStats(tx) {
+			changed[c.Address] = true
+		}
 
 		i.elastic.BatchPersist()
 	}
 
+	for contractAddr := range changed {
+		if err := i.UpdateStats(ctx, contractAddr); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func (i zrc6Indexer) IndexTx(tx entity.Transaction, c entity.Contract) error {
+func (i zrc6Indexer) IndexTx(ctx context.Context, tx entity.Transaction, c entity.Contract) error {
 	if !c.MatchesStandard(entity.ZRC6) {
 		return nil
 	}
 	zap.L().With(zap.String("contract", c.Address), zap.String("txID", tx.ID)).Debug("Zrc6Indexer: Index ZRC6")
 
-	if err := i.mint(tx, c); err != nil {
+	if err := i.mint(ctx, tx, c); err != nil {
+		return err
+	}
+	if err := i.batchMint(ctx, tx, c); err != nil {
+		return err
+	}
+	if err := i.setBaseUri(ctx, tx, c); err != nil {
 		return err
 	}
-	if err := i.batchMint(tx, c); err != nil {
+	if err := i.transferFrom(ctx, tx, c); err != nil {
 		return err
 	}
-	if err := i.setBaseUri(tx, c); err != nil {
+	if err := i.batchTransferFrom(ctx, tx, c); err != nil {
 		return err
 	}
-	if err := i.transferFrom(tx, c); err != nil {
+	if err := i.burn(ctx, tx, c); err != nil {
 		return err
 	}
-	if err := i.burn(tx, c); err != nil {
+	if err := i.batchBurn(ctx, tx, c); err != nil {
 		return err
 	}
-	if err := i.batchBurn(tx, c); err != nil {
+	if err := i.setTokenUri(ctx, tx, c); err != nil {
 		return err
 	}
-	if err := i.setTokenUri(tx, c); err != nil {
+	if err := i.batchSetTokenUri(ctx, tx, c); err != nil {
 		return err
 	}
-	if err := i.batchSetTokenUri(tx, c); err != nil {
+	if err := i.setSpender(ctx, tx, c); err != nil {
 		return err
 	}
+	if err := i.setOperators(ctx, tx, c); err != nil {
+		return err
+	}
+	i.recordGovernance(tx, c)
 
-	return nil
+	return i.handlers.IndexTx(ctx, tx, c)
 }
 
-func (i zrc6Indexer) IndexContract(c entity.Contract) error {
+func (i zrc6Indexer) IndexContract(ctx context.Context, c entity.Contract) error {
 	if !c.MatchesStandard(entity.ZRC6) {
 		return nil
 	}
@@ -103,7 +135,7 @@ func (i zrc6Indexer) IndexContract(c entity.Contract) error {
 	size := 100
 	page := 1
 	for {
-		txs, _, err := i.txRepo.GetContractExecutionsByContract(c, size, page)
+		txs, _, err := i.txRepo.GetContractExecutionsByContract(ctx, c, size, page)
 		if err != nil {
 			return err
 		}
@@ -112,19 +144,51 @@ func (i zrc6Indexer) IndexContract(c entity.Contract) error {
 		}
 
 		for _, tx := range txs {
-			if err := i.IndexTx(tx, c); err != nil {
+			if err := i.IndexTx(ctx, tx, c); err != nil {
 				return err
 			}
 		}
 		page++
 		i.elastic.BatchPersist()
 	}
+	if err := i.UpdateStats(ctx, c.Address); err != nil {
+		return err
+	}
+	i.elastic.Persist()
+
+	return nil
+}
+
+// IndexGovernance records the governance history of a contract from all its txs, for contracts indexed before the
+// history was kept
+func (i zrc6Indexer) IndexGovernance(ctx context.Context, c entity.Contract) error {
+	if !c.MatchesStandard(entity.ZRC6) {
+		return nil
+	}
+
+	size := 100
+	page := 1
+	for {
+		txs, _, err := i.txRepo.GetContractExecutionsByContract(ctx, c, size, page)
+		if err != nil {
+			return err
+		}
+		if len(txs) == 0 {
+			break
+		}
+
+		for _, tx := range txs {
+			i.recordGovernance(tx, c)
+		}
+		page++
+		i.elastic.BatchPersist()
+	}
 	i.elastic.Persist()
 
 	return nil
 }
 
-func (i zrc6Indexer) mint(tx entity.Transaction, c entity.Contract) error {
+func (i zrc6Indexer) mint(ctx context.Context, tx entity.Transaction, c entity.Contract) error {
 	if !tx.HasEventLog(entity.ZRC6MintEvent) {
 		return nil
 	}
@@ -137,7 +201,7 @@ func (i zrc6Indexer) mint(tx entity.Transaction, c entity.Contract) error {
 
 	for idx := range nfts {
 		zap.L().With(zap.String("txId", tx.ID), zap.String("contract", c.Address), zap.Uint64("tokenId", nfts[idx].TokenId)).Info("Mint ZRC6")
-		if exists := i.nftRepo.Exists(nfts[idx].Contract, nfts[idx].TokenId); !exists {
+		if exists := i.nftRepo.Exists(ctx, nfts[idx].Contract, nfts[idx].TokenId); !exists {
 			i.elastic.AddIndexRequest(elastic_search.NftIndex.Get(), nfts[idx], elastic_search.Zrc6Mint)
 		}
 		i.elastic.AddIndexRequest(elastic_search.NftActionIndex.Get(), factory.CreateMintAction(nfts[idx]), elastic_search.NftAction)
@@ -146,7 +210,7 @@ func (i zrc6Indexer) mint(tx entity.Transaction, c entity.Contract) error {
 	return nil
 }
 
-func (i zrc6Indexer) batchMint(tx entity.Transaction, c entity.Contract) error {
+func (i zrc6Indexer) batchMint(ctx context.Context, tx entity.Transaction, c entity.Contract) error {
 	if !tx.HasEventLog(entity.ZRC6BatchMintEvent) {
 		return nil
 	}
@@ -159,7 +223,7 @@ func (i zrc6Indexer) batchMint(tx entity.Transaction, c entity.Contract) error {
 
 	for idx := range nfts {
 		zap.L().With(zap.String("contract", c.Address), zap.Uint64("tokenId", nfts[idx].TokenId)).Info("BatchMint ZRC6")
-		if exists := i.nftRepo.Exists(nfts[idx].Contract, nfts[idx].TokenId); !exists {
+		if exists := i.nftRepo.Exists(ctx, nfts[idx].Contract, nfts[idx].TokenId); !exists {
 			i.elastic.AddIndexRequest(elastic_search.NftIndex.Get(), nfts[idx], elastic_search.Zrc6Mint)
 		}
 		i.elastic.AddIndexRequest(elastic_search.NftActionIndex.Get(), factory.CreateMintAction(nfts[idx]), elastic_search.NftAction)
@@ -169,7 +233,7 @@ func (i zrc6Indexer) batchMint(tx entity.Transaction, c entity.Contract) error {
 	return nil
 }
 
-func (i zrc6Indexer) setBaseUri(tx entity.Transaction, c entity.Contract) error {
+func (i zrc6Indexer) setBaseUri(ctx context.Context, tx entity.Transaction, c entity.Contract) error {
 	for _, event := range tx.GetEventLogs(entity.ZRC6SetBaseURIEvent) {
 		baseUri, err := event.Params.GetParam("base_uri")
 		if err != nil {
@@ -184,7 +248,7 @@ func (i zrc6Indexer) setBaseUri(tx entity.Transaction, c entity.Contract) error
 		size := 100
 		page := 1
 		for {
-			nfts, _, err := i.nftRepo.GetNfts(c.Address, size, page)
+			nfts, _, err := i.nftRepo.GetNfts(ctx, c.Address, size, page)
 			if err != nil {
 				return err
 			}
@@ -193,9 +257,12 @@ func (i zrc6Indexer) setBaseUri(tx entity.Transaction, c entity.Contract) error
 			}
 
 			for _, nft := range nfts {
+				oldBaseUri := nft.BaseUri
 				nft.BaseUri = c.BaseUri
 				nft.Metadata.Uri = factory.GetMetadataUri(nft)
 				i.elastic.AddUpdateRequest(elastic_search.NftIndex.Get(), nft, elastic_search.Zrc6SetBaseUri)
+				i.elastic.AddIndexRequest(elastic_search.NftActionIndex.Get(),
+					factory.CreateChangeAction(entity.BaseUriAction, nft, tx, oldBaseUri, nft.BaseUri), elastic_search.NftAction)
 			}
 
 			i.elastic.BatchPersist()
@@ -206,7 +273,7 @@ func (i zrc6Indexer) setBaseUri(tx entity.Transaction, c entity.Contract) error
 	return nil
 }
 
-func (i zrc6Indexer) setTokenUri(tx entity.Transaction, c entity.Contract) error {
+func (i zrc6Indexer) setTokenUri(ctx context.Context, tx entity.Transaction, c entity.Contract) error {
 	if !tx.HasTransition(string(entity.ZRC6SetTokenURICallback)) {
 		return nil
 	}
@@ -231,7 +298,7 @@ func (i zrc6Indexer) setTokenUri(tx entity.Transaction, c entity.Contract) error
 		return nil
 	}
 
-	nft, err := i.nftRepo.GetNft(c.Address, uint64(tokenIdInt))
+	nft, err := i.nftRepo.GetNft(ctx, c.Address, uint64(tokenIdInt))
 	if err != nil {
 		return nil
 	}
@@ -246,11 +313,13 @@ func (i zrc6Indexer) setTokenUri(tx entity.Transaction, c entity.Contract) error
 		zap.String("to", nft.TokenUri),
 	).Info("Update token URI")
 	i.elastic.AddUpdateRequest(elastic_search.NftIndex.Get(), *nft, elastic_search.Zrc6SetTokenUri)
+	i.elastic.AddIndexRequest(elastic_search.NftActionIndex.Get(),
+		factory.CreateChangeAction(entity.TokenUriAction, *nft, tx, oldTokenUri, nft.TokenUri), elastic_search.NftAction)
 
 	return nil
 }
 
-func (i zrc6Indexer) batchSetTokenUri(tx entity.Transaction, c entity.Contract) error {
+func (i zrc6Indexer) batchSetTokenUri(ctx context.Context, tx entity.Transaction, c entity.Contract) error {
 	if !tx.HasTransition(string(entity.ZRC6BatchSetTokenURICallback)) {
 		return nil
 	}
@@ -280,10 +349,11 @@ func (i zrc6Indexer) batchSetTokenUri(tx entity.Transaction, c entity.Contract)
 		if err != nil {
 			continue
 		}
-		nft, err := i.nftRepo.GetNft(c.Address, uint64(tokenId))
+		nft, err := i.nftRepo.GetNft(ctx, c.Address, uint64(tokenId))
 		if err != nil {
 			continue
 		}
+		oldTokenUri := nft.TokenUri
 		nft.TokenUri = tokenIdTokenUriPair.Arguments[1]
 		nft.Metadata.Uri = factory.GetMetadataUri(*nft)
 		nft.Metadata.IsIpfs = helper.IsIpfs(nft.Metadata.Uri)
@@ -292,12 +362,14 @@ func (i zrc6Indexer) batchSetTokenUri(tx entity.Transaction, c entity.Contract)
 
 		zap.L().With(zap.String("contract", c.Address), zap.Uint64("tokenId", nft.TokenId), zap.String("tokenUri", nft.TokenUri)).Info("Update token URI")
 		i.elastic.AddUpdateRequest(elastic_search.NftIndex.Get(), *nft, elastic_search.Zrc6SetTokenUri)
+		i.elastic.AddIndexRequest(elastic_search.NftActionIndex.Get(),
+			factory.CreateChangeAction(entity.TokenUriAction, *nft, tx, oldTokenUri, nft.TokenUri), elastic_search.NftAction)
 	}
 
 	return nil
 }
 
-func (i zrc6Indexer) transferFrom(tx entity.Transaction, c entity.Contract) error {
+func (i zrc6Indexer) transferFrom(ctx context.Context, tx entity.Transaction, c entity.Contract) error {
 	for _, event := range tx.GetEventLogs(entity.ZRC6TransferFromEvent) {
 		tokenId, err := factory.GetTokenId(event.Params)
 		if err != nil {
@@ -312,7 +384,7 @@ func (i zrc6Indexer) transferFrom(tx entity.Transaction, c entity.Contract) erro
 			return err
 		}
 
-		nft, err := i.nftRepo.GetNft(c.Address, tokenId)
+		nft, err := i.nftRepo.GetNft(ctx, c.Address, tokenId)
 		if err != nil {
 			zap.L().With(
 				zap.Error(err),
@@ -323,35 +395,108 @@ func (i zrc6Indexer) transferFrom(tx entity.Transaction, c entity.Contract) erro
 			continue
 		}
 
-		txType := tx.GetMarketplaceTxType()
-		if txType != "" {
-			if txType == "listing" {
-				nft.IsDelegated = true
-				nft.DelegatedOwner = to.Value.String()
-			} else {
-				nft.IsDelegated = false
-				nft.DelegatedOwner = ""
+		prevOwner, err := getPrevOwner(tx, event, *nft)
+		if err != nil {
+			zap.L().With(zap.Error(err), zap.String("txID", tx.ID), zap.String("contract", c.Address)).Error("Failed to get zrc6:from for transfer")
+			return err
+		}
+
+		if err := i.transfer(ctx, tx, *nft, to.Value.String(), prevOwner); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i zrc6Indexer) batchTransferFrom(ctx context.Context, tx entity.Transaction, c entity.Contract) error {
+	for _, event := range tx.GetEventLogs(entity.ZRC6BatchTransferFromEvent) {
+		if event.Address != c.Address {
+			continue
+		}
+
+		pairs, err := factory.GetToTokenIdPairs(event.Params)
+		if err != nil {
+			zap.L().With(zap.Error(err), zap.String("txID", tx.ID), zap.String("contract", c.Address)).
+				Error("Failed to get zrc6:to_token_id_pair_list")
+			return err
+		}
+
+		for _, pair := range pairs {
+			nft, err := i.nftRepo.GetNft(ctx, c.Address, pair.TokenId)
+			if err != nil {
+				zap.L().With(
+					zap.Error(err),
+					zap.String("txId", tx.ID),
+					zap.String("contract", c.Address),
+					zap.Uint64("tokenId", pair.TokenId),
+				).Error("Failed to find zrc6 nft in index")
+				continue
 			}
-			i.elastic.AddUpdateRequest(elastic_search.NftIndex.Get(), *nft, elastic_search.NftDelegate)
-		} else {
-			prevOwner, err := event.Params.GetParam("from")
+
+			prevOwner, err := getPrevOwner(tx, event, *nft)
 			if err != nil {
-				zap.L().With(zap.Error(err), zap.String("txID", tx.ID), zap.String("contract", c.Address)).Error("Failed to get zrc1:from for transfer")
 				return err
 			}
-			nft.Owner = to.Value.String()
 
-			zap.L().With(zap.String("contract", c.Address), zap.Uint64("tokenId", nft.TokenId)).Info("Transfer ZRC6")
-			i.elastic.AddUpdateRequest(elastic_search.NftIndex.Get(), *nft, elastic_search.Zrc6Transfer)
-			i.elastic.AddIndexRequest(elastic_search.NftActionIndex.Get(), factory.CreateTransferAction(*nft, tx.BlockNum, tx.ID, nft.Owner, prevOwner.Value.String()), elastic_search.Zrc6Transfer)
+			if err := i.transfer(ctx, tx, *nft, pair.To, prevOwner); err != nil {
+				return err
+			}
 		}
+		i.elastic.BatchPersist()
+	}
+
+	return nil
+}
+
+// getPrevOwner resolves who a transfer is recorded from: nobody for a marketplace tx, which moves the nft in or out
+// of escrow, otherwise the from of the event, or the current owner for a BatchTransferFrom which has none.
+func getPrevOwner(tx entity.Transaction, event entity.EventLog, nft entity.Nft) (string, error) {
+	if tx.GetMarketplaceTxType() != "" {
+		return "", nil
+	}
+	if event.EventName == string(entity.ZRC6BatchTransferFromEvent) {
+		return nft.Owner, nil
+	}
 
+	from, err := event.Params.GetParam("from")
+	if err != nil {
+		return "", err
+	}
+	return from.Value.String(), nil
+}
+
+// transfer moves an nft to a new owner, or when the tx is a marketplace listing or delisting, into or out of the
+// marketplace's escrow. The nft keeps its owner while delegated to a marketplace.
+func (i zrc6Indexer) transfer(ctx context.Context, tx entity.Transaction, nft entity.Nft, to, prevOwner string) error {
+	txType := tx.GetMarketplaceTxType()
+	if txType != "" {
+		oldDelegatedOwner := nft.DelegatedOwner
+		if txType == "listing" {
+			nft.IsDelegated = true
+			nft.DelegatedOwner = to
+		} else {
+			nft.IsDelegated = false
+			nft.DelegatedOwner = ""
+		}
+		nft.Spender = ""
+		i.elastic.AddUpdateRequest(elastic_search.NftIndex.Get(), nft, elastic_search.NftDelegate)
+		i.elastic.AddIndexRequest(elastic_search.NftActionIndex.Get(),
+			factory.CreateChangeAction(entity.DelegateAction, nft, tx, oldDelegatedOwner, nft.DelegatedOwner), elastic_search.NftAction)
+		return nil
 	}
 
+	nft.Owner = to
+	nft.Spender = ""
+
+	zap.L().With(zap.String("contract", nft.Contract), zap.Uint64("tokenId", nft.TokenId)).Info("Transfer ZRC6")
+	i.elastic.AddUpdateRequest(elastic_search.NftIndex.Get(), nft, elastic_search.Zrc6Transfer)
+	i.elastic.AddIndexRequest(elastic_search.NftActionIndex.Get(), factory.CreateTransferAction(nft, tx, nft.Owner, prevOwner), elastic_search.Zrc6Transfer)
+
 	return nil
 }
 
-func (i zrc6Indexer) burn(tx entity.Transaction, c entity.Contract) error {
+func (i zrc6Indexer) burn(ctx context.Context, tx entity.Transaction, c entity.Contract) error {
 	for _, event := range tx.GetEventLogs(entity.ZRC6BurnEvent) {
 		tokenId, err := factory.GetTokenId(event.Params)
 		if err != nil {
@@ -360,7 +505,88 @@ func (i zrc6Indexer) burn(tx entity.Transaction, c entity.Contract) error {
 			continue
 		}
 
-		nft, err := i.nftRepo.GetNft(c.Address, tokenId)
+		if err := i.burnNft(ctx, tx, c, tokenId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// batchBurn burns the tokens of a BatchBurn, listed by its event or, for contracts that do not emit one, by the
+// transition params. Tokens also burned by a Burn event of the tx are only counted once.
+func (i zrc6Indexer) batchBurn(ctx context.Context, tx entity.Transaction, c entity.Contract) error {
+	if !tx.HasTransition(string(entity.ZRC6BatchBurnCallback)) {
+		return nil
+	}
+
+	params := tx.Data.Params
+	if event, err := tx.GetEventLogForAddr(c.Address, entity.ZRC6BatchBurnEvent); err == nil {
+		params = event.Params
+	} else if tx.Data.Tag != "BatchBurn" {
+		return nil
+	}
+
+	tokenIds, err := factory.GetTokenIds(params, "token_id_list")
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.String("txId", tx.ID), zap.String("contract", c.Address)).
+			Error("Failed to get zrc6:token_id_list from BatchBurn")
+		return nil
+	}
+
+	for _, tokenId := range tokenIds {
+		if err := i.burnNft(ctx, tx, c, tokenId); err != nil {
+			return err
+		}
+	}
+	i.elastic.BatchPersist()
+
+	return nil
+}
+
+func (i zrc6Indexer) burnNft(ctx context.Context, tx entity.Transaction, c entity.Contract, tokenId uint64) error {
+	nft, err := i.nftRepo.GetNft(ctx, c.Address, tokenId)
+	if err != nil {
+		zap.L().With(
+			zap.Error(err),
+			zap.String("txId", tx.ID),
+			zap.String("contract", c.Address),
+			zap.Uint64("tokenId", tokenId),
+		).Error("Failed to find zrc6 nft in index")
+		return nil
+	}
+
+	nft.BurnedAt = tx.BlockNum
+	nft.Spender = ""
+
+	zap.L().With(zap.String("contract", c.Address), zap.Uint64("tokenId", nft.TokenId)).Info("Burn ZRC6")
+
+	i.elastic.AddUpdateRequest(elastic_search.NftIndex.Get(), *nft, elastic_search.Zrc6Burn)
+	i.elastic.AddIndexRequest(elastic_search.NftActionIndex.Get(), factory.CreateBurnAction(*nft, tx), elastic_search.Zrc6Burn)
+
+	return nil
+}
+
+// setSpender records the address allowed to transfer a token, which the zero address removes
+func (i zrc6Indexer) setSpender(ctx context.Context, tx entity.Transaction, c entity.Contract) error {
+	for _, event := range tx.GetEventLogs(entity.ZRC6SetSpenderEvent) {
+		if event.Address != c.Address {
+			continue
+		}
+
+		tokenId, err := factory.GetTokenId(event.Params)
+		if err != nil {
+			zap.L().With(zap.Error(err), zap.String("contract", c.Address)).Warn("Failed to get token id for zrc6:SetSpender")
+			continue
+		}
+
+		spender, err := event.Params.GetParam("spender")
+		if err != nil {
+			zap.L().With(zap.Error(err), zap.String("txID", tx.ID)).Error("Failed to get zrc6:spender")
+			return err
+		}
+
+		nft, err := i.nftRepo.GetNft(ctx, c.Address, tokenId)
 		if err != nil {
 			zap.L().With(
 				zap.Error(err),
@@ -371,17 +597,91 @@ func (i zrc6Indexer) burn(tx entity.Transaction, c entity.Contract) error {
 			continue
 		}
 
-		nft.BurnedAt = tx.BlockNum
-
-		zap.L().With(zap.String("contract", c.Address), zap.Uint64("tokenId", nft.TokenId)).Info("Burn ZRC6")
+		oldSpender := nft.Spender
+		nft.Spender = strings.ToLower(spender.Value.String())
+		if nft.Spender == zeroAddress {
+			nft.Spender = ""
+		}
 
-		i.elastic.AddUpdateRequest(elastic_search.NftIndex.Get(), *nft, elastic_search.Zrc6Burn)
-		i.elastic.AddIndexRequest(elastic_search.NftActionIndex.Get(), factory.CreateBurnAction(*nft, tx), elastic_search.Zrc6Burn)
+		zap.L().With(zap.String("contract", c.Address), zap.Uint64("tokenId", nft.TokenId), zap.String("spender", nft.Spender)).
+			Info("Set ZRC6 spender")
+		i.elastic.AddUpdateRequest(elastic_search.NftIndex.Get(), *nft, elastic_search.Zrc6SetSpender)
+		i.elastic.AddIndexRequest(elastic_search.NftActionIndex.Get(),
+			factory.CreateChangeAction(entity.SpenderAction, *nft, tx, oldSpender, nft.Spender), elastic_search.NftAction)
 	}
 
 	return nil
 }
 
-func (i zrc6Indexer) batchBurn(tx entity.Transaction, c entity.Contract) error {
+// setOperators records the operators owners add and remove, each allowed to transfer all of the owner's tokens
+func (i zrc6Indexer) setOperators(ctx context.Context, tx entity.Transaction, c entity.Contract) error {
+	for _, event := range tx.Receipt.EventLogs {
+		if event.Address != c.Address {
+			continue
+		}
+
+		eventName := entity.Event(event.EventName)
+		if eventName != entity.ZRC6AddOperatorEvent && eventName != entity.ZRC6RemoveOperatorEvent {
+			continue
+		}
+
+		owner, err := event.Params.GetParam("token_owner")
+		if err != nil {
+			zap.L().With(zap.Error(err), zap.String("txID", tx.ID)).Error("Failed to get zrc6:token_owner")
+			return err
+		}
+		operator, err := event.Params.GetParam("operator")
+		if err != nil {
+			zap.L().With(zap.Error(err), zap.String("txID", tx.ID)).Error("Failed to get zrc6:operator")
+			return err
+		}
+
+		grant, err := i.getOperator(ctx, c.Address, strings.ToLower(owner.Value.String()), strings.ToLower(operator.Value.String()))
+		if err != nil {
+			zap.L().With(zap.Error(err), zap.String("txID", tx.ID), zap.String("contract", c.Address)).Error("Failed to get zrc6 operator")
+			return err
+		}
+
+		change := entity.OperatorChange{Active: eventName == entity.ZRC6AddOperatorEvent, TxID: tx.ID, BlockNum: tx.BlockNum}
+		if len(grant.History) == 0 || grant.History[len(grant.History)-1] != change {
+			grant.History = append(grant.History, change)
+		}
+		grant.Active = change.Active
+		grant.TxID = change.TxID
+		grant.BlockNum = change.BlockNum
+
+		zap.L().With(zap.String("contract", c.Address), zap.String("owner", grant.Owner), zap.String("operator", grant.Operator), zap.Bool("active", grant.Active)).
+			Info("Set ZRC6 operator")
+		i.elastic.AddIndexRequest(elastic_search.OperatorIndex.Get(), grant, elastic_search.Zrc6Operator)
+	}
+
 	return nil
 }
+
+// getOperator returns the grant of an operator by an owner, new when never changed. Grants indexed before their
+// history was kept start it with their current state.
+func (i zrc6Indexer) getOperator(ctx context.Context, contract, owner, operator string) (entity.Operator, error) {
+	grant, err := i.operatorRepo.GetOperator(ctx, contract, owner, operator)
+	if err != nil {
+		if err == repository.ErrOperatorNotFound {
+			return entity.Operator{Contract: contract, Owner: owner, Operator: operator}, nil
+		}
+		return entity.Operator{}, err
+	}
+
+	if len(grant.History) == 0 {
+		grant.History = []entity.OperatorChange{{Active: grant.Active, TxID: grant.TxID, BlockNum: grant.BlockNum}}
+	}
+
+	return *grant, nil
+}
+
+// recordGovernance adds the changes made to the administration of the contract to its history. Events are keyed by
+// their position in the tx, so replaying a tx overwrites them.
+func (i zrc6Indexer) recordGovernance(tx entity.Transaction, c entity.Contract) {
+	for _, event := range factory.CreateGovernanceEvents(tx, c) {
+		zap.L().With(zap.String("contract", c.Address), zap.String("action", string(event.Action)), zap.String("value", event.Value)).
+			Info("Record ZRC6 governance event")
+		i.elastic.AddIndexRequest(elastic_search.GovernanceIndex.Get(), event, elastic_search.Zrc6Governance)
+	}
+}
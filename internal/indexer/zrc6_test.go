@@ -0,0 +1,142 @@
+package indexer
+
+import (
+	"context"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/factory"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/handler"
+	"testing"
+)
+
+const zrc6Contract = "0x6666666666666666666666666666666666666666"
+
+func newZrc6TestIndexer() (testStore, Zrc6Indexer) {
+	s := newTestStore()
+	s.save(elastic_search.ContractIndex, entity.Contract{
+		Address:   zrc6Contract,
+		BlockNum:  1,
+		Data:      entity.Data{Params: entity.Params{param("name", "Ducks"), param("symbol", "DUCK")}},
+		Standards: map[entity.ZrcStandard]bool{entity.ZRC6: true},
+		BaseUri:   "https://example.com/ducks/",
+	})
+
+	i := NewZrc6Indexer(
+		s.index,
+		s.contractRepo,
+		s.nftRepo,
+		s.txRepo,
+		s.operatorRepo,
+		factory.NewZrc6Factory(map[string]string{}),
+		handler.NewRegistry("mainnet", nil),
+	)
+
+	return s, i
+}
+
+func zrc6Mint(id string, blockNum uint64, to string, tokenId string) entity.Transaction {
+	return callTx(id, blockNum, alice, zrc6Contract, entity.ZRC6MintCallback,
+		eventLog(zrc6Contract, entity.ZRC6MintEvent, param("to", to), param("token_id", tokenId)))
+}
+
+func TestZrc6IndexTxs(t *testing.T) {
+	s, i := newZrc6TestIndexer()
+	ctx := context.Background()
+
+	txs := []entity.Transaction{
+		zrc6Mint("mint-1", 10, alice, "1"),
+		zrc6Mint("mint-2", 10, alice, "2"),
+		zrc6Mint("mint-3", 11, bob, "3"),
+		callTx("transfer-1", 12, alice, zrc6Contract, entity.ZRC6RecipientAcceptTransferFrom,
+			eventLog(zrc6Contract, entity.ZRC6TransferFromEvent, param("from", alice), param("to", carol), param("token_id", "1"))),
+		callTx("burn-2", 13, alice, zrc6Contract, entity.ZRC6BurnCallback,
+			eventLog(zrc6Contract, entity.ZRC6BurnEvent, param("token_owner", alice), param("token_id", "2"))),
+	}
+	if err := i.IndexTxs(ctx, txs); err != nil {
+		t.Fatal(err)
+	}
+	s.index.Persist()
+
+	for _, expected := range []struct {
+		tokenId  uint64
+		owner    string
+		burnedAt uint64
+	}{
+		{1, carol, 0},
+		{2, alice, 13},
+		{3, bob, 0},
+	} {
+		nft, err := s.nftRepo.GetNft(ctx, zrc6Contract, expected.tokenId)
+		if err != nil {
+			t.Fatalf("nft %d: %v", expected.tokenId, err)
+		}
+		if nft.Owner != expected.owner || nft.BurnedAt != expected.burnedAt {
+			t.Errorf("nft %d: owner %s burned at %d, expected %s burned at %d",
+				expected.tokenId, nft.Owner, nft.BurnedAt, expected.owner, expected.burnedAt)
+		}
+		if nft.Name != "Ducks" || nft.Symbol != "DUCK" || nft.BaseUri != "https://example.com/ducks/" {
+			t.Errorf("nft %d: not created from the contract, got %s %s %s", expected.tokenId, nft.Name, nft.Symbol, nft.BaseUri)
+		}
+	}
+
+	stats := s.getContract(t, zrc6Contract).NftStats
+	if stats == nil || *stats != (entity.NftStats{Supply: 2, Burned: 1, Holders: 2}) {
+		t.Errorf("contract stats %+v, expected supply 2, burned 1 and 2 holders", stats)
+	}
+
+	nft, _ := s.nftRepo.GetNft(ctx, zrc6Contract, 1)
+	actions, err := s.nftActionRepo.GetNftActions(ctx, *nft, nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actions) != 2 || actions[0].Action != entity.MintAction || actions[1].Action != entity.TransferAction {
+		t.Fatalf("nft 1 actions %+v, expected a mint then a transfer", actions)
+	}
+	if actions[1].From != alice || actions[1].To != carol || actions[1].BlockNum != 12 {
+		t.Errorf("transfer action %+v, expected from %s to %s at block 12", actions[1], alice, carol)
+	}
+}
+
+func TestZrc6BatchTransferRecordsPreviousOwner(t *testing.T) {
+	s, i := newZrc6TestIndexer()
+	ctx := context.Background()
+
+	txs := []entity.Transaction{
+		zrc6Mint("mint-1", 10, alice, "1"),
+		zrc6Mint("mint-2", 10, bob, "2"),
+		callTx("batch-transfer", 11, alice, zrc6Contract, entity.ZRC6BatchTransferFromCallback,
+			eventLog(zrc6Contract, entity.ZRC6BatchTransferFromEvent,
+				entity.Param{VName: "to_token_id_pair_list", Type: "List (Pair ByStr20 Uint256)", Value: &entity.Value{
+					Primitive: `[{"constructor":"Pair","argtypes":["ByStr20","Uint256"],"arguments":["` + carol + `","1"]},` +
+						`{"constructor":"Pair","argtypes":["ByStr20","Uint256"],"arguments":["` + carol + `","2"]}]`,
+				}})),
+	}
+	if err := i.IndexTxs(ctx, txs); err != nil {
+		t.Fatal(err)
+	}
+	s.index.Persist()
+
+	for tokenId, prevOwner := range map[uint64]string{1: alice, 2: bob} {
+		nft, err := s.nftRepo.GetNft(ctx, zrc6Contract, tokenId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if nft.Owner != carol {
+			t.Errorf("nft %d owned by %s, expected %s", tokenId, nft.Owner, carol)
+		}
+
+		actions, err := s.nftActionRepo.GetNftActions(ctx, *nft, nil, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		last := actions[len(actions)-1]
+		if last.Action != entity.TransferAction || last.From != prevOwner {
+			t.Errorf("nft %d last action %+v, expected a transfer from %s", tokenId, last, prevOwner)
+		}
+	}
+
+	stats := s.getContract(t, zrc6Contract).NftStats
+	if stats == nil || stats.Holders != 1 || stats.Supply != 2 {
+		t.Errorf("contract stats %+v, expected supply 2 held by 1", stats)
+	}
+}
@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+	"sort"
+)
+
+type nftActionRepository struct {
+	index Index
+}
+
+func NewNftActionRepository(index Index) repository.NftActionRepository {
+	return nftActionRepository{index}
+}
+
+func (r nftActionRepository) GetNftOwnerBeforeBlockNum(ctx context.Context, nft entity.Nft, blockNum uint64) (string, error) {
+	actions := r.find(func(action entity.NftAction) bool {
+		return action.BlockNum < blockNum &&
+			action.Contract == nft.Contract &&
+			action.TokenId == nft.TokenId &&
+			(action.Action == entity.MintAction || action.Action == entity.TransferAction)
+	})
+	if len(actions) == 0 {
+		return "", repository.ErrNftActionNotFound
+	}
+
+	return actions[len(actions)-1].To, nil
+}
+
+func (r nftActionRepository) GetNftActions(ctx context.Context, nft entity.Nft, after *entity.NftAction, size int) ([]entity.NftAction, error) {
+	return r.findAfter(func(action entity.NftAction) bool {
+		return action.Contract == nft.Contract && action.TokenId == nft.TokenId
+	}, after, size), nil
+}
+
+func (r nftActionRepository) GetActionsAfterBlockNum(ctx context.Context, blockNum uint64, after *entity.NftAction, size int) ([]entity.NftAction, error) {
+	return r.findAfter(func(action entity.NftAction) bool {
+		return action.BlockNum > blockNum
+	}, after, size), nil
+}
+
+func (r nftActionRepository) PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error {
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Purge nft actions after block")
+
+	ids := make([]string, 0)
+	for _, action := range r.find(func(action entity.NftAction) bool {
+		return action.BlockNum > blockNum
+	}) {
+		ids = append(ids, action.Slug())
+	}
+	r.index.DeleteDocuments(elastic_search.NftActionIndex.Get(), ids...)
+
+	return nil
+}
+
+// find returns the matching actions in block order
+func (r nftActionRepository) find(match func(action entity.NftAction) bool) []entity.NftAction {
+	actions := make([]entity.NftAction, 0)
+	for _, doc := range r.index.GetDocuments(elastic_search.NftActionIndex.Get()) {
+		var action entity.NftAction
+		if err := json.Unmarshal(doc.Source, &action); err == nil && match(action) {
+			actions = append(actions, action)
+		}
+	}
+
+	sort.SliceStable(actions, func(a, b int) bool {
+		return actions[a].BlockNum < actions[b].BlockNum
+	})
+
+	return actions
+}
+
+// findAfter returns the matching actions in the order they were made, following the action after when given
+func (r nftActionRepository) findAfter(match func(action entity.NftAction) bool, after *entity.NftAction, size int) []entity.NftAction {
+	actions := r.find(func(action entity.NftAction) bool {
+		return match(action) && (after == nil || actionBefore(*after, action))
+	})
+	sort.SliceStable(actions, func(a, b int) bool {
+		return actionBefore(actions[a], actions[b])
+	})
+	if len(actions) > size {
+		actions = actions[:size]
+	}
+
+	return actions
+}
+
+// actionBefore orders actions by block, tx and action
+func actionBefore(a, b entity.NftAction) bool {
+	if a.BlockNum != b.BlockNum {
+		return a.BlockNum < b.BlockNum
+	}
+	if a.TxID != b.TxID {
+		return a.TxID < b.TxID
+	}
+
+	return a.Action < b.Action
+}
@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+	"sort"
+	"time"
+)
+
+type addressRepository struct {
+	index Index
+}
+
+func NewAddressRepository(index Index) repository.AddressRepository {
+	return addressRepository{index}
+}
+
+func (r addressRepository) GetAddresses(ctx context.Context, addrs []string) (map[string]entity.Address, error) {
+	wanted := make(map[string]bool, len(addrs))
+	for _, addr := range addrs {
+		wanted[addr] = true
+	}
+
+	addresses := make(map[string]entity.Address, len(addrs))
+	for _, address := range r.find() {
+		if wanted[address.Address] {
+			addresses[address.Address] = address
+		}
+	}
+
+	for _, addr := range addrs {
+		if pendingRequest := r.index.GetRequest(elastic_search.AddressIndex.Get(), entity.CreateAddressSlug(addr)); pendingRequest != nil {
+			addresses[addr] = pendingRequest.Entity.(entity.Address)
+		}
+	}
+
+	return addresses, nil
+}
+
+func (r addressRepository) GetAddressesToReconcile(ctx context.Context, before time.Time, size int) ([]entity.Address, error) {
+	addresses := make([]entity.Address, 0)
+	for _, address := range r.find() {
+		if address.ReconciledAt.Before(before) {
+			addresses = append(addresses, address)
+		}
+	}
+
+	sort.SliceStable(addresses, func(a, b int) bool {
+		return addresses[a].ReconciledAt.Before(addresses[b].ReconciledAt)
+	})
+
+	from, to := paginate(len(addresses), size, 1)
+
+	return addresses[from:to], nil
+}
+
+func (r addressRepository) find() []entity.Address {
+	addresses := make([]entity.Address, 0)
+	for _, doc := range r.index.GetDocuments(elastic_search.AddressIndex.Get()) {
+		var address entity.Address
+		if err := json.Unmarshal(doc.Source, &address); err != nil {
+			zap.L().With(zap.Error(err)).Error("Failed to unmarshall address")
+			continue
+		}
+		addresses = append(addresses, address)
+	}
+
+	return addresses
+}
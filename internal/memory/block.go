@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+)
+
+type blockRepository struct {
+	index Index
+}
+
+func NewBlockRepository(index Index) repository.BlockRepository {
+	return blockRepository{index}
+}
+
+func (r blockRepository) PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error {
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Purge blocks after block")
+
+	var latest *entity.Block
+	blockIds := make([]string, 0)
+	for _, doc := range r.index.GetDocuments(elastic_search.BlockIndex.Get()) {
+		var block entity.Block
+		if err := json.Unmarshal(doc.Source, &block); err != nil {
+			continue
+		}
+		if block.BlockNum > blockNum {
+			blockIds = append(blockIds, doc.Id)
+		} else if latest == nil || block.BlockNum > latest.BlockNum {
+			latest = &block
+		}
+	}
+
+	dsBlockIds := make([]string, 0)
+	for _, doc := range r.index.GetDocuments(elastic_search.DsBlockIndex.Get()) {
+		var dsBlock entity.DsBlock
+		if err := json.Unmarshal(doc.Source, &dsBlock); err != nil {
+			continue
+		}
+		if latest == nil || dsBlock.BlockNum > latest.DsBlockNum {
+			dsBlockIds = append(dsBlockIds, doc.Id)
+		}
+	}
+
+	r.index.DeleteDocuments(elastic_search.DsBlockIndex.Get(), dsBlockIds...)
+	r.index.DeleteDocuments(elastic_search.BlockIndex.Get(), blockIds...)
+
+	return nil
+}
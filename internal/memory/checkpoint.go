@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+)
+
+type checkpointRepository struct {
+	index Index
+}
+
+func NewCheckpointRepository(index Index) repository.CheckpointRepository {
+	return checkpointRepository{index}
+}
+
+func (r checkpointRepository) GetCheckpoint(ctx context.Context, stage entity.Stage) (*entity.Checkpoint, error) {
+	pendingRequest := r.index.GetRequest(elastic_search.CheckpointIndex.Get(), entity.CreateCheckpointSlug(stage))
+	if pendingRequest != nil {
+		pendingCheckpoint := pendingRequest.Entity.(entity.Checkpoint)
+		return &pendingCheckpoint, nil
+	}
+
+	for _, doc := range r.index.GetDocuments(elastic_search.CheckpointIndex.Get()) {
+		var checkpoint entity.Checkpoint
+		if err := json.Unmarshal(doc.Source, &checkpoint); err == nil && checkpoint.Stage == stage {
+			return &checkpoint, nil
+		}
+	}
+
+	return nil, repository.ErrCheckpointNotFound
+}
+
+func (r checkpointRepository) GetBlockNum(ctx context.Context, stage entity.Stage) (uint64, error) {
+	checkpoint, err := r.GetCheckpoint(ctx, stage)
+	if err != nil {
+		return 0, err
+	}
+
+	return checkpoint.BlockNum, nil
+}
@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+	"sort"
+)
+
+type contractRepository struct {
+	index Index
+}
+
+func NewContractRepository(index Index) repository.ContractRepository {
+	return contractRepository{index}
+}
+
+func (r contractRepository) GetAllContracts(ctx context.Context, size, page int) ([]entity.Contract, int64, error) {
+	contracts := r.find(func(c entity.Contract) bool {
+		return true
+	})
+	sort.SliceStable(contracts, func(a, b int) bool {
+		return contracts[a].BlockNum > contracts[b].BlockNum
+	})
+
+	return r.findMany(contracts, size, page)
+}
+
+func (r contractRepository) GetAllNftContracts(ctx context.Context, size, page int) ([]entity.Contract, int64, error) {
+	contracts := r.find(func(c entity.Contract) bool {
+		return c.Standards[entity.ZRC1] || c.Standards[entity.ZRC6]
+	})
+	sortContractsByBlockNum(contracts)
+
+	return r.findMany(contracts, size, page)
+}
+
+func (r contractRepository) GetAllZrc2Contracts(ctx context.Context, size, page int) ([]entity.Contract, int64, error) {
+	contracts := r.find(func(c entity.Contract) bool {
+		return c.Standards[entity.ZRC2]
+	})
+	sortContractsByBlockNum(contracts)
+
+	return r.findMany(contracts, size, page)
+}
+
+func (r contractRepository) GetContractByAddress(ctx context.Context, contractAddr string) (*entity.Contract, error) {
+	pendingRequest := r.index.GetRequest(elastic_search.ContractIndex.Get(), entity.CreateContractSlug(contractAddr))
+	if pendingRequest != nil {
+		pendingContract := pendingRequest.Entity.(entity.Contract)
+		return &pendingContract, nil
+	}
+
+	contracts := r.find(func(c entity.Contract) bool {
+		return c.Address == contractAddr
+	})
+	if len(contracts) == 0 {
+		return nil, repository.ErrContractNotFound
+	}
+
+	return &contracts[0], nil
+}
+
+func (r contractRepository) GetBestBlockNum(ctx context.Context) (uint64, error) {
+	contracts := r.find(func(c entity.Contract) bool {
+		return true
+	})
+	if len(contracts) == 0 {
+		return 0, repository.ErrContractNotFound
+	}
+
+	var blockNum uint64
+	for _, c := range contracts {
+		if c.BlockNum > blockNum {
+			blockNum = c.BlockNum
+		}
+	}
+
+	return blockNum, nil
+}
+
+func (r contractRepository) GetContractsAfterBlockNum(ctx context.Context, blockNum uint64, size, page int) ([]entity.Contract, int64, error) {
+	contracts := r.find(func(c entity.Contract) bool {
+		return c.BlockNum > blockNum
+	})
+	sortContractsByBlockNum(contracts)
+
+	return r.findMany(contracts, size, page)
+}
+
+func (r contractRepository) PurgeContract(ctx context.Context, contractAddr string) error {
+	zap.L().With(zap.String("contract", contractAddr)).Info("Purge contract document")
+
+	r.index.DeleteDocuments(elastic_search.ContractIndex.Get(), entity.CreateContractSlug(contractAddr))
+	r.index.DeleteDocuments(elastic_search.ContractStateIndex.Get(), entity.CreateStateSlug(contractAddr))
+	r.index.DeleteDocuments(elastic_search.ContractMetadataIndex.Get(), entity.CreateContractSlug(contractAddr))
+
+	return nil
+}
+
+func (r contractRepository) find(match func(c entity.Contract) bool) []entity.Contract {
+	contracts := make([]entity.Contract, 0)
+	for _, doc := range r.index.GetDocuments(elastic_search.ContractIndex.Get()) {
+		var c entity.Contract
+		if err := json.Unmarshal(doc.Source, &c); err == nil && match(c) {
+			contracts = append(contracts, c)
+		}
+	}
+
+	return contracts
+}
+
+func (r contractRepository) findMany(contracts []entity.Contract, size, page int) ([]entity.Contract, int64, error) {
+	from, to := paginate(len(contracts), size, page)
+
+	return contracts[from:to], int64(len(contracts)), nil
+}
+
+func sortContractsByBlockNum(contracts []entity.Contract) {
+	sort.SliceStable(contracts, func(a, b int) bool {
+		return contracts[a].BlockNum < contracts[b].BlockNum
+	})
+}
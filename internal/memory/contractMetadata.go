@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"strings"
+)
+
+type contractMetadataRepository struct {
+	index Index
+}
+
+func NewContractMetadataRepository(index Index) repository.ContractMetadataRepository {
+	return contractMetadataRepository{index}
+}
+
+func (r contractMetadataRepository) GetMetadataByAddress(ctx context.Context, contractAddr string) (*entity.ContractMetadata, error) {
+	for _, doc := range r.index.GetDocuments(elastic_search.ContractMetadataIndex.Get()) {
+		var md entity.ContractMetadata
+		if err := json.Unmarshal(doc.Source, &md); err != nil {
+			continue
+		}
+
+		if contract, ok := md["contract"].(string); ok && strings.EqualFold(contract, contractAddr) {
+			return &md, nil
+		}
+	}
+
+	return nil, repository.ErrContractMetadataNotFound
+}
@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"strconv"
+)
+
+type contractStateRepository struct {
+	index Index
+}
+
+func NewContractStateRepository(index Index) repository.ContractStateRepository {
+	return contractStateRepository{index}
+}
+
+func (r contractStateRepository) GetStateByAddress(ctx context.Context, contractAddr string) (*entity.ContractState, error) {
+	pendingRequest := r.index.GetRequest(elastic_search.ContractStateIndex.Get(), entity.CreateStateSlug(contractAddr))
+	if pendingRequest != nil {
+		pendingState := pendingRequest.Entity.(entity.ContractState)
+		return &pendingState, nil
+	}
+
+	for _, doc := range r.index.GetDocuments(elastic_search.ContractStateIndex.Get()) {
+		var state entity.ContractState
+		if err := json.Unmarshal(doc.Source, &state); err == nil && state.Address == contractAddr {
+			return &state, nil
+		}
+	}
+
+	return nil, repository.ErrContractStateNotFound
+}
+
+func (r contractStateRepository) GetRoyaltyFeeBps(ctx context.Context, contractAddr string) (uint, error) {
+	state, err := r.GetStateByAddress(ctx, contractAddr)
+	if err != nil {
+		return 0, err
+	}
+
+	royaltyFeeBps, exists := state.GetElement("royalty_fee_bps")
+	if !exists {
+		return 0, nil
+	}
+
+	fee, err := strconv.Atoi(royaltyFeeBps)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(fee), nil
+}
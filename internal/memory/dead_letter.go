@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+	"sort"
+)
+
+type deadLetterRepository struct {
+	index Index
+}
+
+func NewDeadLetterRepository(index Index) repository.DeadLetterRepository {
+	return deadLetterRepository{index}
+}
+
+func (r deadLetterRepository) GetDeadLetter(ctx context.Context, id string) (*entity.DeadLetter, error) {
+	for _, deadLetter := range r.find() {
+		if deadLetter.Slug() == id {
+			return &deadLetter, nil
+		}
+	}
+
+	return nil, repository.ErrDeadLetterNotFound
+}
+
+func (r deadLetterRepository) GetDeadLetters(ctx context.Context, size, page int) ([]entity.DeadLetter, int64, error) {
+	deadLetters := r.find()
+	sort.SliceStable(deadLetters, func(a, b int) bool {
+		return deadLetters[a].CreatedAt.After(deadLetters[b].CreatedAt)
+	})
+
+	from, to := paginate(len(deadLetters), size, page)
+
+	return deadLetters[from:to], int64(len(deadLetters)), nil
+}
+
+// Replay writes the dead letter's document back to its original index, and removes the dead letter once it succeeds
+func (r deadLetterRepository) Replay(ctx context.Context, deadLetter entity.DeadLetter) error {
+	if err := r.index.PutDocument(
+		deadLetter.Index,
+		deadLetter.DocId,
+		deadLetter.Document,
+		elastic_search.RequestType(deadLetter.RequestType),
+	); err != nil {
+		return err
+	}
+
+	return r.Delete(ctx, deadLetter)
+}
+
+func (r deadLetterRepository) Delete(ctx context.Context, deadLetter entity.DeadLetter) error {
+	r.index.DeleteDocuments(elastic_search.DeadLetterIndex.Get(), deadLetter.Slug())
+
+	return nil
+}
+
+func (r deadLetterRepository) find() []entity.DeadLetter {
+	deadLetters := make([]entity.DeadLetter, 0)
+	for _, doc := range r.index.GetDocuments(elastic_search.DeadLetterIndex.Get()) {
+		var deadLetter entity.DeadLetter
+		if err := json.Unmarshal(doc.Source, &deadLetter); err != nil {
+			zap.L().With(zap.Error(err)).Error("Failed to unmarshall dead letter")
+			continue
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+
+	return deadLetters
+}
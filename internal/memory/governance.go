@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+	"sort"
+)
+
+type governanceRepository struct {
+	index Index
+}
+
+func NewGovernanceRepository(index Index) repository.GovernanceRepository {
+	return governanceRepository{index}
+}
+
+func (r governanceRepository) GetHistory(ctx context.Context, contract string, size, page int) ([]entity.GovernanceEvent, int64, error) {
+	events := r.find(func(event entity.GovernanceEvent) bool {
+		return event.Contract == contract
+	})
+	from, to := paginate(len(events), size, page)
+
+	return events[from:to], int64(len(events)), nil
+}
+
+func (r governanceRepository) GetLatestEvent(ctx context.Context, contract string, action entity.GovernanceAction, blockNum uint64) (*entity.GovernanceEvent, error) {
+	pending := repository.LatestPendingGovernanceEvent(r.index, contract, action, blockNum)
+
+	var stored *entity.GovernanceEvent
+	if events := r.find(func(event entity.GovernanceEvent) bool {
+		return event.Contract == contract && event.Action == action && event.BlockNum <= blockNum
+	}); len(events) != 0 {
+		stored = &events[0]
+	}
+
+	return repository.LaterGovernanceEvent(stored, pending)
+}
+
+func (r governanceRepository) PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error {
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Purge governance events after block")
+
+	ids := make([]string, 0)
+	for _, event := range r.find(func(event entity.GovernanceEvent) bool {
+		return event.BlockNum > blockNum
+	}) {
+		ids = append(ids, event.Slug())
+	}
+	r.index.DeleteDocuments(elastic_search.GovernanceIndex.Get(), ids...)
+
+	return nil
+}
+
+// find returns the matching events, most recent first
+func (r governanceRepository) find(match func(event entity.GovernanceEvent) bool) []entity.GovernanceEvent {
+	events := make([]entity.GovernanceEvent, 0)
+	for _, doc := range r.index.GetDocuments(elastic_search.GovernanceIndex.Get()) {
+		var event entity.GovernanceEvent
+		if err := json.Unmarshal(doc.Source, &event); err == nil && match(event) {
+			events = append(events, event)
+		}
+	}
+
+	sort.SliceStable(events, func(a, b int) bool {
+		if events[a].BlockNum != events[b].BlockNum {
+			return events[a].BlockNum > events[b].BlockNum
+		}
+		return events[a].EventIndex > events[b].EventIndex
+	})
+
+	return events
+}
@@ -0,0 +1,209 @@
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/config"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"go.uber.org/zap"
+	"sort"
+	"sync"
+)
+
+var (
+	ErrDocumentMissing = errors.New("document missing")
+)
+
+// Index keeps every document in memory, for tests and dry runs. Documents are stored as json so reads
+// behave as they would against a real store, with updates merged into the stored document.
+type Index interface {
+	elastic_search.Index
+	GetDocuments(index string) []Document
+	PutDocument(index, id string, doc []byte, reqType elastic_search.RequestType) error
+	DeleteDocuments(index string, ids ...string)
+}
+
+type Document struct {
+	Id     string
+	Source []byte
+}
+
+type index struct {
+	*elastic_search.Requests
+	store     *store
+	persistMu *sync.Mutex
+}
+
+type store struct {
+	sync.RWMutex
+	docs map[string]map[string][]byte
+}
+
+func New() Index {
+	bufferSize := config.Get().ElasticSearch.BufferSizeMb * 1024 * 1024
+
+	i := index{nil, &store{docs: map[string]map[string][]byte{}}, &sync.Mutex{}}
+	i.Requests = elastic_search.NewRequests(bufferSize, func() int { return i.Persist() })
+
+	return i
+}
+
+func (i index) InstallMappings() {
+	zap.L().Info("Memory: Nothing to install")
+}
+
+func (i index) PromoteIndices() {}
+
+func (i index) GetMigrations() ([]elastic_search.Migration, error) {
+	return []elastic_search.Migration{}, nil
+}
+
+func (i index) Migrate() error {
+	return nil
+}
+
+func (i index) Save(index string, entity entity.Entity) {
+	i.write(elastic_search.Request{Index: index, Entity: entity, Type: elastic_search.IndexRequest})
+}
+
+// Persist moves the pending requests into the store. Events are not emitted, so a dry run does not
+// queue metadata refreshes.
+func (i index) Persist() int {
+	i.persistMu.Lock()
+	defer i.persistMu.Unlock()
+
+	pending, sequences := i.Pending()
+	for _, req := range pending {
+		i.write(req)
+	}
+	i.Release(pending, sequences)
+
+	return len(pending)
+}
+
+func (i index) write(req elastic_search.Request) {
+	doc, err := json.Marshal(req.Entity)
+	if err != nil {
+		i.deadLetter(req, "mapper_parsing_exception", err.Error())
+		return
+	}
+
+	if err := i.store.put(req.Index, req.Entity.Slug(), doc, req.Type); err != nil {
+		i.deadLetter(req, "document_missing_exception", err.Error())
+	}
+}
+
+func (i index) deadLetter(req elastic_search.Request, errorType, reason string) {
+	deadLetter, err := elastic_search.CreateDeadLetter(req, errorType, reason)
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.String("index", req.Index), zap.String("slug", req.Entity.Slug())).
+			Fatal("Memory: Failed to create dead letter")
+	}
+
+	zap.L().With(
+		zap.String("index", req.Index),
+		zap.String("slug", req.Entity.Slug()),
+		zap.String("errorType", errorType),
+		zap.String("reason", reason),
+	).Warn("Memory: Request moved to dead letters")
+
+	i.write(elastic_search.Request{
+		Index:  elastic_search.DeadLetterIndex.Get(),
+		Entity: deadLetter,
+		Type:   elastic_search.IndexRequest,
+		Action: elastic_search.DeadLetter,
+	})
+}
+
+func (i index) GetDocuments(index string) []Document {
+	return i.store.all(index)
+}
+
+func (i index) PutDocument(index, id string, doc []byte, reqType elastic_search.RequestType) error {
+	return i.store.put(index, id, doc, reqType)
+}
+
+func (i index) DeleteDocuments(index string, ids ...string) {
+	i.store.delete(index, ids...)
+}
+
+// put replaces the document for an index request. An update request is merged into the stored document
+// and fails when there is none, as a partial update does in elasticsearch.
+func (s *store) put(index, id string, doc []byte, reqType elastic_search.RequestType) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, found := s.docs[index]; !found {
+		s.docs[index] = map[string][]byte{}
+	}
+
+	if reqType == elastic_search.UpdateRequest {
+		existing, found := s.docs[index][id]
+		if !found {
+			return ErrDocumentMissing
+		}
+
+		merged, err := mergeDocuments(existing, doc)
+		if err != nil {
+			return err
+		}
+		doc = merged
+	}
+
+	s.docs[index][id] = doc
+
+	return nil
+}
+
+// all returns the documents of an index ordered by id
+func (s *store) all(index string) []Document {
+	s.RLock()
+	defer s.RUnlock()
+
+	docs := make([]Document, 0, len(s.docs[index]))
+	for id, source := range s.docs[index] {
+		docs = append(docs, Document{id, source})
+	}
+
+	sort.Slice(docs, func(a, b int) bool {
+		return docs[a].Id < docs[b].Id
+	})
+
+	return docs
+}
+
+func (s *store) delete(index string, ids ...string) {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, id := range ids {
+		delete(s.docs[index], id)
+	}
+}
+
+func mergeDocuments(existing, update []byte) ([]byte, error) {
+	var dst, src map[string]interface{}
+	if err := json.Unmarshal(existing, &dst); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(update, &src); err != nil {
+		return nil, err
+	}
+
+	mergeFields(dst, src)
+
+	return json.Marshal(dst)
+}
+
+func mergeFields(dst, src map[string]interface{}) {
+	for key, value := range src {
+		if srcObject, ok := value.(map[string]interface{}); ok {
+			if dstObject, ok := dst[key].(map[string]interface{}); ok {
+				mergeFields(dstObject, srcObject)
+				continue
+			}
+		}
+		dst[key] = value
+	}
+}
@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"context"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"testing"
+)
+
+const contract = "0x6666666666666666666666666666666666666666"
+
+func TestPaginate(t *testing.T) {
+	for _, tc := range []struct {
+		total, size, page int
+		from, to          int
+	}{
+		{10, 4, 1, 0, 4},
+		{10, 4, 3, 8, 10},
+		{10, 4, 4, 10, 10},
+		{10, 4, 0, 0, 4},
+		{0, 4, 1, 0, 0},
+	} {
+		from, to := paginate(tc.total, tc.size, tc.page)
+		if from != tc.from || to != tc.to {
+			t.Errorf("paginate(%d, %d, %d) = %d, %d, expected %d, %d", tc.total, tc.size, tc.page, from, to, tc.from, tc.to)
+		}
+	}
+}
+
+func TestGetNftsPagesByTokenId(t *testing.T) {
+	index := New()
+	repo := NewNftRepository(index)
+
+	for _, tokenId := range []uint64{5, 3, 1, 4, 2} {
+		index.Save(elastic_search.NftIndex.Get(), entity.Nft{Contract: contract, TokenId: tokenId})
+	}
+	index.Save(elastic_search.NftIndex.Get(), entity.Nft{Contract: "0xother", TokenId: 1})
+
+	var tokenIds []uint64
+	for page := 1; ; page++ {
+		nfts, total, err := repo.GetNfts(context.Background(), contract, 2, page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 5 {
+			t.Fatalf("total %d, expected 5", total)
+		}
+		if len(nfts) == 0 {
+			break
+		}
+		for _, nft := range nfts {
+			tokenIds = append(tokenIds, nft.TokenId)
+		}
+	}
+
+	for idx, tokenId := range tokenIds {
+		if tokenId != uint64(idx+1) {
+			t.Fatalf("token ids %v, expected 1 to 5 in order", tokenIds)
+		}
+	}
+}
+
+func TestGetNftReadsPendingRequests(t *testing.T) {
+	index := New()
+	repo := NewNftRepository(index)
+	ctx := context.Background()
+
+	index.Save(elastic_search.NftIndex.Get(), entity.Nft{Contract: contract, TokenId: 1, Owner: "0xalice"})
+	index.AddUpdateRequest(elastic_search.NftIndex.Get(), entity.Nft{Contract: contract, TokenId: 1, Owner: "0xbob"}, elastic_search.Zrc6Transfer)
+
+	nft, err := repo.GetNft(ctx, contract, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nft.Owner != "0xbob" {
+		t.Errorf("owner %s before persisting, expected the pending 0xbob", nft.Owner)
+	}
+
+	index.Persist()
+	if nft, err = repo.GetNft(ctx, contract, 1); err != nil || nft.Owner != "0xbob" {
+		t.Errorf("owner %v after persisting, expected 0xbob: %v", nft, err)
+	}
+
+	if _, err := repo.GetNft(ctx, contract, 2); err != repository.ErrNftNotFound {
+		t.Errorf("missing nft returned %v, expected ErrNftNotFound", err)
+	}
+}
+
+func TestUpdateRequestWithoutDocumentIsDeadLettered(t *testing.T) {
+	index := New()
+
+	index.AddUpdateRequest(elastic_search.NftIndex.Get(), entity.Nft{Contract: contract, TokenId: 1}, elastic_search.Zrc6Transfer)
+	index.Persist()
+
+	if docs := index.GetDocuments(elastic_search.NftIndex.Get()); len(docs) != 0 {
+		t.Errorf("%d nfts stored by a partial update, expected none", len(docs))
+	}
+	if docs := index.GetDocuments(elastic_search.DeadLetterIndex.Get()); len(docs) != 1 {
+		t.Errorf("%d dead letters, expected the update", len(docs))
+	}
+}
+
+func TestGetMetadataByStatus(t *testing.T) {
+	index := New()
+	repo := NewNftRepository(index)
+
+	for tokenId, metadata := range map[uint64]*entity.Metadata{
+		1: {Status: entity.MetadataPending, Attempts: 2},
+		2: {Status: entity.MetadataSuccess},
+		3: {Status: entity.MetadataPending, Attempts: 0},
+		4: nil,
+	} {
+		index.Save(elastic_search.NftIndex.Get(), entity.Nft{Contract: contract, TokenId: tokenId, Metadata: metadata})
+	}
+
+	nfts, total, err := repo.GetMetadata(context.Background(), 10, 1, entity.MetadataPending)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 || len(nfts) != 2 || nfts[0].TokenId != 3 || nfts[1].TokenId != 1 {
+		t.Errorf("pending nfts %+v, expected 3 then 1 by attempts", nfts)
+	}
+}
+
+func TestGetContractExecutionsByContractFrom(t *testing.T) {
+	index := New()
+	repo := NewTransactionRepository(index)
+
+	emitted := []entity.EventLog{{EventName: "Mint", Address: contract}}
+	for id, tx := range map[string]entity.Transaction{
+		"a": {BlockNum: 12, IsContractExecution: true, Receipt: entity.TransactionReceipt{EventLogs: emitted}},
+		"b": {BlockNum: 9, IsContractExecution: true, Receipt: entity.TransactionReceipt{EventLogs: emitted}},
+		"c": {BlockNum: 10, IsContractExecution: true, Receipt: entity.TransactionReceipt{EventLogs: emitted}},
+		"d": {BlockNum: 11, IsContractExecution: true, IsFailed: true, Receipt: entity.TransactionReceipt{EventLogs: emitted}},
+		"e": {BlockNum: 11, IsContractExecution: true, Receipt: entity.TransactionReceipt{EventLogs: []entity.EventLog{{EventName: "Mint", Address: "0xother"}}}},
+	} {
+		tx.ID = id
+		index.Save(elastic_search.TransactionIndex.Get(), tx)
+	}
+
+	txs, total, err := repo.GetContractExecutionsByContractFrom(context.Background(), entity.Contract{Address: contract}, 10, 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 || len(txs) != 2 || txs[0].BlockNum != 10 || txs[1].BlockNum != 12 {
+		t.Errorf("executions %+v, expected blocks 10 and 12", txs)
+	}
+}
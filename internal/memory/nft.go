@@ -0,0 +1,239 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+	"sort"
+	"strings"
+)
+
+type nftRepository struct {
+	index Index
+}
+
+func NewNftRepository(index Index) repository.NftRepository {
+	return nftRepository{index}
+}
+
+func (r nftRepository) Exists(ctx context.Context, contract string, tokenId uint64) bool {
+	return len(r.find(func(nft entity.Nft) bool {
+		return nft.Contract == contract && nft.TokenId == tokenId
+	})) != 0
+}
+
+func (r nftRepository) GetNft(ctx context.Context, contract string, tokenId uint64) (*entity.Nft, error) {
+	pendingRequest := r.index.GetRequest(elastic_search.NftIndex.Get(), entity.CreateNftSlug(tokenId, contract))
+	if pendingRequest != nil {
+		pendingNft := pendingRequest.Entity.(entity.Nft)
+		return &pendingNft, nil
+	}
+
+	return r.findOne(r.find(func(nft entity.Nft) bool {
+		return strings.EqualFold(nft.Contract, contract) && nft.TokenId == tokenId
+	}))
+}
+
+// FindNft is GetNft, which does not retry
+func (r nftRepository) FindNft(ctx context.Context, contract string, tokenId uint64) (*entity.Nft, error) {
+	return r.GetNft(ctx, contract, tokenId)
+}
+
+func (r nftRepository) GetNfts(ctx context.Context, contract string, size, page int) ([]entity.Nft, int64, error) {
+	nfts := r.find(func(nft entity.Nft) bool {
+		return nft.Contract == contract
+	})
+	sortByTokenId(nfts)
+
+	return r.findMany(nfts, size, page)
+}
+
+func (r nftRepository) GetBestTokenId(ctx context.Context, contractAddr string, blockNum uint64) (uint64, error) {
+	var tokenId uint64
+	for _, nft := range r.find(func(nft entity.Nft) bool {
+		return nft.Contract == contractAddr && nft.BlockNum < blockNum
+	}) {
+		if nft.TokenId > tokenId {
+			tokenId = nft.TokenId
+		}
+	}
+
+	return tokenId, nil
+}
+
+func (r nftRepository) GetNftStats(ctx context.Context, contract string) (entity.NftStats, error) {
+	return repository.CountNftStats(0, 0, map[string]bool{}, r.findWithPending(contract)), nil
+}
+
+// findWithPending returns the nfts of a contract, pending versions replacing those stored
+func (r nftRepository) findWithPending(contract string) map[string]entity.Nft {
+	nfts := repository.PendingNfts(r.index, contract)
+	for _, nft := range r.find(func(nft entity.Nft) bool {
+		return nft.Contract == contract
+	}) {
+		if _, ok := nfts[nft.Slug()]; !ok {
+			nfts[nft.Slug()] = nft
+		}
+	}
+
+	return nfts
+}
+
+func (r nftRepository) GetAllNfts(ctx context.Context, size, page int) ([]entity.Nft, int64, error) {
+	nfts := r.find(func(nft entity.Nft) bool {
+		return true
+	})
+	sort.SliceStable(nfts, func(a, b int) bool {
+		return nfts[a].BlockNum > nfts[b].BlockNum
+	})
+
+	return r.findMany(nfts, size, page)
+}
+
+func (r nftRepository) GetAllZrc1Nfts(ctx context.Context, size, page int) ([]entity.Nft, int64, error) {
+	nfts := r.find(func(nft entity.Nft) bool {
+		return nft.Zrc1
+	})
+	sortByTokenId(nfts)
+
+	return r.findMany(nfts, size, page)
+}
+
+func (r nftRepository) GetAllZrc6Nfts(ctx context.Context, size, page int) ([]entity.Nft, int64, error) {
+	nfts := r.find(func(nft entity.Nft) bool {
+		return nft.Zrc6
+	})
+	sortByTokenId(nfts)
+
+	return r.findMany(nfts, size, page)
+}
+
+func (r nftRepository) GetMetadata(ctx context.Context, size, page int, status entity.MetadataStatus) ([]entity.Nft, int64, error) {
+	nfts := r.find(func(nft entity.Nft) bool {
+		return nft.Metadata != nil && nft.Metadata.Status == status
+	})
+	sort.SliceStable(nfts, func(a, b int) bool {
+		return nfts[a].Metadata.Attempts < nfts[b].Metadata.Attempts
+	})
+
+	return r.findMany(nfts, size, page)
+}
+
+func (r nftRepository) GetIpfsMetadata(ctx context.Context, size, page int) ([]entity.Nft, int64, error) {
+	nfts := r.find(func(nft entity.Nft) bool {
+		return nft.Metadata != nil && nft.Metadata.IsIpfs
+	})
+	sortByTokenId(nfts)
+
+	return r.findMany(nfts, size, page)
+}
+
+func (r nftRepository) ResetMetadata(ctx context.Context, nft entity.Nft) error {
+	for _, stored := range r.find(func(n entity.Nft) bool {
+		return n.Contract == nft.Contract && n.TokenId == nft.TokenId
+	}) {
+		if stored.Metadata != nil {
+			stored.Metadata.Properties = nil
+		}
+		r.index.Save(elastic_search.NftIndex.Get(), stored)
+	}
+
+	return nil
+}
+
+func (r nftRepository) GetBestBlockNum(ctx context.Context) (uint64, error) {
+	nfts := r.find(func(nft entity.Nft) bool {
+		return true
+	})
+	if len(nfts) == 0 {
+		return 0, repository.ErrNftNotFound
+	}
+
+	var blockNum uint64
+	for _, nft := range nfts {
+		if nft.BlockNum > blockNum {
+			blockNum = nft.BlockNum
+		}
+	}
+
+	return blockNum, nil
+}
+
+func (r nftRepository) PurgeActions(ctx context.Context, contractAddr string) error {
+	zap.L().With(zap.String("contract", contractAddr)).Info("Purge actions for contract")
+
+	ids := make([]string, 0)
+	for _, doc := range r.index.GetDocuments(elastic_search.NftActionIndex.Get()) {
+		var action entity.NftAction
+		if err := json.Unmarshal(doc.Source, &action); err == nil && action.Contract == contractAddr {
+			ids = append(ids, doc.Id)
+		}
+	}
+	r.index.DeleteDocuments(elastic_search.NftActionIndex.Get(), ids...)
+
+	return nil
+}
+
+func (r nftRepository) PurgeContract(ctx context.Context, contractAddr string) error {
+	zap.L().With(zap.String("contract", contractAddr)).Info("Purge contract")
+
+	r.delete(func(nft entity.Nft) bool {
+		return nft.Contract == contractAddr
+	})
+
+	return nil
+}
+
+func (r nftRepository) PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error {
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Purge nfts minted after block")
+
+	r.delete(func(nft entity.Nft) bool {
+		return nft.BlockNum > blockNum
+	})
+
+	return nil
+}
+
+func (r nftRepository) find(match func(nft entity.Nft) bool) []entity.Nft {
+	nfts := make([]entity.Nft, 0)
+	for _, doc := range r.index.GetDocuments(elastic_search.NftIndex.Get()) {
+		var nft entity.Nft
+		if err := json.Unmarshal(doc.Source, &nft); err == nil && match(nft) {
+			nfts = append(nfts, nft)
+		}
+	}
+
+	return nfts
+}
+
+func (r nftRepository) delete(match func(nft entity.Nft) bool) {
+	ids := make([]string, 0)
+	for _, nft := range r.find(match) {
+		ids = append(ids, nft.Slug())
+	}
+
+	r.index.DeleteDocuments(elastic_search.NftIndex.Get(), ids...)
+}
+
+func (r nftRepository) findOne(nfts []entity.Nft) (*entity.Nft, error) {
+	if len(nfts) == 0 {
+		return nil, repository.ErrNftNotFound
+	}
+
+	return &nfts[0], nil
+}
+
+func (r nftRepository) findMany(nfts []entity.Nft, size, page int) ([]entity.Nft, int64, error) {
+	from, to := paginate(len(nfts), size, page)
+
+	return nfts[from:to], int64(len(nfts)), nil
+}
+
+func sortByTokenId(nfts []entity.Nft) {
+	sort.SliceStable(nfts, func(a, b int) bool {
+		return nfts[a].TokenId < nfts[b].TokenId
+	})
+}
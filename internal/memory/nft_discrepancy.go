@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"sort"
+)
+
+type nftDiscrepancyRepository struct {
+	index Index
+}
+
+func NewNftDiscrepancyRepository(index Index) repository.NftDiscrepancyRepository {
+	return nftDiscrepancyRepository{index}
+}
+
+func (r nftDiscrepancyRepository) GetDiscrepancies(ctx context.Context, contract string, status entity.DiscrepancyStatus, size, page int) ([]entity.NftDiscrepancy, int64, error) {
+	discrepancies := make([]entity.NftDiscrepancy, 0)
+	for _, doc := range r.index.GetDocuments(elastic_search.NftDiscrepancyIndex.Get()) {
+		var discrepancy entity.NftDiscrepancy
+		if err := json.Unmarshal(doc.Source, &discrepancy); err == nil && discrepancy.Contract == contract && (status == "" || discrepancy.Status == status) {
+			discrepancies = append(discrepancies, discrepancy)
+		}
+	}
+	sort.SliceStable(discrepancies, func(a, b int) bool {
+		return discrepancies[a].TokenId < discrepancies[b].TokenId
+	})
+	from, to := paginate(len(discrepancies), size, page)
+
+	return discrepancies[from:to], int64(len(discrepancies)), nil
+}
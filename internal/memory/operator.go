@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+	"sort"
+)
+
+type operatorRepository struct {
+	index Index
+}
+
+func NewOperatorRepository(index Index) repository.OperatorRepository {
+	return operatorRepository{index}
+}
+
+func (r operatorRepository) GetOperator(ctx context.Context, contract, owner, operator string) (*entity.Operator, error) {
+	slug := entity.CreateOperatorSlug(contract, owner, operator)
+	if pendingRequest := r.index.GetRequest(elastic_search.OperatorIndex.Get(), slug); pendingRequest != nil {
+		pendingOperator := pendingRequest.Entity.(entity.Operator)
+		return &pendingOperator, nil
+	}
+
+	operators := r.find(func(o entity.Operator) bool {
+		return o.Slug() == slug
+	})
+	if len(operators) == 0 {
+		return nil, repository.ErrOperatorNotFound
+	}
+
+	return &operators[0], nil
+}
+
+// GetOperatorsAfterBlockNum pages by slug
+func (r operatorRepository) GetOperatorsAfterBlockNum(ctx context.Context, blockNum uint64, after *entity.Operator, size int) ([]entity.Operator, error) {
+	operators := r.find(func(o entity.Operator) bool {
+		return o.BlockNum > blockNum && (after == nil || o.Slug() > after.Slug())
+	})
+	sort.SliceStable(operators, func(a, b int) bool {
+		return operators[a].Slug() < operators[b].Slug()
+	})
+	if len(operators) > size {
+		operators = operators[:size]
+	}
+
+	return operators, nil
+}
+
+func (r operatorRepository) PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error {
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Purge operators after block")
+
+	ids := make([]string, 0)
+	for _, operator := range r.find(func(o entity.Operator) bool {
+		return o.BlockNum > blockNum
+	}) {
+		ids = append(ids, operator.Slug())
+	}
+	r.index.DeleteDocuments(elastic_search.OperatorIndex.Get(), ids...)
+
+	return nil
+}
+
+func (r operatorRepository) GetApprovals(ctx context.Context, owner string) ([]entity.Approval, error) {
+	approvals := make([]entity.Approval, 0)
+
+	operators := r.find(func(o entity.Operator) bool {
+		return o.Owner == owner && o.Active
+	})
+	sort.SliceStable(operators, func(a, b int) bool {
+		return operators[a].Contract < operators[b].Contract
+	})
+	for _, operator := range operators {
+		approvals = append(approvals, repository.CreateOperatorApproval(operator))
+	}
+
+	nfts := make([]entity.Nft, 0)
+	for _, doc := range r.index.GetDocuments(elastic_search.NftIndex.Get()) {
+		var nft entity.Nft
+		if err := json.Unmarshal(doc.Source, &nft); err == nil && nft.Owner == owner && nft.BurnedAt == 0 && nft.Spender != "" {
+			nfts = append(nfts, nft)
+		}
+	}
+	sort.SliceStable(nfts, func(a, b int) bool {
+		if nfts[a].Contract != nfts[b].Contract {
+			return nfts[a].Contract < nfts[b].Contract
+		}
+		return nfts[a].TokenId < nfts[b].TokenId
+	})
+	for _, nft := range nfts {
+		approvals = append(approvals, repository.CreateSpenderApproval(nft))
+	}
+
+	return approvals, nil
+}
+
+func (r operatorRepository) find(match func(operator entity.Operator) bool) []entity.Operator {
+	operators := make([]entity.Operator, 0)
+	for _, doc := range r.index.GetDocuments(elastic_search.OperatorIndex.Get()) {
+		var operator entity.Operator
+		if err := json.Unmarshal(doc.Source, &operator); err == nil && match(operator) {
+			operators = append(operators, operator)
+		}
+	}
+
+	return operators
+}
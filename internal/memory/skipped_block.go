@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+	"sort"
+)
+
+type skippedBlockRepository struct {
+	index Index
+}
+
+func NewSkippedBlockRepository(index Index) repository.SkippedBlockRepository {
+	return skippedBlockRepository{index}
+}
+
+func (r skippedBlockRepository) GetSkippedBlocks(ctx context.Context, size, page int) ([]entity.SkippedBlock, int64, error) {
+	skippedBlocks := make([]entity.SkippedBlock, 0)
+	for _, doc := range r.index.GetDocuments(elastic_search.SkippedBlockIndex.Get()) {
+		var skippedBlock entity.SkippedBlock
+		if err := json.Unmarshal(doc.Source, &skippedBlock); err != nil {
+			zap.L().With(zap.Error(err)).Error("Failed to unmarshall skipped block")
+			continue
+		}
+		skippedBlocks = append(skippedBlocks, skippedBlock)
+	}
+
+	sort.SliceStable(skippedBlocks, func(a, b int) bool {
+		return skippedBlocks[a].BlockNum < skippedBlocks[b].BlockNum
+	})
+
+	from, to := paginate(len(skippedBlocks), size, page)
+
+	return skippedBlocks[from:to], int64(len(skippedBlocks)), nil
+}
+
+func (r skippedBlockRepository) Delete(ctx context.Context, skippedBlock entity.SkippedBlock) error {
+	r.index.DeleteDocuments(elastic_search.SkippedBlockIndex.Get(), skippedBlock.Slug())
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"sort"
+)
+
+type tokenBalanceRepository struct {
+	index Index
+}
+
+func NewTokenBalanceRepository(index Index) repository.TokenBalanceRepository {
+	return tokenBalanceRepository{index}
+}
+
+func (r tokenBalanceRepository) GetBalance(ctx context.Context, contract, holder string) (*entity.TokenBalance, error) {
+	pendingRequest := r.index.GetRequest(elastic_search.TokenBalanceIndex.Get(), entity.CreateTokenBalanceSlug(contract, holder))
+	if pendingRequest != nil {
+		pendingBalance := pendingRequest.Entity.(entity.TokenBalance)
+		return &pendingBalance, nil
+	}
+
+	balances := r.find(func(balance entity.TokenBalance) bool {
+		return balance.Contract == contract && balance.Holder == holder
+	})
+	if len(balances) == 0 {
+		return nil, repository.ErrTokenBalanceNotFound
+	}
+
+	return &balances[0], nil
+}
+
+func (r tokenBalanceRepository) GetHolders(ctx context.Context, contract string, size, page int) ([]entity.TokenBalance, int64, error) {
+	balances := r.find(func(balance entity.TokenBalance) bool {
+		return balance.Contract == contract && balance.Balance != "0"
+	})
+	sort.SliceStable(balances, func(a, b int) bool {
+		return balances[a].Holder < balances[b].Holder
+	})
+
+	return r.findMany(balances, size, page)
+}
+
+func (r tokenBalanceRepository) GetBalancesByHolder(ctx context.Context, holder string, size, page int) ([]entity.TokenBalance, int64, error) {
+	balances := r.find(func(balance entity.TokenBalance) bool {
+		return balance.Holder == holder && balance.Balance != "0"
+	})
+	sort.SliceStable(balances, func(a, b int) bool {
+		return balances[a].Contract < balances[b].Contract
+	})
+
+	return r.findMany(balances, size, page)
+}
+
+func (r tokenBalanceRepository) find(match func(balance entity.TokenBalance) bool) []entity.TokenBalance {
+	balances := make([]entity.TokenBalance, 0)
+	for _, doc := range r.index.GetDocuments(elastic_search.TokenBalanceIndex.Get()) {
+		var balance entity.TokenBalance
+		if err := json.Unmarshal(doc.Source, &balance); err == nil && match(balance) {
+			balances = append(balances, balance)
+		}
+	}
+
+	return balances
+}
+
+func (r tokenBalanceRepository) findMany(balances []entity.TokenBalance, size, page int) ([]entity.TokenBalance, int64, error) {
+	from, to := paginate(len(balances), size, page)
+
+	return balances[from:to], int64(len(balances)), nil
+}
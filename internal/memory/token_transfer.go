@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+	"sort"
+)
+
+type tokenTransferRepository struct {
+	index Index
+}
+
+func NewTokenTransferRepository(index Index) repository.TokenTransferRepository {
+	return tokenTransferRepository{index}
+}
+
+func (r tokenTransferRepository) Exists(ctx context.Context, transfer entity.TokenTransfer) (bool, error) {
+	if r.index.GetRequest(elastic_search.TokenTransferIndex.Get(), transfer.Slug()) != nil {
+		return true, nil
+	}
+
+	for _, doc := range r.index.GetDocuments(elastic_search.TokenTransferIndex.Get()) {
+		if doc.Id == transfer.Slug() {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r tokenTransferRepository) GetTransfersByHolder(ctx context.Context, holder string, size, page int) ([]entity.TokenTransfer, int64, error) {
+	transfers := r.find(func(transfer entity.TokenTransfer) bool {
+		return transfer.From == holder || transfer.To == holder
+	})
+	sort.SliceStable(transfers, func(a, b int) bool {
+		return transfers[a].BlockNum > transfers[b].BlockNum
+	})
+
+	return r.findMany(transfers, size, page)
+}
+
+func (r tokenTransferRepository) GetTransfersToRevert(ctx context.Context, blockNum uint64, after *entity.TokenTransfer, size int) ([]entity.TokenTransfer, error) {
+	transfers := r.find(func(transfer entity.TokenTransfer) bool {
+		return transfer.BlockNum > blockNum && !transfer.Reverted && (after == nil || transferBefore(*after, transfer))
+	})
+	sort.SliceStable(transfers, func(a, b int) bool {
+		return transferBefore(transfers[a], transfers[b])
+	})
+	if len(transfers) > size {
+		transfers = transfers[:size]
+	}
+
+	return transfers, nil
+}
+
+func (r tokenTransferRepository) PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error {
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Purge token transfers after block")
+
+	ids := make([]string, 0)
+	for _, transfer := range r.find(func(transfer entity.TokenTransfer) bool {
+		return transfer.BlockNum > blockNum
+	}) {
+		ids = append(ids, transfer.Slug())
+	}
+	r.index.DeleteDocuments(elastic_search.TokenTransferIndex.Get(), ids...)
+
+	return nil
+}
+
+// find returns the matching transfers in block order
+func (r tokenTransferRepository) find(match func(transfer entity.TokenTransfer) bool) []entity.TokenTransfer {
+	transfers := make([]entity.TokenTransfer, 0)
+	for _, doc := range r.index.GetDocuments(elastic_search.TokenTransferIndex.Get()) {
+		var transfer entity.TokenTransfer
+		if err := json.Unmarshal(doc.Source, &transfer); err == nil && match(transfer) {
+			transfers = append(transfers, transfer)
+		}
+	}
+
+	sort.SliceStable(transfers, func(a, b int) bool {
+		return transfers[a].BlockNum < transfers[b].BlockNum
+	})
+
+	return transfers
+}
+
+// transferBefore orders transfers by block, tx and event
+func transferBefore(a, b entity.TokenTransfer) bool {
+	if a.BlockNum != b.BlockNum {
+		return a.BlockNum < b.BlockNum
+	}
+	if a.TxID != b.TxID {
+		return a.TxID < b.TxID
+	}
+
+	return a.EventIndex < b.EventIndex
+}
+
+func (r tokenTransferRepository) findMany(transfers []entity.TokenTransfer, size, page int) ([]entity.TokenTransfer, int64, error) {
+	from, to := paginate(len(transfers), size, page)
+
+	return transfers[from:to], int64(len(transfers)), nil
+}
@@ -0,0 +1,236 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+	"sort"
+)
+
+type transactionRepository struct {
+	index Index
+}
+
+func NewTransactionRepository(index Index) repository.TransactionRepository {
+	return transactionRepository{index}
+}
+
+func (r transactionRepository) GetBestBlockNum(ctx context.Context) (uint64, error) {
+	txs := r.find(func(tx entity.Transaction) bool {
+		return true
+	})
+	if len(txs) == 0 {
+		zap.L().Info("Best block num not found giving 0")
+		return 0, repository.ErrBestBlockNumFound
+	}
+
+	var blockNum uint64
+	for _, tx := range txs {
+		if tx.BlockNum > blockNum {
+			blockNum = tx.BlockNum
+		}
+	}
+
+	return blockNum, nil
+}
+
+func (r transactionRepository) GetTx(ctx context.Context, txId string) (*entity.Transaction, error) {
+	return r.findOne(r.find(func(tx entity.Transaction) bool {
+		return tx.ID == txId
+	}))
+}
+
+func (r transactionRepository) GetMissingTxs(ctx context.Context, txIds []string) (map[string]struct{}, error) {
+	missingTxIds := map[string]struct{}{}
+	for _, tx := range txIds {
+		missingTxIds[tx] = struct{}{}
+	}
+
+	for _, tx := range r.find(func(tx entity.Transaction) bool {
+		_, found := missingTxIds[tx.ID]
+		return found
+	}) {
+		delete(missingTxIds, tx.ID)
+	}
+
+	return missingTxIds, nil
+}
+
+func (r transactionRepository) GetContractCreationTxs(ctx context.Context, fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error) {
+	return r.findMany(r.find(func(tx entity.Transaction) bool {
+		return !tx.IsFailed && tx.IsContractCreation && tx.BlockNum >= fromBlockNum
+	}), size, page)
+}
+
+func (r transactionRepository) GetContractExecutionTxs(ctx context.Context, fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error) {
+	return r.findMany(r.find(func(tx entity.Transaction) bool {
+		return !tx.IsFailed && tx.IsContractExecution && tx.BlockNum >= fromBlockNum
+	}), size, page)
+}
+
+func (r transactionRepository) GetContractCreationForContract(ctx context.Context, contractAddr string) (*entity.Transaction, error) {
+	txs := r.find(func(tx entity.Transaction) bool {
+		return !tx.IsFailed && tx.IsContractCreation && tx.ContractAddress == contractAddr
+	})
+	sortByBlockNum(txs)
+
+	return r.findOne(txs)
+}
+
+func (r transactionRepository) GetContractExecutionsByContract(ctx context.Context, c entity.Contract, size, page int) ([]entity.Transaction, int64, error) {
+	return r.findMany(r.find(func(tx entity.Transaction) bool {
+		return !tx.IsFailed && tx.IsContractExecution && (tx.ContractAddress == c.Address || emittedBy(tx, c.Address))
+	}), size, page)
+}
+
+func (r transactionRepository) GetContractExecutionsByContractFrom(ctx context.Context, c entity.Contract, fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error) {
+	return r.findMany(r.find(func(tx entity.Transaction) bool {
+		return !tx.IsFailed && tx.IsContractExecution && tx.BlockNum >= fromBlockNum && emittedBy(tx, c.Address)
+	}), size, page)
+}
+
+func (r transactionRepository) GetNftMarketplaceExecutionTxs(ctx context.Context, fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error) {
+	return r.findMany(r.find(func(tx entity.Transaction) bool {
+		if tx.IsFailed || !tx.IsContractExecution || tx.BlockNum < fromBlockNum {
+			return false
+		}
+
+		return emitted(tx,
+			entity.MpZilkroadListingEvent,
+			entity.MpZilkroadDelistingEvent,
+			entity.MpZilkroadSaleEvent,
+			entity.MpArkySaleEvent,
+			entity.MpMintableListingEvent,
+			entity.MpMintableDelistingEvent,
+			entity.MpMintableSaleEvent,
+		) ||
+			(tx.Data.Tag == "ConfigurePrice" && tx.ContractAddress == entity.OkimotoMarketplaceAddress) ||
+			(tx.Data.Tag == "WithdrawalToken" && emitted(tx, entity.MpOkiDelistingEvent)) ||
+			(tx.Data.Tag == "Buy" && emitted(tx, entity.MpOkiSaleEvent))
+	}), size, page)
+}
+
+func (r transactionRepository) GetFailedTxsByContract(ctx context.Context, contractAddr string, size, page int) ([]entity.Transaction, int64, error) {
+	return r.findLatest(r.find(func(tx entity.Transaction) bool {
+		return tx.IsFailed && tx.ContractAddress == contractAddr
+	}), size, page)
+}
+
+func (r transactionRepository) GetFailedTxsBySender(ctx context.Context, senderAddr string, size, page int) ([]entity.Transaction, int64, error) {
+	return r.findLatest(r.find(func(tx entity.Transaction) bool {
+		return tx.IsFailed && tx.SenderAddr == senderAddr
+	}), size, page)
+}
+
+func (r transactionRepository) GetPaymentsByAddress(ctx context.Context, addr string, size, page int) ([]entity.Transaction, int64, error) {
+	return r.findLatest(r.find(func(tx entity.Transaction) bool {
+		return tx.IsPayment && (tx.SenderAddr == addr || tx.RecipientAddr == addr)
+	}), size, page)
+}
+
+func (r transactionRepository) GetTxsAfterBlockNum(ctx context.Context, blockNum uint64, after *entity.Transaction, size int) ([]entity.Transaction, error) {
+	txs := r.find(func(tx entity.Transaction) bool {
+		return tx.BlockNum > blockNum && (after == nil || txBefore(*after, tx))
+	})
+	sort.SliceStable(txs, func(a, b int) bool {
+		return txBefore(txs[a], txs[b])
+	})
+	if len(txs) > size {
+		txs = txs[:size]
+	}
+
+	return txs, nil
+}
+
+func (r transactionRepository) PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error {
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Purge transactions after block")
+
+	ids := make([]string, 0)
+	for _, tx := range r.find(func(tx entity.Transaction) bool {
+		return tx.BlockNum > blockNum
+	}) {
+		ids = append(ids, tx.Slug())
+	}
+	r.index.DeleteDocuments(elastic_search.TransactionIndex.Get(), ids...)
+
+	return nil
+}
+
+func (r transactionRepository) find(match func(tx entity.Transaction) bool) []entity.Transaction {
+	txs := make([]entity.Transaction, 0)
+	for _, doc := range r.index.GetDocuments(elastic_search.TransactionIndex.Get()) {
+		var tx entity.Transaction
+		if err := json.Unmarshal(doc.Source, &tx); err == nil && match(tx) {
+			txs = append(txs, tx)
+		}
+	}
+
+	return txs
+}
+
+func (r transactionRepository) findOne(txs []entity.Transaction) (*entity.Transaction, error) {
+	if len(txs) == 0 {
+		return nil, errors.New("no transaction found")
+	}
+
+	return &txs[0], nil
+}
+
+// findMany returns a page of transactions in block order
+func (r transactionRepository) findMany(txs []entity.Transaction, size, page int) ([]entity.Transaction, int64, error) {
+	sortByBlockNum(txs)
+	from, to := paginate(len(txs), size, page)
+
+	return txs[from:to], int64(len(txs)), nil
+}
+
+// findLatest returns a page of transactions, most recent first
+func (r transactionRepository) findLatest(txs []entity.Transaction, size, page int) ([]entity.Transaction, int64, error) {
+	sort.SliceStable(txs, func(a, b int) bool {
+		return txs[a].BlockNum > txs[b].BlockNum
+	})
+	from, to := paginate(len(txs), size, page)
+
+	return txs[from:to], int64(len(txs)), nil
+}
+
+// txBefore orders transactions by block and id
+func txBefore(a, b entity.Transaction) bool {
+	if a.BlockNum != b.BlockNum {
+		return a.BlockNum < b.BlockNum
+	}
+
+	return a.ID < b.ID
+}
+
+func sortByBlockNum(txs []entity.Transaction) {
+	sort.SliceStable(txs, func(a, b int) bool {
+		return txs[a].BlockNum < txs[b].BlockNum
+	})
+}
+
+func emittedBy(tx entity.Transaction, address string) bool {
+	for _, event := range tx.Receipt.EventLogs {
+		if event.Address == address {
+			return true
+		}
+	}
+
+	return false
+}
+
+func emitted(tx entity.Transaction, eventNames ...entity.Event) bool {
+	for _, event := range tx.Receipt.EventLogs {
+		for _, eventName := range eventNames {
+			if event.EventName == string(eventName) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,19 @@
+package memory
+
+// paginate returns the bounds of a page, pages start at 1 as they do in the repositories
+func paginate(total, size, page int) (int, int) {
+	from := size*page - size
+	if from < 0 {
+		from = 0
+	}
+	if from > total {
+		from = total
+	}
+
+	to := from + size
+	if to > total {
+		to = total
+	}
+
+	return from, to
+}
@@ -1,6 +1,7 @@
 package messenger
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/config"
@@ -13,7 +14,7 @@ import (
 type MessageService interface {
 	CreateQueue(queue Queue) (*string, error)
 	SendMessage(queue Queue, body []byte) error
-	PollMessages(queue Queue, chn chan <- *sqs.Message)
+	PollMessages(ctx context.Context, queue Queue, chn chan <- *sqs.Message)
 	DeleteMessage(queue Queue, msg *sqs.Message) error
 	GetQueueSize(queue Queue) (*int, error)
 }
@@ -99,26 +100,35 @@ func (m Messenger) SendMessage(queue Queue, body []byte) error {
 	return err
 }
 
-func (m Messenger) PollMessages(queue Queue, chn chan <- *sqs.Message) {
+// PollMessages sends messages from the queue to chn until ctx is cancelled
+func (m Messenger) PollMessages(ctx context.Context, queue Queue, chn chan <- *sqs.Message) {
 	queueUrl, err := m.getQueueUrl(queue)
 	if err != nil {
 		return
 	}
 
 	for {
-		output, err := m.sqsClient.ReceiveMessage(&sqs.ReceiveMessageInput{
+		output, err := m.sqsClient.ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{
 			QueueUrl: queueUrl,
 			MaxNumberOfMessages: aws.Int64(10),
 			WaitTimeSeconds:     aws.Int64(15),
 		})
 
+		if ctx.Err() != nil {
+			return
+		}
+
 		if err != nil {
 			zap.L().With(zap.Error(err)).Warn("Failed to fetch message")
 			return
 		}
 
 		for _, message := range output.Messages {
-			chn <- message
+			select {
+			case chn <- message:
+			case <-ctx.Done():
+				return
+			}
 		}
 	}
 }
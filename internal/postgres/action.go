@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+)
+
+type nftActionRepository struct {
+	index Index
+}
+
+func NewNftActionRepository(index Index) repository.NftActionRepository {
+	return nftActionRepository{index}
+}
+
+func (r nftActionRepository) GetNftOwnerBeforeBlockNum(ctx context.Context, nft entity.Nft, blockNum uint64) (string, error) {
+	action, err := r.findOne(findDocs(ctx, r.index.GetDB(), `SELECT doc FROM nft_actions
+		WHERE block_num < $1 AND contract = $2 AND token_id = $3 AND action IN ('mint', 'transfer')
+		ORDER BY block_num DESC LIMIT 1`, blockNum, nft.Contract, nft.TokenId))
+	if err != nil {
+		return "", err
+	}
+
+	return action.To, nil
+}
+
+func (r nftActionRepository) GetNftActions(ctx context.Context, nft entity.Nft, after *entity.NftAction, size int) ([]entity.NftAction, error) {
+	return r.findAfter(ctx, "contract = $1 AND token_id = $2", after, size, nft.Contract, nft.TokenId)
+}
+
+func (r nftActionRepository) GetActionsAfterBlockNum(ctx context.Context, blockNum uint64, after *entity.NftAction, size int) ([]entity.NftAction, error) {
+	return r.findAfter(ctx, "block_num > $1", after, size, blockNum)
+}
+
+func (r nftActionRepository) PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error {
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Purge nft actions after block")
+
+	_, err := r.index.GetDB().ExecContext(ctx, "DELETE FROM nft_actions WHERE block_num > $1", blockNum)
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.Uint64("blockNum", blockNum)).Error("Failed to purge nft actions")
+	}
+
+	return err
+}
+
+// findAfter lists the matching actions in the order they were made, following the action after when given
+func (r nftActionRepository) findAfter(ctx context.Context, where string, after *entity.NftAction, size int, args ...interface{}) ([]entity.NftAction, error) {
+	if after != nil {
+		where = fmt.Sprintf("(%s) AND (block_num, tx_id, action) > ($%d, $%d, $%d)", where, len(args)+1, len(args)+2, len(args)+3)
+		args = append(args, after.BlockNum, after.TxID, string(after.Action))
+	}
+
+	docs, err := findDocs(ctx, r.index.GetDB(), fmt.Sprintf(
+		"SELECT doc FROM nft_actions WHERE %s ORDER BY block_num, tx_id, action LIMIT %d", where, size,
+	), args...)
+	actions, _, err := r.findMany(docs, 0, err)
+
+	return actions, err
+}
+
+func (r nftActionRepository) findOne(docs [][]byte, err error) (*entity.NftAction, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	if len(docs) == 0 {
+		return nil, repository.ErrNftActionNotFound
+	}
+
+	var action entity.NftAction
+	err = json.Unmarshal(docs[0], &action)
+
+	return &action, err
+}
+
+func (r nftActionRepository) findMany(docs [][]byte, total int64, err error) ([]entity.NftAction, int64, error) {
+	actions := make([]entity.NftAction, 0)
+
+	if err != nil {
+		return actions, 0, err
+	}
+
+	for _, doc := range docs {
+		var action entity.NftAction
+		if err := json.Unmarshal(doc, &action); err == nil {
+			actions = append(actions, action)
+		}
+	}
+
+	return actions, total, nil
+}
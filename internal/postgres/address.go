@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"time"
+)
+
+type addressRepository struct {
+	index Index
+}
+
+func NewAddressRepository(index Index) repository.AddressRepository {
+	return addressRepository{index}
+}
+
+func (r addressRepository) GetAddresses(ctx context.Context, addrs []string) (map[string]entity.Address, error) {
+	addresses := make(map[string]entity.Address, len(addrs))
+
+	missing := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		if pendingRequest := r.index.GetRequest(elastic_search.AddressIndex.Get(), entity.CreateAddressSlug(addr)); pendingRequest != nil {
+			addresses[addr] = pendingRequest.Entity.(entity.Address)
+			continue
+		}
+		missing = append(missing, addr)
+	}
+
+	if len(missing) == 0 {
+		return addresses, nil
+	}
+
+	found, err := r.findMany(findDocs(ctx, r.index.GetDB(), "SELECT doc FROM addresses WHERE address = ANY($1)", pq.Array(missing)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, address := range found {
+		addresses[address.Address] = address
+	}
+
+	return addresses, nil
+}
+
+func (r addressRepository) GetAddressesToReconcile(ctx context.Context, before time.Time, size int) ([]entity.Address, error) {
+	return r.findMany(findDocs(ctx, r.index.GetDB(),
+		"SELECT doc FROM addresses WHERE reconciled_at < $1 ORDER BY reconciled_at LIMIT $2",
+		before.UTC().Format(time.RFC3339Nano), size))
+}
+
+func (r addressRepository) findMany(docs [][]byte, err error) ([]entity.Address, error) {
+	addresses := make([]entity.Address, 0)
+
+	if err != nil {
+		return addresses, err
+	}
+
+	for _, doc := range docs {
+		var address entity.Address
+		if err := json.Unmarshal(doc, &address); err != nil {
+			zap.L().With(zap.Error(err)).Error("Failed to unmarshall address")
+			continue
+		}
+		addresses = append(addresses, address)
+	}
+
+	return addresses, nil
+}
@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"context"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+)
+
+type blockRepository struct {
+	index Index
+}
+
+func NewBlockRepository(index Index) repository.BlockRepository {
+	return blockRepository{index}
+}
+
+func (r blockRepository) PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error {
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Purge blocks after block")
+
+	_, err := r.index.GetDB().ExecContext(ctx, `DELETE FROM ds_blocks WHERE block_num >
+		coalesce((SELECT ds_block_num FROM blocks WHERE block_num <= $1 ORDER BY block_num DESC LIMIT 1), -1)`, blockNum)
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.Uint64("blockNum", blockNum)).Error("Failed to purge ds blocks")
+		return err
+	}
+
+	_, err = r.index.GetDB().ExecContext(ctx, "DELETE FROM blocks WHERE block_num > $1", blockNum)
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.Uint64("blockNum", blockNum)).Error("Failed to purge blocks")
+	}
+
+	return err
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+)
+
+type checkpointRepository struct {
+	index Index
+}
+
+func NewCheckpointRepository(index Index) repository.CheckpointRepository {
+	return checkpointRepository{index}
+}
+
+func (r checkpointRepository) GetCheckpoint(ctx context.Context, stage entity.Stage) (*entity.Checkpoint, error) {
+	pendingRequest := r.index.GetRequest(elastic_search.CheckpointIndex.Get(), entity.CreateCheckpointSlug(stage))
+	if pendingRequest != nil {
+		pendingCheckpoint := pendingRequest.Entity.(entity.Checkpoint)
+		return &pendingCheckpoint, nil
+	}
+
+	docs, err := findDocs(ctx, r.index.GetDB(), "SELECT doc FROM checkpoints WHERE stage = $1 LIMIT 1", string(stage))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(docs) == 0 {
+		return nil, repository.ErrCheckpointNotFound
+	}
+
+	var checkpoint entity.Checkpoint
+	if err := json.Unmarshal(docs[0], &checkpoint); err != nil {
+		return nil, err
+	}
+
+	return &checkpoint, nil
+}
+
+func (r checkpointRepository) GetBlockNum(ctx context.Context, stage entity.Stage) (uint64, error) {
+	checkpoint, err := r.GetCheckpoint(ctx, stage)
+	if err != nil {
+		return 0, err
+	}
+
+	return checkpoint.BlockNum, nil
+}
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+)
+
+type contractRepository struct {
+	index Index
+}
+
+func NewContractRepository(index Index) repository.ContractRepository {
+	return contractRepository{index}
+}
+
+func (r contractRepository) GetAllContracts(ctx context.Context, size, page int) ([]entity.Contract, int64, error) {
+	zap.L().With(
+		zap.Int("size", size),
+		zap.Int("page", page),
+	).Info("GetAllContracts")
+
+	return r.findMany(findPage(ctx, r.index.GetDB(), "contracts", "true", "block_num DESC", size, page))
+}
+
+func (r contractRepository) GetAllNftContracts(ctx context.Context, size, page int) ([]entity.Contract, int64, error) {
+	zap.L().With(
+		zap.Int("size", size),
+		zap.Int("page", page),
+	).Info("GetAllNftContracts")
+
+	return r.findMany(findPage(ctx, r.index.GetDB(), "contracts",
+		`doc->'standards' @> '{"ZRC1": true}' OR doc->'standards' @> '{"ZRC6": true}'`, "block_num", size, page))
+}
+
+func (r contractRepository) GetAllZrc2Contracts(ctx context.Context, size, page int) ([]entity.Contract, int64, error) {
+	zap.L().With(
+		zap.Int("size", size),
+		zap.Int("page", page),
+	).Info("GetAllZrc2Contracts")
+
+	return r.findMany(findPage(ctx, r.index.GetDB(), "contracts",
+		`doc->'standards' @> '{"ZRC2": true}'`, "block_num", size, page))
+}
+
+func (r contractRepository) GetContractByAddress(ctx context.Context, contractAddr string) (*entity.Contract, error) {
+	pendingRequest := r.index.GetRequest(elastic_search.ContractIndex.Get(), entity.CreateContractSlug(contractAddr))
+	if pendingRequest != nil {
+		pendingContract := pendingRequest.Entity.(entity.Contract)
+		return &pendingContract, nil
+	}
+
+	return r.findOne(findDocs(ctx, r.index.GetDB(), "SELECT doc FROM contracts WHERE address = $1 LIMIT 1", contractAddr))
+}
+
+func (r contractRepository) GetBestBlockNum(ctx context.Context) (uint64, error) {
+	c, err := r.findOne(findDocs(ctx, r.index.GetDB(), "SELECT doc FROM contracts ORDER BY block_num DESC LIMIT 1"))
+	if err != nil {
+		return 0, err
+	}
+
+	return c.BlockNum, nil
+}
+
+func (r contractRepository) GetContractsAfterBlockNum(ctx context.Context, blockNum uint64, size, page int) ([]entity.Contract, int64, error) {
+	return r.findMany(findPage(ctx, r.index.GetDB(), "contracts", "block_num > $1", "block_num", size, page, blockNum))
+}
+
+// PurgeContract removes the contract, its state and metadata together
+func (r contractRepository) PurgeContract(ctx context.Context, contractAddr string) error {
+	zap.L().With(zap.String("contract", contractAddr)).Info("Purge contract document")
+
+	tx, err := r.index.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range []string{
+		"DELETE FROM contracts WHERE address = $1",
+		"DELETE FROM contract_states WHERE address = $1",
+		"DELETE FROM contract_metadata WHERE contract = $1",
+	} {
+		if _, err := tx.ExecContext(ctx, query, contractAddr); err != nil {
+			_ = tx.Rollback()
+			zap.L().With(zap.Error(err), zap.String("contract", contractAddr)).Error("Failed to purge contract document")
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r contractRepository) findOne(docs [][]byte, err error) (*entity.Contract, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	if len(docs) == 0 {
+		return nil, repository.ErrContractNotFound
+	}
+
+	var contract entity.Contract
+	err = json.Unmarshal(docs[0], &contract)
+
+	return &contract, err
+}
+
+func (r contractRepository) findMany(docs [][]byte, total int64, err error) ([]entity.Contract, int64, error) {
+	contracts := make([]entity.Contract, 0)
+
+	if err != nil {
+		return contracts, 0, err
+	}
+
+	for _, doc := range docs {
+		var contract entity.Contract
+		if err := json.Unmarshal(doc, &contract); err == nil {
+			contracts = append(contracts, contract)
+		}
+	}
+
+	return contracts, total, nil
+}
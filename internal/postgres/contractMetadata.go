@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+)
+
+type contractMetadataRepository struct {
+	index Index
+}
+
+func NewContractMetadataRepository(index Index) repository.ContractMetadataRepository {
+	return contractMetadataRepository{index}
+}
+
+func (r contractMetadataRepository) GetMetadataByAddress(ctx context.Context, contractAddr string) (*entity.ContractMetadata, error) {
+	docs, err := findDocs(ctx, r.index.GetDB(), "SELECT doc FROM contract_metadata WHERE lower(contract) = lower($1) LIMIT 1", contractAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(docs) == 0 {
+		return nil, repository.ErrContractMetadataNotFound
+	}
+
+	var md entity.ContractMetadata
+	err = json.Unmarshal(docs[0], &md)
+
+	return &md, err
+}
@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"strconv"
+)
+
+type contractStateRepository struct {
+	index Index
+}
+
+func NewContractStateRepository(index Index) repository.ContractStateRepository {
+	return contractStateRepository{index}
+}
+
+func (r contractStateRepository) GetStateByAddress(ctx context.Context, contractAddr string) (*entity.ContractState, error) {
+	pendingRequest := r.index.GetRequest(elastic_search.ContractStateIndex.Get(), entity.CreateStateSlug(contractAddr))
+	if pendingRequest != nil {
+		pendingState := pendingRequest.Entity.(entity.ContractState)
+		return &pendingState, nil
+	}
+
+	docs, err := findDocs(ctx, r.index.GetDB(), "SELECT doc FROM contract_states WHERE address = $1 LIMIT 1", contractAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(docs) == 0 {
+		return nil, repository.ErrContractStateNotFound
+	}
+
+	var state entity.ContractState
+	err = json.Unmarshal(docs[0], &state)
+
+	return &state, err
+}
+
+func (r contractStateRepository) GetRoyaltyFeeBps(ctx context.Context, contractAddr string) (uint, error) {
+	state, err := r.GetStateByAddress(ctx, contractAddr)
+	if err != nil {
+		return 0, err
+	}
+
+	royaltyFeeBps, exists := state.GetElement("royalty_fee_bps")
+	if !exists {
+		return 0, nil
+	}
+
+	fee, err := strconv.Atoi(royaltyFeeBps)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(fee), nil
+}
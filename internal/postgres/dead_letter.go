@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+)
+
+type deadLetterRepository struct {
+	index Index
+}
+
+func NewDeadLetterRepository(index Index) repository.DeadLetterRepository {
+	return deadLetterRepository{index}
+}
+
+func (r deadLetterRepository) GetDeadLetter(ctx context.Context, id string) (*entity.DeadLetter, error) {
+	docs, err := findDocs(ctx, r.index.GetDB(), "SELECT doc FROM dead_letters WHERE id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(docs) == 0 {
+		return nil, repository.ErrDeadLetterNotFound
+	}
+
+	var deadLetter entity.DeadLetter
+	if err := json.Unmarshal(docs[0], &deadLetter); err != nil {
+		return nil, err
+	}
+
+	return &deadLetter, nil
+}
+
+func (r deadLetterRepository) GetDeadLetters(ctx context.Context, size, page int) ([]entity.DeadLetter, int64, error) {
+	deadLetters := make([]entity.DeadLetter, 0)
+
+	docs, total, err := findPage(ctx, r.index.GetDB(), "dead_letters", "true", "created_at DESC", size, page)
+	if err != nil {
+		return deadLetters, 0, err
+	}
+
+	for _, doc := range docs {
+		var deadLetter entity.DeadLetter
+		if err := json.Unmarshal(doc, &deadLetter); err != nil {
+			zap.L().With(zap.Error(err)).Error("Failed to unmarshall dead letter")
+			continue
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+
+	return deadLetters, total, nil
+}
+
+// Replay writes the dead letter's document back to its original table and removes the dead letter in the same transaction
+func (r deadLetterRepository) Replay(ctx context.Context, deadLetter entity.DeadLetter) error {
+	table, err := getTable(deadLetter.Index)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.index.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := upsert(tx, table, deadLetter.DocId, deadLetter.Document, elastic_search.RequestType(deadLetter.RequestType)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM dead_letters WHERE id = $1", deadLetter.Slug()); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r deadLetterRepository) Delete(ctx context.Context, deadLetter entity.DeadLetter) error {
+	_, err := r.index.GetDB().ExecContext(ctx, "DELETE FROM dead_letters WHERE id = $1", deadLetter.Slug())
+
+	return err
+}
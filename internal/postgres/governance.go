@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+)
+
+type governanceRepository struct {
+	index Index
+}
+
+func NewGovernanceRepository(index Index) repository.GovernanceRepository {
+	return governanceRepository{index}
+}
+
+func (r governanceRepository) GetHistory(ctx context.Context, contract string, size, page int) ([]entity.GovernanceEvent, int64, error) {
+	return r.findMany(findPage(ctx, r.index.GetDB(), "governance_events",
+		"contract = $1", "block_num DESC, event_index DESC", size, page, contract))
+}
+
+func (r governanceRepository) GetLatestEvent(ctx context.Context, contract string, action entity.GovernanceAction, blockNum uint64) (*entity.GovernanceEvent, error) {
+	pending := repository.LatestPendingGovernanceEvent(r.index, contract, action, blockNum)
+
+	events, _, err := r.findMany(findPage(ctx, r.index.GetDB(), "governance_events",
+		"contract = $1 AND action = $2 AND block_num <= $3", "block_num DESC, event_index DESC", 1, 1, contract, string(action), blockNum))
+	if err != nil {
+		return nil, err
+	}
+
+	var stored *entity.GovernanceEvent
+	if len(events) != 0 {
+		stored = &events[0]
+	}
+
+	return repository.LaterGovernanceEvent(stored, pending)
+}
+
+func (r governanceRepository) PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error {
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Purge governance events after block")
+
+	_, err := r.index.GetDB().ExecContext(ctx, "DELETE FROM governance_events WHERE block_num > $1", blockNum)
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.Uint64("blockNum", blockNum)).Error("Failed to purge governance events")
+	}
+
+	return err
+}
+
+func (r governanceRepository) findMany(docs [][]byte, total int64, err error) ([]entity.GovernanceEvent, int64, error) {
+	events := make([]entity.GovernanceEvent, 0)
+
+	if err != nil {
+		return events, 0, err
+	}
+
+	for _, doc := range docs {
+		var event entity.GovernanceEvent
+		if err := json.Unmarshal(doc, &event); err == nil {
+			events = append(events, event)
+		}
+	}
+
+	return events, total, nil
+}
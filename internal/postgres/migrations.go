@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"fmt"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/config"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const SqlMigration elastic_search.MigrationType = "sql"
+
+type schemaMigration struct {
+	elastic_search.Migration
+	statements string
+}
+
+func (i index) GetMigrations() ([]elastic_search.Migration, error) {
+	schemaMigrations, err := i.getMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]elastic_search.Migration, 0, len(schemaMigrations))
+	for _, migration := range schemaMigrations {
+		migrations = append(migrations, migration.Migration)
+	}
+
+	return migrations, nil
+}
+
+// Migrate applies the pending schema migrations in order, each in its own transaction
+func (i index) Migrate() error {
+	migrations, err := i.getMigrations()
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		if migration.AppliedAt != nil {
+			continue
+		}
+
+		zap.L().With(zap.Int("version", migration.Version), zap.String("name", migration.Name)).
+			Info("Postgres: Applying migration")
+
+		tx, err := i.db.Begin()
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(migration.statements); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		_, err = tx.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)",
+			migration.Version, migration.Name, time.Now())
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i index) getMigrations() ([]schemaMigration, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = i.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    integer PRIMARY KEY,
+		name       text NOT NULL,
+		applied_at timestamptz NOT NULL
+	)`)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := i.db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for idx := range migrations {
+		if appliedAt, found := applied[migrations[idx].Version]; found {
+			migrations[idx].AppliedAt = &appliedAt
+		}
+	}
+
+	return migrations, nil
+}
+
+func loadMigrations() ([]schemaMigration, error) {
+	migrations := make([]schemaMigration, 0)
+
+	dir := config.Get().Storage.Postgres.MigrationDir
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".sql" {
+			continue
+		}
+
+		name := strings.TrimSuffix(f.Name(), filepath.Ext(f.Name()))
+		parts := strings.SplitN(name, "_", 2)
+		version, err := strconv.Atoi(parts[0])
+		if err != nil || len(parts) != 2 {
+			return nil, fmt.Errorf("%w: %s is not named <version>_<name>.sql", elastic_search.ErrInvalidMigration, f.Name())
+		}
+
+		b, err := ioutil.ReadFile(fmt.Sprintf("%s/%s", dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, schemaMigration{
+			Migration:  elastic_search.Migration{Version: version, Name: parts[1], Type: SqlMigration},
+			statements: string(b),
+		})
+	}
+
+	sort.Slice(migrations, func(a, b int) bool {
+		return migrations[a].Version < migrations[b].Version
+	})
+
+	for idx := 1; idx < len(migrations); idx++ {
+		if migrations[idx].Version == migrations[idx-1].Version {
+			return nil, fmt.Errorf("%w: version %d is used twice", elastic_search.ErrInvalidMigration, migrations[idx].Version)
+		}
+	}
+
+	return migrations, nil
+}
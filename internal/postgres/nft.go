@@ -0,0 +1,215 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+type nftRepository struct {
+	index Index
+}
+
+func NewNftRepository(index Index) repository.NftRepository {
+	return nftRepository{index}
+}
+
+func (r nftRepository) Exists(ctx context.Context, contract string, tokenId uint64) bool {
+	var exists bool
+	err := r.index.GetDB().
+		QueryRow("SELECT exists(SELECT 1 FROM nfts WHERE contract = $1 AND token_id = $2)", contract, tokenId).
+		Scan(&exists)
+	if err != nil {
+		return false
+	}
+
+	return exists
+}
+
+func (r nftRepository) GetNft(ctx context.Context, contract string, tokenId uint64) (*entity.Nft, error) {
+	pendingRequest := r.index.GetRequest(elastic_search.NftIndex.Get(), entity.CreateNftSlug(tokenId, contract))
+	if pendingRequest != nil {
+		pendingNft := pendingRequest.Entity.(entity.Nft)
+		return &pendingNft, nil
+	}
+
+	return r.findOne(findDocs(ctx, r.index.GetDB(),
+		"SELECT doc FROM nfts WHERE lower(contract) = lower($1) AND token_id = $2 LIMIT 1", contract, tokenId))
+}
+
+// FindNft is GetNft, which does not retry
+func (r nftRepository) FindNft(ctx context.Context, contract string, tokenId uint64) (*entity.Nft, error) {
+	return r.GetNft(ctx, contract, tokenId)
+}
+
+func (r nftRepository) GetNfts(ctx context.Context, contract string, size, page int) ([]entity.Nft, int64, error) {
+	return r.findMany(findPage(ctx, r.index.GetDB(), "nfts", "contract = $1", "token_id", size, page, contract))
+}
+
+func (r nftRepository) GetBestTokenId(ctx context.Context, contractAddr string, blockNum uint64) (uint64, error) {
+	nft, err := r.findOne(findDocs(ctx, r.index.GetDB(),
+		"SELECT doc FROM nfts WHERE contract = $1 AND block_num < $2 ORDER BY token_id DESC LIMIT 1", contractAddr, blockNum))
+	if err != nil {
+		if errors.Is(repository.ErrNftNotFound, err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	return nft.TokenId, nil
+}
+
+func (r nftRepository) GetNftStats(ctx context.Context, contract string) (entity.NftStats, error) {
+	pending := repository.PendingNfts(r.index, contract)
+	slugs := pq.Array(pendingSlugs(pending))
+
+	var supply, burned int64
+	err := r.index.GetDB().QueryRowContext(ctx,
+		"SELECT count(*) FILTER (WHERE (doc->>'burnedAt')::bigint = 0), count(*) FILTER (WHERE (doc->>'burnedAt')::bigint <> 0) "+
+			"FROM nfts WHERE contract = $1 AND NOT id = ANY($2)",
+		contract, slugs).
+		Scan(&supply, &burned)
+	if err != nil {
+		return entity.NftStats{}, err
+	}
+
+	rows, err := r.index.GetDB().QueryContext(ctx,
+		"SELECT DISTINCT owner FROM nfts WHERE contract = $1 AND (doc->>'burnedAt')::bigint = 0 AND NOT id = ANY($2)",
+		contract, slugs)
+	if err != nil {
+		return entity.NftStats{}, err
+	}
+	defer rows.Close()
+
+	owners := make(map[string]bool)
+	for rows.Next() {
+		var owner string
+		if err := rows.Scan(&owner); err != nil {
+			return entity.NftStats{}, err
+		}
+		owners[owner] = true
+	}
+	if err := rows.Err(); err != nil {
+		return entity.NftStats{}, err
+	}
+
+	return repository.CountNftStats(supply, burned, owners, pending), nil
+}
+
+func pendingSlugs(pending map[string]entity.Nft) []string {
+	slugs := make([]string, 0, len(pending))
+	for slug := range pending {
+		slugs = append(slugs, slug)
+	}
+
+	return slugs
+}
+
+func (r nftRepository) GetAllNfts(ctx context.Context, size, page int) ([]entity.Nft, int64, error) {
+	return r.findMany(findPage(ctx, r.index.GetDB(), "nfts", "true", "block_num DESC", size, page))
+}
+
+func (r nftRepository) GetAllZrc1Nfts(ctx context.Context, size, page int) ([]entity.Nft, int64, error) {
+	return r.findMany(findPage(ctx, r.index.GetDB(), "nfts", "doc @> '{\"zrc1\": true}'", "token_id", size, page))
+}
+
+func (r nftRepository) GetAllZrc6Nfts(ctx context.Context, size, page int) ([]entity.Nft, int64, error) {
+	return r.findMany(findPage(ctx, r.index.GetDB(), "nfts", "doc @> '{\"zrc6\": true}'", "token_id", size, page))
+}
+
+func (r nftRepository) GetMetadata(ctx context.Context, size, page int, status entity.MetadataStatus) ([]entity.Nft, int64, error) {
+	return r.findMany(findPage(ctx, r.index.GetDB(), "nfts",
+		"doc->'metadata'->>'status' = $1", "(doc->'metadata'->>'attempts')::integer", size, page, string(status)))
+}
+
+func (r nftRepository) GetIpfsMetadata(ctx context.Context, size, page int) ([]entity.Nft, int64, error) {
+	return r.findMany(findPage(ctx, r.index.GetDB(), "nfts", "doc->'metadata' @> '{\"ipfs\": true}'", "token_id", size, page))
+}
+
+func (r nftRepository) ResetMetadata(ctx context.Context, nft entity.Nft) error {
+	_, err := r.index.GetDB().ExecContext(
+		ctx,
+		"UPDATE nfts SET doc = doc #- '{metadata,properties}' WHERE contract = $1 AND token_id = $2",
+		nft.Contract, nft.TokenId)
+
+	return err
+}
+
+func (r nftRepository) GetBestBlockNum(ctx context.Context) (uint64, error) {
+	nft, err := r.findOne(findDocs(ctx, r.index.GetDB(), "SELECT doc FROM nfts ORDER BY block_num DESC LIMIT 1"))
+	if err != nil {
+		return 0, err
+	}
+
+	return nft.BlockNum, nil
+}
+
+func (r nftRepository) PurgeActions(ctx context.Context, contractAddr string) error {
+	zap.L().With(zap.String("contract", contractAddr)).Info("Purge actions for contract")
+
+	_, err := r.index.GetDB().ExecContext(ctx, "DELETE FROM nft_actions WHERE contract = $1", contractAddr)
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.String("contract", contractAddr)).Error("Failed to purge nft actions")
+	}
+
+	return err
+}
+
+func (r nftRepository) PurgeContract(ctx context.Context, contractAddr string) error {
+	zap.L().With(zap.String("contract", contractAddr)).Info("Purge contract")
+
+	_, err := r.index.GetDB().ExecContext(ctx, "DELETE FROM nfts WHERE contract = $1", contractAddr)
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.String("contract", contractAddr)).Error("Failed to purge contract")
+	}
+
+	return err
+}
+
+func (r nftRepository) PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error {
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Purge nfts minted after block")
+
+	_, err := r.index.GetDB().ExecContext(ctx, "DELETE FROM nfts WHERE block_num > $1", blockNum)
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.Uint64("blockNum", blockNum)).Error("Failed to purge nfts")
+	}
+
+	return err
+}
+
+func (r nftRepository) findOne(docs [][]byte, err error) (*entity.Nft, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	if len(docs) == 0 {
+		return nil, repository.ErrNftNotFound
+	}
+
+	var nft entity.Nft
+	err = json.Unmarshal(docs[0], &nft)
+
+	return &nft, err
+}
+
+func (r nftRepository) findMany(docs [][]byte, total int64, err error) ([]entity.Nft, int64, error) {
+	nfts := make([]entity.Nft, 0)
+
+	if err != nil {
+		return nfts, 0, err
+	}
+
+	for _, doc := range docs {
+		var nft entity.Nft
+		if err := json.Unmarshal(doc, &nft); err == nil {
+			nfts = append(nfts, nft)
+		}
+	}
+
+	return nfts, total, nil
+}
@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+)
+
+type nftDiscrepancyRepository struct {
+	index Index
+}
+
+func NewNftDiscrepancyRepository(index Index) repository.NftDiscrepancyRepository {
+	return nftDiscrepancyRepository{index}
+}
+
+func (r nftDiscrepancyRepository) GetDiscrepancies(ctx context.Context, contract string, status entity.DiscrepancyStatus, size, page int) ([]entity.NftDiscrepancy, int64, error) {
+	if status == "" {
+		return r.findMany(findPage(ctx, r.index.GetDB(), "nft_discrepancies",
+			"contract = $1", "token_id ASC", size, page, contract))
+	}
+
+	return r.findMany(findPage(ctx, r.index.GetDB(), "nft_discrepancies",
+		"contract = $1 AND status = $2", "token_id ASC", size, page, contract, string(status)))
+}
+
+func (r nftDiscrepancyRepository) findMany(docs [][]byte, total int64, err error) ([]entity.NftDiscrepancy, int64, error) {
+	discrepancies := make([]entity.NftDiscrepancy, 0)
+
+	if err != nil {
+		return discrepancies, 0, err
+	}
+
+	for _, doc := range docs {
+		var discrepancy entity.NftDiscrepancy
+		if err := json.Unmarshal(doc, &discrepancy); err == nil {
+			discrepancies = append(discrepancies, discrepancy)
+		}
+	}
+
+	return discrepancies, total, nil
+}
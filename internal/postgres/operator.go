@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+)
+
+type operatorRepository struct {
+	index Index
+}
+
+func NewOperatorRepository(index Index) repository.OperatorRepository {
+	return operatorRepository{index}
+}
+
+func (r operatorRepository) GetOperator(ctx context.Context, contract, owner, operator string) (*entity.Operator, error) {
+	slug := entity.CreateOperatorSlug(contract, owner, operator)
+	if pendingRequest := r.index.GetRequest(elastic_search.OperatorIndex.Get(), slug); pendingRequest != nil {
+		pendingOperator := pendingRequest.Entity.(entity.Operator)
+		return &pendingOperator, nil
+	}
+
+	operators, err := r.findMany(findDocs(ctx, r.index.GetDB(), "SELECT doc FROM operators WHERE id = $1", slug))
+	if err != nil {
+		return nil, err
+	}
+	if len(operators) == 0 {
+		return nil, repository.ErrOperatorNotFound
+	}
+
+	return &operators[0], nil
+}
+
+// GetOperatorsAfterBlockNum pages by id, the slug of the grant
+func (r operatorRepository) GetOperatorsAfterBlockNum(ctx context.Context, blockNum uint64, after *entity.Operator, size int) ([]entity.Operator, error) {
+	afterId := ""
+	if after != nil {
+		afterId = after.Slug()
+	}
+
+	return r.findMany(findDocs(ctx, r.index.GetDB(), fmt.Sprintf(
+		"SELECT doc FROM operators WHERE (doc->>'blockNum')::bigint > $1 AND id > $2 ORDER BY id LIMIT %d", size,
+	), blockNum, afterId))
+}
+
+func (r operatorRepository) PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error {
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Purge operators after block")
+
+	_, err := r.index.GetDB().ExecContext(ctx, "DELETE FROM operators WHERE (doc->>'blockNum')::bigint > $1", blockNum)
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.Uint64("blockNum", blockNum)).Error("Failed to purge operators")
+	}
+
+	return err
+}
+
+func (r operatorRepository) GetApprovals(ctx context.Context, owner string) ([]entity.Approval, error) {
+	docs, _, err := findPage(ctx, r.index.GetDB(), "operators", "owner = $1 AND active", "contract", repository.MaxApprovals, 1, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	operators, err := r.findMany(docs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	approvals := make([]entity.Approval, 0)
+	for _, operator := range operators {
+		approvals = append(approvals, repository.CreateOperatorApproval(operator))
+	}
+
+	docs, _, err = findPage(ctx, r.index.GetDB(), "nfts",
+		"owner = $1 AND (doc->>'burnedAt')::bigint = 0 AND coalesce(doc->>'spender', '') <> ''", "contract, token_id",
+		repository.MaxApprovals, 1, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, doc := range docs {
+		var nft entity.Nft
+		if err := json.Unmarshal(doc, &nft); err != nil {
+			zap.L().With(zap.Error(err)).Error("Failed to unmarshall nft")
+			continue
+		}
+		approvals = append(approvals, repository.CreateSpenderApproval(nft))
+	}
+
+	return approvals, nil
+}
+
+func (r operatorRepository) findMany(docs [][]byte, err error) ([]entity.Operator, error) {
+	operators := make([]entity.Operator, 0)
+
+	if err != nil {
+		return operators, err
+	}
+
+	for _, doc := range docs {
+		var operator entity.Operator
+		if err := json.Unmarshal(doc, &operator); err != nil {
+			zap.L().With(zap.Error(err)).Error("Failed to unmarshall operator")
+			continue
+		}
+		operators = append(operators, operator)
+	}
+
+	return operators, nil
+}
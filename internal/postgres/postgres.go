@@ -0,0 +1,216 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/config"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnknownTable = errors.New("no table for index")
+)
+
+// Index stores the documents buffered by elastic_search.Requests in PostgreSQL, one table per index
+type Index interface {
+	elastic_search.Index
+	GetDB() *sql.DB
+}
+
+type index struct {
+	*elastic_search.Requests
+	db        *sql.DB
+	persistMu *sync.Mutex
+}
+
+var tables = map[elastic_search.Indices]string{
+	elastic_search.BlockIndex:            "blocks",
+	elastic_search.DsBlockIndex:          "ds_blocks",
+	elastic_search.TransactionIndex:      "transactions",
+	elastic_search.ContractIndex:         "contracts",
+	elastic_search.ContractMetadataIndex: "contract_metadata",
+	elastic_search.ContractStateIndex:    "contract_states",
+	elastic_search.NftIndex:              "nfts",
+	elastic_search.NftActionIndex:        "nft_actions",
+	elastic_search.CheckpointIndex:       "checkpoints",
+	elastic_search.DeadLetterIndex:       "dead_letters",
+	elastic_search.SkippedBlockIndex:     "skipped_blocks",
+	elastic_search.AddressIndex:          "addresses",
+	elastic_search.TokenTransferIndex:    "token_transfers",
+	elastic_search.TokenBalanceIndex:     "token_balances",
+	elastic_search.OperatorIndex:         "operators",
+	elastic_search.GovernanceIndex:       "governance_events",
+	elastic_search.NftDiscrepancyIndex:   "nft_discrepancies",
+}
+
+const saveAttempts int = 3
+
+func New(db *sql.DB) (Index, error) {
+	bufferSize := config.Get().ElasticSearch.BufferSizeMb * 1024 * 1024
+
+	i := index{nil, db, &sync.Mutex{}}
+	i.Requests = elastic_search.NewRequests(bufferSize, func() int { return i.Persist() })
+
+	return i, db.Ping()
+}
+
+func (i index) GetDB() *sql.DB {
+	return i.db
+}
+
+func (i index) InstallMappings() {
+	zap.L().Info("Postgres: Install schema")
+
+	if err := i.Migrate(); err != nil {
+		zap.L().With(zap.Error(err)).Fatal("Postgres: Failed to apply migrations")
+	}
+}
+
+// PromoteIndices has nothing to swap, a reindex writes over the existing rows
+func (i index) PromoteIndices() {}
+
+func (i index) Save(index string, entity entity.Entity) {
+	i.save(elastic_search.Request{Index: index, Entity: entity, Type: elastic_search.IndexRequest})
+}
+
+func (i index) save(req elastic_search.Request) {
+	var err error
+	for attempt := 1; attempt <= saveAttempts; attempt++ {
+		if err = write(i.db, req); err == nil {
+			return
+		}
+
+		zap.L().With(zap.Error(err), zap.String("index", req.Index), zap.String("slug", req.Entity.Slug())).
+			Error("Postgres: Failed to save entity")
+		time.Sleep(1 * time.Second)
+	}
+
+	i.deadLetter(req, "save_failed", err.Error())
+}
+
+func (i index) deadLetter(req elastic_search.Request, errorType, reason string) {
+	if req.Index == elastic_search.DeadLetterIndex.Get() {
+		zap.L().With(zap.String("slug", req.Entity.Slug()), zap.String("reason", reason)).
+			Fatal("Postgres: Failed to save dead letter")
+	}
+
+	deadLetter, err := elastic_search.CreateDeadLetter(req, errorType, reason)
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.String("index", req.Index), zap.String("slug", req.Entity.Slug())).
+			Fatal("Postgres: Failed to create dead letter")
+	}
+
+	zap.L().With(
+		zap.String("index", req.Index),
+		zap.String("slug", req.Entity.Slug()),
+		zap.String("errorType", errorType),
+		zap.String("reason", reason),
+	).Warn("Postgres: Request moved to dead letters")
+
+	i.save(elastic_search.Request{
+		Index:  elastic_search.DeadLetterIndex.Get(),
+		Entity: deadLetter,
+		Type:   elastic_search.IndexRequest,
+		Action: elastic_search.DeadLetter,
+	})
+}
+
+// Persist writes the pending requests in a single transaction, checkpoints last. If the transaction fails
+// each request is written on its own, so only the requests that fail are moved to the dead letters.
+func (i index) Persist() int {
+	i.persistMu.Lock()
+	defer i.persistMu.Unlock()
+
+	pending, sequences := i.Pending()
+
+	requests := make([]elastic_search.Request, 0, len(pending))
+	checkpoints := make([]elastic_search.Request, 0)
+	for _, r := range pending {
+		if r.Action == elastic_search.Checkpoint {
+			checkpoints = append(checkpoints, r)
+		} else {
+			requests = append(requests, r)
+		}
+	}
+	requests = append(requests, checkpoints...)
+
+	if err := i.persist(requests); err != nil {
+		zap.L().With(zap.Error(err), zap.Int("actions", len(requests))).Warn("Postgres: Failed to persist requests, writing individually")
+		for _, r := range requests {
+			if err := write(i.db, r); err != nil {
+				i.deadLetter(r, "write_failed", err.Error())
+			}
+		}
+	}
+
+	i.Release(pending, sequences)
+	elastic_search.EmitEvents(pending)
+
+	return len(requests)
+}
+
+func (i index) persist(requests []elastic_search.Request) error {
+	if len(requests) == 0 {
+		return nil
+	}
+
+	tx, err := i.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, r := range requests {
+		if err := write(tx, r); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+func write(exec execer, req elastic_search.Request) error {
+	table, err := getTable(req.Index)
+	if err != nil {
+		return err
+	}
+
+	doc, err := json.Marshal(req.Entity)
+	if err != nil {
+		return err
+	}
+
+	return upsert(exec, table, req.Entity.Slug(), doc, req.Type)
+}
+
+// upsert replaces the document for an index request, and merges into it for an update request
+func upsert(exec execer, table, id string, doc []byte, reqType elastic_search.RequestType) error {
+	query := fmt.Sprintf("INSERT INTO %s (id, doc) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET doc = excluded.doc", table)
+	if reqType == elastic_search.UpdateRequest {
+		query = fmt.Sprintf("INSERT INTO %[1]s (id, doc) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET doc = %[1]s.doc || excluded.doc", table)
+	}
+
+	_, err := exec.Exec(query, id, string(doc))
+
+	return err
+}
+
+func getTable(index string) (string, error) {
+	for idx, table := range tables {
+		if idx.Get() == index {
+			return table, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrUnknownTable, index)
+}
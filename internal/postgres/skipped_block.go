@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+)
+
+type skippedBlockRepository struct {
+	index Index
+}
+
+func NewSkippedBlockRepository(index Index) repository.SkippedBlockRepository {
+	return skippedBlockRepository{index}
+}
+
+func (r skippedBlockRepository) GetSkippedBlocks(ctx context.Context, size, page int) ([]entity.SkippedBlock, int64, error) {
+	skippedBlocks := make([]entity.SkippedBlock, 0)
+
+	docs, total, err := findPage(ctx, r.index.GetDB(), "skipped_blocks", "true", "block_num ASC", size, page)
+	if err != nil {
+		return skippedBlocks, 0, err
+	}
+
+	for _, doc := range docs {
+		var skippedBlock entity.SkippedBlock
+		if err := json.Unmarshal(doc, &skippedBlock); err != nil {
+			zap.L().With(zap.Error(err)).Error("Failed to unmarshall skipped block")
+			continue
+		}
+		skippedBlocks = append(skippedBlocks, skippedBlock)
+	}
+
+	return skippedBlocks, total, nil
+}
+
+func (r skippedBlockRepository) Delete(ctx context.Context, skippedBlock entity.SkippedBlock) error {
+	_, err := r.index.GetDB().ExecContext(ctx, "DELETE FROM skipped_blocks WHERE id = $1", skippedBlock.Slug())
+
+	return err
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+)
+
+type tokenBalanceRepository struct {
+	index Index
+}
+
+func NewTokenBalanceRepository(index Index) repository.TokenBalanceRepository {
+	return tokenBalanceRepository{index}
+}
+
+func (r tokenBalanceRepository) GetBalance(ctx context.Context, contract, holder string) (*entity.TokenBalance, error) {
+	pendingRequest := r.index.GetRequest(elastic_search.TokenBalanceIndex.Get(), entity.CreateTokenBalanceSlug(contract, holder))
+	if pendingRequest != nil {
+		pendingBalance := pendingRequest.Entity.(entity.TokenBalance)
+		return &pendingBalance, nil
+	}
+
+	balances, _, err := r.findMany(findPage(ctx, r.index.GetDB(), "token_balances",
+		"contract = $1 AND holder = $2", "holder", 1, 1, contract, holder))
+	if err != nil {
+		return nil, err
+	}
+	if len(balances) == 0 {
+		return nil, repository.ErrTokenBalanceNotFound
+	}
+
+	return &balances[0], nil
+}
+
+func (r tokenBalanceRepository) GetHolders(ctx context.Context, contract string, size, page int) ([]entity.TokenBalance, int64, error) {
+	return r.findMany(findPage(ctx, r.index.GetDB(), "token_balances",
+		"contract = $1 AND balance <> '0'", "holder", size, page, contract))
+}
+
+func (r tokenBalanceRepository) GetBalancesByHolder(ctx context.Context, holder string, size, page int) ([]entity.TokenBalance, int64, error) {
+	return r.findMany(findPage(ctx, r.index.GetDB(), "token_balances",
+		"holder = $1 AND balance <> '0'", "contract", size, page, holder))
+}
+
+func (r tokenBalanceRepository) findMany(docs [][]byte, total int64, err error) ([]entity.TokenBalance, int64, error) {
+	balances := make([]entity.TokenBalance, 0)
+
+	if err != nil {
+		return balances, 0, err
+	}
+
+	for _, doc := range docs {
+		var balance entity.TokenBalance
+		if err := json.Unmarshal(doc, &balance); err == nil {
+			balances = append(balances, balance)
+		}
+	}
+
+	return balances, total, nil
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+)
+
+type tokenTransferRepository struct {
+	index Index
+}
+
+func NewTokenTransferRepository(index Index) repository.TokenTransferRepository {
+	return tokenTransferRepository{index}
+}
+
+func (r tokenTransferRepository) Exists(ctx context.Context, transfer entity.TokenTransfer) (bool, error) {
+	if r.index.GetRequest(elastic_search.TokenTransferIndex.Get(), transfer.Slug()) != nil {
+		return true, nil
+	}
+
+	var exists bool
+	err := r.index.GetDB().QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM token_transfers WHERE id = $1)", transfer.Slug()).Scan(&exists)
+
+	return exists, err
+}
+
+func (r tokenTransferRepository) GetTransfersByHolder(ctx context.Context, holder string, size, page int) ([]entity.TokenTransfer, int64, error) {
+	return r.findMany(findPage(ctx, r.index.GetDB(), "token_transfers",
+		"from_addr = $1 OR to_addr = $1", "block_num DESC", size, page, holder))
+}
+
+func (r tokenTransferRepository) GetTransfersToRevert(ctx context.Context, blockNum uint64, after *entity.TokenTransfer, size int) ([]entity.TokenTransfer, error) {
+	where := "block_num > $1 AND coalesce((doc->>'reverted')::boolean, false) = false"
+	args := []interface{}{blockNum}
+	if after != nil {
+		where += " AND (block_num, doc->>'txId', (doc->>'eventIndex')::int) > ($2, $3, $4)"
+		args = append(args, after.BlockNum, after.TxID, after.EventIndex)
+	}
+
+	docs, err := findDocs(ctx, r.index.GetDB(), fmt.Sprintf(
+		"SELECT doc FROM token_transfers WHERE %s ORDER BY block_num, doc->>'txId', (doc->>'eventIndex')::int LIMIT %d", where, size,
+	), args...)
+	transfers, _, err := r.findMany(docs, 0, err)
+
+	return transfers, err
+}
+
+func (r tokenTransferRepository) PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error {
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Purge token transfers after block")
+
+	_, err := r.index.GetDB().ExecContext(ctx, "DELETE FROM token_transfers WHERE block_num > $1", blockNum)
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.Uint64("blockNum", blockNum)).Error("Failed to purge token transfers")
+	}
+
+	return err
+}
+
+func (r tokenTransferRepository) findMany(docs [][]byte, total int64, err error) ([]entity.TokenTransfer, int64, error) {
+	transfers := make([]entity.TokenTransfer, 0)
+
+	if err != nil {
+		return transfers, 0, err
+	}
+
+	for _, doc := range docs {
+		var transfer entity.TokenTransfer
+		if err := json.Unmarshal(doc, &transfer); err == nil {
+			transfers = append(transfers, transfer)
+		}
+	}
+
+	return transfers, total, nil
+}
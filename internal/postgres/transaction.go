@@ -0,0 +1,222 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+type transactionRepository struct {
+	index Index
+}
+
+func NewTransactionRepository(index Index) repository.TransactionRepository {
+	return transactionRepository{index}
+}
+
+func (r transactionRepository) GetBestBlockNum(ctx context.Context) (uint64, error) {
+	var blockNum sql.NullInt64
+	if err := r.index.GetDB().QueryRowContext(ctx, "SELECT max(block_num) FROM transactions").Scan(&blockNum); err != nil {
+		return 0, err
+	}
+
+	if !blockNum.Valid {
+		zap.L().Info("Best block num not found giving 0")
+		return 0, repository.ErrBestBlockNumFound
+	}
+
+	return uint64(blockNum.Int64), nil
+}
+
+func (r transactionRepository) GetTx(ctx context.Context, txId string) (*entity.Transaction, error) {
+	zap.L().Debug("TransactionRepository::GetTx: " + txId)
+
+	return r.findOne(findDocs(ctx, r.index.GetDB(), "SELECT doc FROM transactions WHERE tx_id = $1 LIMIT 1", txId))
+}
+
+func (r transactionRepository) GetMissingTxs(ctx context.Context, txIds []string) (map[string]struct{}, error) {
+	zap.L().Debug("TransactionRepository::GetMissingTxs")
+
+	rows, err := r.index.GetDB().QueryContext(ctx, "SELECT tx_id FROM transactions WHERE tx_id = ANY($1)", pq.Array(txIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	missingTxIds := map[string]struct{}{}
+	for _, tx := range txIds {
+		missingTxIds[tx] = struct{}{}
+	}
+
+	for rows.Next() {
+		var txId string
+		if err := rows.Scan(&txId); err != nil {
+			return nil, err
+		}
+		delete(missingTxIds, txId)
+	}
+
+	return missingTxIds, rows.Err()
+}
+
+func (r transactionRepository) GetContractCreationTxs(ctx context.Context, fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error) {
+	zap.L().With(
+		zap.Uint64("blockNum", fromBlockNum),
+		zap.Int("size", size),
+		zap.Int("page", page),
+	).Info("GetContractCreationTxs")
+
+	return r.findMany(findPage(ctx, r.index.GetDB(), "transactions",
+		"NOT failed AND contract_creation AND block_num >= $1", "block_num", size, page, fromBlockNum))
+}
+
+func (r transactionRepository) GetContractExecutionTxs(ctx context.Context, fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error) {
+	zap.L().With(
+		zap.Uint64("blockNum", fromBlockNum),
+		zap.Int("size", size),
+		zap.Int("page", page),
+	).Info("GetContractExecutionTxs")
+
+	return r.findMany(findPage(ctx, r.index.GetDB(), "transactions",
+		"NOT failed AND contract_execution AND block_num >= $1", "block_num", size, page, fromBlockNum))
+}
+
+func (r transactionRepository) GetContractCreationForContract(ctx context.Context, contractAddr string) (*entity.Transaction, error) {
+	return r.findOne(findDocs(ctx, r.index.GetDB(),
+		"SELECT doc FROM transactions WHERE NOT failed AND contract_creation AND contract_address = $1 ORDER BY block_num LIMIT 1", contractAddr))
+}
+
+func (r transactionRepository) GetContractExecutionsByContract(ctx context.Context, c entity.Contract, size, page int) ([]entity.Transaction, int64, error) {
+	zap.L().Info("Get contract executions for " + c.Address)
+
+	return r.findMany(findPage(ctx, r.index.GetDB(), "transactions",
+		"NOT failed AND contract_execution AND (contract_address = $1 OR "+emittedBy("$1")+")", "block_num", size, page, c.Address))
+}
+
+func (r transactionRepository) GetContractExecutionsByContractFrom(ctx context.Context, c entity.Contract, fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error) {
+	return r.findMany(findPage(ctx, r.index.GetDB(), "transactions",
+		"NOT failed AND contract_execution AND block_num >= $1 AND "+emittedBy("$2"), "block_num", size, page, fromBlockNum, c.Address))
+}
+
+func (r transactionRepository) GetNftMarketplaceExecutionTxs(ctx context.Context, fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error) {
+	zap.L().With(
+		zap.Int("size", size),
+		zap.Int("page", page),
+	).Info("GetNftMarketplaceExecutionTxs")
+
+	where := `NOT failed AND contract_execution AND block_num >= $1 AND (
+		EXISTS (SELECT 1 FROM unnest($2::text[]) AS event WHERE ` + emitted("event") + `)
+		OR (contract_address = $3 AND doc->'Data'->>'_tag' = 'ConfigurePrice')
+		OR (` + emitted("$4::text") + ` AND doc->'Data'->>'_tag' = 'WithdrawalToken')
+		OR (` + emitted("$5::text") + ` AND doc->'Data'->>'_tag' = 'Buy')
+	)`
+
+	events := []string{
+		string(entity.MpZilkroadListingEvent),
+		string(entity.MpZilkroadDelistingEvent),
+		string(entity.MpZilkroadSaleEvent),
+		string(entity.MpArkySaleEvent),
+		string(entity.MpMintableListingEvent),
+		string(entity.MpMintableDelistingEvent),
+		string(entity.MpMintableSaleEvent),
+	}
+
+	return r.findMany(findPage(ctx, r.index.GetDB(), "transactions", where, "block_num", size, page,
+		fromBlockNum,
+		pq.Array(events),
+		entity.OkimotoMarketplaceAddress,
+		string(entity.MpOkiDelistingEvent),
+		string(entity.MpOkiSaleEvent),
+	))
+}
+
+func (r transactionRepository) GetFailedTxsByContract(ctx context.Context, contractAddr string, size, page int) ([]entity.Transaction, int64, error) {
+	return r.findMany(findPage(ctx, r.index.GetDB(), "transactions",
+		"failed AND contract_address = $1", "block_num DESC", size, page, contractAddr))
+}
+
+func (r transactionRepository) GetFailedTxsBySender(ctx context.Context, senderAddr string, size, page int) ([]entity.Transaction, int64, error) {
+	return r.findMany(findPage(ctx, r.index.GetDB(), "transactions",
+		"failed AND sender_addr = $1", "block_num DESC", size, page, senderAddr))
+}
+
+func (r transactionRepository) GetPaymentsByAddress(ctx context.Context, addr string, size, page int) ([]entity.Transaction, int64, error) {
+	return r.findMany(findPage(ctx, r.index.GetDB(), "transactions",
+		"payment AND (sender_addr = $1 OR recipient_addr = $1)", "block_num DESC", size, page, addr))
+}
+
+func (r transactionRepository) GetTxsAfterBlockNum(ctx context.Context, blockNum uint64, after *entity.Transaction, size int) ([]entity.Transaction, error) {
+	where := "block_num > $1"
+	args := []interface{}{blockNum}
+	if after != nil {
+		where += " AND (block_num, tx_id) > ($2, $3)"
+		args = append(args, after.BlockNum, after.ID)
+	}
+
+	docs, err := findDocs(ctx, r.index.GetDB(), fmt.Sprintf(
+		"SELECT doc FROM transactions WHERE %s ORDER BY block_num, tx_id LIMIT %d", where, size,
+	), args...)
+	txs, _, err := r.findMany(docs, 0, err)
+
+	return txs, err
+}
+
+func (r transactionRepository) PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error {
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Purge transactions after block")
+
+	_, err := r.index.GetDB().ExecContext(ctx, "DELETE FROM transactions WHERE block_num > $1", blockNum)
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.Uint64("blockNum", blockNum)).Error("Failed to purge transactions")
+	}
+
+	return err
+}
+
+func (r transactionRepository) findOne(docs [][]byte, err error) (*entity.Transaction, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	if len(docs) == 0 {
+		return nil, errors.New("no transaction found")
+	}
+
+	var tx entity.Transaction
+	if err = json.Unmarshal(docs[0], &tx); err != nil {
+		return nil, err
+	}
+
+	return &tx, nil
+}
+
+func (r transactionRepository) findMany(docs [][]byte, total int64, err error) ([]entity.Transaction, int64, error) {
+	txs := make([]entity.Transaction, 0)
+
+	if err != nil {
+		return txs, 0, err
+	}
+
+	for _, doc := range docs {
+		var tx entity.Transaction
+		if err := json.Unmarshal(doc, &tx); err == nil {
+			txs = append(txs, tx)
+		}
+	}
+
+	return txs, total, nil
+}
+
+// Containment on the event logs is served by the gin index, and is false when a tx has no event logs
+func emitted(eventName string) string {
+	return "doc->'Receipt'->'event_logs' @> jsonb_build_array(jsonb_build_object('_eventname', " + eventName + "))"
+}
+
+func emittedBy(address string) string {
+	return "doc->'Receipt'->'event_logs' @> jsonb_build_array(jsonb_build_object('address', " + address + "::text))"
+}
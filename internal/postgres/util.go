@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+func findDocs(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([][]byte, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	docs := make([][]byte, 0)
+	for rows.Next() {
+		var doc []byte
+		if err := rows.Scan(&doc); err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, rows.Err()
+}
+
+// findPage returns a page of documents from the table matching the where clause, along with the total matched
+func findPage(ctx context.Context, db *sql.DB, table, where, orderBy string, size, page int, args ...interface{}) ([][]byte, int64, error) {
+	from := size*page - size
+
+	var total int64
+	if err := db.QueryRowContext(ctx, fmt.Sprintf("SELECT count(*) FROM %s WHERE %s", table, where), args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	docs, err := findDocs(ctx, db, fmt.Sprintf("SELECT doc FROM %s WHERE %s ORDER BY %s LIMIT %d OFFSET %d", table, where, orderBy, size, from), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return docs, total, nil
+}
@@ -1,11 +1,13 @@
 package repository
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
 	"github.com/olivere/elastic/v7"
+	"go.uber.org/zap"
 )
 
 var (
@@ -13,19 +15,22 @@ var (
 )
 
 type NftActionRepository interface {
-	GetNftOwnerBeforeBlockNum(nft entity.Nft, blockNum uint64) (string, error)
+	GetNftOwnerBeforeBlockNum(ctx context.Context, nft entity.Nft, blockNum uint64) (string, error)
+	GetNftActions(ctx context.Context, nft entity.Nft, after *entity.NftAction, size int) ([]entity.NftAction, error)
+	GetActionsAfterBlockNum(ctx context.Context, blockNum uint64, after *entity.NftAction, size int) ([]entity.NftAction, error)
+	PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error
 }
 
 type nftActionRepository struct {
-	elastic elastic_search.Index
+	elastic elastic_search.ElasticIndex
 }
 
-func NewNftActionRepository(elastic elastic_search.Index) NftActionRepository {
+func NewNftActionRepository(elastic elastic_search.ElasticIndex) NftActionRepository {
 	return nftActionRepository{elastic}
 }
 
-func (r nftActionRepository) GetNftOwnerBeforeBlockNum(nft entity.Nft, blockNum uint64) (string, error) {
-	//pendingRequest := r.elastic.GetRequest(entity.CreateNftActionSlug(nft.TokenId, nft.Contract))
+func (r nftActionRepository) GetNftOwnerBeforeBlockNum(ctx context.Context, nft entity.Nft, blockNum uint64) (string, error) {
+	//pendingRequest := r.elastic.GetRequest(elastic_search.NftActionIndex.Get(), entity.CreateNftActionSlug(nft.TokenId, nft.Contract))
 	//if pendingRequest != nil {
 	//	pendingState := pendingRequest.Entity.(entity.ContractState)
 	//	return &pendingState, nil
@@ -38,7 +43,7 @@ func (r nftActionRepository) GetNftOwnerBeforeBlockNum(nft entity.Nft, blockNum
 		elastic.NewTermsQuery("action.keyword", "mint", "transfer"),
 	)
 
-	results, err := search(r.elastic.GetClient().
+	results, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.NftActionIndex.Get()).
 		Query(query).
 		Size(1))
@@ -51,6 +56,52 @@ func (r nftActionRepository) GetNftOwnerBeforeBlockNum(nft entity.Nft, blockNum
 	return action.To, nil
 }
 
+// GetNftActions lists the actions of an nft in the order they were made, following the action after when given
+func (r nftActionRepository) GetNftActions(ctx context.Context, nft entity.Nft, after *entity.NftAction, size int) ([]entity.NftAction, error) {
+	query := elastic.NewBoolQuery().Filter(
+		elastic.NewTermQuery("contract.keyword", nft.Contract),
+		elastic.NewTermQuery("tokenId", nft.TokenId),
+	)
+
+	return r.findAfter(ctx, query, after, size)
+}
+
+// GetActionsAfterBlockNum lists the actions after the block in the order they were made, following the action after
+// when given
+func (r nftActionRepository) GetActionsAfterBlockNum(ctx context.Context, blockNum uint64, after *entity.NftAction, size int) ([]entity.NftAction, error) {
+	return r.findAfter(ctx, elastic.NewRangeQuery("blockNum").Gt(blockNum), after, size)
+}
+
+func (r nftActionRepository) PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error {
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Purge nft actions after block")
+
+	err := deleteByQuery(ctx, r.elastic.GetClient().
+		DeleteByQuery(elastic_search.NftActionIndex.Get()).
+		Query(elastic.NewRangeQuery("blockNum").Gt(blockNum)))
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.Uint64("blockNum", blockNum)).Error("Failed to purge nft actions")
+	}
+
+	return err
+}
+
+func (r nftActionRepository) findAfter(ctx context.Context, query elastic.Query, after *entity.NftAction, size int) ([]entity.NftAction, error) {
+	request := r.elastic.GetClient().
+		Search(elastic_search.NftActionIndex.Get()).
+		Query(query).
+		Sort("blockNum", true).
+		Sort("txId.keyword", true).
+		Sort("action.keyword", true).
+		Size(size)
+	if after != nil {
+		request = request.SearchAfter(after.BlockNum, after.TxID, after.Action)
+	}
+
+	actions, _, err := r.findMany(search(ctx, request))
+
+	return actions, err
+}
+
 func (r nftActionRepository) findOne(results *elastic.SearchResult, err error) (*entity.NftAction, error) {
 	if err != nil {
 		return nil, err
@@ -66,3 +117,20 @@ func (r nftActionRepository) findOne(results *elastic.SearchResult, err error) (
 
 	return &action, nil
 }
+
+func (r nftActionRepository) findMany(results *elastic.SearchResult, err error) ([]entity.NftAction, int64, error) {
+	actions := make([]entity.NftAction, 0)
+
+	if err != nil {
+		return actions, 0, err
+	}
+
+	for _, hit := range results.Hits.Hits {
+		var action entity.NftAction
+		if err := json.Unmarshal(hit.Source, &action); err == nil {
+			actions = append(actions, action)
+		}
+	}
+
+	return actions, results.TotalHits(), nil
+}
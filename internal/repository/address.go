@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/olivere/elastic/v7"
+	"go.uber.org/zap"
+	"time"
+)
+
+type AddressRepository interface {
+	// GetAddresses returns the indexed addresses among addrs, keyed by address
+	GetAddresses(ctx context.Context, addrs []string) (map[string]entity.Address, error)
+	// GetAddressesToReconcile returns the addresses last reconciled before a time, least recently reconciled first
+	GetAddressesToReconcile(ctx context.Context, before time.Time, size int) ([]entity.Address, error)
+}
+
+type addressRepository struct {
+	elastic elastic_search.ElasticIndex
+}
+
+func NewAddressRepository(elastic elastic_search.ElasticIndex) AddressRepository {
+	return addressRepository{elastic}
+}
+
+func (r addressRepository) GetAddresses(ctx context.Context, addrs []string) (map[string]entity.Address, error) {
+	addresses := make(map[string]entity.Address, len(addrs))
+
+	terms := make([]interface{}, 0, len(addrs))
+	for _, addr := range addrs {
+		if pendingRequest := r.elastic.GetRequest(elastic_search.AddressIndex.Get(), entity.CreateAddressSlug(addr)); pendingRequest != nil {
+			addresses[addr] = pendingRequest.Entity.(entity.Address)
+			continue
+		}
+		terms = append(terms, addr)
+	}
+
+	if len(terms) == 0 {
+		return addresses, nil
+	}
+
+	results, err := search(ctx, r.elastic.GetClient().
+		Search(elastic_search.AddressIndex.Get()).
+		Query(elastic.NewTermsQuery("address.keyword", terms...)).
+		Size(len(terms)))
+
+	found, err := r.findMany(results, err)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, address := range found {
+		addresses[address.Address] = address
+	}
+
+	return addresses, nil
+}
+
+func (r addressRepository) GetAddressesToReconcile(ctx context.Context, before time.Time, size int) ([]entity.Address, error) {
+	results, err := search(ctx, r.elastic.GetClient().
+		Search(elastic_search.AddressIndex.Get()).
+		Query(elastic.NewRangeQuery("reconciledAt").Lt(before)).
+		Sort("reconciledAt", true).
+		Size(size))
+
+	return r.findMany(results, err)
+}
+
+func (r addressRepository) findMany(results *elastic.SearchResult, err error) ([]entity.Address, error) {
+	addresses := make([]entity.Address, 0)
+
+	if err != nil {
+		return addresses, err
+	}
+
+	for _, hit := range results.Hits.Hits {
+		var address entity.Address
+		if err := json.Unmarshal(hit.Source, &address); err != nil {
+			zap.L().With(zap.Error(err)).Error("Failed to unmarshall address")
+			continue
+		}
+		addresses = append(addresses, address)
+	}
+
+	return addresses, nil
+}
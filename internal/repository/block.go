@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/olivere/elastic/v7"
+	"go.uber.org/zap"
+)
+
+type BlockRepository interface {
+	// PurgeAfterBlockNum removes the tx blocks after the block, and the ds blocks first reached after it
+	PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error
+}
+
+type blockRepository struct {
+	elastic elastic_search.ElasticIndex
+}
+
+func NewBlockRepository(elastic elastic_search.ElasticIndex) BlockRepository {
+	return blockRepository{elastic}
+}
+
+func (r blockRepository) PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error {
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Purge blocks after block")
+
+	results, err := search(ctx, r.elastic.GetClient().
+		Search(elastic_search.BlockIndex.Get()).
+		Query(elastic.NewRangeQuery("blockNum").Lte(blockNum)).
+		Sort("blockNum", false).
+		Size(1))
+	if err != nil {
+		return err
+	}
+
+	// Without a tx block left, every ds block was reached after the block
+	var dsBlockQuery elastic.Query = elastic.NewMatchAllQuery()
+	if len(results.Hits.Hits) != 0 {
+		var block entity.Block
+		if err := json.Unmarshal(results.Hits.Hits[0].Source, &block); err != nil {
+			return err
+		}
+		dsBlockQuery = elastic.NewRangeQuery("blockNum").Gt(block.DsBlockNum)
+	}
+
+	err = deleteByQuery(ctx, r.elastic.GetClient().
+		DeleteByQuery(elastic_search.DsBlockIndex.Get()).
+		Query(dsBlockQuery))
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.Uint64("blockNum", blockNum)).Error("Failed to purge ds blocks")
+		return err
+	}
+
+	err = deleteByQuery(ctx, r.elastic.GetClient().
+		DeleteByQuery(elastic_search.BlockIndex.Get()).
+		Query(elastic.NewRangeQuery("blockNum").Gt(blockNum)))
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.Uint64("blockNum", blockNum)).Error("Failed to purge blocks")
+	}
+
+	return err
+}
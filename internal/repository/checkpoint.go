@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/olivere/elastic/v7"
+)
+
+var (
+	ErrCheckpointNotFound = errors.New("checkpoint not found")
+)
+
+type CheckpointRepository interface {
+	GetCheckpoint(ctx context.Context, stage entity.Stage) (*entity.Checkpoint, error)
+	GetBlockNum(ctx context.Context, stage entity.Stage) (uint64, error)
+}
+
+type checkpointRepository struct {
+	elastic elastic_search.ElasticIndex
+}
+
+func NewCheckpointRepository(elastic elastic_search.ElasticIndex) CheckpointRepository {
+	return checkpointRepository{elastic}
+}
+
+func (r checkpointRepository) GetCheckpoint(ctx context.Context, stage entity.Stage) (*entity.Checkpoint, error) {
+	pendingRequest := r.elastic.GetRequest(elastic_search.CheckpointIndex.Get(), entity.CreateCheckpointSlug(stage))
+	if pendingRequest != nil {
+		pendingCheckpoint := pendingRequest.Entity.(entity.Checkpoint)
+		return &pendingCheckpoint, nil
+	}
+
+	results, err := search(ctx, r.elastic.GetClient().
+		Search(elastic_search.CheckpointIndex.Get()).
+		Query(elastic.NewTermQuery("stage", stage)).
+		Size(1))
+
+	return r.findOne(results, err)
+}
+
+func (r checkpointRepository) GetBlockNum(ctx context.Context, stage entity.Stage) (uint64, error) {
+	checkpoint, err := r.GetCheckpoint(ctx, stage)
+	if err != nil {
+		return 0, err
+	}
+
+	return checkpoint.BlockNum, nil
+}
+
+func (r checkpointRepository) findOne(results *elastic.SearchResult, err error) (*entity.Checkpoint, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results.Hits.Hits) == 0 {
+		return nil, ErrCheckpointNotFound
+	}
+
+	var checkpoint entity.Checkpoint
+	if err := json.Unmarshal(results.Hits.Hits[0].Source, &checkpoint); err != nil {
+		return nil, err
+	}
+
+	return &checkpoint, nil
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
@@ -14,21 +15,24 @@ var (
 )
 
 type ContractRepository interface {
-	GetAllContracts(size, page int) ([]entity.Contract, int64, error)
-	GetAllNftContracts(size, page int) ([]entity.Contract, int64, error)
-	GetContractByAddress(contractAddr string) (*entity.Contract, error)
-	GetBestBlockNum() (uint64, error)
+	GetAllContracts(ctx context.Context, size, page int) ([]entity.Contract, int64, error)
+	GetAllNftContracts(ctx context.Context, size, page int) ([]entity.Contract, int64, error)
+	GetAllZrc2Contracts(ctx context.Context, size, page int) ([]entity.Contract, int64, error)
+	GetContractByAddress(ctx context.Context, contractAddr string) (*entity.Contract, error)
+	GetBestBlockNum(ctx context.Context) (uint64, error)
+	GetContractsAfterBlockNum(ctx context.Context, blockNum uint64, size, page int) ([]entity.Contract, int64, error)
+	PurgeContract(ctx context.Context, contractAddr string) error
 }
 
 type contractRepository struct {
-	elastic elastic_search.Index
+	elastic elastic_search.ElasticIndex
 }
 
-func NewContractRepository(elastic elastic_search.Index) ContractRepository {
+func NewContractRepository(elastic elastic_search.ElasticIndex) ContractRepository {
 	return contractRepository{elastic}
 }
 
-func (r contractRepository) GetAllContracts(size, page int) ([]entity.Contract, int64, error) {
+func (r contractRepository) GetAllContracts(ctx context.Context, size, page int) ([]entity.Contract, int64, error) {
 	from := size*page - size
 
 	zap.L().With(
@@ -37,7 +41,7 @@ func (r contractRepository) GetAllContracts(size, page int) ([]entity.Contract,
 		zap.Int("from", from),
 	).Info("GetAllContracts")
 
-	results, err := search(r.elastic.GetClient().
+	results, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.ContractIndex.Get()).
 		Sort("blockNum", false).
 		Size(size).
@@ -46,7 +50,7 @@ func (r contractRepository) GetAllContracts(size, page int) ([]entity.Contract,
 	return r.findMany(results, err)
 }
 
-func (r contractRepository) GetAllNftContracts(size, page int) ([]entity.Contract, int64, error) {
+func (r contractRepository) GetAllNftContracts(ctx context.Context, size, page int) ([]entity.Contract, int64, error) {
 	from := size*page - size
 
 	zap.L().With(
@@ -60,7 +64,7 @@ func (r contractRepository) GetAllNftContracts(size, page int) ([]entity.Contrac
 		elastic.NewTermQuery("standards.ZRC6", true),
 	).MinimumShouldMatch("1")
 
-	results, err := search(r.elastic.GetClient().
+	results, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.ContractIndex.Get()).
 		Query(query).
 		Sort("blockNum", true).
@@ -70,14 +74,33 @@ func (r contractRepository) GetAllNftContracts(size, page int) ([]entity.Contrac
 	return r.findMany(results, err)
 }
 
-func (r contractRepository) GetContractByAddress(contractAddr string) (*entity.Contract, error) {
-	pendingRequest := r.elastic.GetRequest(entity.CreateContractSlug(contractAddr))
+func (r contractRepository) GetAllZrc2Contracts(ctx context.Context, size, page int) ([]entity.Contract, int64, error) {
+	from := size*page - size
+
+	zap.L().With(
+		zap.Int("size", size),
+		zap.Int("page", page),
+		zap.Int("from", from),
+	).Info("GetAllZrc2Contracts")
+
+	results, err := search(ctx, r.elastic.GetClient().
+		Search(elastic_search.ContractIndex.Get()).
+		Query(elastic.NewTermQuery("standards.ZRC2", true)).
+		Sort("blockNum", true).
+		Size(size).
+		From(from))
+
+	return r.findMany(results, err)
+}
+
+func (r contractRepository) GetContractByAddress(ctx context.Context, contractAddr string) (*entity.Contract, error) {
+	pendingRequest := r.elastic.GetRequest(elastic_search.ContractIndex.Get(), entity.CreateContractSlug(contractAddr))
 	if pendingRequest != nil {
 		pendingContract := pendingRequest.Entity.(entity.Contract)
 		return &pendingContract, nil
 	}
 
-	results, err := search(r.elastic.GetClient().
+	results, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.ContractIndex.Get()).
 		Query(elastic.NewTermQuery("address.keyword", contractAddr)))
 
@@ -86,8 +109,8 @@ func (r contractRepository) GetContractByAddress(contractAddr string) (*entity.C
 	return c, err
 }
 
-func (r contractRepository) GetBestBlockNum() (uint64, error) {
-	results, err := search(r.elastic.GetClient().
+func (r contractRepository) GetBestBlockNum(ctx context.Context) (uint64, error) {
+	results, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.ContractIndex.Get()).
 		Size(1).
 		Sort("BlockNum", false))
@@ -100,6 +123,46 @@ func (r contractRepository) GetBestBlockNum() (uint64, error) {
 	return c.BlockNum, nil
 }
 
+func (r contractRepository) GetContractsAfterBlockNum(ctx context.Context, blockNum uint64, size, page int) ([]entity.Contract, int64, error) {
+	from := size*page - size
+
+	results, err := search(ctx, r.elastic.GetClient().
+		Search(elastic_search.ContractIndex.Get()).
+		Query(elastic.NewRangeQuery("blockNum").Gt(blockNum)).
+		Sort("blockNum", true).
+		Size(size).
+		From(from).
+		TrackTotalHits(true))
+
+	return r.findMany(results, err)
+}
+
+func (r contractRepository) PurgeContract(ctx context.Context, contractAddr string) error {
+	zap.L().With(zap.String("contract", contractAddr)).Info("Purge contract document")
+
+	for _, index := range []elastic_search.Indices{
+		elastic_search.ContractIndex,
+		elastic_search.ContractStateIndex,
+	} {
+		err := deleteByQuery(ctx, r.elastic.GetClient().
+			DeleteByQuery(index.Get()).
+			Query(elastic.NewTermQuery("address.keyword", contractAddr)))
+		if err != nil {
+			zap.L().With(zap.Error(err), zap.String("contract", contractAddr)).Error("Failed to purge contract document")
+			return err
+		}
+	}
+
+	err := deleteByQuery(ctx, r.elastic.GetClient().
+		DeleteByQuery(elastic_search.ContractMetadataIndex.Get()).
+		Query(elastic.NewTermQuery("contract.keyword", contractAddr)))
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.String("contract", contractAddr)).Error("Failed to purge contract metadata")
+	}
+
+	return err
+}
+
 func (r contractRepository) findOne(results *elastic.SearchResult, err error) (*entity.Contract, error) {
 	if err != nil {
 		return nil, err
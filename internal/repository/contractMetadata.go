@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
@@ -13,19 +14,19 @@ var (
 )
 
 type ContractMetadataRepository interface {
-	GetMetadataByAddress(contractAddr string) (*entity.ContractMetadata, error)
+	GetMetadataByAddress(ctx context.Context, contractAddr string) (*entity.ContractMetadata, error)
 }
 
 type contractMetadataRepository struct {
-	elastic elastic_search.Index
+	elastic elastic_search.ElasticIndex
 }
 
-func NewContractMetadataRepository(elastic elastic_search.Index) ContractMetadataRepository {
+func NewContractMetadataRepository(elastic elastic_search.ElasticIndex) ContractMetadataRepository {
 	return contractMetadataRepository{elastic}
 }
 
-func (r contractMetadataRepository) GetMetadataByAddress(contractAddr string) (*entity.ContractMetadata, error) {
-	results, err := search(r.elastic.GetClient().
+func (r contractMetadataRepository) GetMetadataByAddress(ctx context.Context, contractAddr string) (*entity.ContractMetadata, error) {
+	results, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.ContractMetadataIndex.Get()).
 		Query(elastic.NewTermQuery("contract.keyword", contractAddr).CaseInsensitive(true)))
 
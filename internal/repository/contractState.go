@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
@@ -14,26 +15,26 @@ var (
 )
 
 type ContractStateRepository interface {
-	GetStateByAddress(contractAddr string) (*entity.ContractState, error)
-	GetRoyaltyFeeBps(contractAddr string) (uint, error)
+	GetStateByAddress(ctx context.Context, contractAddr string) (*entity.ContractState, error)
+	GetRoyaltyFeeBps(ctx context.Context, contractAddr string) (uint, error)
 }
 
 type contractStateRepository struct {
-	elastic elastic_search.Index
+	elastic elastic_search.ElasticIndex
 }
 
-func NewContractStateRepository(elastic elastic_search.Index) ContractStateRepository {
+func NewContractStateRepository(elastic elastic_search.ElasticIndex) ContractStateRepository {
 	return contractStateRepository{elastic}
 }
 
-func (r contractStateRepository) GetStateByAddress(contractAddr string) (*entity.ContractState, error) {
-	pendingRequest := r.elastic.GetRequest(entity.CreateStateSlug(contractAddr))
+func (r contractStateRepository) GetStateByAddress(ctx context.Context, contractAddr string) (*entity.ContractState, error) {
+	pendingRequest := r.elastic.GetRequest(elastic_search.ContractStateIndex.Get(), entity.CreateStateSlug(contractAddr))
 	if pendingRequest != nil {
 		pendingState := pendingRequest.Entity.(entity.ContractState)
 		return &pendingState, nil
 	}
 
-	results, err := search(r.elastic.GetClient().
+	results, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.ContractStateIndex.Get()).
 		Query(elastic.NewTermQuery("address.keyword", contractAddr)))
 
@@ -42,8 +43,8 @@ func (r contractStateRepository) GetStateByAddress(contractAddr string) (*entity
 	return state, err
 }
 
-func (r contractStateRepository)  GetRoyaltyFeeBps(contractAddr string) (uint, error) {
-	state, err := r.GetStateByAddress(contractAddr)
+func (r contractStateRepository)  GetRoyaltyFeeBps(ctx context.Context, contractAddr string) (uint, error) {
+	state, err := r.GetStateByAddress(ctx, contractAddr)
 	if err != nil {
 		return 0, err
 	}
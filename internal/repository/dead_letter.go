@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/olivere/elastic/v7"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+)
+
+type DeadLetterRepository interface {
+	GetDeadLetter(ctx context.Context, id string) (*entity.DeadLetter, error)
+	GetDeadLetters(ctx context.Context, size, page int) ([]entity.DeadLetter, int64, error)
+	Replay(ctx context.Context, deadLetter entity.DeadLetter) error
+	Delete(ctx context.Context, deadLetter entity.DeadLetter) error
+}
+
+type deadLetterRepository struct {
+	elastic elastic_search.ElasticIndex
+}
+
+func NewDeadLetterRepository(elastic elastic_search.ElasticIndex) DeadLetterRepository {
+	return deadLetterRepository{elastic}
+}
+
+func (r deadLetterRepository) GetDeadLetter(ctx context.Context, id string) (*entity.DeadLetter, error) {
+	results, err := search(ctx, r.elastic.GetClient().
+		Search(elastic_search.DeadLetterIndex.Get()).
+		Query(elastic.NewIdsQuery().Ids(id)).
+		Size(1))
+
+	return r.findOne(results, err)
+}
+
+func (r deadLetterRepository) GetDeadLetters(ctx context.Context, size, page int) ([]entity.DeadLetter, int64, error) {
+	from := size*page - size
+
+	results, err := search(ctx, r.elastic.GetClient().
+		Search(elastic_search.DeadLetterIndex.Get()).
+		Query(elastic.NewMatchAllQuery()).
+		Sort("createdAt", false).
+		Size(size).
+		From(from).
+		TrackTotalHits(true))
+
+	return r.findMany(results, err)
+}
+
+// Replay writes the dead letter's document back to its original index, and removes the dead letter once it succeeds
+func (r deadLetterRepository) Replay(ctx context.Context, deadLetter entity.DeadLetter) error {
+	var err error
+	if deadLetter.RequestType == string(elastic_search.UpdateRequest) {
+		_, err = r.elastic.GetClient().Update().
+			Index(deadLetter.Index).
+			Id(deadLetter.DocId).
+			Doc(deadLetter.Document).
+			Do(ctx)
+	} else {
+		_, err = r.elastic.GetClient().Index().
+			Index(deadLetter.Index).
+			Id(deadLetter.DocId).
+			BodyJson(deadLetter.Document).
+			Do(ctx)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return r.Delete(ctx, deadLetter)
+}
+
+func (r deadLetterRepository) Delete(ctx context.Context, deadLetter entity.DeadLetter) error {
+	_, err := r.elastic.GetClient().Delete().
+		Index(elastic_search.DeadLetterIndex.Get()).
+		Id(deadLetter.Slug()).
+		Refresh("true").
+		Do(ctx)
+
+	return err
+}
+
+func (r deadLetterRepository) findOne(results *elastic.SearchResult, err error) (*entity.DeadLetter, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results.Hits.Hits) == 0 {
+		return nil, ErrDeadLetterNotFound
+	}
+
+	var deadLetter entity.DeadLetter
+	if err := json.Unmarshal(results.Hits.Hits[0].Source, &deadLetter); err != nil {
+		return nil, err
+	}
+
+	return &deadLetter, nil
+}
+
+func (r deadLetterRepository) findMany(results *elastic.SearchResult, err error) ([]entity.DeadLetter, int64, error) {
+	deadLetters := make([]entity.DeadLetter, 0)
+
+	if err != nil {
+		return deadLetters, 0, err
+	}
+
+	for _, hit := range results.Hits.Hits {
+		var deadLetter entity.DeadLetter
+		if err := json.Unmarshal(hit.Source, &deadLetter); err != nil {
+			zap.L().With(zap.Error(err)).Error("Failed to unmarshall dead letter")
+			continue
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+
+	return deadLetters, results.TotalHits(), nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/olivere/elastic/v7"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrGovernanceEventNotFound = errors.New("governance event not found")
+)
+
+type GovernanceRepository interface {
+	// GetHistory lists the governance events of a contract, most recent first
+	GetHistory(ctx context.Context, contract string, size, page int) ([]entity.GovernanceEvent, int64, error)
+	// GetLatestEvent returns the last event of an action on a contract at or before a block
+	GetLatestEvent(ctx context.Context, contract string, action entity.GovernanceAction, blockNum uint64) (*entity.GovernanceEvent, error)
+	PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error
+}
+
+type governanceRepository struct {
+	elastic elastic_search.ElasticIndex
+}
+
+func NewGovernanceRepository(elastic elastic_search.ElasticIndex) GovernanceRepository {
+	return governanceRepository{elastic}
+}
+
+func (r governanceRepository) GetHistory(ctx context.Context, contract string, size, page int) ([]entity.GovernanceEvent, int64, error) {
+	from := size*page - size
+
+	results, err := search(ctx, r.elastic.GetClient().
+		Search(elastic_search.GovernanceIndex.Get()).
+		Query(elastic.NewTermQuery("contract.keyword", contract)).
+		Sort("blockNum", false).
+		Sort("eventIndex", false).
+		Size(size).
+		From(from).
+		TrackTotalHits(true))
+
+	return r.findMany(results, err)
+}
+
+func (r governanceRepository) GetLatestEvent(ctx context.Context, contract string, action entity.GovernanceAction, blockNum uint64) (*entity.GovernanceEvent, error) {
+	pending := LatestPendingGovernanceEvent(r.elastic, contract, action, blockNum)
+
+	results, err := search(ctx, r.elastic.GetClient().
+		Search(elastic_search.GovernanceIndex.Get()).
+		Query(elastic.NewBoolQuery().Must(
+			elastic.NewTermQuery("contract.keyword", contract),
+			elastic.NewTermQuery("action.keyword", action),
+			elastic.NewRangeQuery("blockNum").Lte(blockNum),
+		)).
+		Sort("blockNum", false).
+		Sort("eventIndex", false).
+		Size(1))
+
+	events, _, err := r.findMany(results, err)
+	if err != nil {
+		return nil, err
+	}
+
+	var stored *entity.GovernanceEvent
+	if len(events) != 0 {
+		stored = &events[0]
+	}
+
+	return LaterGovernanceEvent(stored, pending)
+}
+
+func (r governanceRepository) PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error {
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Purge governance events after block")
+
+	err := deleteByQuery(ctx, r.elastic.GetClient().
+		DeleteByQuery(elastic_search.GovernanceIndex.Get()).
+		Query(elastic.NewRangeQuery("blockNum").Gt(blockNum)))
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.Uint64("blockNum", blockNum)).Error("Failed to purge governance events")
+	}
+
+	return err
+}
+
+func (r governanceRepository) findMany(results *elastic.SearchResult, err error) ([]entity.GovernanceEvent, int64, error) {
+	events := make([]entity.GovernanceEvent, 0)
+
+	if err != nil {
+		return events, 0, err
+	}
+
+	for _, hit := range results.Hits.Hits {
+		var event entity.GovernanceEvent
+		if err := json.Unmarshal(hit.Source, &event); err == nil {
+			events = append(events, event)
+		}
+	}
+
+	return events, results.TotalHits(), nil
+}
+
+// LatestPendingGovernanceEvent returns the last event of an action on a contract at or before a block that is
+// waiting to be persisted
+func LatestPendingGovernanceEvent(index elastic_search.Index, contract string, action entity.GovernanceAction, blockNum uint64) *entity.GovernanceEvent {
+	var latest *entity.GovernanceEvent
+	for _, e := range index.GetEntitiesByIndex(elastic_search.GovernanceIndex.Get()) {
+		event, ok := e.(entity.GovernanceEvent)
+		if !ok || event.Contract != contract || event.Action != action || event.BlockNum > blockNum {
+			continue
+		}
+		latest, _ = LaterGovernanceEvent(latest, &event)
+	}
+
+	return latest
+}
+
+// LaterGovernanceEvent returns the more recent of two events, the second winning a tie as pending events are
+// indexed after those stored
+func LaterGovernanceEvent(a, b *entity.GovernanceEvent) (*entity.GovernanceEvent, error) {
+	if a == nil && b == nil {
+		return nil, ErrGovernanceEventNotFound
+	}
+	if a == nil {
+		return b, nil
+	}
+	if b == nil || a.BlockNum > b.BlockNum || (a.BlockNum == b.BlockNum && a.TxID == b.TxID && a.EventIndex > b.EventIndex) {
+		return a, nil
+	}
+
+	return b, nil
+}
@@ -20,36 +20,40 @@ const (
 )
 
 type NftRepository interface {
-	Exists(contract string, tokenId uint64) bool
-	GetNft(contract string, tokenId uint64) (*entity.Nft, error)
-	GetNfts(contract string, size, page int) ([]entity.Nft, int64, error)
-	GetBestTokenId(contractAddr string, blockNum uint64) (uint64, error)
-	GetAllNfts(size, page int) ([]entity.Nft, int64, error)
-	GetAllZrc1Nfts(size, page int) ([]entity.Nft, int64, error)
-	GetAllZrc6Nfts(size, page int) ([]entity.Nft, int64, error)
-	GetMetadata(size, page int, status entity.MetadataStatus) ([]entity.Nft, int64, error)
-	GetIpfsMetadata(size, page int) ([]entity.Nft, int64, error)
-	ResetMetadata(nft entity.Nft) error
-	GetBestBlockNum() (uint64, error)
-	PurgeActions(contractAddr string) error
-	PurgeContract(contractAddr string) error
+	Exists(ctx context.Context, contract string, tokenId uint64) bool
+	GetNft(ctx context.Context, contract string, tokenId uint64) (*entity.Nft, error)
+	// FindNft looks the nft up once, without waiting for it to be indexed
+	FindNft(ctx context.Context, contract string, tokenId uint64) (*entity.Nft, error)
+	GetNfts(ctx context.Context, contract string, size, page int) ([]entity.Nft, int64, error)
+	GetBestTokenId(ctx context.Context, contractAddr string, blockNum uint64) (uint64, error)
+	GetNftStats(ctx context.Context, contract string) (entity.NftStats, error)
+	GetAllNfts(ctx context.Context, size, page int) ([]entity.Nft, int64, error)
+	GetAllZrc1Nfts(ctx context.Context, size, page int) ([]entity.Nft, int64, error)
+	GetAllZrc6Nfts(ctx context.Context, size, page int) ([]entity.Nft, int64, error)
+	GetMetadata(ctx context.Context, size, page int, status entity.MetadataStatus) ([]entity.Nft, int64, error)
+	GetIpfsMetadata(ctx context.Context, size, page int) ([]entity.Nft, int64, error)
+	ResetMetadata(ctx context.Context, nft entity.Nft) error
+	GetBestBlockNum(ctx context.Context) (uint64, error)
+	PurgeActions(ctx context.Context, contractAddr string) error
+	PurgeContract(ctx context.Context, contractAddr string) error
+	PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error
 }
 
 type nftRepository struct {
-	elastic elastic_search.Index
+	elastic elastic_search.ElasticIndex
 }
 
-func NewNftRepository(elastic elastic_search.Index) NftRepository {
+func NewNftRepository(elastic elastic_search.ElasticIndex) NftRepository {
 	return nftRepository{elastic}
 }
 
-func (r nftRepository) Exists(contract string, tokenId uint64) bool {
+func (r nftRepository) Exists(ctx context.Context, contract string, tokenId uint64) bool {
 	query := elastic.NewBoolQuery().Must(
 		elastic.NewTermQuery("contract.keyword", contract),
 		elastic.NewTermQuery("tokenId", tokenId),
 	)
 
-	result, err := count(r.elastic.GetClient().
+	result, err := count(ctx, r.elastic.GetClient().
 		Count(elastic_search.NftIndex.Get()).
 		Query(query))
 
@@ -60,12 +64,16 @@ func (r nftRepository) Exists(contract string, tokenId uint64) bool {
 	return result != 0
 }
 
-func (r nftRepository) GetNft(contract string, tokenId uint64) (*entity.Nft, error) {
-	return r.getNft(contract, tokenId, 1)
+func (r nftRepository) GetNft(ctx context.Context, contract string, tokenId uint64) (*entity.Nft, error) {
+	return r.getNft(ctx, contract, tokenId, 1)
 }
 
-func (r nftRepository) getNft(contract string, tokenId uint64, attempt int) (*entity.Nft, error) {
-	pendingRequest := r.elastic.GetRequest(entity.CreateNftSlug(tokenId, contract))
+func (r nftRepository) FindNft(ctx context.Context, contract string, tokenId uint64) (*entity.Nft, error) {
+	return r.getNft(ctx, contract, tokenId, -1)
+}
+
+func (r nftRepository) getNft(ctx context.Context, contract string, tokenId uint64, attempt int) (*entity.Nft, error) {
+	pendingRequest := r.elastic.GetRequest(elastic_search.NftIndex.Get(), entity.CreateNftSlug(tokenId, contract))
 	if pendingRequest != nil {
 		pendingNft := pendingRequest.Entity.(entity.Nft)
 		return &pendingNft, nil
@@ -76,7 +84,7 @@ func (r nftRepository) getNft(contract string, tokenId uint64, attempt int) (*en
 		elastic.NewTermQuery("tokenId", tokenId),
 	)
 
-	result, err := search(r.elastic.GetClient().
+	result, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.NftIndex.Get()).
 		Query(query).
 		Size(1))
@@ -88,20 +96,20 @@ func (r nftRepository) getNft(contract string, tokenId uint64, attempt int) (*en
 		}
 		zap.S().With(zap.String("contract", contract), zap.Uint64("tokenId", tokenId)).Errorf("Failed to find NFT in repo. retry(%d)", attempt)
 		time.Sleep(time.Second * 1)
-		return r.getNft(contract, tokenId, attempt+1)
+		return r.getNft(ctx, contract, tokenId, attempt+1)
 	}
 
 	return nft, err
 }
 
-func (r nftRepository) GetNfts(contract string, size, page int) ([]entity.Nft, int64, error) {
+func (r nftRepository) GetNfts(ctx context.Context, contract string, size, page int) ([]entity.Nft, int64, error) {
 	query := elastic.NewBoolQuery().Must(
 		elastic.NewTermQuery("contract.keyword", contract),
 	)
 
 	from := size*page - size
 
-	result, err := search(r.elastic.GetClient().
+	result, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.NftIndex.Get()).
 		Query(query).
 		Size(size).
@@ -112,14 +120,65 @@ func (r nftRepository) GetNfts(contract string, size, page int) ([]entity.Nft, i
 	return r.findMany(result, err)
 }
 
-func (r nftRepository) GetIpfsMetadata(size, page int) ([]entity.Nft, int64, error) {
+// GetNftStats counts the live and burned nfts of a contract, and the distinct owners of the live ones
+func (r nftRepository) GetNftStats(ctx context.Context, contract string) (entity.NftStats, error) {
+	pending := PendingNfts(r.elastic, contract)
+	stats := entity.NftStats{}
+
+	byContract := elastic.NewTermQuery("contract.keyword", contract)
+	live := elastic.NewTermQuery("burnedAt", 0)
+
+	supply, err := count(ctx, r.elastic.GetClient().
+		Count(elastic_search.NftIndex.Get()).
+		Query(elastic.NewBoolQuery().Must(byContract, live).MustNot(excludePending(pending)...)))
+	if err != nil {
+		return stats, err
+	}
+
+	burned, err := count(ctx, r.elastic.GetClient().
+		Count(elastic_search.NftIndex.Get()).
+		Query(elastic.NewBoolQuery().Must(byContract).MustNot(append(excludePending(pending), live)...)))
+	if err != nil {
+		return stats, err
+	}
+
+	owners := make(map[string]bool)
+	agg := elastic.NewCompositeAggregation().
+		Sources(elastic.NewCompositeAggregationTermsValuesSource("owner").Field("owner.keyword")).
+		Size(1000)
+	for {
+		results, err := search(ctx, r.elastic.GetClient().
+			Search(elastic_search.NftIndex.Get()).
+			Query(elastic.NewBoolQuery().Must(byContract, live).MustNot(excludePending(pending)...)).
+			Aggregation("owners", agg).
+			Size(0))
+		if err != nil {
+			return stats, err
+		}
+
+		composite, found := results.Aggregations.Composite("owners")
+		if !found || len(composite.Buckets) == 0 {
+			break
+		}
+		for _, bucket := range composite.Buckets {
+			if owner, ok := bucket.Key["owner"].(string); ok {
+				owners[owner] = true
+			}
+		}
+		agg = agg.AggregateAfter(composite.AfterKey)
+	}
+
+	return CountNftStats(supply, burned, owners, pending), nil
+}
+
+func (r nftRepository) GetIpfsMetadata(ctx context.Context, size, page int) ([]entity.Nft, int64, error) {
 	query := elastic.NewNestedQuery("metadata", elastic.NewBoolQuery().Must(
 		elastic.NewTermQuery("metadata.ipfs", true),
 	))
 
 	from := size*page - size
 
-	result, err := search(r.elastic.GetClient().
+	result, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.NftIndex.Get()).
 		Query(query).
 		Size(size).
@@ -130,10 +189,10 @@ func (r nftRepository) GetIpfsMetadata(size, page int) ([]entity.Nft, int64, err
 	return r.findMany(result, err)
 }
 
-func (r nftRepository) GetAllNfts(size, page int) ([]entity.Nft, int64, error) {
+func (r nftRepository) GetAllNfts(ctx context.Context, size, page int) ([]entity.Nft, int64, error) {
 	from := size*page - size
 
-	result, err := search(r.elastic.GetClient().
+	result, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.NftIndex.Get()).
 		Size(size).
 		Sort("blockNum", false).
@@ -143,14 +202,14 @@ func (r nftRepository) GetAllNfts(size, page int) ([]entity.Nft, int64, error) {
 	return r.findMany(result, err)
 }
 
-func (r nftRepository) GetAllZrc1Nfts(size, page int) ([]entity.Nft, int64, error) {
+func (r nftRepository) GetAllZrc1Nfts(ctx context.Context, size, page int) ([]entity.Nft, int64, error) {
 	query := elastic.NewBoolQuery().Must(
 		elastic.NewTermQuery("zrc1", true),
 	)
 
 	from := size*page - size
 
-	result, err := search(r.elastic.GetClient().
+	result, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.NftIndex.Get()).
 		Query(query).
 		Size(size).
@@ -161,14 +220,14 @@ func (r nftRepository) GetAllZrc1Nfts(size, page int) ([]entity.Nft, int64, erro
 	return r.findMany(result, err)
 }
 
-func (r nftRepository) GetAllZrc6Nfts(size, page int) ([]entity.Nft, int64, error) {
+func (r nftRepository) GetAllZrc6Nfts(ctx context.Context, size, page int) ([]entity.Nft, int64, error) {
 	query := elastic.NewBoolQuery().Must(
 		elastic.NewTermQuery("zrc6", true),
 	)
 
 	from := size*page - size
 
-	result, err := search(r.elastic.GetClient().
+	result, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.NftIndex.Get()).
 		Query(query).
 		Size(size).
@@ -178,7 +237,7 @@ func (r nftRepository) GetAllZrc6Nfts(size, page int) ([]entity.Nft, int64, erro
 
 	return r.findMany(result, err)
 }
-func (r nftRepository) GetMetadata(size, page int, status entity.MetadataStatus) ([]entity.Nft, int64, error) {
+func (r nftRepository) GetMetadata(ctx context.Context, size, page int, status entity.MetadataStatus) ([]entity.Nft, int64, error) {
 	queries := []elastic.Query{
 		elastic.NewTermQuery("metadata.status.keyword", status),
 	}
@@ -186,7 +245,7 @@ func (r nftRepository) GetMetadata(size, page int, status entity.MetadataStatus)
 
 	from := size*page - size
 
-	result, err := search(r.elastic.GetClient().
+	result, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.NftIndex.Get()).
 		Query(elastic.NewNestedQuery("metadata", query)).
 		Size(size).
@@ -197,13 +256,13 @@ func (r nftRepository) GetMetadata(size, page int, status entity.MetadataStatus)
 	return r.findMany(result, err)
 }
 
-func (r nftRepository) GetBestTokenId(contractAddr string, blockNum uint64) (uint64, error) {
+func (r nftRepository) GetBestTokenId(ctx context.Context, contractAddr string, blockNum uint64) (uint64, error) {
 	query := elastic.NewBoolQuery().Must(
 		elastic.NewTermQuery("contract.keyword", contractAddr),
 		elastic.NewRangeQuery("blockNum").Lt(blockNum),
 	)
 
-	result, err := search(r.elastic.GetClient().
+	result, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.NftIndex.Get()).
 		Query(query).
 		Sort("tokenId", false).
@@ -220,7 +279,7 @@ func (r nftRepository) GetBestTokenId(contractAddr string, blockNum uint64) (uin
 	return nft.TokenId, nil
 }
 
-func (r nftRepository) ResetMetadata(nft entity.Nft) error {
+func (r nftRepository) ResetMetadata(ctx context.Context, nft entity.Nft) error {
 	query := elastic.NewBoolQuery().Must(
 		elastic.NewTermQuery("contract.keyword", nft.Contract),
 		elastic.NewTermQuery("tokenId", nft.TokenId),
@@ -230,13 +289,13 @@ func (r nftRepository) ResetMetadata(nft entity.Nft) error {
 		UpdateByQuery(elastic_search.NftIndex.Get()).
 		Query(query).
 		Script(elastic.NewScript("ctx._source.metadata.remove(\"properties\")")).
-		Do(context.Background())
+		Do(ctx)
 
 	return err
 }
 
-func (r nftRepository) GetBestBlockNum() (uint64, error) {
-	results, err := search(r.elastic.GetClient().
+func (r nftRepository) GetBestBlockNum(ctx context.Context) (uint64, error) {
+	results, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.NftIndex.Get()).
 		Size(1).
 		Sort("blockNum", false))
@@ -249,13 +308,13 @@ func (r nftRepository) GetBestBlockNum() (uint64, error) {
 	return nft.BlockNum, nil
 }
 
-func (r nftRepository) PurgeActions(contractAddr string) error {
+func (r nftRepository) PurgeActions(ctx context.Context, contractAddr string) error {
 	zap.L().With(zap.String("contract", contractAddr)).Info("Purge actions for contract")
 
 	_, err := r.elastic.GetClient().
 		DeleteByQuery(elastic_search.NftActionIndex.Get()).
 		Query(elastic.NewTermsQuery("contract.keyword", contractAddr)).
-		Do(context.Background())
+		Do(ctx)
 
 	if err != nil {
 		zap.L().With(zap.Error(err), zap.String("contract", contractAddr)).Error("Failed to purge nft actions")
@@ -264,31 +323,44 @@ func (r nftRepository) PurgeActions(contractAddr string) error {
 	return err
 }
 
-func (r nftRepository) PurgeContract(contractAddr string) error {
+func (r nftRepository) PurgeContract(ctx context.Context, contractAddr string) error {
 	zap.L().With(zap.String("contract", contractAddr)).Info("Purge contract")
 
 	_, err := r.elastic.GetClient().
 		DeleteByQuery(elastic_search.NftIndex.Get()).
 		WaitForCompletion(true).
 		Query(elastic.NewTermsQuery("contract.keyword", contractAddr)).
-		Do(context.Background())
+		Do(ctx)
 	if err != nil {
 		zap.L().With(zap.Error(err), zap.String("contract", contractAddr)).Error("Failed to purge contract")
 		return err
 	}
 
-	return r.validatePurgeContractComplete(contractAddr)
+	return r.validatePurgeContractComplete(ctx, contractAddr)
+}
+
+func (r nftRepository) PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error {
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Purge nfts minted after block")
+
+	err := deleteByQuery(ctx, r.elastic.GetClient().
+		DeleteByQuery(elastic_search.NftIndex.Get()).
+		Query(elastic.NewRangeQuery("blockNum").Gt(blockNum)))
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.Uint64("blockNum", blockNum)).Error("Failed to purge nfts")
+	}
+
+	return err
 }
 
-func (r nftRepository) validatePurgeContractComplete(contractAddr string) error {
+func (r nftRepository) validatePurgeContractComplete(ctx context.Context, contractAddr string) error {
 	zap.L().With(zap.String("contract", contractAddr)).Debug("validatePurgeContractComplete")
-	_, total, err := r.GetNfts(contractAddr, 1, 1)
+	_, total, err := r.GetNfts(ctx, contractAddr, 1, 1)
 	if err != nil {
 		return err
 	}
 	if total != 0 {
 		time.Sleep(1 * time.Second)
-		return r.validatePurgeContractComplete(contractAddr)
+		return r.validatePurgeContractComplete(ctx, contractAddr)
 	}
 	return nil
 }
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/olivere/elastic/v7"
+)
+
+type NftDiscrepancyRepository interface {
+	// GetDiscrepancies lists the discrepancies of a contract by token id, of every status when status is empty
+	GetDiscrepancies(ctx context.Context, contract string, status entity.DiscrepancyStatus, size, page int) ([]entity.NftDiscrepancy, int64, error)
+}
+
+type nftDiscrepancyRepository struct {
+	elastic elastic_search.ElasticIndex
+}
+
+func NewNftDiscrepancyRepository(elastic elastic_search.ElasticIndex) NftDiscrepancyRepository {
+	return nftDiscrepancyRepository{elastic}
+}
+
+func (r nftDiscrepancyRepository) GetDiscrepancies(ctx context.Context, contract string, status entity.DiscrepancyStatus, size, page int) ([]entity.NftDiscrepancy, int64, error) {
+	from := size*page - size
+
+	query := elastic.NewBoolQuery().Must(elastic.NewTermQuery("contract.keyword", contract))
+	if status != "" {
+		query.Must(elastic.NewTermQuery("status.keyword", status))
+	}
+
+	results, err := search(ctx, r.elastic.GetClient().
+		Search(elastic_search.NftDiscrepancyIndex.Get()).
+		Query(query).
+		Sort("tokenId", true).
+		Size(size).
+		From(from).
+		TrackTotalHits(true))
+
+	return r.findMany(results, err)
+}
+
+func (r nftDiscrepancyRepository) findMany(results *elastic.SearchResult, err error) ([]entity.NftDiscrepancy, int64, error) {
+	discrepancies := make([]entity.NftDiscrepancy, 0)
+
+	if err != nil {
+		return discrepancies, 0, err
+	}
+
+	for _, hit := range results.Hits.Hits {
+		var discrepancy entity.NftDiscrepancy
+		if err := json.Unmarshal(hit.Source, &discrepancy); err == nil {
+			discrepancies = append(discrepancies, discrepancy)
+		}
+	}
+
+	return discrepancies, results.TotalHits(), nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/olivere/elastic/v7"
+)
+
+// PendingNfts returns the nfts of a contract waiting to be persisted by their slug. They replace their stored
+// versions when counting.
+func PendingNfts(index elastic_search.Index, contract string) map[string]entity.Nft {
+	nfts := make(map[string]entity.Nft)
+	for _, e := range index.GetEntitiesByIndex(elastic_search.NftIndex.Get()) {
+		if nft, ok := e.(entity.Nft); ok && nft.Contract == contract {
+			nfts[nft.Slug()] = nft
+		}
+	}
+
+	return nfts
+}
+
+func excludePending(pending map[string]entity.Nft) []elastic.Query {
+	if len(pending) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(pending))
+	for slug := range pending {
+		ids = append(ids, slug)
+	}
+
+	return []elastic.Query{elastic.NewIdsQuery().Ids(ids...)}
+}
+
+// CountNftStats adds the pending nfts to the counts of those stored
+func CountNftStats(supply, burned int64, owners map[string]bool, pending map[string]entity.Nft) entity.NftStats {
+	for _, nft := range pending {
+		if nft.BurnedAt != 0 {
+			burned++
+			continue
+		}
+		supply++
+		owners[nft.Owner] = true
+	}
+
+	return entity.NftStats{Supply: supply, Burned: burned, Holders: int64(len(owners))}
+}
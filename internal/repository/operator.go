@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/olivere/elastic/v7"
+	"go.uber.org/zap"
+)
+
+// Approvals returned for an owner, the limit of an elastic search result window
+const MaxApprovals = 10000
+
+var (
+	ErrOperatorNotFound = errors.New("operator not found")
+)
+
+type OperatorRepository interface {
+	GetOperator(ctx context.Context, contract, owner, operator string) (*entity.Operator, error)
+	// GetApprovals lists the active operators and token spenders approved by an owner, across contracts
+	GetApprovals(ctx context.Context, owner string) ([]entity.Approval, error)
+	// GetOperatorsAfterBlockNum lists the grants last changed after the block, following the grant after when given
+	GetOperatorsAfterBlockNum(ctx context.Context, blockNum uint64, after *entity.Operator, size int) ([]entity.Operator, error)
+	PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error
+}
+
+type operatorRepository struct {
+	elastic elastic_search.ElasticIndex
+}
+
+func NewOperatorRepository(elastic elastic_search.ElasticIndex) OperatorRepository {
+	return operatorRepository{elastic}
+}
+
+func (r operatorRepository) GetOperator(ctx context.Context, contract, owner, operator string) (*entity.Operator, error) {
+	pendingRequest := r.elastic.GetRequest(elastic_search.OperatorIndex.Get(), entity.CreateOperatorSlug(contract, owner, operator))
+	if pendingRequest != nil {
+		pendingOperator := pendingRequest.Entity.(entity.Operator)
+		return &pendingOperator, nil
+	}
+
+	results, err := search(ctx, r.elastic.GetClient().
+		Search(elastic_search.OperatorIndex.Get()).
+		Query(elastic.NewBoolQuery().Must(
+			elastic.NewTermQuery("contract.keyword", contract),
+			elastic.NewTermQuery("owner.keyword", owner),
+			elastic.NewTermQuery("operator.keyword", operator),
+		)).
+		Size(1))
+
+	operators, err := r.findMany(results, err)
+	if err != nil {
+		return nil, err
+	}
+	if len(operators) == 0 {
+		return nil, ErrOperatorNotFound
+	}
+
+	return &operators[0], nil
+}
+
+func (r operatorRepository) GetOperatorsAfterBlockNum(ctx context.Context, blockNum uint64, after *entity.Operator, size int) ([]entity.Operator, error) {
+	request := r.elastic.GetClient().
+		Search(elastic_search.OperatorIndex.Get()).
+		Query(elastic.NewRangeQuery("blockNum").Gt(blockNum)).
+		Sort("contract.keyword", true).
+		Sort("owner.keyword", true).
+		Sort("operator.keyword", true).
+		Size(size)
+	if after != nil {
+		request = request.SearchAfter(after.Contract, after.Owner, after.Operator)
+	}
+
+	return r.findMany(search(ctx, request))
+}
+
+func (r operatorRepository) PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error {
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Purge operators after block")
+
+	err := deleteByQuery(ctx, r.elastic.GetClient().
+		DeleteByQuery(elastic_search.OperatorIndex.Get()).
+		Query(elastic.NewRangeQuery("blockNum").Gt(blockNum)))
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.Uint64("blockNum", blockNum)).Error("Failed to purge operators")
+	}
+
+	return err
+}
+
+func (r operatorRepository) GetApprovals(ctx context.Context, owner string) ([]entity.Approval, error) {
+	results, err := search(ctx, r.elastic.GetClient().
+		Search(elastic_search.OperatorIndex.Get()).
+		Query(elastic.NewBoolQuery().Must(
+			elastic.NewTermQuery("owner.keyword", owner),
+			elastic.NewTermQuery("active", true),
+		)).
+		Sort("contract.keyword", true).
+		Size(MaxApprovals))
+
+	operators, err := r.findMany(results, err)
+	if err != nil {
+		return nil, err
+	}
+
+	approvals := make([]entity.Approval, 0)
+	for _, operator := range operators {
+		approvals = append(approvals, CreateOperatorApproval(operator))
+	}
+
+	results, err = search(ctx, r.elastic.GetClient().
+		Search(elastic_search.NftIndex.Get()).
+		Query(elastic.NewBoolQuery().
+			Must(
+				elastic.NewTermQuery("owner.keyword", owner),
+				elastic.NewTermQuery("burnedAt", 0),
+				elastic.NewExistsQuery("spender"),
+			).
+			MustNot(elastic.NewTermQuery("spender", ""))).
+		Sort("contract.keyword", true).
+		Sort("tokenId", true).
+		Size(MaxApprovals))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hit := range results.Hits.Hits {
+		var nft entity.Nft
+		if err := json.Unmarshal(hit.Source, &nft); err != nil {
+			zap.L().With(zap.Error(err)).Error("Failed to unmarshall nft")
+			continue
+		}
+		approvals = append(approvals, CreateSpenderApproval(nft))
+	}
+
+	return approvals, nil
+}
+
+func CreateOperatorApproval(operator entity.Operator) entity.Approval {
+	return entity.Approval{
+		Contract:   operator.Contract,
+		Owner:      operator.Owner,
+		Approved:   operator.Operator,
+		IsOperator: true,
+	}
+}
+
+func CreateSpenderApproval(nft entity.Nft) entity.Approval {
+	return entity.Approval{
+		Contract: nft.Contract,
+		Owner:    nft.Owner,
+		Approved: nft.Spender,
+		TokenId:  nft.TokenId,
+	}
+}
+
+func (r operatorRepository) findMany(results *elastic.SearchResult, err error) ([]entity.Operator, error) {
+	operators := make([]entity.Operator, 0)
+
+	if err != nil {
+		return operators, err
+	}
+
+	for _, hit := range results.Hits.Hits {
+		var operator entity.Operator
+		if err := json.Unmarshal(hit.Source, &operator); err != nil {
+			zap.L().With(zap.Error(err)).Error("Failed to unmarshall operator")
+			continue
+		}
+		operators = append(operators, operator)
+	}
+
+	return operators, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/olivere/elastic/v7"
+	"go.uber.org/zap"
+)
+
+type SkippedBlockRepository interface {
+	GetSkippedBlocks(ctx context.Context, size, page int) ([]entity.SkippedBlock, int64, error)
+	Delete(ctx context.Context, skippedBlock entity.SkippedBlock) error
+}
+
+type skippedBlockRepository struct {
+	elastic elastic_search.ElasticIndex
+}
+
+func NewSkippedBlockRepository(elastic elastic_search.ElasticIndex) SkippedBlockRepository {
+	return skippedBlockRepository{elastic}
+}
+
+func (r skippedBlockRepository) GetSkippedBlocks(ctx context.Context, size, page int) ([]entity.SkippedBlock, int64, error) {
+	from := size*page - size
+
+	results, err := search(ctx, r.elastic.GetClient().
+		Search(elastic_search.SkippedBlockIndex.Get()).
+		Query(elastic.NewMatchAllQuery()).
+		Sort("blockNum", true).
+		Size(size).
+		From(from).
+		TrackTotalHits(true))
+
+	return r.findMany(results, err)
+}
+
+func (r skippedBlockRepository) Delete(ctx context.Context, skippedBlock entity.SkippedBlock) error {
+	_, err := r.elastic.GetClient().Delete().
+		Index(elastic_search.SkippedBlockIndex.Get()).
+		Id(skippedBlock.Slug()).
+		Refresh("true").
+		Do(ctx)
+
+	return err
+}
+
+func (r skippedBlockRepository) findMany(results *elastic.SearchResult, err error) ([]entity.SkippedBlock, int64, error) {
+	skippedBlocks := make([]entity.SkippedBlock, 0)
+
+	if err != nil {
+		return skippedBlocks, 0, err
+	}
+
+	for _, hit := range results.Hits.Hits {
+		var skippedBlock entity.SkippedBlock
+		if err := json.Unmarshal(hit.Source, &skippedBlock); err != nil {
+			zap.L().With(zap.Error(err)).Error("Failed to unmarshall skipped block")
+			continue
+		}
+		skippedBlocks = append(skippedBlocks, skippedBlock)
+	}
+
+	return skippedBlocks, results.TotalHits(), nil
+}
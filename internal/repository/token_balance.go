@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/olivere/elastic/v7"
+)
+
+var (
+	ErrTokenBalanceNotFound = errors.New("token balance not found")
+)
+
+type TokenBalanceRepository interface {
+	GetBalance(ctx context.Context, contract, holder string) (*entity.TokenBalance, error)
+	GetHolders(ctx context.Context, contract string, size, page int) ([]entity.TokenBalance, int64, error)
+	GetBalancesByHolder(ctx context.Context, holder string, size, page int) ([]entity.TokenBalance, int64, error)
+}
+
+type tokenBalanceRepository struct {
+	elastic elastic_search.ElasticIndex
+}
+
+func NewTokenBalanceRepository(elastic elastic_search.ElasticIndex) TokenBalanceRepository {
+	return tokenBalanceRepository{elastic}
+}
+
+func (r tokenBalanceRepository) GetBalance(ctx context.Context, contract, holder string) (*entity.TokenBalance, error) {
+	pendingRequest := r.elastic.GetRequest(elastic_search.TokenBalanceIndex.Get(), entity.CreateTokenBalanceSlug(contract, holder))
+	if pendingRequest != nil {
+		pendingBalance := pendingRequest.Entity.(entity.TokenBalance)
+		return &pendingBalance, nil
+	}
+
+	query := elastic.NewBoolQuery().Must(
+		elastic.NewTermQuery("contract.keyword", contract),
+		elastic.NewTermQuery("holder.keyword", holder),
+	)
+
+	results, err := search(ctx, r.elastic.GetClient().
+		Search(elastic_search.TokenBalanceIndex.Get()).
+		Query(query).
+		Size(1))
+
+	balances, _, err := r.findMany(results, err)
+	if err != nil {
+		return nil, err
+	}
+	if len(balances) == 0 {
+		return nil, ErrTokenBalanceNotFound
+	}
+
+	return &balances[0], nil
+}
+
+// GetHolders lists the holders of a token with a balance
+func (r tokenBalanceRepository) GetHolders(ctx context.Context, contract string, size, page int) ([]entity.TokenBalance, int64, error) {
+	from := size*page - size
+
+	query := elastic.NewBoolQuery().
+		Must(elastic.NewTermQuery("contract.keyword", contract)).
+		MustNot(elastic.NewTermQuery("balance", "0"))
+
+	results, err := search(ctx, r.elastic.GetClient().
+		Search(elastic_search.TokenBalanceIndex.Get()).
+		Query(query).
+		Sort("holder.keyword", true).
+		Size(size).
+		From(from).
+		TrackTotalHits(true))
+
+	return r.findMany(results, err)
+}
+
+// GetBalancesByHolder lists the tokens a holder has a balance of
+func (r tokenBalanceRepository) GetBalancesByHolder(ctx context.Context, holder string, size, page int) ([]entity.TokenBalance, int64, error) {
+	from := size*page - size
+
+	query := elastic.NewBoolQuery().
+		Must(elastic.NewTermQuery("holder.keyword", holder)).
+		MustNot(elastic.NewTermQuery("balance", "0"))
+
+	results, err := search(ctx, r.elastic.GetClient().
+		Search(elastic_search.TokenBalanceIndex.Get()).
+		Query(query).
+		Sort("contract.keyword", true).
+		Size(size).
+		From(from).
+		TrackTotalHits(true))
+
+	return r.findMany(results, err)
+}
+
+func (r tokenBalanceRepository) findMany(results *elastic.SearchResult, err error) ([]entity.TokenBalance, int64, error) {
+	balances := make([]entity.TokenBalance, 0)
+
+	if err != nil {
+		return balances, 0, err
+	}
+
+	for _, hit := range results.Hits.Hits {
+		var balance entity.TokenBalance
+		if err := json.Unmarshal(hit.Source, &balance); err == nil {
+			balances = append(balances, balance)
+		}
+	}
+
+	return balances, results.TotalHits(), nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/olivere/elastic/v7"
+	"go.uber.org/zap"
+)
+
+type TokenTransferRepository interface {
+	Exists(ctx context.Context, transfer entity.TokenTransfer) (bool, error)
+	GetTransfersByHolder(ctx context.Context, holder string, size, page int) ([]entity.TokenTransfer, int64, error)
+	GetTransfersToRevert(ctx context.Context, blockNum uint64, after *entity.TokenTransfer, size int) ([]entity.TokenTransfer, error)
+	PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error
+}
+
+type tokenTransferRepository struct {
+	elastic elastic_search.ElasticIndex
+}
+
+func NewTokenTransferRepository(elastic elastic_search.ElasticIndex) TokenTransferRepository {
+	return tokenTransferRepository{elastic}
+}
+
+func (r tokenTransferRepository) Exists(ctx context.Context, transfer entity.TokenTransfer) (bool, error) {
+	if r.elastic.GetRequest(elastic_search.TokenTransferIndex.Get(), transfer.Slug()) != nil {
+		return true, nil
+	}
+
+	return r.elastic.GetClient().Exists().
+		Index(elastic_search.TokenTransferIndex.Get()).
+		Id(transfer.Slug()).
+		Do(ctx)
+}
+
+// GetTransfersByHolder lists the transfers into and out of a holder's balances, most recent first
+func (r tokenTransferRepository) GetTransfersByHolder(ctx context.Context, holder string, size, page int) ([]entity.TokenTransfer, int64, error) {
+	from := size*page - size
+
+	query := elastic.NewBoolQuery().Should(
+		elastic.NewTermQuery("from.keyword", holder),
+		elastic.NewTermQuery("to.keyword", holder),
+	).MinimumNumberShouldMatch(1)
+
+	results, err := search(ctx, r.elastic.GetClient().
+		Search(elastic_search.TokenTransferIndex.Get()).
+		Query(query).
+		Sort("blockNum", false).
+		Size(size).
+		From(from).
+		TrackTotalHits(true))
+
+	return r.findMany(results, err)
+}
+
+// GetTransfersToRevert lists the transfers after the block not yet reverted, in the order they were made, following
+// the transfer after when given
+func (r tokenTransferRepository) GetTransfersToRevert(ctx context.Context, blockNum uint64, after *entity.TokenTransfer, size int) ([]entity.TokenTransfer, error) {
+	query := elastic.NewBoolQuery().
+		Filter(elastic.NewRangeQuery("blockNum").Gt(blockNum)).
+		MustNot(elastic.NewTermQuery("reverted", true))
+
+	request := r.elastic.GetClient().
+		Search(elastic_search.TokenTransferIndex.Get()).
+		Query(query).
+		Sort("blockNum", true).
+		Sort("txId.keyword", true).
+		Sort("eventIndex", true).
+		Size(size)
+	if after != nil {
+		request = request.SearchAfter(after.BlockNum, after.TxID, after.EventIndex)
+	}
+
+	transfers, _, err := r.findMany(search(ctx, request))
+
+	return transfers, err
+}
+
+func (r tokenTransferRepository) PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error {
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Purge token transfers after block")
+
+	err := deleteByQuery(ctx, r.elastic.GetClient().
+		DeleteByQuery(elastic_search.TokenTransferIndex.Get()).
+		Query(elastic.NewRangeQuery("blockNum").Gt(blockNum)))
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.Uint64("blockNum", blockNum)).Error("Failed to purge token transfers")
+	}
+
+	return err
+}
+
+func (r tokenTransferRepository) findMany(results *elastic.SearchResult, err error) ([]entity.TokenTransfer, int64, error) {
+	transfers := make([]entity.TokenTransfer, 0)
+
+	if err != nil {
+		return transfers, 0, err
+	}
+
+	for _, hit := range results.Hits.Hits {
+		var transfer entity.TokenTransfer
+		if err := json.Unmarshal(hit.Source, &transfer); err == nil {
+			transfers = append(transfers, transfer)
+		}
+	}
+
+	return transfers, results.TotalHits(), nil
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
@@ -15,31 +16,39 @@ var (
 )
 
 type TransactionRepository interface {
-	GetBestBlockNum() (uint64, error)
+	GetBestBlockNum(ctx context.Context) (uint64, error)
 
-	GetTx(txId string) (*entity.Transaction, error)
-	GetMissingTxs(txIds []string) (map[string]struct{}, error)
+	GetTx(ctx context.Context, txId string) (*entity.Transaction, error)
+	GetMissingTxs(ctx context.Context, txIds []string) (map[string]struct{}, error)
 
-	GetContractCreationTxs(fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error)
-	GetContractExecutionTxs(fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error)
+	GetContractCreationTxs(ctx context.Context, fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error)
+	GetContractExecutionTxs(ctx context.Context, fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error)
 
-	GetContractCreationForContract(contractAddr string) (*entity.Transaction, error)
-	GetContractExecutionsByContract(c entity.Contract, size, page int) ([]entity.Transaction, int64, error)
-	GetContractExecutionsByContractFrom(c entity.Contract, fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error)
+	GetContractCreationForContract(ctx context.Context, contractAddr string) (*entity.Transaction, error)
+	GetContractExecutionsByContract(ctx context.Context, c entity.Contract, size, page int) ([]entity.Transaction, int64, error)
+	GetContractExecutionsByContractFrom(ctx context.Context, c entity.Contract, fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error)
 
-	GetNftMarketplaceExecutionTxs(fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error)
+	GetNftMarketplaceExecutionTxs(ctx context.Context, fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error)
+
+	GetFailedTxsByContract(ctx context.Context, contractAddr string, size, page int) ([]entity.Transaction, int64, error)
+	GetFailedTxsBySender(ctx context.Context, senderAddr string, size, page int) ([]entity.Transaction, int64, error)
+
+	GetPaymentsByAddress(ctx context.Context, addr string, size, page int) ([]entity.Transaction, int64, error)
+
+	GetTxsAfterBlockNum(ctx context.Context, blockNum uint64, after *entity.Transaction, size int) ([]entity.Transaction, error)
+	PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error
 }
 
 type transactionRepository struct {
-	elastic elastic_search.Index
+	elastic elastic_search.ElasticIndex
 }
 
-func NewTransactionRepository(elastic elastic_search.Index) TransactionRepository {
+func NewTransactionRepository(elastic elastic_search.ElasticIndex) TransactionRepository {
 	return transactionRepository{elastic}
 }
 
-func (r transactionRepository) GetBestBlockNum() (uint64, error) {
-	result, err := search(r.elastic.GetClient().
+func (r transactionRepository) GetBestBlockNum(ctx context.Context) (uint64, error) {
+	result, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.TransactionIndex.Get()).
 		Size(1))
 
@@ -53,7 +62,7 @@ func (r transactionRepository) GetBestBlockNum() (uint64, error) {
 		return 0, ErrBestBlockNumFound
 	}
 
-	result, err = search(r.elastic.GetClient().
+	result, err = search(ctx, r.elastic.GetClient().
 		Search(elastic_search.TransactionIndex.Get()).
 		Sort("BlockNum", false).
 		Size(1))
@@ -71,23 +80,23 @@ func (r transactionRepository) GetBestBlockNum() (uint64, error) {
 	return tx.BlockNum, nil
 }
 
-func (r transactionRepository) GetTx(txId string) (*entity.Transaction, error) {
+func (r transactionRepository) GetTx(ctx context.Context, txId string) (*entity.Transaction, error) {
 	zap.L().Debug("TransactionRepository::GetTx: "+txId)
-	results, err := search(r.elastic.GetClient().
+	results, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.TransactionIndex.Get()).
 		Query(elastic.NewTermQuery("ID", txId)))
 
 	return r.findOne(results, err)
 }
 
-func (r transactionRepository) GetMissingTxs(txIds []string) (map[string]struct{}, error) {
+func (r transactionRepository) GetMissingTxs(ctx context.Context, txIds []string) (map[string]struct{}, error) {
 	zap.L().Debug("TransactionRepository::GetMissingTxs")
 	values := make([]interface{}, len(txIds))
 	for i, v := range txIds {
 		values[i] = v
 	}
 
-	results, err := search(r.elastic.GetClient().
+	results, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.TransactionIndex.Get()).
 		Query(elastic.NewTermsQuery("ID.keyword", values...)).
 		Aggregation("txId", elastic.NewTermsAggregation().Field("ID.keyword").Size(len(txIds))))
@@ -109,11 +118,11 @@ func (r transactionRepository) GetMissingTxs(txIds []string) (map[string]struct{
 	return missingTxIds, nil
 }
 
-func (r transactionRepository) GetContractCreationTxs(fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error) {
+func (r transactionRepository) GetContractCreationTxs(ctx context.Context, fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error) {
 	query := elastic.NewBoolQuery().Must(
 		elastic.NewTermQuery("ContractCreation", true),
 		elastic.NewRangeQuery("BlockNum").Gte(fromBlockNum),
-	)
+	).MustNot(failed())
 
 	from := size*page - size
 
@@ -124,7 +133,7 @@ func (r transactionRepository) GetContractCreationTxs(fromBlockNum uint64, size,
 		zap.Int("from", from),
 	).Info("GetContractCreationTxs")
 
-	result, err := search(r.elastic.GetClient().
+	result, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.TransactionIndex.Get()).
 		Query(query).
 		Sort("BlockNum", true).
@@ -135,11 +144,11 @@ func (r transactionRepository) GetContractCreationTxs(fromBlockNum uint64, size,
 	return r.findMany(result, err)
 }
 
-func (r transactionRepository) GetContractExecutionTxs(fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error) {
+func (r transactionRepository) GetContractExecutionTxs(ctx context.Context, fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error) {
 	query := elastic.NewBoolQuery().Must(
 		elastic.NewTermQuery("ContractExecution", true),
 		elastic.NewRangeQuery("BlockNum").Gte(fromBlockNum),
-	)
+	).MustNot(failed())
 
 	from := size*page - size
 
@@ -150,7 +159,7 @@ func (r transactionRepository) GetContractExecutionTxs(fromBlockNum uint64, size
 		zap.Int("from", from),
 	).Info("GetContractExecutionTxs")
 
-	result, err := search(r.elastic.GetClient().
+	result, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.TransactionIndex.Get()).
 		Query(query).
 		Sort("BlockNum", true).
@@ -161,13 +170,13 @@ func (r transactionRepository) GetContractExecutionTxs(fromBlockNum uint64, size
 	return r.findMany(result, err)
 }
 
-func (r transactionRepository) GetContractCreationForContract(contractAddr string) (*entity.Transaction, error) {
+func (r transactionRepository) GetContractCreationForContract(ctx context.Context, contractAddr string) (*entity.Transaction, error) {
 	query := elastic.NewBoolQuery().Must(
 		elastic.NewTermQuery("ContractCreation", true),
 		elastic.NewTermQuery("ContractAddress.keyword", contractAddr),
-	)
+	).MustNot(failed())
 
-	result, err := search(r.elastic.GetClient().
+	result, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.TransactionIndex.Get()).
 		Query(query).
 		Sort("BlockNum", true).
@@ -177,18 +186,18 @@ func (r transactionRepository) GetContractCreationForContract(contractAddr strin
 	return r.findOne(result, err)
 }
 
-func (r transactionRepository) GetContractExecutionsByContract(c entity.Contract, size, page int) ([]entity.Transaction, int64, error) {
+func (r transactionRepository) GetContractExecutionsByContract(ctx context.Context, c entity.Contract, size, page int) ([]entity.Transaction, int64, error) {
 	zap.L().Info("Get contract executions for " + c.Address)
 	query := elastic.NewBoolQuery().Must(
 		elastic.NewTermQuery("ContractExecution", true),
-	).Should(
+	).MustNot(failed()).Should(
 		elastic.NewNestedQuery("Receipt.event_logs", elastic.NewTermQuery("Receipt.event_logs.address.keyword", c.Address)),
 		elastic.NewTermQuery("ContractAddress.keyword", c.Address),
 	).MinimumShouldMatch("1")
 
 	from := size*page - size
 
-	result, err := search(r.elastic.GetClient().
+	result, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.TransactionIndex.Get()).
 		Query(query).
 		Sort("BlockNum", true).
@@ -199,15 +208,15 @@ func (r transactionRepository) GetContractExecutionsByContract(c entity.Contract
 	return r.findMany(result, err)
 }
 
-func (r transactionRepository) GetContractExecutionsByContractFrom(c entity.Contract, fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error) {
+func (r transactionRepository) GetContractExecutionsByContractFrom(ctx context.Context, c entity.Contract, fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error) {
 	query := elastic.NewBoolQuery().Must(
 		elastic.NewTermQuery("ContractExecution", true),
 		elastic.NewRangeQuery("BlockNum").Gte(fromBlockNum),
 		elastic.NewNestedQuery("Receipt.event_logs", elastic.NewTermQuery("Receipt.event_logs.address.keyword", c.Address)),
-	)
+	).MustNot(failed())
 	from := size*page - size
 
-	result, err := search(r.elastic.GetClient().
+	result, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.TransactionIndex.Get()).
 		Query(query).
 		Sort("BlockNum", true).
@@ -218,7 +227,7 @@ func (r transactionRepository) GetContractExecutionsByContractFrom(c entity.Cont
 	return r.findMany(result, err)
 }
 
-func (r transactionRepository) GetNftMarketplaceExecutionTxs(fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error) {
+func (r transactionRepository) GetNftMarketplaceExecutionTxs(ctx context.Context, fromBlockNum uint64, size, page int) ([]entity.Transaction, int64, error) {
 	query := elastic.NewBoolQuery().Must(
 		elastic.NewTermQuery("ContractExecution", true),
 		elastic.NewRangeQuery("BlockNum").Gte(fromBlockNum),
@@ -228,7 +237,7 @@ func (r transactionRepository) GetNftMarketplaceExecutionTxs(fromBlockNum uint64
 			okimotoExecutionTxs(),
 			mintableExecutionTxs(),
 		).MinimumNumberShouldMatch(1),
-	)
+	).MustNot(failed())
 
 	from := size*page - size
 
@@ -238,7 +247,7 @@ func (r transactionRepository) GetNftMarketplaceExecutionTxs(fromBlockNum uint64
 		zap.Int("from", from),
 	).Info("GetNftMarketplaceExecutionTxs")
 
-	result, err := search(r.elastic.GetClient().
+	result, err := search(ctx, r.elastic.GetClient().
 		Search(elastic_search.TransactionIndex.Get()).
 		Query(query).
 		Sort("BlockNum", true).
@@ -249,6 +258,82 @@ func (r transactionRepository) GetNftMarketplaceExecutionTxs(fromBlockNum uint64
 	return r.findMany(result, err)
 }
 
+// GetFailedTxsByContract lists the failed calls to a contract, most recent first
+func (r transactionRepository) GetFailedTxsByContract(ctx context.Context, contractAddr string, size, page int) ([]entity.Transaction, int64, error) {
+	return r.findFailed(ctx, elastic.NewTermQuery("ContractAddress.keyword", contractAddr), size, page)
+}
+
+// GetFailedTxsBySender lists the failed calls made by an address, most recent first
+func (r transactionRepository) GetFailedTxsBySender(ctx context.Context, senderAddr string, size, page int) ([]entity.Transaction, int64, error) {
+	return r.findFailed(ctx, elastic.NewTermQuery("SenderAddr.keyword", senderAddr), size, page)
+}
+
+// GetPaymentsByAddress lists the ZIL payments sent or received by an address, most recent first
+func (r transactionRepository) GetPaymentsByAddress(ctx context.Context, addr string, size, page int) ([]entity.Transaction, int64, error) {
+	from := size*page - size
+
+	query := elastic.NewBoolQuery().Must(
+		elastic.NewTermQuery("Payment", true),
+	).Should(
+		elastic.NewTermQuery("SenderAddr.keyword", addr),
+		elastic.NewTermQuery("RecipientAddr.keyword", addr),
+	).MinimumNumberShouldMatch(1)
+
+	result, err := search(ctx, r.elastic.GetClient().
+		Search(elastic_search.TransactionIndex.Get()).
+		Query(query).
+		Sort("BlockNum", false).
+		TrackTotalHits(true).
+		Size(size).
+		From(from))
+
+	return r.findMany(result, err)
+}
+
+func (r transactionRepository) findFailed(ctx context.Context, query elastic.Query, size, page int) ([]entity.Transaction, int64, error) {
+	from := size*page - size
+
+	result, err := search(ctx, r.elastic.GetClient().
+		Search(elastic_search.TransactionIndex.Get()).
+		Query(elastic.NewBoolQuery().Must(failed(), query)).
+		Sort("BlockNum", false).
+		TrackTotalHits(true).
+		Size(size).
+		From(from))
+
+	return r.findMany(result, err)
+}
+
+// GetTxsAfterBlockNum lists the transactions after the block in block order, following the transaction after when given
+func (r transactionRepository) GetTxsAfterBlockNum(ctx context.Context, blockNum uint64, after *entity.Transaction, size int) ([]entity.Transaction, error) {
+	request := r.elastic.GetClient().
+		Search(elastic_search.TransactionIndex.Get()).
+		Query(elastic.NewRangeQuery("BlockNum").Gt(blockNum)).
+		Sort("BlockNum", true).
+		Sort("ID.keyword", true).
+		Size(size)
+	if after != nil {
+		request = request.SearchAfter(after.BlockNum, after.ID)
+	}
+
+	txs, _, err := r.findMany(search(ctx, request))
+
+	return txs, err
+}
+
+func (r transactionRepository) PurgeAfterBlockNum(ctx context.Context, blockNum uint64) error {
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Purge transactions after block")
+
+	err := deleteByQuery(ctx, r.elastic.GetClient().
+		DeleteByQuery(elastic_search.TransactionIndex.Get()).
+		Query(elastic.NewRangeQuery("BlockNum").Gt(blockNum)))
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.Uint64("blockNum", blockNum)).Error("Failed to purge transactions")
+	}
+
+	return err
+}
+
 func (r transactionRepository) findOne(results *elastic.SearchResult, err error) (*entity.Transaction, error) {
 	if err != nil {
 		return nil, err
@@ -283,6 +368,11 @@ func (r transactionRepository) findMany(results *elastic.SearchResult, err error
 	return txs, results.TotalHits(), nil
 }
 
+// Transactions indexed before failed calls were kept have no Failed field, so are matched as successful
+func failed() elastic.Query {
+	return elastic.NewTermQuery("Failed", true)
+}
+
 func zilkRoadExecutionTxs() *elastic.BoolQuery {
 	return elastic.NewBoolQuery().Should(
 		elastic.NewNestedQuery("Receipt.event_logs", elastic.NewBoolQuery().Should(
@@ -8,35 +8,67 @@ import (
 	"time"
 )
 
-func count(countService *elastic.CountService) (int64, error) {
-	result, err := countService.Do(context.Background())
+func count(ctx context.Context, countService *elastic.CountService) (int64, error) {
+	result, err := countService.Do(ctx)
 	if err != nil && err.Error() == "elastic: Error 429 (Too Many Requests)" {
 		zap.L().Warn("Elastic: 429 (Too Many Requests)")
-		time.Sleep(5 * time.Second)
-		return count(countService)
+		if err := wait(ctx); err != nil {
+			return 0, err
+		}
+		return count(ctx, countService)
 	}
 
 	return result, err
 }
-func search(searchService *elastic.SearchService) (*elastic.SearchResult, error) {
-	result, err := searchService.Do(context.Background())
+
+func deleteByQuery(ctx context.Context, deleteService *elastic.DeleteByQueryService) error {
+	_, err := deleteService.WaitForCompletion(true).Refresh("true").Do(ctx)
+	if err != nil && err.Error() == "elastic: Error 429 (Too Many Requests)" {
+		zap.L().Warn("Elastic: 429 (Too Many Requests)")
+		if err := wait(ctx); err != nil {
+			return err
+		}
+		return deleteByQuery(ctx, deleteService)
+	}
+
+	return err
+}
+
+func search(ctx context.Context, searchService *elastic.SearchService) (*elastic.SearchResult, error) {
+	result, err := searchService.Do(ctx)
 	if err != nil {
 		if err.Error() == "elastic: Error 429 (Too Many Requests)" {
 			zap.L().Warn("Elastic: 429 (Too Many Requests)")
-			time.Sleep(5 * time.Second)
-			return search(searchService)
+			if err := wait(ctx); err != nil {
+				return nil, err
+			}
+			return search(ctx, searchService)
 		}
 		if strings.Contains(err.Error(), "GOAWAY") {
 			zap.L().Warn("Elastic: Transport received Server's graceful shutdown GOAWAY")
-			time.Sleep(5 * time.Second)
-			return search(searchService)
+			if err := wait(ctx); err != nil {
+				return nil, err
+			}
+			return search(ctx, searchService)
 		}
 		if strings.Contains(err.Error(), "no available connection") {
 			zap.L().Warn("Elastic: no available connection: no Elasticsearch node available")
-			time.Sleep(5 * time.Second)
-			return search(searchService)
+			if err := wait(ctx); err != nil {
+				return nil, err
+			}
+			return search(ctx, searchService)
 		}
 	}
 
 	return result, err
 }
+
+// wait backs off before a retry, giving up early if the context is cancelled
+func wait(ctx context.Context) error {
+	select {
+	case <-time.After(5 * time.Second):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
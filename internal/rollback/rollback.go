@@ -0,0 +1,500 @@
+package rollback
+
+import (
+	"context"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/elastic_search"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/entity"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/factory"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/helper"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/indexer"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/repository"
+	"go.uber.org/zap"
+	"math/big"
+)
+
+// Service removes or reverts every document derived from blocks above a target height, so the
+// indices look as they would after indexing up to (and including) that height.
+type Service interface {
+	RollbackTo(ctx context.Context, blockNum uint64) error
+}
+
+type service struct {
+	elastic        elastic_search.Index
+	txRepo         repository.TransactionRepository
+	contractRepo   repository.ContractRepository
+	nftRepo        repository.NftRepository
+	nftActionRepo  repository.NftActionRepository
+	checkpointRepo repository.CheckpointRepository
+
+	tokenTransferRepo repository.TokenTransferRepository
+	tokenBalanceRepo  repository.TokenBalanceRepository
+	governanceRepo    repository.GovernanceRepository
+	operatorRepo      repository.OperatorRepository
+	blockRepo         repository.BlockRepository
+
+	addressIndexer indexer.AddressIndexer
+}
+
+func NewService(
+	elastic elastic_search.Index,
+	txRepo repository.TransactionRepository,
+	contractRepo repository.ContractRepository,
+	nftRepo repository.NftRepository,
+	nftActionRepo repository.NftActionRepository,
+	checkpointRepo repository.CheckpointRepository,
+	tokenTransferRepo repository.TokenTransferRepository,
+	tokenBalanceRepo repository.TokenBalanceRepository,
+	governanceRepo repository.GovernanceRepository,
+	operatorRepo repository.OperatorRepository,
+	blockRepo repository.BlockRepository,
+	addressIndexer indexer.AddressIndexer,
+) Service {
+	return service{elastic, txRepo, contractRepo, nftRepo, nftActionRepo, checkpointRepo, tokenTransferRepo, tokenBalanceRepo, governanceRepo, operatorRepo, blockRepo, addressIndexer}
+}
+
+func (s service) RollbackTo(ctx context.Context, blockNum uint64) error {
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Rollback: Rolling back indices")
+
+	s.elastic.ClearRequests()
+
+	// Token supplies are reverted on their contracts, so before the contracts are purged
+	if err := s.rollbackTokens(ctx, blockNum); err != nil {
+		return err
+	}
+
+	if err := s.rollbackContracts(ctx, blockNum); err != nil {
+		return err
+	}
+
+	if err := s.rollbackNfts(ctx, blockNum); err != nil {
+		return err
+	}
+
+	if err := s.governanceRepo.PurgeAfterBlockNum(ctx, blockNum); err != nil {
+		return err
+	}
+
+	if err := s.rollbackOperators(ctx, blockNum); err != nil {
+		return err
+	}
+
+	// Address balances are reverted from the transactions, so before they are purged
+	if err := s.addressIndexer.Rollback(ctx, blockNum); err != nil {
+		return err
+	}
+
+	if err := s.txRepo.PurgeAfterBlockNum(ctx, blockNum); err != nil {
+		return err
+	}
+
+	if err := s.blockRepo.PurgeAfterBlockNum(ctx, blockNum); err != nil {
+		return err
+	}
+
+	if err := s.rollbackCheckpoints(ctx, blockNum); err != nil {
+		return err
+	}
+
+	zap.L().With(zap.Uint64("blockNum", blockNum)).Info("Rollback: Complete")
+
+	return nil
+}
+
+func (s service) rollbackContracts(ctx context.Context, blockNum uint64) error {
+	size := 100
+
+	for {
+		// Always read the first page, the previous page has been purged
+		contracts, _, err := s.contractRepo.GetContractsAfterBlockNum(ctx, blockNum, size, 1)
+		if err != nil {
+			return err
+		}
+		if len(contracts) == 0 {
+			break
+		}
+
+		for _, c := range contracts {
+			zap.L().With(zap.String("contract", c.Address), zap.Uint64("blockNum", c.BlockNum)).Info("Rollback: Purge contract")
+			if err := s.contractRepo.PurgeContract(ctx, c.Address); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// rollbackOperators purges the grants changed after the block, then restores those granted by it to their state at
+// the block from their history
+func (s service) rollbackOperators(ctx context.Context, blockNum uint64) error {
+	size := 1000
+
+	restored := make([]entity.Operator, 0)
+
+	var after *entity.Operator
+	for {
+		operators, err := s.operatorRepo.GetOperatorsAfterBlockNum(ctx, blockNum, after, size)
+		if err != nil {
+			return err
+		}
+		if len(operators) == 0 {
+			break
+		}
+
+		for _, operator := range operators {
+			history := make([]entity.OperatorChange, 0, len(operator.History))
+			for _, change := range operator.History {
+				if change.BlockNum <= blockNum {
+					history = append(history, change)
+				}
+			}
+			if len(history) == 0 {
+				continue
+			}
+
+			operator.History = history
+			operator.Active = history[len(history)-1].Active
+			operator.TxID = history[len(history)-1].TxID
+			operator.BlockNum = history[len(history)-1].BlockNum
+			restored = append(restored, operator)
+		}
+		after = &operators[len(operators)-1]
+	}
+
+	if err := s.operatorRepo.PurgeAfterBlockNum(ctx, blockNum); err != nil {
+		return err
+	}
+
+	for _, operator := range restored {
+		zap.L().With(zap.String("contract", operator.Contract), zap.String("owner", operator.Owner), zap.String("operator", operator.Operator)).
+			Info("Rollback: Restore operator")
+		s.elastic.AddIndexRequest(elastic_search.OperatorIndex.Get(), operator, elastic_search.Zrc6Operator)
+		s.elastic.BatchPersist()
+	}
+	s.elastic.Persist()
+
+	return nil
+}
+
+func (s service) rollbackCheckpoints(ctx context.Context, blockNum uint64) error {
+	for _, stage := range entity.Stages {
+		checkpoint, err := s.checkpointRepo.GetCheckpoint(ctx, stage)
+		if err != nil {
+			if err == repository.ErrCheckpointNotFound {
+				continue
+			}
+			return err
+		}
+
+		// Page cursors are meaningless once the documents they page over have changed
+		if checkpoint.BlockNum <= blockNum && checkpoint.Page == 0 {
+			continue
+		}
+
+		if checkpoint.BlockNum > blockNum {
+			checkpoint.BlockNum = blockNum
+		}
+
+		zap.L().With(zap.String("stage", string(stage)), zap.Uint64("blockNum", checkpoint.BlockNum)).Info("Rollback: Reset checkpoint")
+		s.elastic.AddIndexRequest(elastic_search.CheckpointIndex.Get(), factory.CreateCheckpoint(stage, checkpoint.BlockNum, 0), elastic_search.Checkpoint)
+	}
+	s.elastic.Persist()
+
+	return nil
+}
+
+func (s service) rollbackNfts(ctx context.Context, blockNum uint64) error {
+	if err := s.nftRepo.PurgeAfterBlockNum(ctx, blockNum); err != nil {
+		return err
+	}
+
+	affected, err := s.getAffectedNfts(ctx, blockNum)
+	if err != nil {
+		return err
+	}
+
+	zap.L().With(zap.Int("count", len(affected))).Info("Rollback: Reverting nfts")
+
+	for _, key := range affected {
+		nft, err := s.nftRepo.FindNft(ctx, key.contract, key.tokenId)
+		if err != nil {
+			if err == repository.ErrNftNotFound {
+				// Minted after the target height and already purged
+				continue
+			}
+			return err
+		}
+
+		actions, err := s.getNftActions(ctx, *nft)
+		if err != nil {
+			return err
+		}
+
+		revertNft(nft, actions, blockNum)
+
+		s.elastic.AddIndexRequest(elastic_search.NftIndex.Get(), *nft, elastic_search.NftRollback)
+		s.elastic.BatchPersist()
+	}
+	if err := s.resetNftStats(ctx, affected); err != nil {
+		return err
+	}
+	s.elastic.Persist()
+
+	return s.nftActionRepo.PurgeAfterBlockNum(ctx, blockNum)
+}
+
+// resetNftStats drops the stats of the contracts with reverted nfts, which are counted again on their next change
+func (s service) resetNftStats(ctx context.Context, affected []nftKey) error {
+	seen := map[string]struct{}{}
+	for _, key := range affected {
+		if _, ok := seen[key.contract]; ok {
+			continue
+		}
+		seen[key.contract] = struct{}{}
+
+		c, err := s.contractRepo.GetContractByAddress(ctx, key.contract)
+		if err != nil {
+			if err == repository.ErrContractNotFound {
+				continue
+			}
+			return err
+		}
+		if c.NftStats == nil {
+			continue
+		}
+
+		c.NftStats = nil
+		s.elastic.AddIndexRequest(elastic_search.ContractIndex.Get(), *c, elastic_search.NftRollback)
+	}
+
+	return nil
+}
+
+// rollbackTokens reverts the balances and supplies changed by the token transfers after the block, then purges them.
+// Each page of transfers is marked reverted as its balances are persisted, so a rollback which fails part way does
+// not revert them twice when run again.
+func (s service) rollbackTokens(ctx context.Context, blockNum uint64) error {
+	size := 1000
+
+	var after *entity.TokenTransfer
+	for {
+		transfers, err := s.tokenTransferRepo.GetTransfersToRevert(ctx, blockNum, after, size)
+		if err != nil {
+			return err
+		}
+		if len(transfers) == 0 {
+			break
+		}
+
+		for _, transfer := range transfers {
+			amount, ok := new(big.Int).SetString(transfer.Amount, 10)
+			if !ok {
+				continue
+			}
+
+			if transfer.From != "" {
+				if err := s.revertTokenBalance(ctx, transfer.Contract, transfer.From, blockNum, amount); err != nil {
+					return err
+				}
+			}
+			if transfer.To != "" {
+				if err := s.revertTokenBalance(ctx, transfer.Contract, transfer.To, blockNum, new(big.Int).Neg(amount)); err != nil {
+					return err
+				}
+			}
+
+			switch transfer.Action {
+			case entity.TokenMintAction:
+				if err := s.revertTokenSupply(ctx, transfer.Contract, new(big.Int).Neg(amount)); err != nil {
+					return err
+				}
+			case entity.TokenBurnAction:
+				if err := s.revertTokenSupply(ctx, transfer.Contract, amount); err != nil {
+					return err
+				}
+			}
+
+			transfer.Reverted = true
+			s.elastic.AddIndexRequest(elastic_search.TokenTransferIndex.Get(), transfer, elastic_search.Zrc2Rollback)
+		}
+		s.elastic.Persist()
+		after = &transfers[len(transfers)-1]
+	}
+
+	return s.tokenTransferRepo.PurgeAfterBlockNum(ctx, blockNum)
+}
+
+func (s service) revertTokenBalance(ctx context.Context, contract, holder string, blockNum uint64, amount *big.Int) error {
+	tokenBalance, err := s.tokenBalanceRepo.GetBalance(ctx, contract, holder)
+	if err != nil {
+		if err == repository.ErrTokenBalanceNotFound {
+			return nil
+		}
+		return err
+	}
+
+	balance, ok := new(big.Int).SetString(tokenBalance.Balance, 10)
+	if !ok {
+		balance = new(big.Int)
+	}
+	tokenBalance.Balance = balance.Add(balance, amount).String()
+	if tokenBalance.BlockNum > blockNum {
+		tokenBalance.BlockNum = blockNum
+	}
+
+	s.elastic.AddIndexRequest(elastic_search.TokenBalanceIndex.Get(), *tokenBalance, elastic_search.Zrc2Rollback)
+
+	return nil
+}
+
+func (s service) revertTokenSupply(ctx context.Context, contractAddr string, amount *big.Int) error {
+	c, err := s.contractRepo.GetContractByAddress(ctx, contractAddr)
+	if err != nil {
+		if err == repository.ErrContractNotFound {
+			return nil
+		}
+		return err
+	}
+	if c.Zrc2 == nil {
+		return nil
+	}
+
+	token := *c.Zrc2
+	supply, ok := new(big.Int).SetString(token.TotalSupply, 10)
+	if !ok {
+		supply = new(big.Int)
+	}
+	token.TotalSupply = supply.Add(supply, amount).String()
+	c.Zrc2 = &token
+
+	s.elastic.AddIndexRequest(elastic_search.ContractIndex.Get(), *c, elastic_search.Zrc2Rollback)
+
+	return nil
+}
+
+type nftKey struct {
+	contract string
+	tokenId  uint64
+}
+
+func (s service) getAffectedNfts(ctx context.Context, blockNum uint64) ([]nftKey, error) {
+	size := 1000
+
+	seen := map[nftKey]struct{}{}
+	keys := make([]nftKey, 0)
+
+	var after *entity.NftAction
+	for {
+		actions, err := s.nftActionRepo.GetActionsAfterBlockNum(ctx, blockNum, after, size)
+		if err != nil {
+			return nil, err
+		}
+		if len(actions) == 0 {
+			break
+		}
+
+		for _, action := range actions {
+			key := nftKey{action.Contract, action.TokenId}
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				keys = append(keys, key)
+			}
+		}
+		after = &actions[len(actions)-1]
+	}
+
+	return keys, nil
+}
+
+// getNftActions returns the whole action log of the nft, in the order the actions were made
+func (s service) getNftActions(ctx context.Context, nft entity.Nft) ([]entity.NftAction, error) {
+	size := 1000
+
+	log := make([]entity.NftAction, 0)
+
+	var after *entity.NftAction
+	for {
+		actions, err := s.nftActionRepo.GetNftActions(ctx, nft, after, size)
+		if err != nil {
+			return nil, err
+		}
+		if len(actions) == 0 {
+			break
+		}
+
+		log = append(log, actions...)
+		after = &log[len(log)-1]
+	}
+
+	return log, nil
+}
+
+// revertNft rebuilds the nft as it was at the block from its action log. The owner, burn, delegation and spender are
+// replayed from the mint up to the block. The uris are set at the mint without an action, so are taken from the
+// first change after the block, and the metadata is fetched again when they differ.
+func revertNft(nft *entity.Nft, actions []entity.NftAction, blockNum uint64) {
+	delegatedOwner := nft.DelegatedOwner
+
+	nft.BurnedAt = 0
+	nft.IsDelegated = false
+	nft.DelegatedOwner = ""
+	nft.Spender = ""
+
+	tokenUri, baseUri := nft.TokenUri, nft.BaseUri
+	uriReverted := map[entity.ActionType]bool{}
+
+	for _, action := range actions {
+		if action.BlockNum > blockNum {
+			if uriReverted[action.Action] {
+				continue
+			}
+			switch action.Action {
+			case entity.TokenUriAction:
+				tokenUri = action.From
+				uriReverted[action.Action] = true
+			case entity.BaseUriAction:
+				baseUri = action.From
+				uriReverted[action.Action] = true
+			}
+			continue
+		}
+
+		switch action.Action {
+		case entity.MintAction, entity.TransferAction, entity.ReconcileAction:
+			nft.Owner = action.To
+			nft.IsDelegated = false
+			nft.DelegatedOwner = ""
+			nft.Spender = ""
+		case entity.BurnAction:
+			nft.BurnedAt = action.BlockNum
+			nft.Spender = ""
+		case entity.DelegateAction:
+			nft.IsDelegated = action.To != ""
+			nft.DelegatedOwner = action.To
+			nft.Spender = ""
+		case entity.SpenderAction:
+			nft.Spender = action.To
+		case entity.MarketplaceListingAction:
+			nft.IsDelegated = true
+		case entity.MarketplaceDelistingAction, entity.MarketplaceSaleAction:
+			nft.IsDelegated = false
+			nft.DelegatedOwner = ""
+		}
+	}
+
+	// Listings indexed before delegations were recorded keep the marketplace the nft was delegated to
+	if nft.IsDelegated && nft.DelegatedOwner == "" {
+		nft.DelegatedOwner = delegatedOwner
+	}
+
+	if tokenUri != nft.TokenUri || baseUri != nft.BaseUri {
+		nft.TokenUri = tokenUri
+		nft.BaseUri = baseUri
+		if nft.Metadata != nil {
+			nft.Metadata.Uri = factory.GetMetadataUri(*nft)
+			nft.Metadata.IsIpfs = helper.IsIpfs(nft.Metadata.Uri)
+			nft.Metadata.Status = entity.MetadataPending
+			nft.Metadata.Error = ""
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package scilla
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Contract is the outline of a Scilla source file. Expressions and statements are not kept, only the declarations
+// and the events the code emits.
+type Contract struct {
+	Version     int
+	Imports     []string
+	Library     *Library
+	Name        string
+	Params      []Param
+	Fields      []Field
+	Procedures  []Component
+	Transitions []Component
+	Events      []Event
+}
+
+type Library struct {
+	Name  string
+	Lets  []string
+	Types []TypeDef
+	// Values holds the tokens of the expression bound by each let
+	Values map[string][]Token
+}
+
+// TypeDef is a user defined ADT of the library
+type TypeDef struct {
+	Name         string
+	Constructors []Constructor
+}
+
+type Constructor struct {
+	Name string
+	Args []Type
+}
+
+type Param struct {
+	Name string
+	Type Type
+}
+
+// Field is a mutable field of the contract, or a field of an address type which has no Value
+type Field struct {
+	Name  string
+	Type  Type
+	Value []Token
+}
+
+// Component is a transition or a procedure
+type Component struct {
+	Name   string
+	Params []Param
+}
+
+// Event is a record with an _eventname built by the code, with the names of its other entries. Component is empty
+// for events built in the library.
+type Event struct {
+	Name      string
+	Params    []string
+	Component string
+}
+
+// maxLetDepth bounds how many lets Literal follows, a let may rebind its own name
+const maxLetDepth = 16
+
+func (c Contract) GetField(name string) (Field, bool) {
+	for _, field := range c.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return Field{}, false
+}
+
+// Literal resolves an expression to the literal it evaluates to, e.g. 1000 for Uint128 1000, following the library
+// lets and the contract params, whose values are given by params. Expressions computed when the contract runs, such as
+// a builtin call, do not resolve.
+func (c Contract) Literal(expr []Token, params map[string]string) (string, bool) {
+	return c.literal(expr, params, 0)
+}
+
+func (c Contract) literal(expr []Token, params map[string]string, depth int) (string, bool) {
+	if depth > maxLetDepth {
+		return "", false
+	}
+	for len(expr) > 2 && expr[0].Is(Symbol, "(") && expr[len(expr)-1].Is(Symbol, ")") {
+		expr = expr[1 : len(expr)-1]
+	}
+
+	switch {
+	case len(expr) == 1 && isLiteral(expr[0]):
+		return expr[0].Value, true
+
+	case len(expr) == 2 && expr[0].Kind == Ident && isLiteral(expr[1]):
+		return expr[1].Value, true
+
+	case len(expr) == 1 && expr[0].Kind == Ident:
+		for _, param := range c.Params {
+			if param.Name == expr[0].Value {
+				value, ok := params[param.Name]
+				return value, ok
+			}
+		}
+		if c.Library != nil {
+			if value, ok := c.Library.Values[expr[0].Value]; ok {
+				return c.literal(value, params, depth+1)
+			}
+		}
+	}
+
+	return "", false
+}
+
+func isLiteral(t Token) bool {
+	return t.Kind == Number || t.Kind == String || t.Kind == Hex
+}
+
+type Type interface {
+	String() string
+}
+
+// TypeApp is a type constructor applied to its arguments, e.g. Map ByStr20 Uint128
+type TypeApp struct {
+	Name string
+	Args []Type
+}
+
+func (t TypeApp) String() string {
+	parts := []string{t.Name}
+	for _, arg := range t.Args {
+		parts = append(parts, argString(arg))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// AddressType is an address with the fields its contract must have, e.g. ByStr20 with contract field f : T end
+type AddressType struct {
+	Base     string
+	Contract bool
+	Params   []Param
+	Fields   []Field
+}
+
+func (t AddressType) String() string {
+	if !t.Contract {
+		return t.Base + " with end"
+	}
+
+	s := t.Base + " with contract "
+	if len(t.Params) != 0 {
+		params := make([]string, 0, len(t.Params))
+		for _, param := range t.Params {
+			params = append(params, fmt.Sprintf("%s : %s", param.Name, param.Type))
+		}
+		s += "(" + strings.Join(params, ", ") + ") "
+	}
+
+	fields := make([]string, 0, len(t.Fields))
+	for _, field := range t.Fields {
+		fields = append(fields, fmt.Sprintf("field %s : %s", field.Name, field.Type))
+	}
+	if len(fields) != 0 {
+		s += strings.Join(fields, ", ") + " "
+	}
+
+	return s + "end"
+}
+
+type FunType struct {
+	Arg Type
+	Ret Type
+}
+
+func (t FunType) String() string {
+	return argString(t.Arg) + " -> " + t.Ret.String()
+}
+
+type PolyType struct {
+	Var  string
+	Body Type
+}
+
+func (t PolyType) String() string {
+	return fmt.Sprintf("forall %s. %s", t.Var, t.Body)
+}
+
+type TypeVarType struct {
+	Name string
+}
+
+func (t TypeVarType) String() string {
+	return t.Name
+}
+
+// argString parenthesises a type used as the argument of another
+func argString(t Type) string {
+	if app, ok := t.(TypeApp); ok && len(app.Args) == 0 {
+		return app.String()
+	}
+	if _, ok := t.(TypeVarType); ok {
+		return t.String()
+	}
+
+	return "(" + t.String() + ")"
+}
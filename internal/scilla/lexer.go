@@ -0,0 +1,158 @@
+package scilla
+
+import (
+	"fmt"
+	"unicode"
+)
+
+type TokenKind int
+
+const (
+	EOF TokenKind = iota
+	Ident
+	TypeVar
+	Number
+	Hex
+	String
+	Symbol
+)
+
+type Token struct {
+	Kind  TokenKind
+	Value string
+	Line  int
+}
+
+func (t Token) Is(kind TokenKind, value string) bool {
+	return t.Kind == kind && t.Value == value
+}
+
+func (t Token) String() string {
+	if t.Kind == EOF {
+		return "end of file"
+	}
+	return fmt.Sprintf("%q", t.Value)
+}
+
+// Multi character symbols, longest first
+var symbols = []string{"<-&", "=>", "<-", ":=", "->"}
+
+// Lex splits Scilla source into tokens, dropping whitespace and (nested) comments
+func Lex(code string) ([]Token, error) {
+	src := []rune(code)
+	tokens := make([]Token, 0)
+	line := 1
+
+	for pos := 0; pos < len(src); {
+		r := src[pos]
+
+		switch {
+		case r == '\n':
+			line++
+			pos++
+
+		case unicode.IsSpace(r):
+			pos++
+
+		case r == '(' && pos+1 < len(src) && src[pos+1] == '*':
+			start := line
+			depth := 0
+			for ; pos < len(src); pos++ {
+				if src[pos] == '\n' {
+					line++
+				} else if src[pos] == '(' && pos+1 < len(src) && src[pos+1] == '*' {
+					depth++
+					pos++
+				} else if src[pos] == '*' && pos+1 < len(src) && src[pos+1] == ')' {
+					depth--
+					pos++
+					if depth == 0 {
+						pos++
+						break
+					}
+				}
+			}
+			if depth != 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", start)
+			}
+
+		case r == '"':
+			start := pos
+			for pos++; pos < len(src) && src[pos] != '"'; pos++ {
+				if src[pos] == '\\' {
+					pos++
+				} else if src[pos] == '\n' {
+					line++
+				}
+			}
+			if pos >= len(src) {
+				return nil, fmt.Errorf("line %d: unterminated string", line)
+			}
+			pos++
+			tokens = append(tokens, Token{String, string(src[start+1 : pos-1]), line})
+
+		case r == '0' && pos+1 < len(src) && (src[pos+1] == 'x' || src[pos+1] == 'X'):
+			start := pos
+			for pos += 2; pos < len(src) && isHexDigit(src[pos]); pos++ {
+			}
+			tokens = append(tokens, Token{Hex, string(src[start:pos]), line})
+
+		case unicode.IsDigit(r) || (r == '-' && pos+1 < len(src) && unicode.IsDigit(src[pos+1])):
+			start := pos
+			for pos++; pos < len(src) && unicode.IsDigit(src[pos]); pos++ {
+			}
+			tokens = append(tokens, Token{Number, string(src[start:pos]), line})
+
+		case r == '\'' && pos+1 < len(src) && isIdentStart(src[pos+1]):
+			start := pos
+			for pos++; pos < len(src) && isIdentPart(src[pos]); pos++ {
+			}
+			tokens = append(tokens, Token{TypeVar, string(src[start:pos]), line})
+
+		case isIdentStart(r):
+			start := pos
+			for pos++; pos < len(src) && isIdentPart(src[pos]); pos++ {
+			}
+			tokens = append(tokens, Token{Ident, string(src[start:pos]), line})
+
+		default:
+			symbol := string(r)
+			for _, s := range symbols {
+				if hasPrefix(src[pos:], s) {
+					symbol = s
+					break
+				}
+			}
+			pos += len([]rune(symbol))
+			tokens = append(tokens, Token{Symbol, symbol, line})
+		}
+	}
+
+	return append(tokens, Token{EOF, "", line}), nil
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isHexDigit(r rune) bool {
+	return unicode.IsDigit(r) || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')
+}
+
+func hasPrefix(src []rune, prefix string) bool {
+	runes := []rune(prefix)
+	if len(src) < len(runes) {
+		return false
+	}
+	for i, r := range runes {
+		if src[i] != r {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,585 @@
+package scilla
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var keywords = map[string]bool{
+	"scilla_version": true, "import": true, "library": true, "let": true, "in": true, "type": true, "of": true,
+	"contract": true, "field": true, "procedure": true, "transition": true, "end": true, "match": true,
+	"with": true, "fun": true, "tfun": true, "builtin": true, "send": true, "event": true, "throw": true,
+	"accept": true, "forall": true, "as": true, "delete": true, "exists": true,
+}
+
+type parser struct {
+	tokens    []Token
+	pos       int
+	contract  *Contract
+	component string
+}
+
+// Parse reads the declarations of a Scilla contract. Expressions and statements are skipped over, noting the
+// events they build.
+func Parse(code string) (*Contract, error) {
+	tokens, err := Lex(code)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, contract: &Contract{}}
+	if err := p.parseFile(); err != nil {
+		return nil, err
+	}
+
+	return p.contract, nil
+}
+
+func (p *parser) parseFile() error {
+	if p.accept(Ident, "scilla_version") {
+		t, err := p.expect(Number, "")
+		if err != nil {
+			return err
+		}
+		p.contract.Version, _ = strconv.Atoi(t.Value)
+	}
+
+	for p.accept(Ident, "import") {
+		for p.peek().Kind == Ident && !keywords[p.peek().Value] {
+			p.contract.Imports = append(p.contract.Imports, p.next().Value)
+			if p.accept(Ident, "as") {
+				if _, err := p.expect(Ident, ""); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if p.accept(Ident, "library") {
+		if err := p.parseLibrary(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := p.expect(Ident, "contract"); err != nil {
+		return err
+	}
+	name, err := p.expect(Ident, "")
+	if err != nil {
+		return err
+	}
+	p.contract.Name = name.Value
+
+	if p.contract.Params, err = p.parseParams(); err != nil {
+		return err
+	}
+
+	if p.accept(Ident, "with") {
+		if err := p.skipConstraint(); err != nil {
+			return err
+		}
+	}
+
+	for p.peek().Kind != EOF {
+		t := p.next()
+		switch {
+		case t.Is(Ident, "field"):
+			if err := p.parseField(); err != nil {
+				return err
+			}
+		case t.Is(Ident, "procedure"):
+			procedure, err := p.parseComponent()
+			if err != nil {
+				return err
+			}
+			p.contract.Procedures = append(p.contract.Procedures, *procedure)
+		case t.Is(Ident, "transition"):
+			transition, err := p.parseComponent()
+			if err != nil {
+				return err
+			}
+			p.contract.Transitions = append(p.contract.Transitions, *transition)
+		default:
+			return p.errorf(t, "expected field, procedure or transition, found %s", t)
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) parseLibrary() error {
+	name, err := p.expect(Ident, "")
+	if err != nil {
+		return err
+	}
+	p.contract.Library = &Library{Name: name.Value, Values: map[string][]Token{}}
+
+	for {
+		switch {
+		case p.accept(Ident, "let"):
+			name, err := p.expect(Ident, "")
+			if err != nil {
+				return err
+			}
+			p.contract.Library.Lets = append(p.contract.Library.Lets, name.Value)
+
+			if p.accept(Symbol, ":") {
+				if _, err := p.parseType(); err != nil {
+					return err
+				}
+			}
+			if _, err := p.expect(Symbol, "="); err != nil {
+				return err
+			}
+			start := p.pos
+			if err := p.skip(p.endsLibraryEntry); err != nil {
+				return err
+			}
+			p.contract.Library.Values[name.Value] = p.tokens[start:p.pos]
+
+		case p.accept(Ident, "type"):
+			typeDef, err := p.parseTypeDef()
+			if err != nil {
+				return err
+			}
+			p.contract.Library.Types = append(p.contract.Library.Types, *typeDef)
+
+		default:
+			return nil
+		}
+	}
+}
+
+// endsLibraryEntry is whether a token starts the next library entry. A let continuing an expression follows one of
+// the tokens an expression cannot end with.
+func (p *parser) endsLibraryEntry(t Token) bool {
+	if t.Is(Ident, "type") || t.Is(Ident, "contract") {
+		return true
+	}
+	if !t.Is(Ident, "let") {
+		return false
+	}
+
+	prev := p.tokens[p.pos-1]
+	return !(prev.Is(Ident, "in") || prev.Is(Symbol, "=") || prev.Is(Symbol, "=>") || prev.Is(Symbol, "("))
+}
+
+func (p *parser) parseTypeDef() (*TypeDef, error) {
+	name, err := p.expect(Ident, "")
+	if err != nil {
+		return nil, err
+	}
+	typeDef := &TypeDef{Name: name.Value}
+
+	if _, err := p.expect(Symbol, "="); err != nil {
+		return nil, err
+	}
+
+	for p.accept(Symbol, "|") {
+		name, err := p.expect(Ident, "")
+		if err != nil {
+			return nil, err
+		}
+		constructor := Constructor{Name: name.Value}
+
+		if p.accept(Ident, "of") {
+			for p.isAtomTypeStart(p.peek()) {
+				arg, err := p.parseAtomType()
+				if err != nil {
+					return nil, err
+				}
+				constructor.Args = append(constructor.Args, arg)
+			}
+		}
+		typeDef.Constructors = append(typeDef.Constructors, constructor)
+	}
+
+	return typeDef, nil
+}
+
+func (p *parser) parseField() error {
+	name, err := p.expect(Ident, "")
+	if err != nil {
+		return err
+	}
+	if _, err := p.expect(Symbol, ":"); err != nil {
+		return err
+	}
+	fieldType, err := p.parseType()
+	if err != nil {
+		return err
+	}
+
+	if _, err := p.expect(Symbol, "="); err != nil {
+		return err
+	}
+
+	start := p.pos
+	err = p.skip(func(t Token) bool {
+		return t.Is(Ident, "field") || t.Is(Ident, "procedure") || t.Is(Ident, "transition")
+	})
+	if err != nil {
+		return err
+	}
+	p.contract.Fields = append(p.contract.Fields, Field{Name: name.Value, Type: fieldType, Value: p.tokens[start:p.pos]})
+
+	return nil
+}
+
+func (p *parser) parseComponent() (*Component, error) {
+	name, err := p.expect(Ident, "")
+	if err != nil {
+		return nil, err
+	}
+	component := &Component{Name: name.Value}
+
+	if component.Params, err = p.parseParams(); err != nil {
+		return nil, err
+	}
+
+	p.component = component.Name
+	defer func() { p.component = "" }()
+
+	if err := p.skip(func(t Token) bool { return t.Is(Ident, "end") }); err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(Ident, "end"); err != nil {
+		return nil, err
+	}
+
+	return component, nil
+}
+
+func (p *parser) parseParams() ([]Param, error) {
+	params := make([]Param, 0)
+
+	if _, err := p.expect(Symbol, "("); err != nil {
+		return nil, err
+	}
+	if p.accept(Symbol, ")") {
+		return params, nil
+	}
+
+	for {
+		name, err := p.expect(Ident, "")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(Symbol, ":"); err != nil {
+			return nil, err
+		}
+		paramType, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, Param{Name: name.Value, Type: paramType})
+
+		if !p.accept(Symbol, ",") {
+			break
+		}
+	}
+
+	if _, err := p.expect(Symbol, ")"); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+func (p *parser) parseType() (Type, error) {
+	if p.accept(Ident, "forall") {
+		typeVar, err := p.expect(TypeVar, "")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(Symbol, "."); err != nil {
+			return nil, err
+		}
+		body, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		return PolyType{Var: typeVar.Value, Body: body}, nil
+	}
+
+	var t Type
+	var err error
+	if next := p.peek(); next.Kind == Ident && !keywords[next.Value] && !p.peekAt(1).Is(Ident, "with") {
+		p.next()
+		app := TypeApp{Name: next.Value}
+		for p.isAtomTypeStart(p.peek()) {
+			arg, err := p.parseAtomType()
+			if err != nil {
+				return nil, err
+			}
+			app.Args = append(app.Args, arg)
+		}
+		t = app
+	} else if t, err = p.parseAtomType(); err != nil {
+		return nil, err
+	}
+
+	if p.accept(Symbol, "->") {
+		ret, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		return FunType{Arg: t, Ret: ret}, nil
+	}
+
+	return t, nil
+}
+
+func (p *parser) isAtomTypeStart(t Token) bool {
+	return t.Kind == TypeVar || t.Is(Symbol, "(") || (t.Kind == Ident && !keywords[t.Value])
+}
+
+func (p *parser) parseAtomType() (Type, error) {
+	t := p.next()
+
+	switch {
+	case t.Kind == TypeVar:
+		return TypeVarType{Name: t.Value}, nil
+	case t.Is(Symbol, "("):
+		inner, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(Symbol, ")"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	case t.Kind == Ident && !keywords[t.Value]:
+		if p.accept(Ident, "with") {
+			return p.parseAddressType(t.Value)
+		}
+		return TypeApp{Name: t.Value}, nil
+	}
+
+	return nil, p.errorf(t, "expected a type, found %s", t)
+}
+
+// parseAddressType reads the constraints of an address type, following its with
+func (p *parser) parseAddressType(base string) (Type, error) {
+	address := AddressType{Base: base}
+	if p.accept(Ident, "end") {
+		return address, nil
+	}
+
+	if _, err := p.expect(Ident, "contract"); err != nil {
+		return nil, err
+	}
+	address.Contract = true
+
+	if p.peek().Is(Symbol, "(") {
+		params, err := p.parseParams()
+		if err != nil {
+			return nil, err
+		}
+		address.Params = params
+	}
+
+	for p.accept(Ident, "field") {
+		name, err := p.expect(Ident, "")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(Symbol, ":"); err != nil {
+			return nil, err
+		}
+		fieldType, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		address.Fields = append(address.Fields, Field{Name: name.Value, Type: fieldType})
+
+		if !p.accept(Symbol, ",") {
+			break
+		}
+	}
+
+	if _, err := p.expect(Ident, "end"); err != nil {
+		return nil, err
+	}
+
+	return address, nil
+}
+
+// skip consumes an expression or a list of statements until stop holds for a token outside any brackets or match,
+// noting the events built on the way. Braces hold either a record or the type arguments of an instantiation.
+func (p *parser) skip(stop func(t Token) bool) error {
+	depth := 0
+	matches := 0
+
+	for {
+		t := p.peek()
+		if t.Kind == EOF {
+			if depth != 0 || matches != 0 {
+				return p.errorf(t, "unexpected end of file")
+			}
+			return nil
+		}
+		if depth == 0 && matches == 0 && stop(t) {
+			return nil
+		}
+
+		switch {
+		case t.Is(Symbol, "{") && p.peekAt(1).Kind == Ident && p.peekAt(2).Is(Symbol, ":"):
+			if err := p.parseRecord(); err != nil {
+				return err
+			}
+			continue
+		case t.Kind == Ident && strings.HasPrefix(t.Value, "ByStr") && p.peekAt(1).Is(Ident, "with"):
+			if _, err := p.parseAtomType(); err != nil {
+				return err
+			}
+			continue
+		case t.Is(Symbol, "(") || t.Is(Symbol, "{"):
+			depth++
+		case t.Is(Symbol, ")") || t.Is(Symbol, "}"):
+			if depth--; depth < 0 {
+				return p.errorf(t, "unbalanced %s", t)
+			}
+		case t.Is(Ident, "match"):
+			matches++
+		case t.Is(Ident, "end"):
+			if matches--; matches < 0 {
+				return p.errorf(t, "unexpected end")
+			}
+		}
+		p.next()
+	}
+}
+
+// skipConstraint consumes the constraint of the contract and the => closing it. The constraint is an expression, so
+// the arrows of the functions it binds with let ... in are passed over, while skip keeps the arrows of match arms
+// out of reach of the stop.
+func (p *parser) skipConstraint() error {
+	lets, funs := 0, 0
+	err := p.skip(func(t Token) bool {
+		switch {
+		case t.Is(Ident, "let"):
+			lets++
+		case t.Is(Ident, "in"):
+			lets--
+		case t.Is(Ident, "fun") || t.Is(Ident, "tfun"):
+			funs++
+		case t.Is(Symbol, "=>"):
+			if funs > 0 {
+				funs--
+				return false
+			}
+			return lets == 0
+		}
+		return false
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = p.expect(Symbol, "=>")
+	return err
+}
+
+// parseRecord reads a record literal, adding it to the events when it has an _eventname
+func (p *parser) parseRecord() error {
+	p.next()
+
+	name := ""
+	params := make([]string, 0)
+	for !p.accept(Symbol, "}") {
+		key, err := p.expect(Ident, "")
+		if err != nil {
+			return err
+		}
+		if _, err := p.expect(Symbol, ":"); err != nil {
+			return err
+		}
+
+		start := p.pos
+		depth := 0
+		for t := p.peek(); depth > 0 || !(t.Is(Symbol, ";") || t.Is(Symbol, "}")); t = p.peek() {
+			if t.Kind == EOF {
+				return p.errorf(t, "unterminated record")
+			}
+			if t.Is(Symbol, "(") || t.Is(Symbol, "{") {
+				depth++
+			} else if t.Is(Symbol, ")") || t.Is(Symbol, "}") {
+				depth--
+			}
+			p.next()
+		}
+		value := p.tokens[start:p.pos]
+
+		if key.Value == "_eventname" {
+			if len(value) == 1 && value[0].Kind == String {
+				name = value[0].Value
+			}
+		} else {
+			params = append(params, key.Value)
+		}
+		p.accept(Symbol, ";")
+	}
+
+	if name != "" {
+		p.addEvent(Event{Name: name, Params: params, Component: p.component})
+	}
+
+	return nil
+}
+
+func (p *parser) addEvent(event Event) {
+	for _, e := range p.contract.Events {
+		if e.Name == event.Name && e.Component == event.Component && strings.Join(e.Params, ",") == strings.Join(event.Params, ",") {
+			return
+		}
+	}
+	p.contract.Events = append(p.contract.Events, event)
+}
+
+func (p *parser) peek() Token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) peekAt(n int) Token {
+	if p.pos+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+n]
+}
+
+func (p *parser) next() Token {
+	t := p.tokens[p.pos]
+	if t.Kind != EOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) accept(kind TokenKind, value string) bool {
+	if p.peek().Is(kind, value) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+// expect consumes the next token, which must be of the kind and, unless empty, the value
+func (p *parser) expect(kind TokenKind, value string) (Token, error) {
+	t := p.peek()
+	if t.Kind != kind || (value != "" && t.Value != value) || (kind == Ident && value == "" && keywords[t.Value]) {
+		if value == "" {
+			value = [...]string{"end of file", "an identifier", "a type variable", "a number", "a hex", "a string", "a symbol"}[kind]
+		} else {
+			value = strconv.Quote(value)
+		}
+		return t, p.errorf(t, "expected %s, found %s", value, t)
+	}
+
+	return p.next(), nil
+}
+
+func (p *parser) errorf(t Token, format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", t.Line, fmt.Sprintf(format, args...))
+}
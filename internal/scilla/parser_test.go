@@ -0,0 +1,194 @@
+package scilla
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func parseZrc6(t *testing.T) *Contract {
+	t.Helper()
+
+	code, err := ioutil.ReadFile("testdata/zrc6.scilla")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Parse(string(code))
+	if err != nil {
+		t.Fatalf("parsing the ZRC6 reference contract: %v", err)
+	}
+
+	return c
+}
+
+func TestParseZrc6Contract(t *testing.T) {
+	c := parseZrc6(t)
+
+	if c.Name != "ZRC6" || c.Version != 0 || strings.Join(c.Imports, " ") != "BoolUtils ListUtils IntUtils" {
+		t.Errorf("contract %s version %d importing %v, expected ZRC6 version 0", c.Name, c.Version, c.Imports)
+	}
+
+	assertParams(t, "contract", c.Params, "initial_contract_owner : ByStr20", "initial_base_uri : String", "name : String", "symbol : String")
+
+	// The fields follow the constraint, whose lambda and match arrows do not close it
+	if len(c.Fields) != 16 || c.Fields[0].Name != "is_paused" || c.Fields[15].Name != "tmp_token_id" {
+		t.Errorf("%d fields from %v, expected 16 from is_paused", len(c.Fields), c.Fields)
+	}
+}
+
+func TestParseZrc6Library(t *testing.T) {
+	c := parseZrc6(t)
+
+	if c.Library == nil || c.Library.Name != "ZRC6" {
+		t.Fatalf("library %+v, expected ZRC6", c.Library)
+	}
+	for _, let := range []string{"one_msg", "two_msgs", "get_bal", "make_error", "zero_address", "max_fee_bps", "portion", "build_pair"} {
+		if _, ok := c.Library.Values[let]; !ok {
+			t.Errorf("library let %s not parsed", let)
+		}
+	}
+
+	types := map[string]int{}
+	for _, typeDef := range c.Library.Types {
+		types[typeDef.Name] = len(typeDef.Constructors)
+	}
+	if types["Error"] != 18 || types["Dummy"] != 1 {
+		t.Errorf("library types %v, expected Error with 18 constructors and Dummy with 1", types)
+	}
+}
+
+func TestParseZrc6Types(t *testing.T) {
+	c := parseZrc6(t)
+
+	for name, expected := range map[string]string{
+		"royalty_fee_bps": "Uint128",
+		"token_uris":      "Map Uint256 String",
+		"minters":         "Map ByStr20 Dummy",
+		"operators":       "Map ByStr20 (Map ByStr20 Dummy)",
+	} {
+		field, ok := c.GetField(name)
+		if !ok {
+			t.Errorf("field %s not parsed", name)
+			continue
+		}
+		if field.Type.String() != expected {
+			t.Errorf("field %s of type %s, expected %s", name, field.Type, expected)
+		}
+	}
+
+	field, _ := c.GetField("royalty_fee_bps")
+	if fee, ok := c.Literal(field.Value, nil); !ok || fee != "1000" {
+		t.Errorf("royalty_fee_bps initialised to %q, expected 1000", fee)
+	}
+}
+
+func TestParseZrc6Components(t *testing.T) {
+	c := parseZrc6(t)
+
+	transitions := components(c.Transitions)
+	for name, params := range map[string][]string{
+		"Pause":                   nil,
+		"SetRoyaltyFeeBPS":        {"fee_bps : Uint128"},
+		"Mint":                    {"to : ByStr20", "token_uri : String"},
+		"BatchMint":               {"to_token_uri_pair_list : List (Pair ByStr20 String)"},
+		"TransferFrom":            {"to : ByStr20 with end", "token_id : Uint256"},
+		"BatchTransferFrom":       {"to_token_id_pair_list : List (Pair ByStr20 Uint256)"},
+		"AcceptContractOwnership": nil,
+	} {
+		transition, ok := transitions[name]
+		if !ok {
+			t.Errorf("transition %s not parsed", name)
+			continue
+		}
+		assertParams(t, name, transition.Params, params...)
+	}
+	if len(c.Transitions) != 18 {
+		t.Errorf("%d transitions, expected 18", len(c.Transitions))
+	}
+
+	procedures := components(c.Procedures)
+	for name, params := range map[string][]string{
+		"Throw":                   {"error : Error"},
+		"RequireAccessToTransfer": {"token_owner : ByStr20", "token_id : Uint256"},
+		"HandleMint":              {"to_token_uri_pair : Pair ByStr20 String"},
+		"TransferToken":           {"to : ByStr20", "token_id : Uint256"},
+	} {
+		procedure, ok := procedures[name]
+		if !ok {
+			t.Errorf("procedure %s not parsed", name)
+			continue
+		}
+		assertParams(t, name, procedure.Params, params...)
+	}
+	if len(c.Procedures) != 18 {
+		t.Errorf("%d procedures, expected 18", len(c.Procedures))
+	}
+}
+
+func TestParseZrc6Events(t *testing.T) {
+	c := parseZrc6(t)
+
+	events := map[string]Event{}
+	for _, event := range c.Events {
+		events[event.Name] = event
+	}
+
+	for name, expected := range map[string]Event{
+		"Mint":             {Params: []string{"to", "token_id", "token_uri"}, Component: "Mint"},
+		"BatchMint":        {Params: []string{"to_token_uri_pair_list", "start_id", "end_id"}, Component: "BatchMint"},
+		"Burn":             {Params: []string{"token_owner", "token_id"}, Component: "BurnToken"},
+		"TransferFrom":     {Params: []string{"from", "to", "token_id"}, Component: "TransferToken"},
+		"SetRoyaltyFeeBPS": {Params: []string{"royalty_fee_bps"}, Component: "SetRoyaltyFeeBPS"},
+	} {
+		event, ok := events[name]
+		if !ok {
+			t.Errorf("event %s not parsed", name)
+			continue
+		}
+		if strings.Join(event.Params, ",") != strings.Join(expected.Params, ",") || event.Component != expected.Component {
+			t.Errorf("event %s with %v in %s, expected %v in %s", name, event.Params, event.Component, expected.Params, expected.Component)
+		}
+	}
+}
+
+func TestParseConstraint(t *testing.T) {
+	for name, constraint := range map[string]string{
+		"none":   "",
+		"plain":  "with let x = builtin eq a a in negb x =>",
+		"lambda": "with let f = fun (s : String) => let n = builtin strlen s in n in let y = f a in True =>",
+		"match":  "with match a with | True => False | False => True end =>",
+		"nested": "with let f = fun (s : String) => fun (t : String) => match s with | _ => True end in (fun (u : Bool) => u) True =>",
+		"tfun":   "with let id = tfun 'A => fun (x : 'A) => x in let b = @id Bool in b True =>",
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, err := Parse("scilla_version 0\ncontract C (a : Bool)\n" + constraint + "\nfield f : Bool = True\ntransition T()\nend\n")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(c.Fields) != 1 || len(c.Transitions) != 1 {
+				t.Errorf("fields %v and transitions %v, expected f and T", c.Fields, c.Transitions)
+			}
+		})
+	}
+}
+
+func components(list []Component) map[string]Component {
+	byName := make(map[string]Component, len(list))
+	for _, component := range list {
+		byName[component.Name] = component
+	}
+	return byName
+}
+
+func assertParams(t *testing.T, name string, params []Param, expected ...string) {
+	t.Helper()
+
+	got := make([]string, 0, len(params))
+	for _, param := range params {
+		got = append(got, param.Name+" : "+param.Type.String())
+	}
+	if strings.Join(got, ", ") != strings.Join(expected, ", ") {
+		t.Errorf("%s params %v, expected %v", name, got, expected)
+	}
+}
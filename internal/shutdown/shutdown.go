@@ -0,0 +1,67 @@
+package shutdown
+
+import (
+	"context"
+	"github.com/ZilDuck/zilliqa-chain-indexer/internal/config"
+	"go.uber.org/zap"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Notify returns a context that is cancelled on SIGINT or SIGTERM
+func Notify() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+func Timeout() time.Duration {
+	return time.Duration(config.Get().ShutdownTimeout) * time.Second
+}
+
+// Grace returns a context that outlives ctx by half the shutdown timeout, so work in flight when ctx is cancelled
+// can finish and still leave time to persist it.
+func Grace(ctx context.Context) (context.Context, context.CancelFunc) {
+	grace, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-grace.Done():
+			return
+		}
+
+		select {
+		case <-time.After(Timeout() / 2):
+			cancel()
+		case <-grace.Done():
+		}
+	}()
+
+	return grace, cancel
+}
+
+// Run calls fn and returns once it has. After ctx is cancelled fn has the shutdown timeout to return before the
+// process exits regardless.
+func Run(ctx context.Context, fn func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+
+	select {
+	case <-done:
+		return
+	case <-ctx.Done():
+	}
+
+	zap.L().With(zap.Duration("timeout", Timeout())).Info("Shutting down")
+
+	select {
+	case <-done:
+		zap.L().Info("Shutdown complete")
+	case <-time.After(Timeout()):
+		zap.L().Fatal("Failed to shut down within the timeout")
+	}
+}
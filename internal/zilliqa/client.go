@@ -2,13 +2,13 @@ package zilliqa
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/hashicorp/go-retryablehttp"
 	"go.uber.org/zap"
 	"io/ioutil"
-	"net/http"
 	"time"
 )
 
@@ -16,12 +16,13 @@ const (
 	jsonrpcVersion = "2.0"
 )
 
-// A rpcClient represents a JSON RPC client (over HTTP(s)).
+// A rpcClient represents a JSON RPC client (over HTTP(s)), routing each call to the healthiest of its endpoints.
 type rpcClient struct {
-	url        string
-	httpClient *retryablehttp.Client
-	timeout    int
-	debug      bool
+	endpoints   []*endpoint
+	httpClient  *retryablehttp.Client
+	timeout     int
+	maxBlockLag uint64
+	debug       bool
 }
 
 // rpcRequest represent a RCP request
@@ -80,49 +81,49 @@ func (rResp rpcResponse) ResultAsInt64() (int64, error) {
 	return json.Number(rResp.Result).Int64()
 }
 
-func NewClient(url string, timeout int, debug bool) (*rpcClient, error) {
-	if len(url) == 0 {
+// NewClient spreads calls over the urls, checking the height of each every healthCheckInterval seconds until ctx is
+// cancelled
+func NewClient(ctx context.Context, urls []string, timeout int, maxBlockLag uint64, healthCheckInterval int, debug bool) (*rpcClient, error) {
+	endpoints := make([]*endpoint, 0, len(urls))
+	for _, url := range urls {
+		if len(url) != 0 {
+			endpoints = append(endpoints, newEndpoint(url))
+		}
+	}
+
+	if len(endpoints) == 0 {
 		return nil, errors.New("bad call missing argument host")
 	}
 
 	retryClient := retryablehttp.NewClient()
 	retryClient.Logger = nil
 	retryClient.RetryMax = 3
+	if len(endpoints) > 1 {
+		// Failing over to the next endpoint is quicker than retrying a node that is struggling
+		retryClient.RetryMax = 0
+	}
 
-	return &rpcClient{
-		url,
+	c := &rpcClient{
+		endpoints,
 		retryClient,
 		timeout,
+		maxBlockLag,
 		debug,
-	}, nil
+	}
+
+	if healthCheckInterval > 0 {
+		go c.monitor(ctx, time.Duration(healthCheckInterval)*time.Second)
+	}
+
+	return c, nil
 }
 
 func NewRequest(method string, params ...interface{}) *rpcRequest{
 	return &rpcRequest{method, params, time.Now().UnixNano(), jsonrpcVersion}
 }
 
-// doTimeoutRequest process a HTTP request with timeout
-func (c *rpcClient) doTimeoutRequest(timer *time.Timer, req *retryablehttp.Request) (*http.Response, error) {
-	type result struct {
-		resp *http.Response
-		err  error
-	}
-	done := make(chan result, 1)
-	go func() {
-		resp, err := c.httpClient.Do(req)
-		done <- result{resp, err}
-	}()
-	// Wait for the read or the timeout
-	select {
-	case r := <-done:
-		return r.resp, r.err
-	case <-timer.C:
-		return nil, errors.New("timeout reading data from server")
-	}
-}
-
 // call prepare & exec the request
-func (c *rpcClient) call(method string, params interface{}) (rr *rpcResponse, err error) {
+func (c *rpcClient) call(ctx context.Context, method string, params interface{}) (rr *rpcResponse, err error) {
 	rpcR := rpcRequest{method, params, time.Now().UnixNano(), jsonrpcVersion}
 	payloadBuffer := &bytes.Buffer{}
 	jsonEncoder := json.NewEncoder(payloadBuffer)
@@ -136,36 +137,21 @@ func (c *rpcClient) call(method string, params interface{}) (rr *rpcResponse, er
 		zap.L().With(zap.String("request", payloadBuffer.String())).Debug("Zilliqa: RPC Request")
 	}
 
-	req, err := retryablehttp.NewRequest("POST", c.url, payloadBuffer)
-	if err != nil {
-		return
-	}
+	err = c.send(ctx, payloadBuffer.Bytes(), func(data []byte) error {
+		rr = nil
+		if err := json.Unmarshal(data, &rr); err != nil {
+			return err
+		}
+		if rr != nil && rr.Error != nil && isNodeError(*rr.Error) {
+			return rr.Error
+		}
+		return nil
+	})
 
-	req.Header.Add("Content-Type", "application/json;charset=utf-8")
-	req.Header.Add("Accept", "application/json")
-
-	resp, err := c.doTimeoutRequest(time.NewTimer(time.Duration(c.timeout)*time.Second), req)
-	if err != nil {
-		zap.L().With(zap.Error(err)).Warn("Zilliqa: RPC Failure")
-		return
-	}
-	defer resp.Body.Close()
-
-	data, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return
-	}
-
-	if c.debug {
-		zap.L().With(zap.String("response", string(data))).Debug("Zilliqa: RPC Response")
-	}
-
-	err = json.Unmarshal(data, &rr)
 	return
 }
 
-
-func (c *rpcClient) callBatch(requests rpcRequests) (rr rpcResponses, err error) {
+func (c *rpcClient) callBatch(ctx context.Context, requests rpcRequests) (rr rpcResponses, err error) {
 	if len(requests) == 0 {
 		return nil, errors.New("empty request list")
 	}
@@ -185,28 +171,99 @@ func (c *rpcClient) callBatch(requests rpcRequests) (rr rpcResponses, err error)
 	if c.debug {
 		zap.L().With(zap.String("request", payloadBuffer.String())).Debug("Zilliqa: RPC Request")
 	}
-	req, err := retryablehttp.NewRequest("POST", c.url, payloadBuffer)
+
+	err = c.send(ctx, payloadBuffer.Bytes(), func(data []byte) error {
+		rr = nil
+		if err := json.Unmarshal(data, &rr); err != nil {
+			return err
+		}
+		for _, response := range rr {
+			if response != nil && response.Error != nil && isNodeError(*response.Error) {
+				return response.Error
+			}
+		}
+		return nil
+	})
+
+	return
+}
+
+// callRaw sends a prepared request and returns the response body as is
+func (c *rpcClient) callRaw(ctx context.Context, payload []byte) (data []byte, err error) {
+	err = c.send(ctx, payload, func(body []byte) error {
+		var response struct {
+			Error *RPCError `json:"error"`
+		}
+		if err := json.Unmarshal(body, &response); err != nil {
+			return err
+		}
+		if response.Error != nil && isNodeError(*response.Error) {
+			return response.Error
+		}
+		data = body
+		return nil
+	})
+
+	return
+}
+
+// send tries each endpoint in turn until one returns a response the handler accepts
+func (c *rpcClient) send(ctx context.Context, payload []byte, handle func(data []byte) error) (err error) {
+	for _, e := range c.route() {
+		start := time.Now()
+
+		var data []byte
+		if data, err = c.post(ctx, e.url, payload); err == nil {
+			err = handle(data)
+		}
+
+		// A cancelled call says nothing about the health of the endpoint
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		e.record(time.Since(start), err)
+
+		if err == nil {
+			return nil
+		}
+
+		zap.L().With(zap.Error(err), zap.String("url", e.url)).Warn("Zilliqa: RPC Failure")
+	}
+
+	return err
+}
+
+func (c *rpcClient) post(ctx context.Context, url string, payload []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(c.timeout)*time.Second)
+	defer cancel()
+
+	req, err := retryablehttp.NewRequestWithContext(ctx, "POST", url, payload)
 	if err != nil {
-		return
+		return nil, err
 	}
+
 	req.Header.Add("Content-Type", "application/json;charset=utf-8")
 	req.Header.Add("Accept", "application/json")
 
-	resp, err := c.doTimeoutRequest(time.NewTimer(time.Duration(c.timeout)*time.Second), req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	data, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return
+		return nil, err
 	}
 
 	if c.debug {
-		zap.L().With(zap.String("response", string(data))).Debug("Zilliqa: RPC Response")
+		zap.L().With(zap.String("response", string(data)), zap.String("url", url)).Debug("Zilliqa: RPC Response")
 	}
 
-	err = json.Unmarshal(data, &rr)
-	return
-}
\ No newline at end of file
+	return data, nil
+}
+
+// isNodeError is true for errors caused by the node rather than the request, which another node may not return
+func isNodeError(err RPCError) bool {
+	return errors.Is(err, ErrNodeError)
+}
@@ -0,0 +1,223 @@
+package zilliqa
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Weight given to the latest sample in the moving averages of latency and error rate
+const ewmaWeight = 0.2
+
+type endpoint struct {
+	mu          sync.RWMutex
+	url         string
+	requests    uint64
+	errors      uint64
+	errorRate   float64
+	latency     float64
+	blockHeight uint64
+	lastError   string
+	lastErrorAt *time.Time
+}
+
+// EndpointStats is a snapshot of the health of an RPC endpoint
+type EndpointStats struct {
+	Url         string     `json:"url"`
+	Requests    uint64     `json:"requests"`
+	Errors      uint64     `json:"errors"`
+	ErrorRate   float64    `json:"errorRate"`
+	LatencyMs   float64    `json:"latencyMs"`
+	BlockHeight uint64     `json:"blockHeight"`
+	Behind      bool       `json:"behind"`
+	LastError   string     `json:"lastError,omitempty"`
+	LastErrorAt *time.Time `json:"lastErrorAt,omitempty"`
+}
+
+func newEndpoint(url string) *endpoint {
+	return &endpoint{url: url}
+}
+
+func (e *endpoint) record(elapsed time.Duration, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	failed := 0.0
+	if err != nil {
+		failed = 1
+		now := time.Now()
+		e.errors++
+		e.lastError = err.Error()
+		e.lastErrorAt = &now
+	}
+
+	latency := float64(elapsed.Milliseconds())
+	if e.requests == 0 {
+		e.latency = latency
+		e.errorRate = failed
+	} else {
+		e.latency = ewmaWeight*latency + (1-ewmaWeight)*e.latency
+		e.errorRate = ewmaWeight*failed + (1-ewmaWeight)*e.errorRate
+	}
+	e.requests++
+}
+
+func (e *endpoint) setBlockHeight(height uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.blockHeight = height
+}
+
+func (e *endpoint) getBlockHeight() uint64 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.blockHeight
+}
+
+// score ranks endpoints by latency, heavily penalising those that have been failing. Lower is better.
+func (e *endpoint) score() float64 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return (e.latency + 1) * (1 + 10*e.errorRate)
+}
+
+// behind is true when the endpoint has reported a height more than maxBlockLag blocks below the tip
+func (e *endpoint) behind(tip, maxBlockLag uint64) bool {
+	height := e.getBlockHeight()
+
+	return height != 0 && tip > height+maxBlockLag
+}
+
+func (e *endpoint) stats(tip, maxBlockLag uint64) EndpointStats {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return EndpointStats{
+		Url:         e.url,
+		Requests:    e.requests,
+		Errors:      e.errors,
+		ErrorRate:   e.errorRate,
+		LatencyMs:   e.latency,
+		BlockHeight: e.blockHeight,
+		Behind:      e.blockHeight != 0 && tip > e.blockHeight+maxBlockLag,
+		LastError:   e.lastError,
+		LastErrorAt: e.lastErrorAt,
+	}
+}
+
+// route orders the endpoints to try for a call, healthy endpoints at the tip first
+func (c *rpcClient) route() []*endpoint {
+	tip := c.tip()
+
+	type candidate struct {
+		endpoint *endpoint
+		behind   bool
+		score    float64
+	}
+
+	candidates := make([]candidate, 0, len(c.endpoints))
+	for _, e := range c.endpoints {
+		candidates = append(candidates, candidate{e, e.behind(tip, c.maxBlockLag), e.score()})
+	}
+
+	sort.SliceStable(candidates, func(a, b int) bool {
+		if candidates[a].behind != candidates[b].behind {
+			return !candidates[a].behind
+		}
+		return candidates[a].score < candidates[b].score
+	})
+
+	endpoints := make([]*endpoint, 0, len(candidates))
+	for _, candidate := range candidates {
+		endpoints = append(endpoints, candidate.endpoint)
+	}
+
+	return endpoints
+}
+
+func (c *rpcClient) tip() uint64 {
+	var tip uint64
+	for _, e := range c.endpoints {
+		if height := e.getBlockHeight(); height > tip {
+			tip = height
+		}
+	}
+
+	return tip
+}
+
+func (c *rpcClient) Stats() []EndpointStats {
+	tip := c.tip()
+
+	stats := make([]EndpointStats, 0, len(c.endpoints))
+	for _, e := range c.endpoints {
+		stats = append(stats, e.stats(tip, c.maxBlockLag))
+	}
+
+	return stats
+}
+
+// monitor polls the block height of every endpoint until ctx is cancelled, so routing can avoid nodes behind the
+// chain tip
+func (c *rpcClient) monitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, e := range c.endpoints {
+			go c.checkHeight(ctx, e)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *rpcClient) checkHeight(ctx context.Context, e *endpoint) {
+	payload, err := json.Marshal(NewRequest("GetNumTxBlocks"))
+	if err != nil {
+		return
+	}
+
+	start := time.Now()
+	height, err := c.getHeight(ctx, e, payload)
+	if ctx.Err() != nil {
+		return
+	}
+	e.record(time.Since(start), err)
+	if err == nil {
+		e.setBlockHeight(height)
+	}
+}
+
+func (c *rpcClient) getHeight(ctx context.Context, e *endpoint, payload []byte) (uint64, error) {
+	data, err := c.post(ctx, e.url, payload)
+	if err != nil {
+		return 0, err
+	}
+
+	var response rpcResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return 0, err
+	}
+	if response.Error != nil {
+		return 0, response.Error
+	}
+
+	numTxBlocks, err := strconv.ParseUint(strings.Trim(response.ResultAsString(), "\""), 10, 64)
+	if err != nil || numTxBlocks == 0 {
+		return 0, err
+	}
+
+	return numTxBlocks - 1, nil
+}
@@ -0,0 +1,55 @@
+package zilliqa
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMonitorChecksHeightsUntilCancelled(t *testing.T) {
+	var checks int64
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&checks, 1)
+		_, _ = w.Write([]byte(`{"id":1,"jsonrpc":"2.0","result":"101"}`))
+	}))
+	defer node.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c, err := NewClient(ctx, []string{node.URL}, 5, 0, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		c.monitor(ctx, 10*time.Millisecond)
+		close(stopped)
+	}()
+
+	deadline := time.After(5 * time.Second)
+	for c.endpoints[0].getBlockHeight() != 100 {
+		select {
+		case <-deadline:
+			t.Fatalf("endpoint height %d, expected 100", c.endpoints[0].getBlockHeight())
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("monitor did not stop when cancelled")
+	}
+
+	// A check already in flight may still land, none start afterwards
+	time.Sleep(50 * time.Millisecond)
+	after := atomic.LoadInt64(&checks)
+	time.Sleep(50 * time.Millisecond)
+	if atomic.LoadInt64(&checks) != after {
+		t.Error("heights checked after the monitor stopped")
+	}
+}
@@ -0,0 +1,63 @@
+package zilliqa
+
+import "errors"
+
+// Error codes returned by the Zilliqa JSON-RPC api
+const (
+	RPCMiscError           RPCErrorCode = -1
+	RPCInvalidAddressOrKey RPCErrorCode = -5
+	RPCDatabaseError       RPCErrorCode = -20
+	RPCInvalidParams       RPCErrorCode = -32602
+	RPCInternalError       RPCErrorCode = -32603
+)
+
+var (
+	// ErrBlockNotFound is returned for blocks the node does not have, usually because it has not produced them yet
+	ErrBlockNotFound = errors.New("block not found")
+
+	// ErrMicroblockUnavailable is returned when the node has the tx block but cannot read one of its microblocks
+	ErrMicroblockUnavailable = errors.New("microblock unavailable")
+
+	// ErrNoTransactions is returned for tx blocks without any transactions
+	ErrNoTransactions = errors.New("tx block has no transactions")
+
+	// ErrTxnHashNotPresent is returned when the node cannot find the transactions of a tx block
+	ErrTxnHashNotPresent = errors.New("txn hash not present")
+
+	// ErrAccountNotCreated is returned for the balance of an address that has never received any ZIL
+	ErrAccountNotCreated = errors.New("account is not created")
+
+	// ErrDatabaseError matches any error the node returned reading its database
+	ErrDatabaseError = errors.New("database error")
+
+	// ErrNodeError matches errors caused by the node rather than the request, which another node may not return
+	ErrNodeError = errors.New("node error")
+)
+
+// Is lets RPC errors be matched against the sentinel errors with errors.Is
+func (e RPCError) Is(target error) bool {
+	switch target {
+	case ErrBlockNotFound:
+		return e.Code == RPCInvalidParams
+	case ErrMicroblockUnavailable:
+		return e.Code == RPCDatabaseError && e.Message == "Failed to get Microblock"
+	case ErrNoTransactions:
+		return e.Code == RPCMiscError && e.Message == "TxBlock has no transactions"
+	case ErrTxnHashNotPresent:
+		return e.Code == RPCDatabaseError && e.Message == "Txn Hash not Present"
+	case ErrAccountNotCreated:
+		return e.Code == RPCInvalidAddressOrKey && e.Message == "Account is not created"
+	case ErrDatabaseError:
+		return e.Code == RPCDatabaseError
+	case ErrNodeError:
+		return e.Code == RPCDatabaseError || e.Code == RPCInternalError
+	}
+
+	return false
+}
+
+// IsBlockUnavailable is true when the node could not serve a block, either because it does not have it yet or
+// because it cannot read it
+func IsBlockUnavailable(err error) bool {
+	return errors.Is(err, ErrBlockNotFound) || errors.Is(err, ErrDatabaseError)
+}
@@ -17,12 +17,10 @@
 package zilliqa
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"go.uber.org/zap"
-	"io/ioutil"
-	"net/http"
 )
 
 type Provider struct {
@@ -34,8 +32,8 @@ func NewProvider(rpcClient *rpcClient) *Provider {
 	return &Provider{rpcClient: rpcClient}
 }
 
-func (p *Provider) GetNetworkId() (string, error) {
-	response, err := p.call("GetNetworkId")
+func (p *Provider) GetNetworkId(ctx context.Context) (string, error) {
+	response, err := p.call(ctx, "GetNetworkId")
 	if err != nil {
 		return "", err
 	}
@@ -43,8 +41,8 @@ func (p *Provider) GetNetworkId() (string, error) {
 	return response.ResultAsString(), nil
 }
 
-func (p *Provider) GetBlockchainInfo() (*BlockchainInfo, error) {
-	response, err := p.call("GetBlockchainInfo")
+func (p *Provider) GetBlockchainInfo(ctx context.Context) (*BlockchainInfo, error) {
+	response, err := p.call(ctx, "GetBlockchainInfo")
 	if err != nil {
 		return nil, err
 	}
@@ -62,8 +60,8 @@ func (p *Provider) GetBlockchainInfo() (*BlockchainInfo, error) {
 	return &blockchainInfo, nil
 }
 
-func (p *Provider) GetShardingStructure() (*ShardingStructure, error) {
-	response, err := p.call("GetShardingStructure")
+func (p *Provider) GetShardingStructure(ctx context.Context) (*ShardingStructure, error) {
+	response, err := p.call(ctx, "GetShardingStructure")
 	if err != nil {
 		return nil, err
 	}
@@ -82,8 +80,8 @@ func (p *Provider) GetShardingStructure() (*ShardingStructure, error) {
 
 }
 
-func (p *Provider) GetDsBlock(blockNum string) (*DSBlock, error) {
-	result, err := p.call("GetDsBlock", blockNum)
+func (p *Provider) GetDsBlock(ctx context.Context, blockNum string) (*DSBlock, error) {
+	result, err := p.call(ctx, "GetDsBlock", blockNum)
 	if err != nil {
 		return nil, err
 	}
@@ -101,8 +99,8 @@ func (p *Provider) GetDsBlock(blockNum string) (*DSBlock, error) {
 	return &dsBlock, nil
 }
 
-func (p *Provider) GetLatestDsBlock() (*DSBlock, error) {
-	result, err := p.call("GetLatestDsBlock")
+func (p *Provider) GetLatestDsBlock(ctx context.Context) (*DSBlock, error) {
+	result, err := p.call(ctx, "GetLatestDsBlock")
 	if err != nil {
 		return nil, err
 	}
@@ -120,8 +118,8 @@ func (p *Provider) GetLatestDsBlock() (*DSBlock, error) {
 	return &dsBlock, nil
 }
 
-func (p *Provider) GetNumDSBlocks() (string, error) {
-	response, err := p.call("GetNumDSBlocks")
+func (p *Provider) GetNumDSBlocks(ctx context.Context) (string, error) {
+	response, err := p.call(ctx, "GetNumDSBlocks")
 	if err != nil {
 		return "", err
 	}
@@ -129,8 +127,8 @@ func (p *Provider) GetNumDSBlocks() (string, error) {
 	return response.ResultAsString(), nil
 }
 
-func (p *Provider) GetDSBlockRate() (float64, error) {
-	result, err := p.call("GetDSBlockRate")
+func (p *Provider) GetDSBlockRate(ctx context.Context) (float64, error) {
+	result, err := p.call(ctx, "GetDSBlockRate")
 	if err != nil {
 		return 0, err
 	}
@@ -138,8 +136,8 @@ func (p *Provider) GetDSBlockRate() (float64, error) {
 	return json.Number(result.Result).Float64()
 }
 
-func (p *Provider) DSBlockListing(dsBlockListing int) (*BlockList, error) {
-	response, err := p.call("DSBlockListing", dsBlockListing)
+func (p *Provider) DSBlockListing(ctx context.Context, dsBlockListing int) (*BlockList, error) {
+	response, err := p.call(ctx, "DSBlockListing", dsBlockListing)
 	if err != nil {
 		return nil, err
 	}
@@ -157,8 +155,8 @@ func (p *Provider) DSBlockListing(dsBlockListing int) (*BlockList, error) {
 	return &list, nil
 }
 
-func (p *Provider) GetTxBlock(blockNum string) (*TxBlock, error) {
-	response, err := p.call("GetTxBlock", blockNum)
+func (p *Provider) GetTxBlock(ctx context.Context, blockNum string) (*TxBlock, error) {
+	response, err := p.call(ctx, "GetTxBlock", blockNum)
 	if err != nil {
 		return nil, err
 	}
@@ -176,14 +174,14 @@ func (p *Provider) GetTxBlock(blockNum string) (*TxBlock, error) {
 	return &txBlock, nil
 }
 
-func (p *Provider) GetTxBlocks(blockNums []string) ([]TxBlock, error) {
+func (p *Provider) GetTxBlocks(ctx context.Context, blockNums []string) ([]TxBlock, error) {
 	var requests rpcRequests
 	for _, blockNum := range blockNums {
 		r := NewRequest("GetTxBlock", blockNum)
 		requests = append(requests, r)
 	}
 
-	responses, err := p.callBatch(requests)
+	responses, err := p.callBatch(ctx, requests)
 	if err != nil {
 		return nil, err
 	}
@@ -191,6 +189,10 @@ func (p *Provider) GetTxBlocks(blockNums []string) ([]TxBlock, error) {
 	var txBlocks []TxBlock
 
 	for _, response := range responses {
+		if response.Error != nil {
+			return nil, response.Error
+		}
+
 		jsonString, err := response.ResultAsJson()
 		if err != nil {
 			return nil, err
@@ -207,8 +209,8 @@ func (p *Provider) GetTxBlocks(blockNums []string) ([]TxBlock, error) {
 	return txBlocks, nil
 }
 
-func (p *Provider) GetLatestTxBlock() (*TxBlock, error) {
-	response, err := p.call("GetLatestTxBlock")
+func (p *Provider) GetLatestTxBlock(ctx context.Context) (*TxBlock, error) {
+	response, err := p.call(ctx, "GetLatestTxBlock")
 	if err != nil {
 		return nil, err
 	}
@@ -226,8 +228,8 @@ func (p *Provider) GetLatestTxBlock() (*TxBlock, error) {
 	return &txBlock, nil
 }
 
-func (p *Provider) GetNumTxBlocks() (string, error) {
-	response, err := p.call("GetNumTxBlocks")
+func (p *Provider) GetNumTxBlocks(ctx context.Context) (string, error) {
+	response, err := p.call(ctx, "GetNumTxBlocks")
 	if err != nil {
 		return "", err
 	}
@@ -235,8 +237,8 @@ func (p *Provider) GetNumTxBlocks() (string, error) {
 	return response.ResultAsString(), nil
 }
 
-func (p *Provider) GetTxBlockRate() (float64, error) {
-	response, err := p.call("GetTxBlockRate")
+func (p *Provider) GetTxBlockRate(ctx context.Context) (float64, error) {
+	response, err := p.call(ctx, "GetTxBlockRate")
 	if err != nil {
 		return 0, err
 	}
@@ -244,8 +246,8 @@ func (p *Provider) GetTxBlockRate() (float64, error) {
 	return response.ResultAsFloat64()
 }
 
-func (p *Provider) TxBlockListing(page int) (*BlockList, error) {
-	response, err := p.call("TxBlockListing", page)
+func (p *Provider) TxBlockListing(ctx context.Context, page int) (*BlockList, error) {
+	response, err := p.call(ctx, "TxBlockListing", page)
 	if err != nil {
 		return nil, err
 	}
@@ -263,8 +265,8 @@ func (p *Provider) TxBlockListing(page int) (*BlockList, error) {
 	return &list, nil
 }
 
-func (p *Provider) GetNumTransactions() (string, error) {
-	response, err := p.call("GetNumTransactions")
+func (p *Provider) GetNumTransactions(ctx context.Context) (string, error) {
+	response, err := p.call(ctx, "GetNumTransactions")
 	if err != nil {
 		return "", err
 	}
@@ -272,8 +274,8 @@ func (p *Provider) GetNumTransactions() (string, error) {
 	return response.ResultAsString(), nil
 }
 
-func (p *Provider) GetTransactionRate() (float64, error) {
-	response, err := p.call("GetTransactionRate")
+func (p *Provider) GetTransactionRate(ctx context.Context) (float64, error) {
+	response, err := p.call(ctx, "GetTransactionRate")
 	if err != nil {
 		return 0, err
 	}
@@ -281,8 +283,8 @@ func (p *Provider) GetTransactionRate() (float64, error) {
 	return response.ResultAsFloat64()
 }
 
-func (p *Provider) GetCurrentMiniEpoch() (string, error) {
-	response, err := p.call("GetCurrentMiniEpoch")
+func (p *Provider) GetCurrentMiniEpoch(ctx context.Context) (string, error) {
+	response, err := p.call(ctx, "GetCurrentMiniEpoch")
 	if err != nil {
 		return "", err
 	}
@@ -290,8 +292,8 @@ func (p *Provider) GetCurrentMiniEpoch() (string, error) {
 	return response.ResultAsString(), nil
 }
 
-func (p *Provider) GetCurrentDSEpoch() (string, error) {
-	response, err := p.call("GetCurrentDSEpoch")
+func (p *Provider) GetCurrentDSEpoch(ctx context.Context) (string, error) {
+	response, err := p.call(ctx, "GetCurrentDSEpoch")
 	if err != nil {
 		return "", err
 	}
@@ -299,8 +301,8 @@ func (p *Provider) GetCurrentDSEpoch() (string, error) {
 	return response.ResultAsString(), nil
 }
 
-func (p *Provider) GetPrevDifficulty() (int64, error) {
-	response, err := p.call("GetPrevDifficulty")
+func (p *Provider) GetPrevDifficulty(ctx context.Context) (int64, error) {
+	response, err := p.call(ctx, "GetPrevDifficulty")
 	if err != nil {
 		return 0, err
 	}
@@ -308,8 +310,8 @@ func (p *Provider) GetPrevDifficulty() (int64, error) {
 	return response.ResultAsInt64()
 }
 
-func (p *Provider) GetPrevDSDifficulty() (int64, error) {
-	response, err := p.call("GetPrevDSDifficulty")
+func (p *Provider) GetPrevDSDifficulty(ctx context.Context) (int64, error) {
+	response, err := p.call(ctx, "GetPrevDSDifficulty")
 	if err != nil {
 		return 0, err
 	}
@@ -317,8 +319,8 @@ func (p *Provider) GetPrevDSDifficulty() (int64, error) {
 	return response.ResultAsInt64()
 }
 
-func (p *Provider) GetTotalCoinSupply() (string, error) {
-	response, err := p.call("GetTotalCoinSupply")
+func (p *Provider) GetTotalCoinSupply(ctx context.Context) (string, error) {
+	response, err := p.call(ctx, "GetTotalCoinSupply")
 	if err != nil {
 		return "", err
 	}
@@ -326,8 +328,8 @@ func (p *Provider) GetTotalCoinSupply() (string, error) {
 	return response.ResultAsString(), nil
 }
 
-func (p *Provider) GetMinerInfo(dsNumber string) (*MinerInfo, error) {
-	response, err := p.call("GetMinerInfo", dsNumber)
+func (p *Provider) GetMinerInfo(ctx context.Context, dsNumber string) (*MinerInfo, error) {
+	response, err := p.call(ctx, "GetMinerInfo", dsNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -352,8 +354,8 @@ func (p *Provider) GetMinerInfo(dsNumber string) (*MinerInfo, error) {
 //  false	1	Nonce too high
 //  false	2	Could not fit in as microblock gas limit reached
 //  false	3	Transaction valid but consensus not reached
-func (p *Provider) GetPendingTxn(tx string) (*rpcResponse, error) {
-	return p.call("GetPendingTxn", tx)
+func (p *Provider) GetPendingTxn(ctx context.Context, tx string) (*rpcResponse, error) {
+	return p.call(ctx, "GetPendingTxn", tx)
 }
 
 // Returns the pending status of all unvalidated Transactions.
@@ -365,12 +367,12 @@ func (p *Provider) GetPendingTxn(tx string) (*rpcResponse, error) {
 //  false	1	Nonce too high
 //  false	2	Could not fit in as microblock gas limit reached
 //  false	3	Transaction valid but consensus not reached
-func (p *Provider) GetPendingTxns() (*rpcResponse, error) {
-	return p.call("GetPendingTxns")
+func (p *Provider) GetPendingTxns(ctx context.Context) (*rpcResponse, error) {
+	return p.call(ctx, "GetPendingTxns")
 }
 
-func (p *Provider) GetTransaction(txId string) (*Transaction, error) {
-	response, err := p.call("GetTransaction", txId)
+func (p *Provider) GetTransaction(ctx context.Context, txId string) (*Transaction, error) {
+	response, err := p.call(ctx, "GetTransaction", txId)
 	if err != nil {
 		return nil, err
 	}
@@ -388,14 +390,14 @@ func (p *Provider) GetTransaction(txId string) (*Transaction, error) {
 	return &transaction, nil
 }
 
-func (p *Provider) GetTransactionBatch(txIds []string) ([]*Transaction, error) {
+func (p *Provider) GetTransactionBatch(ctx context.Context, txIds []string) ([]*Transaction, error) {
 	var requests rpcRequests
 	for _, hash := range txIds {
 		r := NewRequest("GetTransaction", []string{hash})
 		requests = append(requests, r)
 	}
 
-	responses, err := p.rpcClient.callBatch(requests)
+	responses, err := p.rpcClient.callBatch(ctx, requests)
 	if err != nil {
 		return nil, err
 	}
@@ -420,8 +422,8 @@ func (p *Provider) GetTransactionBatch(txIds []string) ([]*Transaction, error) {
 
 }
 
-func (p *Provider) GetRecentTransactions() (*Transactions, error) {
-	response, err := p.call("GetRecentTransactions")
+func (p *Provider) GetRecentTransactions(ctx context.Context) (*Transactions, error) {
+	response, err := p.call(ctx, "GetRecentTransactions")
 	if err != nil {
 		return nil, err
 	}
@@ -439,8 +441,8 @@ func (p *Provider) GetRecentTransactions() (*Transactions, error) {
 	return &transactions, nil
 }
 
-func (p *Provider) GetTransactionsForTxBlock(blockNum string) ([][]string, error) {
-	response, err := p.call("GetTransactionsForTxBlock", blockNum)
+func (p *Provider) GetTransactionsForTxBlock(ctx context.Context, blockNum string) ([][]string, error) {
+	response, err := p.call(ctx, "GetTransactionsForTxBlock", blockNum)
 	if err != nil {
 		return nil, err
 	}
@@ -458,12 +460,12 @@ func (p *Provider) GetTransactionsForTxBlock(blockNum string) ([][]string, error
 	return transactions, nil
 }
 
-func (p *Provider) GetTxnBodiesForTxBlock(blockNum string) ([]Transaction, error) {
+func (p *Provider) GetTxnBodiesForTxBlock(ctx context.Context, blockNum string) ([]Transaction, error) {
 	var transactions []Transaction
 
-	response, err := p.call("GetTxnBodiesForTxBlock", blockNum)
+	response, err := p.call(ctx, "GetTxnBodiesForTxBlock", blockNum)
 	if err != nil {
-		if err.Error() == "-1:TxBlock has no transactions" || err.Error() == "-20:Failed to get Microblock" || err.Error() == "-20:Txn Hash not Present" {
+		if errors.Is(err, ErrNoTransactions) || errors.Is(err, ErrTxnHashNotPresent) {
 			return transactions, nil
 		}
 		return nil, err
@@ -480,14 +482,14 @@ func (p *Provider) GetTxnBodiesForTxBlock(blockNum string) ([]Transaction, error
 	return transactions, nil
 }
 
-func (p *Provider) GetTxnBodiesForTxBlocks(blockNums []string) (map[string][]Transaction, error) {
+func (p *Provider) GetTxnBodiesForTxBlocks(ctx context.Context, blockNums []string) (map[string][]Transaction, error) {
 	var requests rpcRequests
 	for _, blockNum := range blockNums {
 		r := NewRequest("GetTxnBodiesForTxBlock", blockNum)
 		requests = append(requests, r)
 	}
 
-	responses, err := p.callBatch(requests)
+	responses, err := p.callBatch(ctx, requests)
 	if err != nil {
 		return nil, err
 	}
@@ -496,10 +498,7 @@ func (p *Provider) GetTxnBodiesForTxBlocks(blockNums []string) (map[string][]Tra
 
 	for idx, response := range responses {
 		if response.Error != nil {
-			if response.Error.Message == "TxBlock has no transactions" {
-				continue
-			}
-			if response.Error.Message == "Txn Hash not Present" {
+			if errors.Is(response.Error, ErrNoTransactions) || errors.Is(response.Error, ErrTxnHashNotPresent) {
 				continue
 			}
 			return nil, response.Error
@@ -521,8 +520,8 @@ func (p *Provider) GetTxnBodiesForTxBlocks(blockNums []string) (map[string][]Tra
 	return transactions, nil
 }
 
-func (p *Provider) GetNumTxnsTxEpoch() (string, error) {
-	response, err := p.call("GetNumTxnsTxEpoch")
+func (p *Provider) GetNumTxnsTxEpoch(ctx context.Context) (string, error) {
+	response, err := p.call(ctx, "GetNumTxnsTxEpoch")
 	if err != nil {
 		return "", err
 	}
@@ -530,8 +529,8 @@ func (p *Provider) GetNumTxnsTxEpoch() (string, error) {
 	return response.ResultAsString(), nil
 }
 
-func (p *Provider) GetNumTxnsDSEpoch() (string, error) {
-	response, err := p.call("GetNumTxnsDSEpoch")
+func (p *Provider) GetNumTxnsDSEpoch(ctx context.Context) (string, error) {
+	response, err := p.call(ctx, "GetNumTxnsDSEpoch")
 	if err != nil {
 		return "", err
 	}
@@ -539,8 +538,8 @@ func (p *Provider) GetNumTxnsDSEpoch() (string, error) {
 	return response.ResultAsString(), nil
 }
 
-func (p *Provider) GetMinimumGasPrice() (string, error) {
-	response, err := p.call("GetMinimumGasPrice")
+func (p *Provider) GetMinimumGasPrice(ctx context.Context) (string, error) {
+	response, err := p.call(ctx, "GetMinimumGasPrice")
 	if err != nil {
 		return "", err
 	}
@@ -548,8 +547,8 @@ func (p *Provider) GetMinimumGasPrice() (string, error) {
 	return response.ResultAsString(), nil
 }
 
-func (p *Provider) GetSmartContractCode(contractAddr string) (string, error) {
-	result, err := p.call("GetSmartContractCode", contractAddr)
+func (p *Provider) GetSmartContractCode(ctx context.Context, contractAddr string) (string, error) {
+	result, err := p.call(ctx, "GetSmartContractCode", contractAddr)
 	if err != nil {
 		return "", err
 	}
@@ -563,8 +562,8 @@ func (p *Provider) GetSmartContractCode(contractAddr string) (string, error) {
 	return "", errors.New("failed to get code for contract")
 }
 
-func (p *Provider) GetSmartContractInit(contractAddr string) ([]ContractValue, error) {
-	response, err := p.call("GetSmartContractInit", contractAddr)
+func (p *Provider) GetSmartContractInit(ctx context.Context, contractAddr string) ([]ContractValue, error) {
+	response, err := p.call(ctx, "GetSmartContractInit", contractAddr)
 	if err != nil {
 		return nil, err
 	}
@@ -582,14 +581,14 @@ func (p *Provider) GetSmartContractInit(contractAddr string) ([]ContractValue, e
 	return init, nil
 }
 
-func (p *Provider) GetSmartContractInits(contractAddresses []string) ([][]ContractValue, error) {
+func (p *Provider) GetSmartContractInits(ctx context.Context, contractAddresses []string) ([][]ContractValue, error) {
 	var requests rpcRequests
 	for _, contractAddress := range contractAddresses {
 		r := NewRequest("GetSmartContractInit", contractAddress)
 		requests = append(requests, r)
 	}
 
-	responses, err := p.rpcClient.callBatch(requests)
+	responses, err := p.rpcClient.callBatch(ctx, requests)
 	if err != nil {
 		return nil, err
 	}
@@ -607,11 +606,11 @@ func (p *Provider) GetSmartContractInits(contractAddresses []string) ([][]Contra
 	return contractValues, nil
 }
 
-func (p *Provider) GetSmartContractState(contractAddr string) (*rpcResponse, error) {
-	return p.call("GetSmartContractState", contractAddr)
+func (p *Provider) GetSmartContractState(ctx context.Context, contractAddr string) (*rpcResponse, error) {
+	return p.call(ctx, "GetSmartContractState", contractAddr)
 }
 
-func (p *Provider) GetSmartContractSubState(contractAddr string, params ...interface{}) (string, error) {
+func (p *Provider) GetSmartContractSubState(ctx context.Context, contractAddr string, params ...interface{}) (string, error) {
 	//we should hack here for now
 	type req struct {
 		Id      string      `json:"id"`
@@ -636,20 +635,7 @@ func (p *Provider) GetSmartContractSubState(contractAddr string, params ...inter
 	}
 
 	b, _ := json.Marshal(r)
-	reader := bytes.NewReader(b)
-	request, err := http.NewRequest("POST", p.rpcClient.url, reader)
-	if err != nil {
-		return "", err
-	}
-	request.Header.Set("Content-Type", "application/json;charset=UTF-8")
-	client := http.Client{}
-	resp, err := client.Do(request)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	result, err := ioutil.ReadAll(resp.Body)
+	result, err := p.rpcClient.callRaw(ctx, b)
 	if err != nil {
 		return "", err
 	}
@@ -659,15 +645,15 @@ func (p *Provider) GetSmartContractSubState(contractAddr string, params ...inter
 }
 
 // Returns the list of smart contract addresses created by an User's account and the contracts' latest states.
-func (p *Provider) GetSmartContracts(address string) (*rpcResponse, error) {
-	return p.call("GetSmartContracts", address)
+func (p *Provider) GetSmartContracts(ctx context.Context, address string) (*rpcResponse, error) {
+	return p.call(ctx, "GetSmartContracts", address)
 }
 
 // Returns a smart contract address of 20 bytes. This is represented as a String.
 // NOTE: This only works for contract deployment transactions.
-func (p *Provider) GetContractAddressFromTransactionID(txId string) (string, error) {
+func (p *Provider) GetContractAddressFromTransactionID(ctx context.Context, txId string) (string, error) {
 	zap.S().Infof("GetContractAddressFromTransactionID(%s)", txId)
-	result, err := p.call("GetContractAddressFromTransactionID", txId)
+	result, err := p.call(ctx, "GetContractAddressFromTransactionID", txId)
 	if err != nil {
 		return "", err
 	}
@@ -681,7 +667,7 @@ func (p *Provider) GetContractAddressFromTransactionID(txId string) (string, err
 
 // Returns a smart contract address of 20 bytes. This is represented as a String.
 // NOTE: This only works for contract deployment transactions.
-func (p *Provider) GetContractAddressFromTransactionIDs(transactionIds []string) (map[string]string, error) {
+func (p *Provider) GetContractAddressFromTransactionIDs(ctx context.Context, transactionIds []string) (map[string]string, error) {
 	if len(transactionIds) == 0 {
 		return map[string]string{}, nil
 	}
@@ -692,7 +678,7 @@ func (p *Provider) GetContractAddressFromTransactionIDs(transactionIds []string)
 		requests = append(requests, r)
 	}
 
-	results, err := p.callBatch(requests)
+	results, err := p.callBatch(ctx, requests)
 	if err != nil {
 		return nil, err
 	}
@@ -713,8 +699,8 @@ func (p *Provider) GetContractAddressFromTransactionIDs(transactionIds []string)
 // Returns the current balance of an account, measured in the smallest accounting unit Qa (or 10^-12 Zil).
 // This is represented as a String
 // Returns the current nonce of an account. This is represented as an Number.
-func (p *Provider) GetBalance(address string) (*BalanceAndNonce, error) {
-	result, err := p.call("GetBalance", address)
+func (p *Provider) GetBalance(ctx context.Context, address string) (*BalanceAndNonce, error) {
+	result, err := p.call(ctx, "GetBalance", address)
 	if err != nil {
 		return nil, err
 	}
@@ -740,8 +726,8 @@ func (p *Provider) GetBalance(address string) (*BalanceAndNonce, error) {
 	return &balanceAndNonce, nil
 }
 
-func (p *Provider) call(method string, params ...interface{}) (*rpcResponse, error) {
-	response, err := p.rpcClient.call(method, params)
+func (p *Provider) call(ctx context.Context, method string, params ...interface{}) (*rpcResponse, error) {
+	response, err := p.rpcClient.call(ctx, method, params)
 
 	if err != nil {
 		return nil, err
@@ -758,8 +744,12 @@ func (p *Provider) call(method string, params ...interface{}) (*rpcResponse, err
 	return response, nil
 }
 
-func (p *Provider) callBatch(requests rpcRequests) (rpcResponses, error) {
-	responses, err := p.rpcClient.callBatch(requests)
+func (p *Provider) Stats() []EndpointStats {
+	return p.rpcClient.Stats()
+}
+
+func (p *Provider) callBatch(ctx context.Context, requests rpcRequests) (rpcResponses, error) {
+	responses, err := p.rpcClient.callBatch(ctx, requests)
 
 	if err != nil {
 		return nil, err
@@ -0,0 +1,42 @@
+package zilliqa
+
+import "fmt"
+
+// Error codes in the receipt of a failed transaction, indexed by their value in the node's ErrTxnStatus
+var receiptErrors = []string{
+	"NOT_PRESENT",
+	"CHECKER_FAILED",
+	"RUNNER_FAILED",
+	"BALANCE_TRANSFER_FAILED",
+	"EXECUTE_CMD_FAILED",
+	"EXECUTE_CMD_TIMEOUT",
+	"NO_GAS_REMAINING_FOUND",
+	"NO_ACCEPTED_FOUND",
+	"CALL_CONTRACT_FAILED",
+	"CREATE_CONTRACT_FAILED",
+	"JSON_OUTPUT_CORRUPTED",
+	"CONTRACT_NOT_EXIST",
+	"STATE_CORRUPTED",
+	"LOG_ENTRY_INSTALL_FAILED",
+	"MESSAGE_CORRUPTED",
+	"RECEIPT_IS_NULL",
+	"MAX_EDGES_REACHED",
+	"CHAIN_CALL_DIFF_SHARD",
+	"PREPARATION_FAILED",
+	"NO_OUTPUT",
+	"OUTPUT_ILLEGAL",
+	"MAP_DEPTH_MISSING",
+	"GAS_NOT_SUFFICIENT",
+	"INTERNAL_ERROR",
+	"LIBRARY_AS_RECIPIENT",
+	"VERSION_INCONSISTENT",
+	"LIBRARY_EXTRACTION_FAILED",
+}
+
+func ReceiptErrorName(code int) string {
+	if code < 0 || code >= len(receiptErrors) {
+		return fmt.Sprintf("UNKNOWN_%d", code)
+	}
+
+	return receiptErrors[code]
+}
@@ -1,71 +1,84 @@
 package zilliqa
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"go.uber.org/zap"
+	"strings"
 )
 
 type Service interface {
-	GetBlockchainInfo() (*BlockchainInfo, error)
-	GetDSBlock(height uint64) (*DSBlock, error)
-	GetTxBlock(height uint64) (*TxBlock, error)
-	GetTxBlocks(from uint64, count uint) ([]TxBlock, error)
-	GetLatestTxBlock() (*TxBlock, error)
-	GetTransactionsForTxBlock(height uint64) ([]string, error)
-	GetTxnBodiesForTxBlock(height uint64) ([]Transaction, error)
-	GetTxnBodiesForTxBlocks(from, count uint64) (map[string][]Transaction, error)
-	GetTransaction(hash string) (*Transaction, error)
-
-	GetContractAddressFromTransactionID(txId string) (string, error)
-	GetContractAddressFromTransactionIDs(txIds []string) (map[string]string, error)
-	GetSmartContractInit(contractAddress string) ([]ContractValue, error)
-	GetSmartContractInits(contractAddresses []string) ([][]ContractValue, error)
-	GetSmartContractCode(contractAddress string) (string, error)
-	GetContractState(contractAddress string) (map[string]interface{}, error)
-	GetContractSubState(contractAddress string, params ...interface{}) (string, error)
+	GetBlockchainInfo(ctx context.Context) (*BlockchainInfo, error)
+	GetDSBlock(ctx context.Context, height uint64) (*DSBlock, error)
+	GetTxBlock(ctx context.Context, height uint64) (*TxBlock, error)
+	GetTxBlocks(ctx context.Context, from uint64, count uint) ([]TxBlock, error)
+	GetLatestTxBlock(ctx context.Context) (*TxBlock, error)
+	GetTransactionsForTxBlock(ctx context.Context, height uint64) ([]string, error)
+	GetTxnBodiesForTxBlock(ctx context.Context, height uint64) ([]Transaction, error)
+	GetTxnBodiesForTxBlocks(ctx context.Context, from, count uint64) (map[string][]Transaction, error)
+	GetTransaction(ctx context.Context, hash string) (*Transaction, error)
+
+	GetContractAddressFromTransactionID(ctx context.Context, txId string) (string, error)
+	GetContractAddressFromTransactionIDs(ctx context.Context, txIds []string) (map[string]string, error)
+	GetSmartContractInit(ctx context.Context, contractAddress string) ([]ContractValue, error)
+	GetSmartContractInits(ctx context.Context, contractAddresses []string) ([][]ContractValue, error)
+	GetSmartContractCode(ctx context.Context, contractAddress string) (string, error)
+	GetContractState(ctx context.Context, contractAddress string) (map[string]interface{}, error)
+	GetContractSubState(ctx context.Context, contractAddress string, params ...interface{}) (string, error)
+
+	GetBalance(ctx context.Context, address string) (*BalanceAndNonce, error)
+
+	GetEndpointStats() []EndpointStats
 }
 
 type service struct {
-	provider *Provider
+	provider   *Provider
+	skipBlocks map[uint64]bool
 }
 
-func NewZilliqaService(provider *Provider) Service {
-	return service{provider}
+// NewZilliqaService skips fetching the transactions of skipBlocks, blocks the node is known to be unable to serve
+func NewZilliqaService(provider *Provider, skipBlocks []uint64) Service {
+	skip := make(map[uint64]bool, len(skipBlocks))
+	for _, blockNum := range skipBlocks {
+		skip[blockNum] = true
+	}
+
+	return service{provider, skip}
 }
 
-func (s service) GetBlockchainInfo() (*BlockchainInfo, error) {
-	return s.provider.GetBlockchainInfo()
+func (s service) GetBlockchainInfo(ctx context.Context) (*BlockchainInfo, error) {
+	return s.provider.GetBlockchainInfo(ctx)
 }
 
-func (s service) GetDSBlock(height uint64) (*DSBlock, error) {
-	return s.provider.GetDsBlock(fmt.Sprintf("%d", height))
+func (s service) GetDSBlock(ctx context.Context, height uint64) (*DSBlock, error) {
+	return s.provider.GetDsBlock(ctx, fmt.Sprintf("%d", height))
 }
 
-func (s service) GetTxBlock(height uint64) (*TxBlock, error) {
-	return s.provider.GetTxBlock(fmt.Sprintf("%d", height))
+func (s service) GetTxBlock(ctx context.Context, height uint64) (*TxBlock, error) {
+	return s.provider.GetTxBlock(ctx, fmt.Sprintf("%d", height))
 }
 
-func (s service) GetTxBlocks(from uint64, count uint) ([]TxBlock, error) {
+func (s service) GetTxBlocks(ctx context.Context, from uint64, count uint) ([]TxBlock, error) {
 	blockNums := make([]string, 0)
 
 	for x := from; x < from+uint64(count); x++ {
 		blockNums = append(blockNums, fmt.Sprintf("%d", x))
 	}
 
-	return s.provider.GetTxBlocks(blockNums)
+	return s.provider.GetTxBlocks(ctx, blockNums)
 }
 
-func (s service) GetLatestTxBlock() (*TxBlock, error) {
-	return s.provider.GetLatestTxBlock()
+func (s service) GetLatestTxBlock(ctx context.Context) (*TxBlock, error) {
+	return s.provider.GetLatestTxBlock(ctx)
 }
 
-func (s service) GetTransactionsForTxBlock(blockNum uint64) ([]string, error) {
+func (s service) GetTransactionsForTxBlock(ctx context.Context, blockNum uint64) ([]string, error) {
 	addrs := make([]string, 0)
 
-	txBlock, err := s.provider.GetTransactionsForTxBlock(fmt.Sprintf("%d", blockNum))
-	if err != nil && err.Error() == "-1:TxBlock has no transactions" {
+	txBlock, err := s.provider.GetTransactionsForTxBlock(ctx, fmt.Sprintf("%d", blockNum))
+	if errors.Is(err, ErrNoTransactions) {
 		zap.L().With(zap.Uint64("height", blockNum)).Warn("TxBlock has no transactions")
 		return []string{}, nil
 	}
@@ -79,8 +92,8 @@ func (s service) GetTransactionsForTxBlock(blockNum uint64) ([]string, error) {
 	return addrs, err
 }
 
-func (s service) GetTxnBodiesForTxBlock(height uint64) ([]Transaction, error) {
-	txs, err := s.provider.GetTxnBodiesForTxBlock(fmt.Sprintf("%d", height))
+func (s service) GetTxnBodiesForTxBlock(ctx context.Context, height uint64) ([]Transaction, error) {
+	txs, err := s.provider.GetTxnBodiesForTxBlock(ctx, fmt.Sprintf("%d", height))
 	if err != nil {
 		return []Transaction{}, nil
 	}
@@ -88,22 +101,25 @@ func (s service) GetTxnBodiesForTxBlock(height uint64) ([]Transaction, error) {
 	return txs, err
 }
 
-func (s service) GetTxnBodiesForTxBlocks(from, count uint64) (txs map[string][]Transaction, err error) {
+func (s service) GetTxnBodiesForTxBlocks(ctx context.Context, from, count uint64) (txs map[string][]Transaction, err error) {
 	zap.L().With(zap.Uint64("from", from), zap.Uint64("count", count)).Debug("GetTxnBodiesForTxBlocks")
 
 	blockNums := make([]string, 0)
 	for x := from; x < from+count; x++ {
-		if x == 1664279 {
-			// @todo 1664279 returns a  -20:Failed to get Microblock on mainnet. No fucking idea why
+		if s.skipBlocks[x] {
+			zap.L().With(zap.Uint64("height", x)).Warn("Skipping block from the skip list")
 			continue
 		}
 		blockNums = append(blockNums, fmt.Sprintf("%d", x))
 	}
+	if len(blockNums) == 0 {
+		return map[string][]Transaction{}, nil
+	}
 
-	if txs, err = s.provider.GetTxnBodiesForTxBlocks(blockNums); err != nil {
+	if txs, err = s.provider.GetTxnBodiesForTxBlocks(ctx, blockNums); err != nil {
 		txs = map[string][]Transaction{}
 		for _, blockNum := range blockNums {
-			blockTxs, err := s.provider.GetTxnBodiesForTxBlock(blockNum)
+			blockTxs, err := s.provider.GetTxnBodiesForTxBlock(ctx, blockNum)
 			if err != nil {
 				return nil, err
 			}
@@ -114,32 +130,32 @@ func (s service) GetTxnBodiesForTxBlocks(from, count uint64) (txs map[string][]T
 	return txs, nil
 }
 
-func (s service) GetTransaction(hash string) (*Transaction, error) {
-	return s.provider.GetTransaction(hash)
+func (s service) GetTransaction(ctx context.Context, hash string) (*Transaction, error) {
+	return s.provider.GetTransaction(ctx, hash)
 }
 
-func (s service) GetContractAddressFromTransactionID(txId string) (string, error) {
-	return s.provider.GetContractAddressFromTransactionID(txId)
+func (s service) GetContractAddressFromTransactionID(ctx context.Context, txId string) (string, error) {
+	return s.provider.GetContractAddressFromTransactionID(ctx, txId)
 }
 
-func (s service) GetContractAddressFromTransactionIDs(txIds []string) (map[string]string, error) {
-	return s.provider.GetContractAddressFromTransactionIDs(txIds)
+func (s service) GetContractAddressFromTransactionIDs(ctx context.Context, txIds []string) (map[string]string, error) {
+	return s.provider.GetContractAddressFromTransactionIDs(ctx, txIds)
 }
 
-func (s service) GetSmartContractInit(contractAddress string) ([]ContractValue, error) {
-	return s.provider.GetSmartContractInit(contractAddress)
+func (s service) GetSmartContractInit(ctx context.Context, contractAddress string) ([]ContractValue, error) {
+	return s.provider.GetSmartContractInit(ctx, contractAddress)
 }
 
-func (s service) GetSmartContractInits(contractAddresses []string) ([][]ContractValue, error) {
-	return s.provider.GetSmartContractInits(contractAddresses)
+func (s service) GetSmartContractInits(ctx context.Context, contractAddresses []string) ([][]ContractValue, error) {
+	return s.provider.GetSmartContractInits(ctx, contractAddresses)
 }
 
-func (s service) GetSmartContractCode(contractAddress string) (string, error) {
-	return s.provider.GetSmartContractCode(contractAddress)
+func (s service) GetSmartContractCode(ctx context.Context, contractAddress string) (string, error) {
+	return s.provider.GetSmartContractCode(ctx, contractAddress)
 }
 
-func (s service) GetContractState(contractAddress string) (map[string]interface{}, error) {
-	resp, err := s.provider.GetSmartContractState(contractAddress)
+func (s service) GetContractState(ctx context.Context, contractAddress string) (map[string]interface{}, error) {
+	resp, err := s.provider.GetSmartContractState(ctx, contractAddress)
 	if err != nil {
 		return nil, err
 	}
@@ -161,11 +177,26 @@ func (s service) GetContractState(contractAddress string) (map[string]interface{
 	return respInt, err
 }
 
-func (s service) GetContractSubState(contractAddress string, params ...interface{}) (string, error) {
-	resp, err := s.provider.GetSmartContractSubState(contractAddress, params)
+func (s service) GetContractSubState(ctx context.Context, contractAddress string, params ...interface{}) (string, error) {
+	resp, err := s.provider.GetSmartContractSubState(ctx, contractAddress, params...)
 	if err != nil {
 		return "", err
 	}
 
 	return resp, err
 }
+
+// GetBalance returns the balance in Qa and the nonce of an address, which are zero for addresses the chain has
+// never credited
+func (s service) GetBalance(ctx context.Context, address string) (*BalanceAndNonce, error) {
+	balance, err := s.provider.GetBalance(ctx, strings.TrimPrefix(address, "0x"))
+	if errors.Is(err, ErrAccountNotCreated) {
+		return &BalanceAndNonce{Balance: "0", Nonce: 0}, nil
+	}
+
+	return balance, err
+}
+
+func (s service) GetEndpointStats() []EndpointStats {
+	return s.provider.Stats()
+}
@@ -0,0 +1,181 @@
+package zilliqa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+	"strconv"
+	"time"
+)
+
+// Subscriber announces new tx blocks as the chain produces them
+type Subscriber interface {
+	// Subscribe sends the number of each new tx block to blocks until ctx is cancelled
+	Subscribe(ctx context.Context, blocks chan<- uint64)
+}
+
+type subscriber struct {
+	url          string
+	service      Service
+	pollInterval time.Duration
+	maxBackoff   time.Duration
+}
+
+type wsMessage struct {
+	Type   string `json:"type"`
+	Values []struct {
+		Query string          `json:"query"`
+		Value json.RawMessage `json:"value"`
+	} `json:"values"`
+}
+
+type wsNewBlock struct {
+	TxBlock TxBlock `json:"TxBlock"`
+}
+
+var ErrSubscriptionClosed = errors.New("websocket subscription closed")
+
+// Tx blocks are produced every minute or so, a subscription silent for longer than this has stalled
+const staleSubscription = 3 * time.Minute
+
+// NewSubscriber listens for NewBlock notifications on the websocket url, polling the rpc service for the latest
+// block while the websocket is unavailable. Without a url it only polls.
+func NewSubscriber(url string, service Service, pollInterval, maxBackoff time.Duration) Subscriber {
+	if pollInterval <= 0 {
+		pollInterval = 5 * time.Second
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = time.Minute
+	}
+
+	return subscriber{url, service, pollInterval, maxBackoff}
+}
+
+func (s subscriber) Subscribe(ctx context.Context, blocks chan<- uint64) {
+	if s.url == "" {
+		s.poll(ctx, blocks, nil)
+		return
+	}
+
+	backoff := time.Second
+	for ctx.Err() == nil {
+		err := s.listen(ctx, blocks, func() {
+			backoff = time.Second
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		zap.L().With(zap.Error(err), zap.Duration("backoff", backoff)).Warn("Zilliqa: Websocket unavailable, polling for blocks")
+		s.poll(ctx, blocks, time.After(backoff))
+
+		if backoff *= 2; backoff > s.maxBackoff {
+			backoff = s.maxBackoff
+		}
+	}
+}
+
+// listen subscribes to NewBlock on the websocket, calling connected once the subscription is accepted
+func (s subscriber) listen(ctx context.Context, blocks chan<- uint64, connected func()) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, s.url, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Unblock the read below when ctx is cancelled
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	if err := conn.WriteJSON(map[string]string{"query": "NewBlock"}); err != nil {
+		return err
+	}
+
+	zap.L().With(zap.String("url", s.url)).Info("Zilliqa: Subscribed to new blocks")
+	connected()
+
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(staleSubscription))
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		var msg wsMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			zap.L().With(zap.Error(err), zap.String("message", string(data))).Warn("Zilliqa: Unreadable websocket message")
+			continue
+		}
+
+		if msg.Type == "Unsubscribe" {
+			return ErrSubscriptionClosed
+		}
+		if msg.Type != "Notification" {
+			continue
+		}
+
+		for _, value := range msg.Values {
+			if value.Query != "NewBlock" {
+				continue
+			}
+
+			var newBlock wsNewBlock
+			if err := json.Unmarshal(value.Value, &newBlock); err != nil {
+				zap.L().With(zap.Error(err)).Warn("Zilliqa: Unreadable NewBlock notification")
+				continue
+			}
+
+			blockNum, err := strconv.ParseUint(newBlock.TxBlock.Header.BlockNum, 0, 64)
+			if err != nil {
+				zap.L().With(zap.Error(err)).Warn("Zilliqa: Unreadable NewBlock block num")
+				continue
+			}
+
+			if !send(ctx, blocks, blockNum) {
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// poll sends the latest block every poll interval until ctx is cancelled or until fires
+func (s subscriber) poll(ctx context.Context, blocks chan<- uint64, until <-chan time.Time) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if latest, err := s.service.GetLatestTxBlock(ctx); err == nil {
+			if blockNum, err := strconv.ParseUint(latest.Header.BlockNum, 0, 64); err == nil {
+				if !send(ctx, blocks, blockNum) {
+					return
+				}
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-until:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func send(ctx context.Context, blocks chan<- uint64, blockNum uint64) bool {
+	select {
+	case blocks <- blockNum:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,236 @@
+package zilliqa
+
+import (
+	"context"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// latestBlockService answers the polls of the subscriber with the latest block of the chain
+type latestBlockService struct {
+	Service
+	latest uint64
+}
+
+func (s *latestBlockService) GetLatestTxBlock(ctx context.Context) (*TxBlock, error) {
+	return &TxBlock{Header: TxBlockHeader{BlockNum: strconv.FormatUint(atomic.LoadUint64(&s.latest), 10)}}, nil
+}
+
+// wsStandIn is a websocket node running one script per connection, rejecting the connection when the script is nil
+type wsStandIn struct {
+	*httptest.Server
+	scripts       []func(conn *websocket.Conn)
+	subscriptions chan string
+
+	mu       sync.Mutex
+	attempts int
+}
+
+func newWsStandIn(t *testing.T, scripts ...func(conn *websocket.Conn)) *wsStandIn {
+	s := &wsStandIn{scripts: scripts, subscriptions: make(chan string, 10)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *wsStandIn) url() string {
+	return "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+func (s *wsStandIn) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	var script func(conn *websocket.Conn)
+	if s.attempts < len(s.scripts) {
+		script = s.scripts[s.attempts]
+	}
+	s.attempts++
+	s.mu.Unlock()
+
+	if script == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	_, query, err := conn.ReadMessage()
+	if err != nil {
+		return
+	}
+	s.subscriptions <- strings.TrimSpace(string(query))
+
+	script(conn)
+}
+
+func notify(blockNums ...uint64) func(conn *websocket.Conn) {
+	return func(conn *websocket.Conn) {
+		for _, blockNum := range blockNums {
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(
+				`{"type":"Notification","values":[{"query":"NewBlock","value":{"TxBlock":{"header":{"BlockNum":"%d"}}}}]}`,
+				blockNum,
+			)))
+		}
+	}
+}
+
+// stayConnected keeps the connection open until the subscriber closes it
+func stayConnected(conn *websocket.Conn) {
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+func subscribe(t *testing.T, url string, service Service) <-chan uint64 {
+	ctx, cancel := context.WithCancel(context.Background())
+	blocks := make(chan uint64)
+	stopped := make(chan struct{})
+
+	go func() {
+		NewSubscriber(url, service, 10*time.Millisecond, time.Second).Subscribe(ctx, blocks)
+		close(stopped)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			t.Error("subscriber did not stop when cancelled")
+		}
+	})
+
+	return blocks
+}
+
+// receive reads blocks until last, dropping the repeats of the polls
+func receive(t *testing.T, blocks <-chan uint64, last uint64) []uint64 {
+	t.Helper()
+
+	var received []uint64
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case blockNum := <-blocks:
+			if len(received) == 0 || received[len(received)-1] != blockNum {
+				received = append(received, blockNum)
+			}
+			if blockNum == last {
+				return received
+			}
+		case <-timeout:
+			t.Fatalf("received blocks %v, expected to reach %d", received, last)
+		}
+	}
+}
+
+func assertBlocks(t *testing.T, received []uint64, expected ...uint64) {
+	t.Helper()
+
+	if fmt.Sprint(received) != fmt.Sprint(expected) {
+		t.Errorf("received blocks %v, expected %v", received, expected)
+	}
+}
+
+func assertSubscriptions(t *testing.T, s *wsStandIn, count int) {
+	t.Helper()
+
+	for idx := 0; idx < count; idx++ {
+		select {
+		case query := <-s.subscriptions:
+			if query != `{"query":"NewBlock"}` {
+				t.Errorf("subscribed with %s, expected the NewBlock query", query)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%d subscriptions, expected %d", idx, count)
+		}
+	}
+}
+
+func TestSubscribeSendsNewBlocks(t *testing.T) {
+	s := newWsStandIn(t, func(conn *websocket.Conn) {
+		for _, msg := range []string{
+			`not json`,
+			`{"type":"Notification","values":[{"query":"EventLog","value":{}}]}`,
+			`{"type":"Notification","values":[{"query":"NewBlock","value":{"TxBlock":{"header":{"BlockNum":"x"}}}}]}`,
+			`{"type":"SubscribeNewBlock"}`,
+		} {
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(msg))
+		}
+		notify(100, 101)(conn)
+		stayConnected(conn)
+	})
+
+	blocks := subscribe(t, s.url(), &latestBlockService{latest: 1})
+
+	assertBlocks(t, receive(t, blocks, 101), 100, 101)
+	assertSubscriptions(t, s, 1)
+}
+
+func TestSubscribeResubscribesAfterDisconnect(t *testing.T) {
+	for name, disconnect := range map[string]func(conn *websocket.Conn){
+		"closed": func(conn *websocket.Conn) {},
+		"unsubscribed": func(conn *websocket.Conn) {
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"Unsubscribe"}`))
+			stayConnected(conn)
+		},
+	} {
+		disconnect := disconnect
+		t.Run(name, func(t *testing.T) {
+			s := newWsStandIn(t,
+				func(conn *websocket.Conn) {
+					notify(100)(conn)
+					disconnect(conn)
+				},
+				func(conn *websocket.Conn) {
+					notify(104)(conn)
+					stayConnected(conn)
+				},
+			)
+
+			// The chain moves on to 103 while disconnected, the poll announces it so the gap is indexed
+			blocks := subscribe(t, s.url(), &latestBlockService{latest: 103})
+
+			assertBlocks(t, receive(t, blocks, 104), 100, 103, 104)
+			assertSubscriptions(t, s, 2)
+		})
+	}
+}
+
+func TestSubscribeBackfillsWhileWebsocketUnavailable(t *testing.T) {
+	service := &latestBlockService{latest: 50}
+	s := newWsStandIn(t, nil, func(conn *websocket.Conn) {
+		notify(52)(conn)
+		stayConnected(conn)
+	})
+
+	blocks := subscribe(t, s.url(), service)
+
+	assertBlocks(t, receive(t, blocks, 50), 50)
+	atomic.StoreUint64(&service.latest, 51)
+	assertBlocks(t, receive(t, blocks, 52), 51, 52)
+	assertSubscriptions(t, s, 1)
+}
+
+func TestSubscribeWithoutUrlPolls(t *testing.T) {
+	service := &latestBlockService{latest: 7}
+	blocks := subscribe(t, "", service)
+
+	assertBlocks(t, receive(t, blocks, 7), 7)
+	atomic.StoreUint64(&service.latest, 8)
+	assertBlocks(t, receive(t, blocks, 8), 8)
+}